@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/infra"
 	"north-post/service/internal/repository"
 	"north-post/service/internal/services"
@@ -28,6 +32,43 @@ import (
 
 var PORT_NUMBER = 8080
 
+// Route serving the local bucket files that presigned URLs point to in local storage mode
+const localBucketRoute = "/local-bucket"
+
+// Languages tried when a request's language and its fallbacks are all disabled, comma separated
+func getDefaultLanguageChain() []models.Language {
+	chain := []models.Language{}
+	for _, code := range strings.Split(os.Getenv("LANGUAGE_FALLBACK_CHAIN"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			chain = append(chain, models.Language(code))
+		}
+	}
+	if len(chain) == 0 {
+		return []models.Language{models.LanguageEN}
+	}
+	return chain
+}
+
+// Days an address stays in the trash before it is purged
+func getTrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("ADDRESS_TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return services.DefaultTrashRetention
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Users granted the owner role at startup, comma separated uids
+func getBootstrapOwners() []string {
+	uids := []string{}
+	for _, uid := range strings.Split(os.Getenv("ADMIN_OWNER_UIDS"), ",") {
+		if uid = strings.TrimSpace(uid); uid != "" {
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
+
 func getPort() string {
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -61,6 +102,10 @@ func main() {
 		logger.Info("loaded environment configuration", "file", envFile)
 	}
 
+	// Storage mode decides between the cloud services and the local backends
+	storageMode := infra.GetStorageMode()
+	logger.Info("storage mode selected", "mode", storageMode)
+
 	// Initialize Firebase client
 	firebaseClient, err := infra.NewFirebaseClient(logger)
 	if err != nil {
@@ -73,34 +118,105 @@ func main() {
 		}
 	}()
 
-	// Initialize typesense client
-	typesenseClient, err := infra.NewTypesenseClient(logger)
-	if err != nil {
-		logger.Error("failed to initialize typesense service", "error", err)
-		log.Fatalf("failed to initialize typesense service: %v", err)
-	}
+	var (
+		searchClient       infra.SearchClient
+		bucketClient       infra.BucketClient
+		llmClient          infra.CompletionClient
+		localStorageBucket *infra.LocalStorageBucket
+	)
+	if storageMode == infra.StorageModeLocal {
+		searchClient = infra.NewMemorySearchClient(logger)
+		localBucketURL := fmt.Sprintf("http://localhost:%s%s", getPort(), localBucketRoute)
+		localStorageBucket, err = infra.NewLocalStorageBucket(localBucketURL, logger)
+		if err != nil {
+			logger.Error("failed to initialize local storage bucket", "error", err)
+			log.Fatalf("failed to initialize local storage bucket: %v", err)
+		}
+		bucketClient = localStorageBucket
+		llmClient = infra.NewStubLLMClient(logger)
+	} else {
+		// Initialize typesense client
+		typesenseClient, err := infra.NewTypesenseClient(logger)
+		if err != nil {
+			logger.Error("failed to initialize typesense service", "error", err)
+			log.Fatalf("failed to initialize typesense service: %v", err)
+		}
+		searchClient = typesenseClient
 
-	// Initialize storage bucket client
-	storageBucketClient, err := infra.NewStorageBucketClient(logger)
-	if err != nil {
-		logger.Error("failed to initialize storage bucker", "error", err)
-		log.Fatalf("failed to initialize storage bucket: %v", err)
+		// Initialize storage bucket client
+		storageBucketClient, err := infra.NewStorageBucketClient(logger)
+		if err != nil {
+			logger.Error("failed to initialize storage bucker", "error", err)
+			log.Fatalf("failed to initialize storage bucket: %v", err)
+		}
+		bucketClient = storageBucketClient
+
+		// Initialize LLM client
+		cloudLLMClient, err := infra.NewLLMClient(logger)
+		if err != nil {
+			logger.Error("failed to initialize llm client", "error", err)
+			log.Fatalf("failed to initialize llm client %v", err)
+		}
+		llmClient = cloudLLMClient
 	}
 
-	// Initialize LLM client
-	llmClient, err := infra.NewLLMClient(logger)
-	if err != nil {
-		logger.Error("failed to initialize llm client", "error", err)
-		log.Fatalf("failed to initialize llm client %v", err)
+	// Language registry, stored languages are loaded before anything validates a language
+	languageRepo := repository.NewLanguageRepository(firebaseClient.Firestore, logger)
+	languageService := services.NewLanguageService(languageRepo, models.Languages(), logger)
+	models.Languages().SetDefaultChain(getDefaultLanguageChain())
+	if err := languageService.Load(context.Background()); err != nil {
+		logger.Warn("failed to load languages, using the built-in ones", "error", err)
 	}
+	go languageService.Run(context.Background())
+	adminLanguageHandler := adminHandlers.NewLanguageHandler(languageService, logger)
 
 	// Address service
 	addressRepo := repository.NewAddressRepository(
 		firebaseClient.Firestore,
-		typesenseClient,
+		searchClient,
 		logger)
 	addressService := services.NewAddressService(addressRepo, llmClient)
-	adminAddressHandler := adminHandlers.NewAddressHandler(addressService, logger)
+
+	// Job service
+	jobRepo := repository.NewJobRepository(firebaseClient.Firestore, logger)
+	jobService := services.NewJobService(jobRepo, logger)
+	jobService.Register(models.JobTypeTypesenseSync, addressService.RunSyncToTypesenseJob)
+	jobService.Register(models.JobTypeTagsRefresh, addressService.RunRefreshTagsJob)
+	jobService.Register(models.JobTypeTagsMigration, addressService.RunMigrateTagsJob)
+	jobService.Register(models.JobTypeDuplicateKeysBackfill, addressService.RunBackfillDuplicateKeysJob)
+	go jobService.Run(context.Background())
+	// search indexes created before the facet and sort fields are rebuilt in the background
+	outdatedIndexes, err := addressService.ListOutdatedSearchIndexes(context.Background())
+	if err != nil {
+		logger.Warn("failed to check the search index schema", "error", err)
+	}
+	for _, language := range outdatedIndexes {
+		_, err := jobService.SubmitJob(context.Background(), services.SubmitJobInput{
+			Type: models.JobTypeTypesenseSync,
+			Params: map[string]string{
+				models.JobParamLanguage: language.Get(),
+				models.JobParamFullSync: "true",
+			},
+		})
+		if err != nil {
+			logger.Warn("failed to start the search index rebuild", "language", language, "error", err)
+		} else {
+			logger.Info("rebuilding the outdated search index", "language", language)
+		}
+	}
+	adminJobHandler := adminHandlers.NewJobHandler(jobService, logger)
+
+	// Search outbox, applies address changes to the search index in the background
+	outboxRepo := repository.NewOutboxRepository(firebaseClient.Firestore, logger)
+	outboxService := services.NewOutboxService(outboxRepo, addressRepo, logger)
+	go outboxService.Run(context.Background())
+	adminOutboxHandler := adminHandlers.NewOutboxHandler(outboxService, logger)
+
+	// Trash purge, permanently removes addresses deleted longer ago than the retention window
+	trashService := services.NewTrashService(addressRepo, getTrashRetention(), logger)
+	go trashService.Run(context.Background())
+
+	adminAddressHandler := adminHandlers.NewAddressHandler(addressService, jobService, logger)
 	userAddressHandler := userHandlers.NewAddressHandler(addressService, logger)
 
 	// Prompt service
@@ -110,27 +226,36 @@ func main() {
 
 	// User data service
 	userRepo := repository.NewUserRepository(firebaseClient, logger)
-	userService := services.NewUserService(userRepo)
+	userService := services.NewUserService(userRepo, logger)
+	if err := userService.BootstrapOwners(context.Background(), getBootstrapOwners()); err != nil {
+		logger.Error("failed to bootstrap owners", "error", err)
+	}
 	adminUserDataHandler := adminHandlers.NewUserHandler(userService, logger)
-	appUserDataHandler := userHandlers.NewUserHandler(userRepo, logger)
+	languagePreferences := middleware.NewLanguagePreferenceCache()
+	appUserDataHandler := userHandlers.NewUserHandler(userRepo, languagePreferences, logger)
 
 	// Music service
 	musicRepo := repository.NewMusicRepository(
-		storageBucketClient.R2Storage,
-		storageBucketClient.R2Presigned,
+		bucketClient,
 		firebaseClient.Firestore,
 		logger,
 	)
 	musicService := services.NewMusicService(musicRepo)
 	adminMusicHandler := adminHandlers.NewMusicHandler(musicService, logger)
 	userMusicHandler := userHandlers.NewMusicHandler(musicService, logger)
+	userLikedMusicHandler := userHandlers.NewLikedMusicHandler(userRepo, musicRepo, logger)
 
 	// Typesense handler
-	adminTypesenseHandler := adminHandlers.NewTypesenseHandler(typesenseClient, logger)
+	adminTypesenseHandler := adminHandlers.NewTypesenseHandler(searchClient, logger)
 
 	// User Address Book
 	userAddressBookHandler := userHandlers.NewAddressBookHandler(userRepo, addressRepo, logger)
 
+	// Letter drafts service
+	draftRepo := repository.NewDraftRepository(firebaseClient.Firestore, logger)
+	draftService := services.NewDraftService(draftRepo, addressRepo, musicRepo)
+	userDraftHandler := userHandlers.NewDraftHandler(draftService, logger)
+
 	// Setup routers
 	router := gin.Default()
 	allowedOrigins := os.Getenv("ALLOWED_ORIGINS")
@@ -151,9 +276,12 @@ func main() {
 		swaggerURL := fmt.Sprintf("http://localhost:%s/swagger/index.html", getPort())
 		logger.Info("swagger UI enabled", "url", swaggerURL)
 	}
+	if localStorageBucket != nil {
+		router.Static(localBucketRoute, localStorageBucket.Root())
+	}
 	router_v1 := router.Group("/v1")
 
-	middlewares := middleware.SetupMiddlewares(firebaseClient.Auth, logger)
+	middlewares := middleware.SetupMiddlewares(firebaseClient.Auth, userRepo, userRepo, languagePreferences, logger)
 	admin.SetupAdminRouter(router_v1,
 		&admin.Handlers{
 			Address:   adminAddressHandler,
@@ -161,6 +289,9 @@ func main() {
 			User:      adminUserDataHandler,
 			Music:     adminMusicHandler,
 			Typesense: adminTypesenseHandler,
+			Job:       adminJobHandler,
+			Outbox:    adminOutboxHandler,
+			Language:  adminLanguageHandler,
 		},
 		middlewares)
 
@@ -170,6 +301,8 @@ func main() {
 			User:        appUserDataHandler,
 			Address:     userAddressHandler,
 			AddressBook: userAddressBookHandler,
+			Draft:       userDraftHandler,
+			LikedMusic:  userLikedMusicHandler,
 		},
 		middlewares)
 
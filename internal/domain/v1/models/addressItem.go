@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"slices"
+)
+
 type AddressGenerationSchema struct {
 	Name       string   `json:"name"`
 	BriefIntro string   `json:"briefIntro"`
@@ -11,6 +16,18 @@ type BatchAddressGenerationSchema struct {
 	Addresses []AddressGenerationSchema
 }
 
+// Translated texts of an address, the postal fields are kept in the local script and not translated
+type AddressTranslationSchema struct {
+	SourceID   string   `json:"sourceId"`
+	Name       string   `json:"name"`
+	BriefIntro string   `json:"briefIntro"`
+	Tags       []string `json:"tags"`
+}
+
+type BatchAddressTranslationSchema struct {
+	Translations []AddressTranslationSchema
+}
+
 type AddressItem struct {
 	ID         string   `json:"id" firestore:"id"`
 	Name       string   `json:"name" firestore:"name"`
@@ -19,6 +36,18 @@ type AddressItem struct {
 	UpdatedAt  int64    `json:"updatedAt" firestore:"updatedAt"`
 	Tags       []string `json:"tags" firestore:"tags"`
 	Address    Address  `json:"address" firestore:"address"`
+	DeletedAt  int64    `json:"deletedAt,omitempty" firestore:"deletedAt,omitempty"`   // set while the address is in the trash
+	Revision   int64    `json:"revision" firestore:"revision"`                         // number of the latest revision
+	MergedInto string   `json:"mergedInto,omitempty" firestore:"mergedInto,omitempty"` // surviving address after a merge
+	// entries of the same place in the other languages share it, see TranslationGroup
+	TranslationGroupID string `json:"translationGroupId,omitempty" firestore:"translationGroupId,omitempty"`
+	// normalized keys of the duplicate detector, see UpdateDuplicateKeys
+	NameKey   string `json:"-" firestore:"nameKey,omitempty"`
+	PostalKey string `json:"-" firestore:"postalKey,omitempty"`
+}
+
+func (a AddressItem) IsDeleted() bool {
+	return a.DeletedAt > 0
 }
 
 type Address struct {
@@ -35,3 +64,58 @@ type TagsRecord struct {
 	Tags        map[string][]string `json:"tags" firestore:"tags"`
 	RefreshedAt int64               `json:"refreshedAt" firestore:"refreshedAt"`
 }
+
+// Left behind when an address is deleted so the incremental search sync can remove it
+type AddressTombstone struct {
+	ID        string `json:"id" firestore:"id"`
+	DeletedAt int64  `json:"deletedAt" firestore:"deletedAt"`
+}
+
+type SearchSyncMode string
+
+const (
+	SearchSyncModeFull        SearchSyncMode = "full"
+	SearchSyncModeIncremental SearchSyncMode = "incremental"
+)
+
+// Progress of the search index sync of one language
+type SearchSyncState struct {
+	Watermark int64          `json:"watermark" firestore:"watermark"` // changes after this time are not indexed yet
+	Mode      SearchSyncMode `json:"mode" firestore:"mode"`
+	SyncedAt  int64          `json:"syncedAt" firestore:"syncedAt"`
+}
+
+// Order of address search results
+type AddressSort string
+
+const (
+	AddressSortUpdated   AddressSort = "updated" // most recently updated first, the default
+	AddressSortRelevance AddressSort = "relevance"
+	AddressSortName      AddressSort = "name"
+)
+
+var AddressSorts = []AddressSort{AddressSortUpdated, AddressSortRelevance, AddressSortName}
+
+func (s AddressSort) Validate() error {
+	if s == "" || slices.Contains(AddressSorts, s) {
+		return nil
+	}
+	return fmt.Errorf("unsupported sort: %s", s)
+}
+
+// Number of search results carrying each tag of a category
+type TagFacet struct {
+	Category TagCategory
+	Counts   []TagFacetCount // the most frequent tags first
+}
+
+type TagFacetCount struct {
+	Value string
+	Count int
+}
+
+// Fields of a search hit with the matched keywords wrapped in <mark> tags, empty when the field didn't match
+type AddressHighlight struct {
+	Name       string // the whole name
+	BriefIntro string // a snippet around the matches
+}
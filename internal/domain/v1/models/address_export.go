@@ -0,0 +1,19 @@
+package models
+
+import "fmt"
+
+type AddressExportFormat string
+
+const (
+	AddressExportFormatJSONL AddressExportFormat = "jsonl"
+	AddressExportFormatCSV   AddressExportFormat = "csv"
+	AddressExportFormatZip   AddressExportFormat = "zip" // one JSONL file per supported language
+)
+
+func (f AddressExportFormat) Validate() error {
+	switch f {
+	case AddressExportFormatJSONL, AddressExportFormatCSV, AddressExportFormatZip:
+		return nil
+	}
+	return fmt.Errorf("unsupported export format: %s", f)
+}
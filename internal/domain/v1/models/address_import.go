@@ -0,0 +1,37 @@
+package models
+
+import "fmt"
+
+type AddressImportFormat string
+
+const (
+	AddressImportFormatCSV   AddressImportFormat = "csv"
+	AddressImportFormatJSONL AddressImportFormat = "jsonl"
+)
+
+func (f AddressImportFormat) Validate() error {
+	switch f {
+	case AddressImportFormatCSV, AddressImportFormatJSONL:
+		return nil
+	}
+	return fmt.Errorf("unsupported import format: %s", f)
+}
+
+type AddressImportStatus string
+
+const (
+	AddressImportStatusReady     AddressImportStatus = "ready" // dry run only, the row would be created
+	AddressImportStatusCreated   AddressImportStatus = "created"
+	AddressImportStatusInvalid   AddressImportStatus = "invalid"
+	AddressImportStatusDuplicate AddressImportStatus = "duplicate"
+	AddressImportStatusFailed    AddressImportStatus = "failed"
+)
+
+// Outcome of one record of a bulk import
+type AddressImportRow struct {
+	Row    int                 `json:"row"` // 1-based record number, the CSV header is not counted
+	Status AddressImportStatus `json:"status"`
+	ID     string              `json:"id,omitempty"`
+	Name   string              `json:"name,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "fmt"
+
+type AdminRole string
+
+const (
+	AdminRoleViewer AdminRole = "viewer"
+	AdminRoleEditor AdminRole = "editor"
+	AdminRoleOwner  AdminRole = "owner"
+)
+
+type Permission string
+
+const (
+	PermissionReadContent     Permission = "content:read"
+	PermissionWriteAddress    Permission = "address:write"
+	PermissionGenerateAddress Permission = "address:generate"
+	PermissionDeleteAddress   Permission = "address:delete"
+	PermissionSyncIndex       Permission = "index:sync"
+	PermissionManageJobs      Permission = "jobs:manage"
+	PermissionManageAdmins    Permission = "admin:manage"
+	PermissionManageLanguages Permission = "languages:manage"
+)
+
+// Each role includes all permissions of the roles below it
+var rolePermissions = map[AdminRole][]Permission{
+	AdminRoleViewer: {
+		PermissionReadContent,
+	},
+	AdminRoleEditor: {
+		PermissionReadContent,
+		PermissionWriteAddress,
+		PermissionGenerateAddress,
+		PermissionManageJobs,
+	},
+	AdminRoleOwner: {
+		PermissionReadContent,
+		PermissionWriteAddress,
+		PermissionGenerateAddress,
+		PermissionDeleteAddress,
+		PermissionSyncIndex,
+		PermissionManageJobs,
+		PermissionManageAdmins,
+		PermissionManageLanguages,
+	},
+}
+
+func (r AdminRole) Validate() error {
+	if _, ok := rolePermissions[r]; !ok {
+		return fmt.Errorf("unsupported admin role: %s", r)
+	}
+	return nil
+}
+
+func (r AdminRole) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
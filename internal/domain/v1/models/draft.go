@@ -0,0 +1,12 @@
+package models
+
+type Draft struct {
+	ID                 string   `json:"id" firestore:"id"`
+	OwnerID            string   `json:"ownerId" firestore:"ownerId"`
+	RecipientAddressID string   `json:"recipientAddressId" firestore:"recipientAddressId"`
+	Language           Language `json:"language" firestore:"language"`
+	Body               string   `json:"body" firestore:"body"`
+	MusicFilename      string   `json:"musicFilename,omitempty" firestore:"musicFilename"`
+	CreatedAt          int64    `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt          int64    `json:"updatedAt" firestore:"updatedAt"`
+}
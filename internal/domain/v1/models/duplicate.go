@@ -0,0 +1,133 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type DuplicateReason string
+
+const (
+	DuplicateReasonName          DuplicateReason = "name"           // same normalized name
+	DuplicateReasonFuzzyName     DuplicateReason = "fuzzy_name"     // close name found by the search engine
+	DuplicateReasonPostalAddress DuplicateReason = "postal_address" // same normalized street address
+)
+
+// Existing address that looks like the one being checked
+type DuplicateMatch struct {
+	Address        AddressItem       `json:"address"`
+	Reasons        []DuplicateReason `json:"reasons"`
+	NameSimilarity float64           `json:"nameSimilarity"`
+	TagSimilarity  float32           `json:"tagSimilarity"`
+}
+
+// Addresses of the catalog that are suspected to describe the same place
+type DuplicateCluster struct {
+	ID        string            `json:"id"` // ID of the oldest address, a stable handle for the cluster
+	Reasons   []DuplicateReason `json:"reasons"`
+	Addresses []AddressItem     `json:"addresses"`
+}
+
+// Redirect from the ID of a merged address to the address it was merged into,
+// saved addresses and drafts that still use the old ID keep working through it
+type AddressAlias struct {
+	ID        string `json:"id" firestore:"id"` // ID of the merged address, also the document ID
+	TargetID  string `json:"targetId" firestore:"targetId"`
+	Actor     string `json:"actor" firestore:"actor"`
+	CreatedAt int64  `json:"createdAt" firestore:"createdAt"`
+}
+
+// Leading words that don't tell two places apart
+var nameArticles = []string{"the", "a", "an"}
+
+// Common street type abbreviations, expanded so "Baker St." and "Baker Street" compare equal
+var streetAbbreviations = map[string]string{
+	"st":   "street",
+	"rd":   "road",
+	"ave":  "avenue",
+	"av":   "avenue",
+	"blvd": "boulevard",
+	"ln":   "lane",
+	"dr":   "drive",
+	"sq":   "square",
+	"pl":   "place",
+	"ct":   "court",
+	"hwy":  "highway",
+	"pkwy": "parkway",
+}
+
+// Lower case words of the name without punctuation and leading articles,
+// "The Sherlock Holmes Museum" and "Sherlock Holmes museum." share the same key
+func NormalizeAddressName(name string) string {
+	words := normalizeWords(name)
+	for len(words) > 1 && slices.Contains(nameArticles, words[0]) {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Key of the street address: first address line with expanded abbreviations and the postal code,
+// or the city when there is no postal code. Empty when the address has no first line
+func NormalizePostalAddress(address Address) string {
+	words := normalizeWords(address.Line1)
+	if len(words) == 0 {
+		return ""
+	}
+	for i, word := range words {
+		if expanded, ok := streetAbbreviations[word]; ok {
+			words[i] = expanded
+		}
+	}
+	area := strings.Join(normalizeWords(address.PostalCode), "")
+	if area == "" {
+		area = strings.Join(normalizeWords(address.City), " ")
+	}
+	return strings.Join(words, " ") + "|" + area
+}
+
+// Dice coefficient of the character bigrams of the normalized names, 1 for equal names.
+// It tolerates typos and extra words, and works the same for scripts without spaces
+func NameSimilarity(a string, b string) float64 {
+	bigramsA := nameBigrams(NormalizeAddressName(a))
+	bigramsB := nameBigrams(NormalizeAddressName(b))
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+	counts := map[string]int{}
+	for _, bigram := range bigramsA {
+		counts[bigram]++
+	}
+	shared := 0
+	for _, bigram := range bigramsB {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// Store the normalized keys the duplicate detector queries, call before writing the address
+func (a *AddressItem) UpdateDuplicateKeys() {
+	a.NameKey = NormalizeAddressName(a.Name)
+	a.PostalKey = NormalizePostalAddress(a.Address)
+}
+
+func normalizeWords(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func nameBigrams(key string) []string {
+	runes := []rune(strings.ReplaceAll(key, " ", ""))
+	if len(runes) == 1 {
+		return []string{string(runes)}
+	}
+	bigrams := make([]string, 0, len(runes))
+	for i := 0; i+1 < len(runes); i++ {
+		bigrams = append(bigrams, string(runes[i:i+2]))
+	}
+	return bigrams
+}
@@ -0,0 +1,60 @@
+package models
+
+import "fmt"
+
+type JobType string
+
+const (
+	JobTypeTypesenseSync JobType = "typesense_sync"
+	JobTypeTagsRefresh   JobType = "tags_refresh"
+	JobTypeTagsMigration JobType = "tags_migration"
+	// normalized keys of the duplicate detector on addresses written before they existed
+	JobTypeDuplicateKeysBackfill JobType = "duplicate_keys_backfill"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// Keys of Job.Params
+const (
+	JobParamLanguage = "language"
+	JobParamFullSync = "fullSync"
+	JobParamSeedTags = "seed"
+	JobParamActor    = "actor" // admin the revisions written by the job are attributed to
+)
+
+func (t JobType) Validate() error {
+	switch t {
+	case JobTypeTypesenseSync, JobTypeTagsRefresh, JobTypeTagsMigration, JobTypeDuplicateKeysBackfill:
+		return nil
+	}
+	return fmt.Errorf("unsupported job type: %s", t)
+}
+
+// A finished job never changes its status again
+func (s JobStatus) IsFinished() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed || s == JobStatusCanceled
+}
+
+type Job struct {
+	ID              string            `json:"id" firestore:"id"`
+	Type            JobType           `json:"type" firestore:"type"`
+	Status          JobStatus         `json:"status" firestore:"status"`
+	Params          map[string]string `json:"params" firestore:"params"`
+	Processed       int64             `json:"processed" firestore:"processed"`
+	Failed          int64             `json:"failed" firestore:"failed"`
+	Error           string            `json:"error,omitempty" firestore:"error"`
+	CancelRequested bool              `json:"cancelRequested" firestore:"cancelRequested"`
+	CreatedBy       string            `json:"createdBy" firestore:"createdBy"`
+	CreatedAt       int64             `json:"createdAt" firestore:"createdAt"`
+	StartedAt       int64             `json:"startedAt,omitempty" firestore:"startedAt"`
+	FinishedAt      int64             `json:"finishedAt,omitempty" firestore:"finishedAt"`
+	UpdatedAt       int64             `json:"updatedAt" firestore:"updatedAt"` // refreshed by the runner heartbeat
+}
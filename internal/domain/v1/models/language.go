@@ -1,8 +1,12 @@
 package models
 
 import (
+	"cmp"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 )
 
 type Language string
@@ -12,13 +16,181 @@ const (
 	LanguageEN Language = "en"
 )
 
+// Settings of a language of the catalog, collection names and saved address keys use its code
+type LanguageConfig struct {
+	Code     Language `json:"code" firestore:"code"`
+	Name     string   `json:"name" firestore:"name"`     // English name, used in the LLM prompts
+	Locale   string   `json:"locale" firestore:"locale"` // Typesense locale of the text fields
+	Fallback Language `json:"fallback,omitempty" firestore:"fallback,omitempty"`
+	// disabled languages stay available to admins so their catalog can be prepared, app users can't pick them
+	Enabled bool `json:"enabled" firestore:"enabled"`
+}
+
+// Languages known without any stored configuration, stored configurations override them
+var DefaultLanguageConfigs = []LanguageConfig{
+	{Code: LanguageEN, Name: "English", Locale: "en", Enabled: true},
+	{Code: LanguageZH, Name: "Simplified Chinese", Locale: "zh", Fallback: LanguageEN, Enabled: true},
+}
+
+// Codes end up in collection names, so they are kept to lower case letters, digits and dashes
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Languages the service works with, safe for concurrent use. Replace swaps all of them at once
+type LanguageRegistry struct {
+	mu           sync.RWMutex
+	configs      map[Language]LanguageConfig
+	defaultChain []Language
+}
+
+var languages = mustNewLanguageRegistry(DefaultLanguageConfigs)
+
+// Registry consulted by Validate and the other Language methods
+func Languages() *LanguageRegistry {
+	return languages
+}
+
+func NewLanguageRegistry(configs []LanguageConfig) (*LanguageRegistry, error) {
+	registry := &LanguageRegistry{defaultChain: []Language{LanguageEN}}
+	if err := registry.Replace(configs); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Replace all languages, the registry is left unchanged when the configs are invalid
+func (r *LanguageRegistry) Replace(configs []LanguageConfig) error {
+	byCode, err := validateLanguageConfigs(configs)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs = byCode
+	return nil
+}
+
+func (r *LanguageRegistry) Get(language Language) (LanguageConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	config, ok := r.configs[language.Lower()]
+	return config, ok
+}
+
+// Every language, enabled or not, ordered by code
+func (r *LanguageRegistry) All() []LanguageConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	configs := make([]LanguageConfig, 0, len(r.configs))
+	for _, config := range r.configs {
+		configs = append(configs, config)
+	}
+	slices.SortFunc(configs, func(a, b LanguageConfig) int {
+		return cmp.Compare(a.Code, b.Code)
+	})
+	return configs
+}
+
+// Codes of every language, for the jobs that maintain the data of disabled languages too
+func (r *LanguageRegistry) Codes() []Language {
+	configs := r.All()
+	codes := make([]Language, len(configs))
+	for i, config := range configs {
+		codes[i] = config.Code
+	}
+	return codes
+}
+
+// Codes of the languages offered to app users, ordered by code
+func (r *LanguageRegistry) Enabled() []Language {
+	codes := []Language{}
+	for _, config := range r.All() {
+		if config.Enabled {
+			codes = append(codes, config.Code)
+		}
+	}
+	return codes
+}
+
+// Languages tried when neither a requested language nor its fallbacks are enabled, english unless configured
+func (r *LanguageRegistry) DefaultChain() []Language {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.defaultChain)
+}
+
+func (r *LanguageRegistry) SetDefaultChain(chain []Language) {
+	normalized := make([]Language, 0, len(chain))
+	for _, language := range chain {
+		if language = Language(strings.TrimSpace(string(language))).Lower(); language != "" {
+			normalized = append(normalized, language)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultChain = normalized
+}
+
+// The first enabled language of the candidates, then the first enabled one in the fallbacks of a candidate,
+// then in the default chain. It ends with the first enabled language, so there is always a result
+func (r *LanguageRegistry) Negotiate(candidates []Language) Language {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, chain := range [][]Language{candidates, r.defaultChain} {
+		for _, candidate := range chain {
+			if config, ok := r.configs[candidate.Lower()]; ok && config.Enabled {
+				return config.Code
+			}
+		}
+		for _, candidate := range chain {
+			for _, fallback := range r.fallbackChain(candidate.Lower())[1:] {
+				if r.configs[fallback].Enabled {
+					return fallback
+				}
+			}
+		}
+	}
+	enabled := []Language{}
+	for code, config := range r.configs {
+		if config.Enabled {
+			enabled = append(enabled, code)
+		}
+	}
+	return slices.Min(enabled)
+}
+
 func (l Language) Validate() error {
-	switch l.Lower() {
-	case LanguageZH, LanguageEN:
-		return nil
-	default:
+	if _, ok := languages.Get(l); !ok {
 		return fmt.Errorf("unsupported language: %s", l)
 	}
+	return nil
+}
+
+func (l Language) IsEnabled() bool {
+	config, ok := languages.Get(l)
+	return ok && config.Enabled
+}
+
+// Typesense locale of the language, the code itself when the language is unknown
+func (l Language) Locale() string {
+	if config, ok := languages.Get(l); ok {
+		return config.Locale
+	}
+	return l.Get()
+}
+
+// English name of the language, the code itself when the language is unknown
+func (l Language) Name() string {
+	if config, ok := languages.Get(l); ok && config.Name != "" {
+		return config.Name
+	}
+	return l.Get()
+}
+
+// The language followed by its fallbacks, the fallback of the fallback and so on
+func (l Language) FallbackChain() []Language {
+	languages.mu.RLock()
+	defer languages.mu.RUnlock()
+	return languages.fallbackChain(l.Lower())
 }
 
 func (l Language) Get() string {
@@ -28,3 +200,59 @@ func (l Language) Get() string {
 func (l Language) Lower() Language {
 	return Language(l.Get())
 }
+
+// ---------- Helper functions ----------
+
+// Callers hold the lock, the configs are validated against cycles
+func (r *LanguageRegistry) fallbackChain(language Language) []Language {
+	chain := []Language{language}
+	for {
+		config, ok := r.configs[chain[len(chain)-1]]
+		if !ok || config.Fallback == "" {
+			return chain
+		}
+		chain = append(chain, config.Fallback)
+	}
+}
+
+// Configs keyed by code, codes have to be unique and fallbacks have to point at a known language without cycles
+func validateLanguageConfigs(configs []LanguageConfig) (map[Language]LanguageConfig, error) {
+	byCode := make(map[Language]LanguageConfig, len(configs))
+	for _, config := range configs {
+		config.Code = config.Code.Lower()
+		config.Fallback = config.Fallback.Lower()
+		if !languageCodePattern.MatchString(string(config.Code)) {
+			return nil, fmt.Errorf("invalid language code: %q", config.Code)
+		}
+		if _, ok := byCode[config.Code]; ok {
+			return nil, fmt.Errorf("language %s is configured twice", config.Code)
+		}
+		byCode[config.Code] = config
+	}
+	enabled := false
+	for code, config := range byCode {
+		enabled = enabled || config.Enabled
+		seen := []Language{code}
+		for fallback := config.Fallback; fallback != ""; fallback = byCode[fallback].Fallback {
+			if _, ok := byCode[fallback]; !ok {
+				return nil, fmt.Errorf("fallback %s of language %s is not configured", fallback, seen[len(seen)-1])
+			}
+			if slices.Contains(seen, fallback) {
+				return nil, fmt.Errorf("fallbacks of language %s form a cycle", code)
+			}
+			seen = append(seen, fallback)
+		}
+	}
+	if !enabled {
+		return nil, fmt.Errorf("at least one language has to be enabled")
+	}
+	return byCode, nil
+}
+
+func mustNewLanguageRegistry(configs []LanguageConfig) *LanguageRegistry {
+	registry, err := NewLanguageRegistry(configs)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
@@ -3,6 +3,7 @@ package models
 type Music struct {
 	Filename     string  `json:"filename" firestore:"filename"`
 	Title        string  `json:"title" firestore:"title"`
+	Artist       string  `json:"artist" firestore:"artist"`
 	Genre        string  `json:"genre" firestore:"genre"`
 	Size         float64 `json:"size" firestore:"size"`
 	LastModified int64   `json:"lastModified" firestore:"lastModified"`
@@ -0,0 +1,40 @@
+package models
+
+import "fmt"
+
+type OutboxOperation string
+
+const (
+	OutboxOperationUpsert OutboxOperation = "upsert"
+	OutboxOperationDelete OutboxOperation = "delete"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusFailed  OutboxStatus = "failed" // gave up after the last retry
+)
+
+func (s OutboxStatus) Validate() error {
+	switch s {
+	case OutboxStatusPending, OutboxStatusFailed:
+		return nil
+	}
+	return fmt.Errorf("unsupported outbox status: %s", s)
+}
+
+// Search index change waiting to be applied, written together with the address change.
+// There is at most one entry per address, a newer change replaces the pending one.
+type OutboxEntry struct {
+	ID            string          `json:"id" firestore:"id"`
+	Operation     OutboxOperation `json:"operation" firestore:"operation"`
+	Language      Language        `json:"language" firestore:"language"`
+	AddressID     string          `json:"addressId" firestore:"addressId"`
+	Status        OutboxStatus    `json:"status" firestore:"status"`
+	Attempts      int             `json:"attempts" firestore:"attempts"`
+	LastError     string          `json:"lastError,omitempty" firestore:"lastError"`
+	NextAttemptAt int64           `json:"nextAttemptAt" firestore:"nextAttemptAt"`
+	CreatedAt     int64           `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt     int64           `json:"updatedAt" firestore:"updatedAt"`
+}
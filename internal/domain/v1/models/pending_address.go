@@ -0,0 +1,25 @@
+package models
+
+// Settings of the LLM run that generated a pending address
+type GenerationMetadata struct {
+	RunID           string `json:"runId" firestore:"runId"` // shared by the addresses of one generation request
+	Model           string `json:"model" firestore:"model"`
+	SystemPrompt    string `json:"systemPrompt" firestore:"systemPrompt"`
+	Prompt          string `json:"prompt" firestore:"prompt"`
+	ReasoningEffort string `json:"reasoningEffort,omitempty" firestore:"reasoningEffort"`
+	ThinkingLevel   string `json:"thinkingLevel,omitempty" firestore:"thinkingLevel"`
+	GeneratedBy     string `json:"generatedBy" firestore:"generatedBy"`
+	GeneratedAt     int64  `json:"generatedAt" firestore:"generatedAt"`
+}
+
+// Generated address waiting for an admin review, it leaves the staging collection when it is approved or rejected
+type PendingAddress struct {
+	ID         string             `json:"id" firestore:"id"`
+	Language   Language           `json:"language" firestore:"language"`
+	Address    AddressItem        `json:"address" firestore:"address"`
+	Generation GenerationMetadata `json:"generation" firestore:"generation"`
+	// address this one was translated from, the two are linked when it is approved
+	Source    *AddressRef `json:"source,omitempty" firestore:"source,omitempty"`
+	CreatedAt int64       `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt int64       `json:"updatedAt" firestore:"updatedAt"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "slices"
+
+type RevisionAction string
+
+const (
+	RevisionActionCreate   RevisionAction = "create"
+	RevisionActionUpdate   RevisionAction = "update"
+	RevisionActionDelete   RevisionAction = "delete"
+	RevisionActionRestore  RevisionAction = "restore"
+	RevisionActionRollback RevisionAction = "rollback"
+	RevisionActionMerge    RevisionAction = "merge"    // the address was merged into another one
+	RevisionActionBaseline RevisionAction = "baseline" // content from before revisions were recorded
+)
+
+// One change of an address, stored in the revisions subcollection of the address document
+type AddressRevision struct {
+	Number     int64          `json:"number" firestore:"number"`
+	Action     RevisionAction `json:"action" firestore:"action"`
+	Actor      string         `json:"actor" firestore:"actor"` // admin UID, empty for system changes
+	CreatedAt  int64          `json:"createdAt" firestore:"createdAt"`
+	Changes    []FieldChange  `json:"changes" firestore:"changes"`
+	Snapshot   AddressItem    `json:"snapshot" firestore:"snapshot"`                         // the address after the change
+	RollbackOf int64          `json:"rollbackOf,omitempty" firestore:"rollbackOf,omitempty"` // revision restored by a rollback
+}
+
+type FieldChange struct {
+	Field  string      `json:"field" firestore:"field"`
+	Before interface{} `json:"before" firestore:"before"`
+	After  interface{} `json:"after" firestore:"after"`
+}
+
+// Field-level changes from one version of an address to another, bookkeeping fields are ignored
+func DiffAddressItems(before AddressItem, after AddressItem) []FieldChange {
+	changes := []FieldChange{}
+	addChange := func(field string, from interface{}, to interface{}) {
+		changes = append(changes, FieldChange{Field: field, Before: from, After: to})
+	}
+	diffString := func(field string, from string, to string) {
+		if from != to {
+			addChange(field, from, to)
+		}
+	}
+	diffString("name", before.Name, after.Name)
+	diffString("briefIntro", before.BriefIntro, after.BriefIntro)
+	if !slices.Equal(before.Tags, after.Tags) {
+		addChange("tags", before.Tags, after.Tags)
+	}
+	diffString("address.city", before.Address.City, after.Address.City)
+	diffString("address.country", before.Address.Country, after.Address.Country)
+	diffString("address.line1", before.Address.Line1, after.Address.Line1)
+	diffString("address.line2", before.Address.Line2, after.Address.Line2)
+	diffString("address.buildingName", before.Address.BuildingName, after.Address.BuildingName)
+	diffString("address.postalCode", before.Address.PostalCode, after.Address.PostalCode)
+	diffString("address.region", before.Address.Region, after.Address.Region)
+	if before.DeletedAt != after.DeletedAt {
+		addChange("deletedAt", before.DeletedAt, after.DeletedAt)
+	}
+	diffString("mergedInto", before.MergedInto, after.MergedInto)
+	return changes
+}
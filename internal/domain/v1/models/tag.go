@@ -0,0 +1,274 @@
+package models
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type TagCategory string
+
+const (
+	TagCategoryCountry TagCategory = "country"
+	TagCategoryRole    TagCategory = "role"
+	TagCategoryFigure  TagCategory = "figure"
+)
+
+// Every tag category, also the order of the categories in TagsRecord.
+// A new category is appended here only, LegacyTagCategories keeps the historical positions
+var TagCategories = []TagCategory{TagCategoryCountry, TagCategoryRole, TagCategoryFigure}
+
+// Before the taxonomy the category of a tag was its position in AddressItem.Tags, the tag migration
+// seeds from it and the tags record falls back to it while the taxonomy is empty.
+// It has the same values as TagCategories on purpose but is frozen: stored addresses were written
+// in this order, so it must not follow later changes to TagCategories
+var LegacyTagCategories = []TagCategory{TagCategoryCountry, TagCategoryRole, TagCategoryFigure}
+
+// Slugs are lower case words joined by dashes, they are also document IDs
+var tagSlugPattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}]+(-[\p{Ll}\p{Lo}\p{N}]+)*$`)
+
+func (c TagCategory) Validate() error {
+	if slices.Contains(TagCategories, c) {
+		return nil
+	}
+	return fmt.Errorf("unsupported tag category: %s", c)
+}
+
+// Entry of the tag taxonomy, shared by the catalogs of all languages.
+// Addresses store the display name of their language, the taxonomy tells its category
+type Tag struct {
+	Slug     string            `json:"slug" firestore:"slug"` // canonical handle, also the document ID
+	Category TagCategory       `json:"category" firestore:"category"`
+	Names    map[string]string `json:"names" firestore:"names"` // display name by language code
+	// other spellings by language code, they resolve to the tag but are never stored on an address
+	Synonyms  map[string][]string `json:"synonyms,omitempty" firestore:"synonyms,omitempty"`
+	CreatedAt int64               `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt int64               `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// Display name of the tag in the language, empty when the tag has none
+func (t Tag) Name(language Language) string {
+	return t.Names[language.Lower().Get()]
+}
+
+// Trim the names and synonyms and drop the empty ones, synonyms equal to a name of the same language are dropped too
+func (t *Tag) Normalize() {
+	t.Slug = strings.ToLower(strings.TrimSpace(t.Slug))
+	t.Category = TagCategory(strings.ToLower(strings.TrimSpace(string(t.Category))))
+	names := map[string]string{}
+	for language, name := range t.Names {
+		if name = strings.Join(strings.Fields(name), " "); name != "" {
+			names[strings.ToLower(strings.TrimSpace(language))] = name
+		}
+	}
+	t.Names = names
+	synonyms := map[string][]string{}
+	for language, values := range t.Synonyms {
+		language = strings.ToLower(strings.TrimSpace(language))
+		for _, value := range values {
+			value = strings.Join(strings.Fields(value), " ")
+			if value == "" || NormalizeTagTerm(value) == NormalizeTagTerm(names[language]) {
+				continue
+			}
+			if !slices.ContainsFunc(synonyms[language], func(s string) bool {
+				return NormalizeTagTerm(s) == NormalizeTagTerm(value)
+			}) {
+				synonyms[language] = append(synonyms[language], value)
+			}
+		}
+	}
+	t.Synonyms = synonyms
+}
+
+// Check the slug, the category and the languages, at least one display name is required
+func (t Tag) Validate() error {
+	if !tagSlugPattern.MatchString(t.Slug) {
+		return fmt.Errorf("invalid tag slug %q, use lower case words joined by dashes", t.Slug)
+	}
+	if err := t.Category.Validate(); err != nil {
+		return err
+	}
+	if len(t.Names) == 0 {
+		return fmt.Errorf("tag %s has no name", t.Slug)
+	}
+	for language := range t.Names {
+		if err := Language(language).Validate(); err != nil {
+			return fmt.Errorf("name of tag %s: %w", t.Slug, err)
+		}
+	}
+	for language := range t.Synonyms {
+		if err := Language(language).Validate(); err != nil {
+			return fmt.Errorf("synonyms of tag %s: %w", t.Slug, err)
+		}
+	}
+	return nil
+}
+
+// Names and synonyms compare case-insensitively and ignore repeated spaces
+func NormalizeTagTerm(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+// Slug derived from a display name, e.g. "Nobel Laureate" becomes "nobel-laureate"
+func NewTagSlug(name string) string {
+	return strings.Join(normalizeWords(name), "-")
+}
+
+// Lookup of the taxonomy by slug, name and synonym, build it with NewTagTaxonomy
+type TagTaxonomy struct {
+	tags  map[string]Tag
+	terms map[string]map[string]string // language, normalized name or synonym, slug
+}
+
+func NewTagTaxonomy(tags []Tag) *TagTaxonomy {
+	taxonomy := &TagTaxonomy{
+		tags:  map[string]Tag{},
+		terms: map[string]map[string]string{},
+	}
+	for _, tag := range tags {
+		taxonomy.Add(tag)
+	}
+	return taxonomy
+}
+
+// Add a tag or replace the one with the same slug, terms already taken by another tag keep resolving to it
+func (t *TagTaxonomy) Add(tag Tag) {
+	if previous, ok := t.tags[tag.Slug]; ok {
+		for language, terms := range t.terms {
+			for _, term := range tagTerms(previous, language) {
+				if terms[term] == tag.Slug {
+					delete(terms, term)
+				}
+			}
+		}
+	}
+	t.tags[tag.Slug] = tag
+	for _, language := range tagLanguages(tag) {
+		if t.terms[language] == nil {
+			t.terms[language] = map[string]string{}
+		}
+		for _, term := range tagTerms(tag, language) {
+			if _, taken := t.terms[language][term]; !taken {
+				t.terms[language][term] = tag.Slug
+			}
+		}
+	}
+}
+
+func (t *TagTaxonomy) IsEmpty() bool {
+	return len(t.tags) == 0
+}
+
+func (t *TagTaxonomy) Get(slug string) (Tag, bool) {
+	tag, ok := t.tags[slug]
+	return tag, ok
+}
+
+// Every tag ordered by category, then by slug
+func (t *TagTaxonomy) All() []Tag {
+	tags := make([]Tag, 0, len(t.tags))
+	for _, tag := range t.tags {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, compareTags)
+	return tags
+}
+
+// The tag a value names in the language, by its display name, a synonym or its slug.
+// A tag without a display name in the language doesn't resolve
+func (t *TagTaxonomy) Resolve(language Language, value string) (Tag, bool) {
+	term := NormalizeTagTerm(value)
+	slug, ok := t.terms[language.Lower().Get()][term]
+	if !ok {
+		slug = term
+	}
+	tag, ok := t.tags[slug]
+	if !ok || tag.Name(language) == "" {
+		return Tag{}, false
+	}
+	return tag, true
+}
+
+// Replace each tag by the display name of the tag it resolves to, duplicates are dropped.
+// Values that don't resolve are returned separately in their original order
+func (t *TagTaxonomy) Canonicalize(language Language, values []string) (canonical []string, unknown []string) {
+	canonical, unknown = []string{}, []string{}
+	for _, value := range values {
+		tag, ok := t.Resolve(language, value)
+		if !ok {
+			if strings.TrimSpace(value) != "" && !slices.Contains(unknown, value) {
+				unknown = append(unknown, value)
+			}
+			continue
+		}
+		if name := tag.Name(language); !slices.Contains(canonical, name) {
+			canonical = append(canonical, name)
+		}
+	}
+	return canonical, unknown
+}
+
+// Display names of the values that resolve to a tag, grouped by the category of the tag
+func (t *TagTaxonomy) Categorize(language Language, values []string) map[TagCategory][]string {
+	categorized := map[TagCategory][]string{}
+	for _, value := range values {
+		tag, ok := t.Resolve(language, value)
+		if !ok {
+			continue
+		}
+		if name := tag.Name(language); !slices.Contains(categorized[tag.Category], name) {
+			categorized[tag.Category] = append(categorized[tag.Category], name)
+		}
+	}
+	return categorized
+}
+
+// Names and synonyms of the tag that already resolve to another tag of the taxonomy
+func (t *TagTaxonomy) Conflicts(tag Tag) []string {
+	conflicts := []string{}
+	for _, language := range tagLanguages(tag) {
+		for _, term := range tagTerms(tag, language) {
+			if slug, ok := t.terms[language][term]; ok && slug != tag.Slug {
+				conflicts = append(conflicts, fmt.Sprintf("%s %q is used by tag %s", language, term, slug))
+			}
+		}
+	}
+	slices.Sort(conflicts)
+	return conflicts
+}
+
+// ---------- Helper functions ----------
+
+func compareTags(a, b Tag) int {
+	return cmp.Or(
+		cmp.Compare(slices.Index(TagCategories, a.Category), slices.Index(TagCategories, b.Category)),
+		cmp.Compare(a.Slug, b.Slug),
+	)
+}
+
+func tagLanguages(tag Tag) []string {
+	languages := []string{}
+	for language := range tag.Names {
+		languages = append(languages, language)
+	}
+	for language := range tag.Synonyms {
+		if !slices.Contains(languages, language) {
+			languages = append(languages, language)
+		}
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+func tagTerms(tag Tag, language string) []string {
+	terms := []string{}
+	if name := tag.Names[language]; name != "" {
+		terms = append(terms, NormalizeTagTerm(name))
+	}
+	for _, synonym := range tag.Synonyms[language] {
+		terms = append(terms, NormalizeTagTerm(synonym))
+	}
+	return terms
+}
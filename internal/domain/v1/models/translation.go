@@ -0,0 +1,21 @@
+package models
+
+// Entries of the same place in the different language collections, one address per language
+type TranslationGroup struct {
+	ID        string            `json:"id" firestore:"id"`
+	Members   map[string]string `json:"members" firestore:"members"` // language code to address ID
+	CreatedAt int64             `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt int64             `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// An address of one of the language collections
+type AddressRef struct {
+	Language Language `json:"language" firestore:"language"`
+	ID       string   `json:"id" firestore:"id"`
+}
+
+// Address with the languages that have no linked entry for it
+type MissingTranslation struct {
+	Address          AddressItem `json:"address"`
+	MissingLanguages []Language  `json:"missingLanguages"`
+}
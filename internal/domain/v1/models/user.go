@@ -1,11 +1,13 @@
 package models
 
 type AdminUser struct {
-	Email       string `json:"email" firestore:"email"`
-	DisplayName string `json:"displayName" firestore:"displayName"`
-	CreatedAt   int64  `json:"createdAt" firestore:"createdAt"`
-	LastLogin   int64  `json:"lastLogin" firestore:"lastLogin"`
-	ImageUrl    string `json:"imageUrl,omitempty" firestore:"imageUrl"`
+	Uid         string    `json:"uid" firestore:"-"` // document ID
+	Email       string    `json:"email" firestore:"email"`
+	DisplayName string    `json:"displayName" firestore:"displayName"`
+	CreatedAt   int64     `json:"createdAt" firestore:"createdAt"`
+	LastLogin   int64     `json:"lastLogin" firestore:"lastLogin"`
+	ImageUrl    string    `json:"imageUrl,omitempty" firestore:"imageUrl"`
+	Role        AdminRole `json:"role" firestore:"role"`
 }
 
 type AppUser struct {
@@ -15,8 +17,9 @@ type AppUser struct {
 	LastLogin   int64        `json:"lastLogin" firestore:"lastLogin"`
 	ImageUrl    string       `json:"imageUrl,omitempty" firestore:"imageUrl"`
 	LikedMusics []string     `json:"likedMusics" firestore:"likedMusics"`
-	Drafts      []string     `json:"drafts" firestore:"drafts"`
+	Drafts      []string     `json:"drafts" firestore:"drafts"` // IDs of documents in the drafts collection
 	AddressBook *AddressBook `json:"addressBook,omitempty" firestore:"addressBook,omitempty"`
+	Language    Language     `json:"language,omitempty" firestore:"language,omitempty"` // preferred language, used when a request doesn't pick one
 }
 
 type AddressBook struct {
@@ -0,0 +1,51 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+)
+
+// Longest uid Firebase Auth accepts
+const maxLocalUidLength = 128
+
+// Stands in for Firebase Auth in local mode. The bearer token is the uid of the caller, so requests are sent
+// with "Authorization: Bearer <uid>", and every uid exists with a profile made up from it
+type LocalAuthClient struct{}
+
+func NewLocalAuthClient(logger *slog.Logger) *LocalAuthClient {
+	logger.Warn("Local auth client initialized, bearer tokens are taken as uids without verification")
+	return &LocalAuthClient{}
+}
+
+func (a *LocalAuthClient) VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	uid := strings.TrimSpace(idToken)
+	if uid == "" || len(uid) > maxLocalUidLength || strings.ContainsAny(uid, "/ ") {
+		return nil, fmt.Errorf("invalid local id token, expected a uid")
+	}
+	now := time.Now()
+	return &auth.Token{
+		Issuer:   "local",
+		Subject:  uid,
+		UID:      uid,
+		IssuedAt: now.Unix(),
+		Expires:  now.Add(time.Hour).Unix(),
+		Firebase: auth.FirebaseInfo{SignInProvider: "custom"},
+	}, nil
+}
+
+func (a *LocalAuthClient) GetUser(ctx context.Context, uid string) (*auth.UserRecord, error) {
+	return &auth.UserRecord{
+		UserInfo: &auth.UserInfo{
+			UID:         uid,
+			DisplayName: uid,
+			Email:       uid + "@localhost",
+			ProviderID:  "local",
+		},
+		UserMetadata: &auth.UserMetadata{},
+	}, nil
+}
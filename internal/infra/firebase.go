@@ -2,6 +2,7 @@ package infra
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -12,13 +13,30 @@ import (
 	"google.golang.org/api/option"
 )
 
+// Firebase Auth operations the service uses, Firebase Auth in the cloud and a local verifier in local mode
+type AuthClient interface {
+	VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error)
+	GetUser(ctx context.Context, uid string) (*auth.UserRecord, error)
+}
+
+var (
+	_ AuthClient = (*auth.Client)(nil)
+	_ AuthClient = (*LocalAuthClient)(nil)
+)
+
 type FirebaseClient struct {
 	Firestore *firestore.Client
-	Auth      *auth.Client
+	Auth      AuthClient
+	local     *memoryFirestore // serves Firestore in local mode
 }
 
+const defaultLocalProjectID = "north-post-local"
+
 func NewFirebaseClient(logger *slog.Logger) (*FirebaseClient, error) {
 	ctx := context.Background()
+	if GetStorageMode() == StorageModeLocal {
+		return newLocalFirebaseClient(ctx, logger)
+	}
 	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
 	projectID := os.Getenv("GOOGLE_PROJECT_ID")
 	if projectID == "" {
@@ -63,9 +81,36 @@ func NewFirebaseClient(logger *slog.Logger) (*FirebaseClient, error) {
 	}, nil
 }
 
+// Serve Firestore from memory, saved to LOCAL_FIRESTORE_FILE, and accept uids as id tokens.
+// Nothing leaves the process, so no credentials, emulators or network are needed
+func newLocalFirebaseClient(ctx context.Context, logger *slog.Logger) (*FirebaseClient, error) {
+	file := os.Getenv("LOCAL_FIRESTORE_FILE")
+	if file == "" {
+		file = defaultLocalFirestoreFile
+	}
+	local, err := newMemoryFirestore(file, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error loading local firestore: %w", err)
+	}
+	firestoreClient, err := local.connect(ctx, defaultLocalProjectID)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("in-memory firestore initialized successfully", "file", file, "documents", len(local.documents))
+	return &FirebaseClient{
+		Firestore: firestoreClient,
+		Auth:      NewLocalAuthClient(logger),
+		local:     local,
+	}, nil
+}
+
 func (f *FirebaseClient) Close() error {
+	var err error
 	if f.Firestore != nil {
-		return f.Firestore.Close()
+		err = f.Firestore.Close()
+	}
+	if f.local != nil {
+		err = errors.Join(err, f.local.Close())
 	}
-	return nil
+	return err
 }
@@ -0,0 +1,538 @@
+package infra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	pb "cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultLocalFirestoreFile = "local/firestore.json"
+
+// Writes within this delay are saved to the local file together
+const memoryFirestoreSaveDelay = time.Second
+
+// In-process replacement of Firestore for the local storage mode. It serves the part of the Firestore API the
+// repositories use over an in-memory gRPC connection, so they run unchanged against it. The documents are
+// saved to a file to survive restarts. Transactions are optimistic, a commit is aborted when a document the
+// transaction read was written since, and the client retries it.
+type memoryFirestore struct {
+	pb.UnimplementedFirestoreServer
+	mu           sync.Mutex
+	documents    map[string]*pb.Document                      // by full document name
+	transactions map[string]map[string]*timestamppb.Timestamp // update times read by each transaction, nil when missing
+	lastWrite    time.Time
+	file         string
+	saveTimer    *time.Timer
+	saveMu       sync.Mutex // one save at a time
+	server       *grpc.Server
+	logger       *slog.Logger
+}
+
+// The file is read when it exists, an empty file name keeps the documents in memory only
+func newMemoryFirestore(file string, logger *slog.Logger) (*memoryFirestore, error) {
+	m := &memoryFirestore{
+		documents:    map[string]*pb.Document{},
+		transactions: map[string]map[string]*timestamppb.Timestamp{},
+		file:         file,
+		logger:       logger,
+	}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Start serving and return a Firestore client connected to the in-memory backend
+func (m *memoryFirestore) connect(ctx context.Context, projectID string) (*firestore.Client, error) {
+	listener := bufconn.Listen(1 << 20)
+	m.server = grpc.NewServer()
+	pb.RegisterFirestoreServer(m.server, m)
+	go func() {
+		if err := m.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			m.logger.Error("in-memory firestore stopped", "error", err)
+		}
+	}()
+	conn, err := grpc.NewClient("passthrough:///memory-firestore",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		m.server.Stop()
+		return nil, fmt.Errorf("failed to connect to the in-memory firestore: %w", err)
+	}
+	client, err := firestore.NewClient(ctx, projectID, option.WithGRPCConn(conn))
+	if err != nil {
+		m.server.Stop()
+		return nil, fmt.Errorf("failed to create the in-memory firestore client: %w", err)
+	}
+	return client, nil
+}
+
+// Stop serving and save the pending writes
+func (m *memoryFirestore) Close() error {
+	if m.server != nil {
+		m.server.Stop()
+	}
+	m.mu.Lock()
+	if m.saveTimer != nil {
+		m.saveTimer.Stop()
+		m.saveTimer = nil
+	}
+	m.mu.Unlock()
+	return m.save()
+}
+
+func (m *memoryFirestore) BeginTransaction(
+	ctx context.Context, req *pb.BeginTransactionRequest) (*pb.BeginTransactionResponse, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create transaction ID: %v", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transactions[string(id)] = map[string]*timestamppb.Timestamp{}
+	return &pb.BeginTransactionResponse{Transaction: id}, nil
+}
+
+func (m *memoryFirestore) Rollback(ctx context.Context, req *pb.RollbackRequest) (*emptypb.Empty, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.transactions, string(req.Transaction))
+	return &emptypb.Empty{}, nil
+}
+
+func (m *memoryFirestore) BatchGetDocuments(
+	req *pb.BatchGetDocumentsRequest, stream pb.Firestore_BatchGetDocumentsServer) error {
+	m.mu.Lock()
+	reads, err := m.getTransactionReads(req.GetTransaction())
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	readTime := timestamppb.Now()
+	responses := make([]*pb.BatchGetDocumentsResponse, 0, len(req.Documents))
+	for _, name := range req.Documents {
+		doc := m.documents[name]
+		recordRead(reads, name, doc)
+		response := &pb.BatchGetDocumentsResponse{ReadTime: readTime}
+		if doc == nil {
+			response.Result = &pb.BatchGetDocumentsResponse_Missing{Missing: name}
+		} else {
+			response.Result = &pb.BatchGetDocumentsResponse_Found{Found: applyMask(doc, req.Mask)}
+		}
+		responses = append(responses, response)
+	}
+	m.mu.Unlock()
+	for _, response := range responses {
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryFirestore) RunQuery(req *pb.RunQueryRequest, stream pb.Firestore_RunQueryServer) error {
+	query := req.GetStructuredQuery()
+	if query == nil {
+		return status.Error(codes.InvalidArgument, "only structured queries are supported")
+	}
+	m.mu.Lock()
+	reads, err := m.getTransactionReads(req.GetTransaction())
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	docs, err := runStructuredQuery(m.documents, req.Parent, query)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	for _, doc := range docs {
+		recordRead(reads, doc.Name, m.documents[doc.Name])
+	}
+	m.mu.Unlock()
+	readTime := timestamppb.Now()
+	if len(docs) == 0 {
+		return stream.Send(&pb.RunQueryResponse{ReadTime: readTime})
+	}
+	for _, doc := range docs {
+		if err := stream.Send(&pb.RunQueryResponse{Document: doc, ReadTime: readTime}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Documents of the collection, with ShowMissing also the missing ones that have subcollections
+func (m *memoryFirestore) ListDocuments(ctx context.Context, req *pb.ListDocumentsRequest) (*pb.ListDocumentsResponse, error) {
+	prefix := req.Parent + "/" + req.CollectionId + "/"
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := map[string]bool{}
+	for name := range m.documents {
+		id, rest, _ := strings.Cut(strings.TrimPrefix(name, prefix), "/")
+		if !strings.HasPrefix(name, prefix) || (rest != "" && !req.ShowMissing) {
+			continue
+		}
+		names[prefix+id] = true
+	}
+	response := &pb.ListDocumentsResponse{}
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		doc := m.documents[name]
+		if doc == nil {
+			doc = &pb.Document{Name: name}
+		}
+		response.Documents = append(response.Documents, applyMask(doc, req.Mask))
+	}
+	return response, nil
+}
+
+// Apply the writes at once, after checking that nothing the transaction read was written since
+func (m *memoryFirestore) Commit(ctx context.Context, req *pb.CommitRequest) (*pb.CommitResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if req.Transaction != nil {
+		reads, err := m.getTransactionReads(req.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		delete(m.transactions, string(req.Transaction))
+		for name, updateTime := range reads {
+			if !sameUpdateTime(m.documents[name], updateTime) {
+				return nil, status.Errorf(codes.Aborted, "transaction aborted, %s was written since it was read", name)
+			}
+		}
+	}
+	commitTime := m.nextWriteTime()
+	staged := map[string]*pb.Document{}
+	results := make([]*pb.WriteResult, 0, len(req.Writes))
+	for _, write := range req.Writes {
+		result, err := m.applyWrite(staged, write, commitTime)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	m.storeStaged(staged)
+	return &pb.CommitResponse{WriteResults: results, CommitTime: commitTime}, nil
+}
+
+// Apply each write on its own, a failed write doesn't stop the others
+func (m *memoryFirestore) BatchWrite(ctx context.Context, req *pb.BatchWriteRequest) (*pb.BatchWriteResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	commitTime := m.nextWriteTime()
+	response := &pb.BatchWriteResponse{}
+	for _, write := range req.Writes {
+		staged := map[string]*pb.Document{}
+		result, err := m.applyWrite(staged, write, commitTime)
+		if err != nil {
+			response.WriteResults = append(response.WriteResults, &pb.WriteResult{})
+			response.Status = append(response.Status, status.Convert(err).Proto())
+			continue
+		}
+		m.storeStaged(staged)
+		response.WriteResults = append(response.WriteResults, result)
+		response.Status = append(response.Status, status.New(codes.OK, "").Proto())
+	}
+	return response, nil
+}
+
+// =========== Helper methods ==========
+
+func (m *memoryFirestore) getTransactionReads(id []byte) (map[string]*timestamppb.Timestamp, error) {
+	if id == nil {
+		return nil, nil
+	}
+	reads, ok := m.transactions[string(id)]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "transaction has expired or is invalid")
+	}
+	return reads, nil
+}
+
+// Remember the version of the first read of each document, reads outside a transaction are not tracked
+func recordRead(reads map[string]*timestamppb.Timestamp, name string, doc *pb.Document) {
+	if reads == nil {
+		return
+	}
+	if _, ok := reads[name]; ok {
+		return
+	}
+	if doc == nil {
+		reads[name] = nil
+		return
+	}
+	reads[name] = doc.UpdateTime
+}
+
+func sameUpdateTime(doc *pb.Document, updateTime *timestamppb.Timestamp) bool {
+	if doc == nil || updateTime == nil {
+		return doc == nil && updateTime == nil
+	}
+	return proto.Equal(doc.UpdateTime, updateTime)
+}
+
+// Update times are unique and increasing, so a precondition on the update time detects every later write
+func (m *memoryFirestore) nextWriteTime() *timestamppb.Timestamp {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if !now.After(m.lastWrite) {
+		now = m.lastWrite.Add(time.Microsecond)
+	}
+	m.lastWrite = now
+	return timestamppb.New(now)
+}
+
+// The document as the earlier writes of the same commit left it
+func (m *memoryFirestore) lookup(staged map[string]*pb.Document, name string) *pb.Document {
+	if doc, ok := staged[name]; ok {
+		return doc
+	}
+	return m.documents[name]
+}
+
+// A nil document in staged stands for a deleted one
+func (m *memoryFirestore) storeStaged(staged map[string]*pb.Document) {
+	for name, doc := range staged {
+		if doc == nil {
+			delete(m.documents, name)
+		} else {
+			m.documents[name] = doc
+		}
+	}
+	if len(staged) > 0 {
+		m.scheduleSave()
+	}
+}
+
+func (m *memoryFirestore) applyWrite(
+	staged map[string]*pb.Document,
+	write *pb.Write,
+	commitTime *timestamppb.Timestamp) (*pb.WriteResult, error) {
+	var name string
+	switch operation := write.Operation.(type) {
+	case *pb.Write_Update:
+		name = operation.Update.Name
+	case *pb.Write_Delete:
+		name = operation.Delete
+	case *pb.Write_Transform:
+		name = operation.Transform.Document
+	default:
+		return nil, status.Error(codes.InvalidArgument, "unsupported write operation")
+	}
+	current := m.lookup(staged, name)
+	if err := checkPrecondition(name, current, write.CurrentDocument); err != nil {
+		return nil, err
+	}
+	fields := map[string]*pb.Value{}
+	if current != nil {
+		fields = cloneFields(current.Fields)
+	}
+	transforms := write.UpdateTransforms
+	switch operation := write.Operation.(type) {
+	case *pb.Write_Delete:
+		staged[name] = nil
+		return &pb.WriteResult{}, nil
+	case *pb.Write_Transform:
+		transforms = operation.Transform.FieldTransforms
+	case *pb.Write_Update:
+		// without a mask the document is replaced, with one only the listed fields change
+		if write.UpdateMask == nil {
+			fields = cloneFields(operation.Update.Fields)
+		}
+		for _, path := range write.GetUpdateMask().GetFieldPaths() {
+			segments, err := parseFieldPath(path)
+			if err != nil {
+				return nil, err
+			}
+			if value, ok := getField(operation.Update.Fields, segments); ok {
+				setField(fields, segments, proto.Clone(value).(*pb.Value))
+			} else {
+				deleteField(fields, segments)
+			}
+		}
+	}
+	transformResults := make([]*pb.Value, 0, len(transforms))
+	for _, transform := range transforms {
+		result, err := applyTransform(fields, transform, commitTime)
+		if err != nil {
+			return nil, err
+		}
+		transformResults = append(transformResults, result)
+	}
+	createTime := commitTime
+	if current != nil {
+		createTime = current.CreateTime
+	}
+	staged[name] = &pb.Document{Name: name, Fields: fields, CreateTime: createTime, UpdateTime: commitTime}
+	return &pb.WriteResult{UpdateTime: commitTime, TransformResults: transformResults}, nil
+}
+
+func checkPrecondition(name string, current *pb.Document, precondition *pb.Precondition) error {
+	switch condition := precondition.GetConditionType().(type) {
+	case *pb.Precondition_Exists:
+		if condition.Exists && current == nil {
+			return status.Errorf(codes.NotFound, "no entity to update: %s", name)
+		}
+		if !condition.Exists && current != nil {
+			return status.Errorf(codes.AlreadyExists, "document already exists: %s", name)
+		}
+	case *pb.Precondition_UpdateTime:
+		if current == nil {
+			return status.Errorf(codes.NotFound, "no entity to update: %s", name)
+		}
+		if !proto.Equal(current.UpdateTime, condition.UpdateTime) {
+			return status.Errorf(codes.FailedPrecondition, "%s was updated since the precondition was taken", name)
+		}
+	}
+	return nil
+}
+
+func applyTransform(
+	fields map[string]*pb.Value,
+	transform *pb.DocumentTransform_FieldTransform,
+	commitTime *timestamppb.Timestamp) (*pb.Value, error) {
+	segments, err := parseFieldPath(transform.FieldPath)
+	if err != nil {
+		return nil, err
+	}
+	current, _ := getField(fields, segments)
+	var result *pb.Value
+	switch t := transform.TransformType.(type) {
+	case *pb.DocumentTransform_FieldTransform_SetToServerValue:
+		result = &pb.Value{ValueType: &pb.Value_TimestampValue{TimestampValue: commitTime}}
+	case *pb.DocumentTransform_FieldTransform_Increment:
+		result = addNumbers(current, t.Increment)
+	case *pb.DocumentTransform_FieldTransform_Maximum:
+		result = t.Maximum
+		if isNumber(current) && compareValues(current, t.Maximum) >= 0 {
+			result = current
+		}
+	case *pb.DocumentTransform_FieldTransform_Minimum:
+		result = t.Minimum
+		if isNumber(current) && compareValues(current, t.Minimum) <= 0 {
+			result = current
+		}
+	case *pb.DocumentTransform_FieldTransform_AppendMissingElements:
+		values := slices.Clone(current.GetArrayValue().GetValues())
+		for _, value := range t.AppendMissingElements.GetValues() {
+			if !containsValue(values, value) {
+				values = append(values, value)
+			}
+		}
+		result = &pb.Value{ValueType: &pb.Value_ArrayValue{ArrayValue: &pb.ArrayValue{Values: values}}}
+	case *pb.DocumentTransform_FieldTransform_RemoveAllFromArray:
+		values := slices.DeleteFunc(slices.Clone(current.GetArrayValue().GetValues()), func(value *pb.Value) bool {
+			return containsValue(t.RemoveAllFromArray.GetValues(), value)
+		})
+		result = &pb.Value{ValueType: &pb.Value_ArrayValue{ArrayValue: &pb.ArrayValue{Values: values}}}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported transform of %s", transform.FieldPath)
+	}
+	result = proto.Clone(result).(*pb.Value)
+	setField(fields, segments, result)
+	return result, nil
+}
+
+func (m *memoryFirestore) scheduleSave() {
+	if m.file == "" || m.saveTimer != nil {
+		return
+	}
+	m.saveTimer = time.AfterFunc(memoryFirestoreSaveDelay, func() {
+		m.mu.Lock()
+		m.saveTimer = nil
+		m.mu.Unlock()
+		if err := m.save(); err != nil {
+			m.logger.Error("failed to save the in-memory firestore", "file", m.file, "error", err)
+		}
+	})
+}
+
+type memoryFirestoreFile struct {
+	Documents []json.RawMessage `json:"documents"`
+}
+
+func (m *memoryFirestore) load() error {
+	if m.file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read local firestore file: %w", err)
+	}
+	var content memoryFirestoreFile
+	if err := json.Unmarshal(data, &content); err != nil {
+		return fmt.Errorf("failed to parse local firestore file: %w", err)
+	}
+	for _, raw := range content.Documents {
+		doc := &pb.Document{}
+		if err := protojson.Unmarshal(raw, doc); err != nil {
+			return fmt.Errorf("failed to parse document in local firestore file: %w", err)
+		}
+		m.documents[doc.Name] = doc
+		if updateTime := doc.UpdateTime.AsTime(); updateTime.After(m.lastWrite) {
+			m.lastWrite = updateTime
+		}
+	}
+	return nil
+}
+
+// Written to a temporary file first, so a crash never leaves a partial file behind
+func (m *memoryFirestore) save() error {
+	if m.file == "" {
+		return nil
+	}
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+	m.mu.Lock()
+	content := memoryFirestoreFile{Documents: make([]json.RawMessage, 0, len(m.documents))}
+	for _, name := range slices.Sorted(maps.Keys(m.documents)) {
+		raw, err := protojson.Marshal(m.documents[name])
+		if err != nil {
+			m.mu.Unlock()
+			return fmt.Errorf("failed to encode document %s: %w", name, err)
+		}
+		content.Documents = append(content.Documents, raw)
+	}
+	m.mu.Unlock()
+	data, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to encode local firestore file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.file), 0o755); err != nil {
+		return fmt.Errorf("failed to create local firestore directory: %w", err)
+	}
+	temporary := m.file + ".tmp"
+	if err := os.WriteFile(temporary, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write local firestore file: %w", err)
+	}
+	return os.Rename(temporary, m.file)
+}
@@ -0,0 +1,509 @@
+package infra
+
+import (
+	"bytes"
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	pb "cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const documentNameField = "__name__"
+
+// Run the query the way Firestore does: filter, order with the implicit inequality and name orders,
+// apply the cursors, the offset, the limit and the projection
+func runStructuredQuery(
+	documents map[string]*pb.Document,
+	parent string,
+	query *pb.StructuredQuery) ([]*pb.Document, error) {
+	if len(query.From) != 1 {
+		return nil, status.Error(codes.InvalidArgument, "a query needs exactly one collection")
+	}
+	orders, err := queryOrders(query)
+	if err != nil {
+		return nil, err
+	}
+	matches := []*pb.Document{}
+	for name, doc := range documents {
+		if !inCollection(name, parent, query.From[0]) {
+			continue
+		}
+		ok, err := matchesFilter(doc, query.Where)
+		if err != nil {
+			return nil, err
+		}
+		// documents without an ordered field are left out of the results
+		if ok && hasOrderedFields(doc, orders) {
+			matches = append(matches, doc)
+		}
+	}
+	slices.SortFunc(matches, func(a, b *pb.Document) int {
+		for _, order := range orders {
+			if c := compareOrdered(a, b, order); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	matches = slices.DeleteFunc(matches, func(doc *pb.Document) bool {
+		return !afterStart(doc, orders, query.StartAt) || !beforeEnd(doc, orders, query.EndAt)
+	})
+	offset := min(int(query.Offset), len(matches))
+	matches = matches[offset:]
+	if query.Limit != nil && int(query.Limit.Value) < len(matches) {
+		matches = matches[:query.Limit.Value]
+	}
+	results := make([]*pb.Document, 0, len(matches))
+	for _, doc := range matches {
+		results = append(results, projectDocument(doc, query.Select))
+	}
+	return results, nil
+}
+
+// The explicit orders, followed by the inequality fields that aren't ordered and by the document name
+func queryOrders(query *pb.StructuredQuery) ([]*pb.StructuredQuery_Order, error) {
+	orders := slices.Clone(query.OrderBy)
+	ordered := map[string]bool{}
+	for _, order := range orders {
+		ordered[order.Field.FieldPath] = true
+	}
+	inequalities := []string{}
+	collectInequalityFields(query.Where, &inequalities)
+	slices.Sort(inequalities)
+	for _, field := range slices.Compact(inequalities) {
+		if !ordered[field] {
+			orders = append(orders, &pb.StructuredQuery_Order{
+				Field:     &pb.StructuredQuery_FieldReference{FieldPath: field},
+				Direction: pb.StructuredQuery_ASCENDING,
+			})
+			ordered[field] = true
+		}
+	}
+	if !ordered[documentNameField] {
+		direction := pb.StructuredQuery_ASCENDING
+		if len(orders) > 0 {
+			direction = orders[len(orders)-1].Direction
+		}
+		orders = append(orders, &pb.StructuredQuery_Order{
+			Field:     &pb.StructuredQuery_FieldReference{FieldPath: documentNameField},
+			Direction: direction,
+		})
+	}
+	for _, order := range orders {
+		if _, err := parseFieldPath(order.Field.FieldPath); err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+func collectInequalityFields(filter *pb.StructuredQuery_Filter, fields *[]string) {
+	switch f := filter.GetFilterType().(type) {
+	case *pb.StructuredQuery_Filter_CompositeFilter:
+		for _, sub := range f.CompositeFilter.Filters {
+			collectInequalityFields(sub, fields)
+		}
+	case *pb.StructuredQuery_Filter_FieldFilter:
+		switch f.FieldFilter.Op {
+		case pb.StructuredQuery_FieldFilter_LESS_THAN,
+			pb.StructuredQuery_FieldFilter_LESS_THAN_OR_EQUAL,
+			pb.StructuredQuery_FieldFilter_GREATER_THAN,
+			pb.StructuredQuery_FieldFilter_GREATER_THAN_OR_EQUAL,
+			pb.StructuredQuery_FieldFilter_NOT_EQUAL,
+			pb.StructuredQuery_FieldFilter_NOT_IN:
+			*fields = append(*fields, f.FieldFilter.Field.FieldPath)
+		}
+	}
+}
+
+func inCollection(name string, parent string, collection *pb.StructuredQuery_CollectionSelector) bool {
+	rest, ok := strings.CutPrefix(name, parent+"/")
+	if !ok {
+		return false
+	}
+	segments := strings.Split(rest, "/")
+	if collection.AllDescendants {
+		return segments[len(segments)-2] == collection.CollectionId
+	}
+	return len(segments) == 2 && segments[0] == collection.CollectionId
+}
+
+func matchesFilter(doc *pb.Document, filter *pb.StructuredQuery_Filter) (bool, error) {
+	switch f := filter.GetFilterType().(type) {
+	case nil:
+		return true, nil
+	case *pb.StructuredQuery_Filter_CompositeFilter:
+		isOr := f.CompositeFilter.Op == pb.StructuredQuery_CompositeFilter_OR
+		for _, sub := range f.CompositeFilter.Filters {
+			ok, err := matchesFilter(doc, sub)
+			if err != nil {
+				return false, err
+			}
+			if ok == isOr {
+				return isOr, nil
+			}
+		}
+		return !isOr, nil
+	case *pb.StructuredQuery_Filter_FieldFilter:
+		return matchesFieldFilter(doc, f.FieldFilter)
+	case *pb.StructuredQuery_Filter_UnaryFilter:
+		value, ok, err := documentValue(doc, f.UnaryFilter.GetField().GetFieldPath())
+		if err != nil || !ok {
+			return false, err
+		}
+		isNaN := math.IsNaN(value.GetDoubleValue())
+		_, isNull := value.ValueType.(*pb.Value_NullValue)
+		switch f.UnaryFilter.Op {
+		case pb.StructuredQuery_UnaryFilter_IS_NAN:
+			return isNaN, nil
+		case pb.StructuredQuery_UnaryFilter_IS_NULL:
+			return isNull, nil
+		case pb.StructuredQuery_UnaryFilter_IS_NOT_NAN:
+			return !isNaN && !isNull, nil
+		case pb.StructuredQuery_UnaryFilter_IS_NOT_NULL:
+			return !isNull, nil
+		}
+	}
+	return false, status.Error(codes.InvalidArgument, "unsupported query filter")
+}
+
+func matchesFieldFilter(doc *pb.Document, filter *pb.StructuredQuery_FieldFilter) (bool, error) {
+	value, ok, err := documentValue(doc, filter.Field.FieldPath)
+	if err != nil || !ok {
+		return false, err
+	}
+	operand := filter.Value
+	_, isNull := value.ValueType.(*pb.Value_NullValue)
+	// range filters only match values of the same type
+	sameType := valueTypeOrder(value) == valueTypeOrder(operand)
+	switch filter.Op {
+	case pb.StructuredQuery_FieldFilter_EQUAL:
+		return compareValues(value, operand) == 0, nil
+	case pb.StructuredQuery_FieldFilter_NOT_EQUAL:
+		return !isNull && compareValues(value, operand) != 0, nil
+	case pb.StructuredQuery_FieldFilter_LESS_THAN:
+		return sameType && compareValues(value, operand) < 0, nil
+	case pb.StructuredQuery_FieldFilter_LESS_THAN_OR_EQUAL:
+		return sameType && compareValues(value, operand) <= 0, nil
+	case pb.StructuredQuery_FieldFilter_GREATER_THAN:
+		return sameType && compareValues(value, operand) > 0, nil
+	case pb.StructuredQuery_FieldFilter_GREATER_THAN_OR_EQUAL:
+		return sameType && compareValues(value, operand) >= 0, nil
+	case pb.StructuredQuery_FieldFilter_ARRAY_CONTAINS:
+		return containsValue(value.GetArrayValue().GetValues(), operand), nil
+	case pb.StructuredQuery_FieldFilter_IN:
+		return containsValue(operand.GetArrayValue().GetValues(), value), nil
+	case pb.StructuredQuery_FieldFilter_ARRAY_CONTAINS_ANY:
+		return slices.ContainsFunc(value.GetArrayValue().GetValues(), func(element *pb.Value) bool {
+			return containsValue(operand.GetArrayValue().GetValues(), element)
+		}), nil
+	case pb.StructuredQuery_FieldFilter_NOT_IN:
+		return !isNull && !containsValue(operand.GetArrayValue().GetValues(), value), nil
+	}
+	return false, status.Errorf(codes.InvalidArgument, "unsupported filter operator %s", filter.Op)
+}
+
+func hasOrderedFields(doc *pb.Document, orders []*pb.StructuredQuery_Order) bool {
+	for _, order := range orders {
+		if _, ok, _ := documentValue(doc, order.Field.FieldPath); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func compareOrdered(a *pb.Document, b *pb.Document, order *pb.StructuredQuery_Order) int {
+	valueA, _, _ := documentValue(a, order.Field.FieldPath)
+	valueB, _, _ := documentValue(b, order.Field.FieldPath)
+	c := compareValues(valueA, valueB)
+	if order.Direction == pb.StructuredQuery_DESCENDING {
+		return -c
+	}
+	return c
+}
+
+// Position of the document relative to the cursor values, in the order of the query
+func compareToCursor(doc *pb.Document, orders []*pb.StructuredQuery_Order, cursor *pb.Cursor) int {
+	for i, cursorValue := range cursor.Values {
+		if i >= len(orders) {
+			break
+		}
+		value, _, _ := documentValue(doc, orders[i].Field.FieldPath)
+		c := compareValues(value, cursorValue)
+		if orders[i].Direction == pb.StructuredQuery_DESCENDING {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func afterStart(doc *pb.Document, orders []*pb.StructuredQuery_Order, cursor *pb.Cursor) bool {
+	if cursor == nil {
+		return true
+	}
+	c := compareToCursor(doc, orders, cursor)
+	return c > 0 || (c == 0 && cursor.Before)
+}
+
+func beforeEnd(doc *pb.Document, orders []*pb.StructuredQuery_Order, cursor *pb.Cursor) bool {
+	if cursor == nil {
+		return true
+	}
+	c := compareToCursor(doc, orders, cursor)
+	return c < 0 || (c == 0 && !cursor.Before)
+}
+
+func projectDocument(doc *pb.Document, projection *pb.StructuredQuery_Projection) *pb.Document {
+	if projection == nil || len(projection.Fields) == 0 {
+		return proto.Clone(doc).(*pb.Document)
+	}
+	paths := make([]string, 0, len(projection.Fields))
+	for _, field := range projection.Fields {
+		if field.FieldPath != documentNameField {
+			paths = append(paths, field.FieldPath)
+		}
+	}
+	return applyMask(doc, &pb.DocumentMask{FieldPaths: paths})
+}
+
+// A copy of the document with only the fields of the mask, all of them without a mask
+func applyMask(doc *pb.Document, mask *pb.DocumentMask) *pb.Document {
+	if mask == nil {
+		return proto.Clone(doc).(*pb.Document)
+	}
+	masked := &pb.Document{
+		Name:       doc.Name,
+		Fields:     map[string]*pb.Value{},
+		CreateTime: doc.CreateTime,
+		UpdateTime: doc.UpdateTime,
+	}
+	for _, path := range mask.FieldPaths {
+		segments, err := parseFieldPath(path)
+		if err != nil {
+			continue
+		}
+		if value, ok := getField(doc.Fields, segments); ok {
+			setField(masked.Fields, segments, proto.Clone(value).(*pb.Value))
+		}
+	}
+	return masked
+}
+
+// The value at the field path, the document name is a reference value
+func documentValue(doc *pb.Document, path string) (*pb.Value, bool, error) {
+	if path == documentNameField {
+		return &pb.Value{ValueType: &pb.Value_ReferenceValue{ReferenceValue: doc.Name}}, true, nil
+	}
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	value, ok := getField(doc.Fields, segments)
+	return value, ok, nil
+}
+
+// Split a field path into its segments, segments can be quoted with backticks
+func parseFieldPath(path string) ([]string, error) {
+	segments := []string{}
+	var segment strings.Builder
+	quoted, escaped, wasQuoted := false, false, false
+	for _, r := range path {
+		switch {
+		case escaped:
+			segment.WriteRune(r)
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '`':
+			quoted = !quoted
+			wasQuoted = true
+		case r == '.' && !quoted:
+			if segment.Len() == 0 && !wasQuoted {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid field path %q", path)
+			}
+			segments = append(segments, segment.String())
+			segment.Reset()
+			wasQuoted = false
+		default:
+			segment.WriteRune(r)
+		}
+	}
+	if quoted || escaped || (segment.Len() == 0 && !wasQuoted) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid field path %q", path)
+	}
+	return append(segments, segment.String()), nil
+}
+
+func getField(fields map[string]*pb.Value, segments []string) (*pb.Value, bool) {
+	value, ok := fields[segments[0]]
+	if !ok || len(segments) == 1 {
+		return value, ok
+	}
+	nested := value.GetMapValue()
+	if nested == nil {
+		return nil, false
+	}
+	return getField(nested.Fields, segments[1:])
+}
+
+// Intermediate maps are created, or replace values that aren't maps
+func setField(fields map[string]*pb.Value, segments []string, value *pb.Value) {
+	if len(segments) == 1 {
+		fields[segments[0]] = value
+		return
+	}
+	nested := fields[segments[0]].GetMapValue()
+	if nested == nil {
+		nested = &pb.MapValue{}
+		fields[segments[0]] = &pb.Value{ValueType: &pb.Value_MapValue{MapValue: nested}}
+	}
+	if nested.Fields == nil {
+		nested.Fields = map[string]*pb.Value{}
+	}
+	setField(nested.Fields, segments[1:], value)
+}
+
+func deleteField(fields map[string]*pb.Value, segments []string) {
+	if len(segments) == 1 {
+		delete(fields, segments[0])
+		return
+	}
+	if nested := fields[segments[0]].GetMapValue(); nested != nil {
+		deleteField(nested.Fields, segments[1:])
+	}
+}
+
+func cloneFields(fields map[string]*pb.Value) map[string]*pb.Value {
+	cloned := make(map[string]*pb.Value, len(fields))
+	for key, value := range fields {
+		cloned[key] = proto.Clone(value).(*pb.Value)
+	}
+	return cloned
+}
+
+func containsValue(values []*pb.Value, value *pb.Value) bool {
+	return slices.ContainsFunc(values, func(v *pb.Value) bool { return compareValues(v, value) == 0 })
+}
+
+func isNumber(value *pb.Value) bool {
+	switch value.GetValueType().(type) {
+	case *pb.Value_IntegerValue, *pb.Value_DoubleValue:
+		return true
+	}
+	return false
+}
+
+// Integers stay integers, anything that isn't a number counts as zero
+func addNumbers(current *pb.Value, increment *pb.Value) *pb.Value {
+	if !isNumber(current) {
+		return increment
+	}
+	a, aIsInt := current.ValueType.(*pb.Value_IntegerValue)
+	b, bIsInt := increment.ValueType.(*pb.Value_IntegerValue)
+	if aIsInt && bIsInt {
+		return &pb.Value{ValueType: &pb.Value_IntegerValue{IntegerValue: a.IntegerValue + b.IntegerValue}}
+	}
+	sum := numberValue(current) + numberValue(increment)
+	return &pb.Value{ValueType: &pb.Value_DoubleValue{DoubleValue: sum}}
+}
+
+func numberValue(value *pb.Value) float64 {
+	if v, ok := value.ValueType.(*pb.Value_IntegerValue); ok {
+		return float64(v.IntegerValue)
+	}
+	return value.GetDoubleValue()
+}
+
+// Rank of the value type in the Firestore ordering, integers and doubles are both numbers
+func valueTypeOrder(value *pb.Value) int {
+	switch value.GetValueType().(type) {
+	case *pb.Value_NullValue:
+		return 0
+	case *pb.Value_BooleanValue:
+		return 1
+	case *pb.Value_IntegerValue, *pb.Value_DoubleValue:
+		return 2
+	case *pb.Value_TimestampValue:
+		return 3
+	case *pb.Value_StringValue:
+		return 4
+	case *pb.Value_BytesValue:
+		return 5
+	case *pb.Value_ReferenceValue:
+		return 6
+	case *pb.Value_GeoPointValue:
+		return 7
+	case *pb.Value_ArrayValue:
+		return 8
+	default:
+		return 9
+	}
+}
+
+func compareValues(a *pb.Value, b *pb.Value) int {
+	if c := cmp.Compare(valueTypeOrder(a), valueTypeOrder(b)); c != 0 {
+		return c
+	}
+	switch v := a.GetValueType().(type) {
+	case *pb.Value_BooleanValue:
+		return compareBools(v.BooleanValue, b.GetBooleanValue())
+	case *pb.Value_IntegerValue:
+		if other, ok := b.ValueType.(*pb.Value_IntegerValue); ok {
+			return cmp.Compare(v.IntegerValue, other.IntegerValue)
+		}
+		return cmp.Compare(numberValue(a), numberValue(b))
+	case *pb.Value_DoubleValue:
+		return cmp.Compare(v.DoubleValue, numberValue(b))
+	case *pb.Value_TimestampValue:
+		return cmp.Or(
+			cmp.Compare(v.TimestampValue.GetSeconds(), b.GetTimestampValue().GetSeconds()),
+			cmp.Compare(v.TimestampValue.GetNanos(), b.GetTimestampValue().GetNanos()))
+	case *pb.Value_StringValue:
+		return strings.Compare(v.StringValue, b.GetStringValue())
+	case *pb.Value_BytesValue:
+		return bytes.Compare(v.BytesValue, b.GetBytesValue())
+	case *pb.Value_ReferenceValue:
+		return slices.Compare(strings.Split(v.ReferenceValue, "/"), strings.Split(b.GetReferenceValue(), "/"))
+	case *pb.Value_GeoPointValue:
+		return cmp.Or(
+			cmp.Compare(v.GeoPointValue.GetLatitude(), b.GetGeoPointValue().GetLatitude()),
+			cmp.Compare(v.GeoPointValue.GetLongitude(), b.GetGeoPointValue().GetLongitude()))
+	case *pb.Value_ArrayValue:
+		return slices.CompareFunc(v.ArrayValue.GetValues(), b.GetArrayValue().GetValues(), compareValues)
+	case *pb.Value_MapValue:
+		return compareMaps(v.MapValue.GetFields(), b.GetMapValue().GetFields())
+	}
+	return 0
+}
+
+func compareBools(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Maps compare by their entries in key order
+func compareMaps(a map[string]*pb.Value, b map[string]*pb.Value) int {
+	keysA := slices.Sorted(maps.Keys(a))
+	keysB := slices.Sorted(maps.Keys(b))
+	for i := 0; i < len(keysA) && i < len(keysB); i++ {
+		if c := cmp.Or(strings.Compare(keysA[i], keysB[i]), compareValues(a[keysA[i]], b[keysB[i]])); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(keysA), len(keysB))
+}
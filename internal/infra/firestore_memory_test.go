@@ -0,0 +1,194 @@
+package infra
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testMemoryDoc struct {
+	Name      string   `firestore:"name"`
+	Tags      []string `firestore:"tags"`
+	DeletedAt int64    `firestore:"deletedAt"`
+}
+
+func newTestMemoryFirestore(t *testing.T, file string) (*memoryFirestore, *firestore.Client) {
+	t.Helper()
+	local, err := newMemoryFirestore(file, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	client, err := local.connect(context.Background(), "test-project")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = local.Close()
+	})
+	return local, client
+}
+
+func TestMemoryFirestore_Documents(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, client := newTestMemoryFirestore(t, "")
+	docRef := client.Collection("addresses").Doc("a1")
+
+	_, err := docRef.Get(ctx)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = docRef.Create(ctx, testMemoryDoc{Name: "Baker Street", Tags: []string{"uk"}})
+	require.NoError(t, err)
+	_, err = docRef.Create(ctx, testMemoryDoc{Name: "again"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = docRef.Update(ctx, []firestore.Update{
+		{Path: "tags", Value: firestore.ArrayUnion("detective", "uk")},
+		{Path: "address.city", Value: "London"},
+	})
+	require.NoError(t, err)
+	doc, err := docRef.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"uk", "detective"}, doc.Data()["tags"])
+	city, err := doc.DataAt("address.city")
+	require.NoError(t, err)
+	assert.Equal(t, "London", city)
+
+	_, err = docRef.Update(ctx, []firestore.Update{
+		{Path: "tags", Value: firestore.ArrayRemove("uk")},
+		{Path: "address", Value: firestore.Delete},
+	})
+	require.NoError(t, err)
+	doc, err = docRef.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "Baker Street", "tags": []interface{}{"detective"}, "deletedAt": int64(0)},
+		doc.Data())
+
+	_, err = client.Collection("addresses").Doc("missing").Update(ctx, []firestore.Update{{Path: "name", Value: "x"}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = docRef.Delete(ctx, firestore.LastUpdateTime(doc.UpdateTime.Add(-1)))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = docRef.Delete(ctx, firestore.LastUpdateTime(doc.UpdateTime))
+	require.NoError(t, err)
+	_, err = docRef.Get(ctx)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestMemoryFirestore_Queries(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, client := newTestMemoryFirestore(t, "")
+	addresses := client.Collection("addresses")
+	docs := map[string]testMemoryDoc{
+		"a": {Name: "Abbey Road", Tags: []string{"uk", "music"}},
+		"b": {Name: "Baker Street", Tags: []string{"uk"}, DeletedAt: 30},
+		"c": {Name: "Champs-Elysees", Tags: []string{"france"}, DeletedAt: 10},
+		"d": {Name: "Dam Square", Tags: []string{"netherlands"}, DeletedAt: 20},
+	}
+	for id, doc := range docs {
+		_, err := addresses.Doc(id).Set(ctx, doc)
+		require.NoError(t, err)
+	}
+	// documents of subcollections are not part of the collection
+	_, err := addresses.Doc("a").Collection("revisions").Doc("1").Set(ctx, testMemoryDoc{Name: "Abbey Road"})
+	require.NoError(t, err)
+
+	ids := func(query firestore.Query) []string {
+		t.Helper()
+		snapshots, err := query.Documents(ctx).GetAll()
+		require.NoError(t, err)
+		result := []string{}
+		for _, snapshot := range snapshots {
+			result = append(result, snapshot.Ref.ID)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids(addresses.Query))
+	assert.Equal(t, []string{"c", "d", "b"}, ids(addresses.Where("deletedAt", ">", 0)))
+	assert.Equal(t, []string{"b", "d"}, ids(addresses.Where("deletedAt", ">", 0).OrderBy("deletedAt", firestore.Desc).Limit(2)))
+	assert.Equal(t, []string{"a", "b"}, ids(addresses.Where("tags", "array-contains-any", []string{"uk"})))
+	assert.Equal(t, []string{"a", "c"}, ids(addresses.Where("name", "in", []string{"Abbey Road", "Champs-Elysees"})))
+	assert.Equal(t, []string{"c"}, ids(addresses.Where("deletedAt", ">", 0).Where("deletedAt", "<=", 10)))
+
+	first, err := addresses.Doc("b").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, ids(addresses.OrderBy(firestore.DocumentID, firestore.Asc).StartAfter(first)))
+
+	refs, err := addresses.DocumentRefs(ctx).GetAll()
+	require.NoError(t, err)
+	assert.Len(t, refs, 4)
+	revisions, err := addresses.Doc("a").Collection("revisions").DocumentRefs(ctx).GetAll()
+	require.NoError(t, err)
+	assert.Len(t, revisions, 1)
+}
+
+func TestMemoryFirestore_Transactions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, client := newTestMemoryFirestore(t, "")
+	counter := client.Collection("counters").Doc("c")
+	_, err := counter.Set(ctx, map[string]interface{}{"value": 0})
+	require.NoError(t, err)
+
+	// concurrent read-modify-write transactions are retried until none of the increments is lost
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+				doc, err := tx.Get(counter)
+				if err != nil {
+					return err
+				}
+				value, _ := doc.DataAt("value")
+				return tx.Set(counter, map[string]interface{}{"value": value.(int64) + 1})
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	doc, err := counter.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), doc.Data()["value"])
+
+	// writes of a failed transaction are discarded
+	_ = client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Set(counter, map[string]interface{}{"value": -1}); err != nil {
+			return err
+		}
+		return tx.Create(counter, map[string]interface{}{"value": -2})
+	})
+	doc, err = counter.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), doc.Data()["value"])
+}
+
+func TestMemoryFirestore_BulkWriterAndFile(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "firestore.json")
+	local, client := newTestMemoryFirestore(t, file)
+	bulkWriter := client.BulkWriter(ctx)
+	created, err := bulkWriter.Create(client.Collection("addresses").Doc("a"), testMemoryDoc{Name: "Abbey Road"})
+	require.NoError(t, err)
+	missing, err := bulkWriter.Update(client.Collection("addresses").Doc("b"), []firestore.Update{{Path: "name", Value: "x"}})
+	require.NoError(t, err)
+	bulkWriter.End()
+	_, err = created.Results()
+	assert.NoError(t, err)
+	_, err = missing.Results()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	require.NoError(t, local.Close())
+
+	// the documents are read back from the file
+	_, reloaded := newTestMemoryFirestore(t, file)
+	doc, err := reloaded.Collection("addresses").Doc("a").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Abbey Road", doc.Data()["name"])
+}
@@ -0,0 +1,85 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// Structured completion provider, the LLM APIs in the cloud and a stub in local mode
+type CompletionClient interface {
+	StructuredCompletion(
+		ctx context.Context,
+		opts StructuredCompletionOptions,
+		schemaInstance interface{},
+		result interface{}) error
+}
+
+var (
+	_ CompletionClient = (*LLMClient)(nil)
+	_ CompletionClient = (*StubLLMClient)(nil)
+)
+
+// Fills the result with placeholder values so generation flows can run without any API key
+type StubLLMClient struct {
+	logger *slog.Logger
+}
+
+func NewStubLLMClient(logger *slog.Logger) *StubLLMClient {
+	logger.Info("Stub LLM client initialized successfully")
+	return &StubLLMClient{logger: logger}
+}
+
+func (l *StubLLMClient) StructuredCompletion(
+	ctx context.Context,
+	opts StructuredCompletionOptions,
+	schemaInstance interface{},
+	result interface{}) error {
+	if strings.TrimSpace(opts.Prompt) == "" {
+		l.logger.Error("invalid prompt", "error", "the prompt shouldn't be empty")
+		return fmt.Errorf("invalid prompt, the prompt shouldn't be an empty string")
+	}
+	value := reflect.ValueOf(result)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("result must be a non-nil pointer")
+	}
+	l.logger.Info("Stub structured completion", "model", opts.Model)
+	fillStubValue(value.Elem(), "value")
+	return nil
+}
+
+// Strings get the field name as content and every slice gets a single element
+func fillStubValue(value reflect.Value, name string) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(fmt.Sprintf("stub %s", name))
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			field := valueType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fillStubValue(value.Field(i), stubFieldName(field))
+		}
+	case reflect.Slice:
+		slice := reflect.MakeSlice(value.Type(), 1, 1)
+		fillStubValue(slice.Index(0), name)
+		value.Set(slice)
+	case reflect.Pointer:
+		pointer := reflect.New(value.Type().Elem())
+		fillStubValue(pointer.Elem(), name)
+		value.Set(pointer)
+	case reflect.Map:
+		value.Set(reflect.MakeMap(value.Type()))
+	}
+}
+
+func stubFieldName(field reflect.StructField) string {
+	if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+		return tag
+	}
+	return field.Name
+}
@@ -0,0 +1,35 @@
+package infra
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStubLLMClient_StructuredCompletion(t *testing.T) {
+	t.Parallel()
+	client := NewStubLLMClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var result models.BatchAddressGenerationSchema
+	opts := StructuredCompletionOptions{Prompt: "generate an address", Model: "gpt-5-mini"}
+	err := client.StructuredCompletion(context.Background(), opts, models.BatchAddressGenerationSchema{}, &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Addresses, 1)
+	assert.Equal(t, "stub name", result.Addresses[0].Name)
+	assert.Equal(t, []string{"stub tags"}, result.Addresses[0].Tags)
+	assert.Equal(t, "stub city", result.Addresses[0].Address.City)
+}
+
+func TestStubLLMClient_StructuredCompletion_Errors(t *testing.T) {
+	t.Parallel()
+	client := NewStubLLMClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var result models.BatchAddressGenerationSchema
+	err := client.StructuredCompletion(context.Background(), StructuredCompletionOptions{Prompt: " "}, nil, &result)
+	assert.Error(t, err)
+	err = client.StructuredCompletion(context.Background(), StructuredCompletionOptions{Prompt: "prompt"}, nil, result)
+	assert.Error(t, err)
+}
@@ -0,0 +1,20 @@
+package infra
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	StorageModeCloud = "cloud"
+	StorageModeLocal = "local"
+)
+
+// Read the storage mode from STORAGE_MODE, the cloud services are used unless it is set to local.
+// The local mode keeps Firestore, Auth, search, the LLM and the buckets in process or on the local disk.
+func GetStorageMode() string {
+	if strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_MODE"))) == StorageModeLocal {
+		return StorageModeLocal
+	}
+	return StorageModeCloud
+}
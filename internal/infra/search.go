@@ -0,0 +1,133 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"html"
+	"maps"
+	"north-post/service/internal/domain/v1/models"
+	"strings"
+)
+
+// Address search engine, backed by Typesense in the cloud and by an in-memory index in local mode.
+// Collection names are stable per language, a sync replaces the contents behind the name at once.
+type SearchClient interface {
+	CreateAddressRecord(
+		addressItem *models.AddressItem,
+		categorizedTags map[models.TagCategory][]string) TypesenseAddressRecord
+	SyncAddressDatabase(
+		ctx context.Context,
+		language models.Language,
+		collectionName string,
+		documents []interface{}) (*SyncDatabaseResult, error)
+	// rejected with ErrInvalidSearchQuery when the index can't run the query, e.g. an older schema
+	SearchAddresses(ctx context.Context, params *SearchAddressesParams) (*SearchAddressesResult, error)
+	SearchSimilarNames(ctx context.Context, params *SearchSimilarNamesParams) ([]string, error)
+	UpsertAddressRecords(
+		ctx context.Context,
+		collectionName string,
+		documents []interface{}) (*SyncDatabaseResult, error)
+	DeleteAddressRecords(ctx context.Context, collectionName string, addressIDs []string) (int, error)
+	GetSystemInfo(ctx context.Context) (*TypesenseSystemInfo, error)
+	// false when the index misses fields of the current schema or was not created yet, a full sync rebuilds it
+	IsAddressSchemaCurrent(ctx context.Context, language models.Language, collectionName string) (bool, error)
+}
+
+var ErrInvalidSearchQuery = errors.New("search query rejected by the search engine")
+
+var (
+	_ SearchClient = (*TypesenseClient)(nil)
+	_ SearchClient = (*MemorySearchClient)(nil)
+)
+
+// Bumped when fields are added to the record, records of an older version miss some list view fields
+const addressRecordVersion = 1
+
+// categorizedTags are the display names of the address tags by taxonomy category, they back the facets
+func newAddressRecord(
+	addressItem *models.AddressItem,
+	categorizedTags map[models.TagCategory][]string) TypesenseAddressRecord {
+	return TypesenseAddressRecord{
+		ID:                 addressItem.ID,
+		Name:               addressItem.Name,
+		BriefIntro:         addressItem.BriefIntro,
+		Tags:               addressItem.Tags,
+		CountryTags:        append([]string{}, categorizedTags[models.TagCategoryCountry]...),
+		RoleTags:           append([]string{}, categorizedTags[models.TagCategoryRole]...),
+		FigureTags:         append([]string{}, categorizedTags[models.TagCategoryFigure]...),
+		UpdatedAt:          addressItem.UpdatedAt,
+		CreatedAt:          addressItem.CreatedAt,
+		Address:            addressItem.Address,
+		Revision:           addressItem.Revision,
+		TranslationGroupID: addressItem.TranslationGroupID,
+		RecordVersion:      addressRecordVersion,
+	}
+}
+
+// The address as the list views show it, false when the record predates some of their fields
+// and the address document has to be read instead
+func (r TypesenseAddressRecord) ToAddressItem() (models.AddressItem, bool) {
+	if r.RecordVersion < addressRecordVersion {
+		return models.AddressItem{}, false
+	}
+	return models.AddressItem{
+		ID:                 r.ID,
+		Name:               r.Name,
+		BriefIntro:         r.BriefIntro,
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
+		Tags:               r.Tags,
+		Address:            r.Address,
+		Revision:           r.Revision,
+		TranslationGroupID: r.TranslationGroupID,
+	}, true
+}
+
+// Tags of the record in the category
+func (r TypesenseAddressRecord) categoryTags(category models.TagCategory) []string {
+	switch category {
+	case models.TagCategoryCountry:
+		return r.CountryTags
+	case models.TagCategoryRole:
+		return r.RoleTags
+	case models.TagCategoryFigure:
+		return r.FigureTags
+	}
+	return nil
+}
+
+// Name of the record field holding the tags of the category
+func tagFacetField(category models.TagCategory) string {
+	return string(category) + "Tags"
+}
+
+// The tag filters of the other categories, the facet of a category is counted with them
+// so selecting one of its values doesn't hide the others
+func withoutCategoryFilter(
+	tagFilters map[models.TagCategory][]string, category models.TagCategory) map[models.TagCategory][]string {
+	filters := maps.Clone(tagFilters)
+	delete(filters, category)
+	return filters
+}
+
+// HTML escape the highlighted text, then turn the match markers into <mark> tags
+// so the client can render the highlight as is
+func escapeHighlight(text string) string {
+	return strings.NewReplacer(
+		highlightStartMarker, "<mark>",
+		highlightEndMarker, "</mark>",
+	).Replace(html.EscapeString(text))
+}
+
+// Clamp the requested page and page size to the values the search engines accept
+func normalizePagination(page int, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	} else if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
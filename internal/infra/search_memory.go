@@ -0,0 +1,289 @@
+package infra
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"north-post/service/internal/domain/v1/models"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const memorySimilarNameScore = 0.5
+
+// In-memory replacement of Typesense for the local storage mode.
+// The index is rebuilt from Firestore with the admin sync endpoint after each restart.
+type MemorySearchClient struct {
+	mu          sync.RWMutex
+	collections map[string]map[string]TypesenseAddressRecord
+	logger      *slog.Logger
+}
+
+func NewMemorySearchClient(logger *slog.Logger) *MemorySearchClient {
+	logger.Info("In-memory search client initialized successfully")
+	return &MemorySearchClient{
+		collections: make(map[string]map[string]TypesenseAddressRecord),
+		logger:      logger,
+	}
+}
+
+func (c *MemorySearchClient) CreateAddressRecord(
+	addressItem *models.AddressItem,
+	categorizedTags map[models.TagCategory][]string) TypesenseAddressRecord {
+	return newAddressRecord(addressItem, categorizedTags)
+}
+
+func (c *MemorySearchClient) SyncAddressDatabase(
+	ctx context.Context,
+	language models.Language,
+	collectionName string,
+	documents []interface{}) (*SyncDatabaseResult, error) {
+	collection := make(map[string]TypesenseAddressRecord, len(documents))
+	failed := 0
+	for _, document := range documents {
+		record, ok := document.(TypesenseAddressRecord)
+		if !ok {
+			c.logger.Warn("unsupported document type for in-memory search", "collectionName", collectionName)
+			failed++
+			continue
+		}
+		collection[record.ID] = record
+	}
+	c.mu.Lock()
+	c.collections[collectionName] = collection
+	c.mu.Unlock()
+	return &SyncDatabaseResult{
+		Total:   len(documents),
+		Success: len(documents) - failed,
+		Failed:  failed,
+	}, nil
+}
+
+// Every keyword has to appear in the name or brief intro, any of the tags has to match
+// and any tag of each filtered category. Relevance ranks addresses by the keywords found in their name
+func (c *MemorySearchClient) SearchAddresses(
+	ctx context.Context, params *SearchAddressesParams) (*SearchAddressesResult, error) {
+	page, perPage := normalizePagination(params.Page, params.PageSize)
+	keywords := strings.Fields(strings.ToLower(params.Keywords))
+	c.mu.RLock()
+	// the facets are counted before the tag filters
+	candidates := []TypesenseAddressRecord{}
+	for _, record := range c.collections[params.CollectionName] {
+		if matchesKeywords(record, keywords) && matchesAnyTag(record.Tags, params.Tags) {
+			candidates = append(candidates, record)
+		}
+	}
+	c.mu.RUnlock()
+	matches := []TypesenseAddressRecord{}
+	for _, record := range candidates {
+		if matchesTagFilters(record, params.TagFilters) {
+			matches = append(matches, record)
+		}
+	}
+	slices.SortFunc(matches, func(a, b TypesenseAddressRecord) int {
+		switch params.Sort {
+		case models.AddressSortRelevance:
+			if order := cmp.Compare(nameScore(b, keywords), nameScore(a, keywords)); order != 0 {
+				return order
+			}
+		case models.AddressSortName:
+			if order := cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); order != 0 {
+				return order
+			}
+		}
+		return cmp.Or(cmp.Compare(b.UpdatedAt, a.UpdatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	hits := []string{}
+	highlights := map[string]models.AddressHighlight{}
+	pattern := keywordsPattern(keywords)
+	start := min((page-1)*perPage, len(matches))
+	end := min(start+perPage, len(matches))
+	for _, record := range matches[start:end] {
+		hits = append(hits, record.ID)
+		highlight := models.AddressHighlight{
+			Name:       highlightKeywords(record.Name, pattern),
+			BriefIntro: highlightKeywords(record.BriefIntro, pattern),
+		}
+		if highlight != (models.AddressHighlight{}) {
+			highlights[record.ID] = highlight
+		}
+	}
+	return &SearchAddressesResult{
+		Hits:       hits,
+		Records:    slices.Clone(matches[start:end]),
+		Highlights: highlights,
+		Facets:     countTagFacets(candidates, params.TagFilters),
+		Page:       page,
+		PageSize:   perPage,
+		TotalCount: int64(len(matches)),
+	}, nil
+}
+
+// Names sharing at least half of their character bigrams, the closest first
+func (c *MemorySearchClient) SearchSimilarNames(
+	ctx context.Context, params *SearchSimilarNamesParams) ([]string, error) {
+	_, limit := normalizePagination(1, params.Limit)
+	type scoredRecord struct {
+		id    string
+		score float64
+	}
+	c.mu.RLock()
+	matches := []scoredRecord{}
+	for _, record := range c.collections[params.CollectionName] {
+		if score := models.NameSimilarity(record.Name, params.Name); score >= memorySimilarNameScore {
+			matches = append(matches, scoredRecord{id: record.ID, score: score})
+		}
+	}
+	c.mu.RUnlock()
+	slices.SortFunc(matches, func(a, b scoredRecord) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.id, b.id))
+	})
+	ids := []string{}
+	for _, match := range matches[:min(limit, len(matches))] {
+		ids = append(ids, match.id)
+	}
+	return ids, nil
+}
+
+func (c *MemorySearchClient) UpsertAddressRecords(
+	ctx context.Context,
+	collectionName string,
+	documents []interface{}) (*SyncDatabaseResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.collections[collectionName] == nil {
+		c.collections[collectionName] = make(map[string]TypesenseAddressRecord)
+	}
+	failed := 0
+	for _, document := range documents {
+		record, ok := document.(TypesenseAddressRecord)
+		if !ok {
+			c.logger.Warn("unsupported document type for in-memory search", "collectionName", collectionName)
+			failed++
+			continue
+		}
+		c.collections[collectionName][record.ID] = record
+	}
+	return &SyncDatabaseResult{
+		Total:   len(documents),
+		Success: len(documents) - failed,
+		Failed:  failed,
+	}, nil
+}
+
+func (c *MemorySearchClient) DeleteAddressRecords(
+	ctx context.Context, collectionName string, addressIDs []string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deleted := 0
+	for _, id := range addressIDs {
+		if _, ok := c.collections[collectionName][id]; ok {
+			delete(c.collections[collectionName], id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (c *MemorySearchClient) GetSystemInfo(ctx context.Context) (*TypesenseSystemInfo, error) {
+	return &TypesenseSystemInfo{Health: true}, nil
+}
+
+// The in-memory index always has the current schema, it is rebuilt after every restart anyway
+func (c *MemorySearchClient) IsAddressSchemaCurrent(
+	ctx context.Context, language models.Language, collectionName string) (bool, error) {
+	return true, nil
+}
+
+// Helper functions
+func matchesKeywords(record TypesenseAddressRecord, keywords []string) bool {
+	text := strings.ToLower(record.Name + " " + record.BriefIntro)
+	for _, keyword := range keywords {
+		if !strings.Contains(text, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAnyTag(recordTags []string, tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if slices.Contains(recordTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesTagFilters(record TypesenseAddressRecord, tagFilters map[models.TagCategory][]string) bool {
+	for category, values := range tagFilters {
+		if !matchesAnyTag(record.categoryTags(category), values) {
+			return false
+		}
+	}
+	return true
+}
+
+// Number of keywords found in the name
+func nameScore(record TypesenseAddressRecord, keywords []string) int {
+	name := strings.ToLower(record.Name)
+	score := 0
+	for _, keyword := range keywords {
+		if strings.Contains(name, keyword) {
+			score++
+		}
+	}
+	return score
+}
+
+// Matches any of the keywords case insensitively, nil without keywords
+func keywordsPattern(keywords []string) *regexp.Regexp {
+	if len(keywords) == 0 {
+		return nil
+	}
+	patterns := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		patterns[i] = regexp.QuoteMeta(keyword)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(patterns, "|"))
+}
+
+// The text with the keywords wrapped like Typesense highlights, empty when none of them is found
+func highlightKeywords(text string, pattern *regexp.Regexp) string {
+	if pattern == nil || !pattern.MatchString(text) {
+		return ""
+	}
+	return escapeHighlight(pattern.ReplaceAllString(text, highlightStartMarker+"$0"+highlightEndMarker))
+}
+
+// Same shape as the Typesense facets, the most frequent tags first. A category is counted
+// with the filters of the other categories only, so its other values stay selectable
+func countTagFacets(records []TypesenseAddressRecord, tagFilters map[models.TagCategory][]string) []models.TagFacet {
+	facets := make([]models.TagFacet, len(models.TagCategories))
+	for i, category := range models.TagCategories {
+		otherFilters := withoutCategoryFilter(tagFilters, category)
+		counted := map[string]int{}
+		for _, record := range records {
+			if !matchesTagFilters(record, otherFilters) {
+				continue
+			}
+			for _, tag := range record.categoryTags(category) {
+				counted[tag]++
+			}
+		}
+		counts := []models.TagFacetCount{}
+		for value, count := range counted {
+			counts = append(counts, models.TagFacetCount{Value: value, Count: count})
+		}
+		slices.SortFunc(counts, func(a, b models.TagFacetCount) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+		})
+		facets[i] = models.TagFacet{Category: category, Counts: counts[:min(len(counts), maxFacetValues)]}
+	}
+	return facets
+}
@@ -0,0 +1,236 @@
+package infra
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMemorySearchClient() *MemorySearchClient {
+	client := NewMemorySearchClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	documents := []interface{}{
+		TypesenseAddressRecord{ID: "a", Name: "Baker Street", BriefIntro: "Home of a detective", Tags: []string{"uk"}, UpdatedAt: 1},
+		TypesenseAddressRecord{ID: "b", Name: "North Pole", BriefIntro: "Santa's workshop", Tags: []string{"arctic"}, UpdatedAt: 3},
+		TypesenseAddressRecord{ID: "c", Name: "Abbey Road", BriefIntro: "Famous street crossing", Tags: []string{"uk"}, UpdatedAt: 2},
+	}
+	_, _ = client.SyncAddressDatabase(context.Background(), models.LanguageEN, "addresses_en", documents)
+	return client
+}
+
+func TestMemorySearchClient_SearchAddresses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		params        SearchAddressesParams
+		expectedHits  []string
+		expectedTotal int64
+	}{
+		{
+			name:          "all addresses sorted by update time",
+			params:        SearchAddressesParams{CollectionName: "addresses_en"},
+			expectedHits:  []string{"b", "c", "a"},
+			expectedTotal: 3,
+		},
+		{
+			name:          "keywords match name and intro case insensitively",
+			params:        SearchAddressesParams{CollectionName: "addresses_en", Keywords: "STREET"},
+			expectedHits:  []string{"c", "a"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "every keyword has to match",
+			params:        SearchAddressesParams{CollectionName: "addresses_en", Keywords: "street detective"},
+			expectedHits:  []string{"a"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "tags filter",
+			params:        SearchAddressesParams{CollectionName: "addresses_en", Tags: []string{"arctic", "none"}},
+			expectedHits:  []string{"b"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "pagination",
+			params:        SearchAddressesParams{CollectionName: "addresses_en", Page: 2, PageSize: 2},
+			expectedHits:  []string{"a"},
+			expectedTotal: 3,
+		},
+		{
+			name:          "unknown collection",
+			params:        SearchAddressesParams{CollectionName: "addresses_zh"},
+			expectedHits:  []string{},
+			expectedTotal: 0,
+		},
+	}
+	client := newTestMemorySearchClient()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.SearchAddresses(context.Background(), &tt.params)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHits, result.Hits)
+			assert.Equal(t, tt.expectedTotal, result.TotalCount)
+		})
+	}
+}
+
+func TestMemorySearchClient_SearchAddresses_TagFiltersAndFacets(t *testing.T) {
+	t.Parallel()
+	client := NewMemorySearchClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	documents := []interface{}{
+		TypesenseAddressRecord{ID: "austen", Name: "Jane Austen's House", CountryTags: []string{"UK"},
+			RoleTags: []string{"Writer"}, UpdatedAt: 1},
+		TypesenseAddressRecord{ID: "hugo", Name: "Maison de Victor Hugo", CountryTags: []string{"France"},
+			RoleTags: []string{"Writer", "Poet"}, UpdatedAt: 2},
+		TypesenseAddressRecord{ID: "monet", Name: "Monet's Garden", BriefIntro: "House of the painter", CountryTags: []string{"France"},
+			RoleTags: []string{"Painter"}, UpdatedAt: 3},
+	}
+	_, _ = client.SyncAddressDatabase(context.Background(), models.LanguageEN, "addresses_en", documents)
+	tests := []struct {
+		name           string
+		params         SearchAddressesParams
+		expectedHits   []string
+		expectedFacets []models.TagFacet
+	}{
+		{
+			name:         "facets over every address",
+			params:       SearchAddressesParams{CollectionName: "addresses_en"},
+			expectedHits: []string{"monet", "hugo", "austen"},
+			expectedFacets: []models.TagFacet{
+				{Category: models.TagCategoryCountry, Counts: []models.TagFacetCount{{Value: "France", Count: 2}, {Value: "UK", Count: 1}}},
+				{Category: models.TagCategoryRole, Counts: []models.TagFacetCount{
+					{Value: "Writer", Count: 2}, {Value: "Painter", Count: 1}, {Value: "Poet", Count: 1}}},
+				{Category: models.TagCategoryFigure, Counts: []models.TagFacetCount{}},
+			},
+		},
+		{
+			name: "any tag within a category, every category",
+			params: SearchAddressesParams{CollectionName: "addresses_en", TagFilters: map[models.TagCategory][]string{
+				models.TagCategoryCountry: {"France", "UK"},
+				models.TagCategoryRole:    {"Writer"},
+			}},
+			expectedHits: []string{"hugo", "austen"},
+			expectedFacets: []models.TagFacet{
+				{Category: models.TagCategoryCountry, Counts: []models.TagFacetCount{{Value: "France", Count: 1}, {Value: "UK", Count: 1}}},
+				// the role filter doesn't narrow the role facet, it counts what the country filter lets through
+				{Category: models.TagCategoryRole, Counts: []models.TagFacetCount{
+					{Value: "Writer", Count: 2}, {Value: "Painter", Count: 1}, {Value: "Poet", Count: 1}}},
+				{Category: models.TagCategoryFigure, Counts: []models.TagFacetCount{}},
+			},
+		},
+		{
+			name: "a filtered category keeps counting its other values",
+			params: SearchAddressesParams{CollectionName: "addresses_en", TagFilters: map[models.TagCategory][]string{
+				models.TagCategoryRole: {"Painter"},
+			}},
+			expectedHits: []string{"monet"},
+			expectedFacets: []models.TagFacet{
+				{Category: models.TagCategoryCountry, Counts: []models.TagFacetCount{{Value: "France", Count: 1}}},
+				{Category: models.TagCategoryRole, Counts: []models.TagFacetCount{
+					{Value: "Writer", Count: 2}, {Value: "Painter", Count: 1}, {Value: "Poet", Count: 1}}},
+				{Category: models.TagCategoryFigure, Counts: []models.TagFacetCount{}},
+			},
+		},
+		{
+			name:         "sorted by name",
+			params:       SearchAddressesParams{CollectionName: "addresses_en", Sort: models.AddressSortName},
+			expectedHits: []string{"austen", "hugo", "monet"},
+		},
+		{
+			name:         "sorted by relevance, keywords in the name first",
+			params:       SearchAddressesParams{CollectionName: "addresses_en", Keywords: "house", Sort: models.AddressSortRelevance},
+			expectedHits: []string{"austen", "monet"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.SearchAddresses(context.Background(), &tt.params)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHits, result.Hits)
+			if tt.expectedFacets != nil {
+				assert.Equal(t, tt.expectedFacets, result.Facets)
+			}
+		})
+	}
+}
+
+func TestMemorySearchClient_SearchAddresses_Highlights(t *testing.T) {
+	t.Parallel()
+	client := newTestMemorySearchClient()
+	address := models.AddressItem{
+		ID:         "d",
+		Name:       "Street of <Dreams>",
+		BriefIntro: "A street in the city",
+		CreatedAt:  4,
+		UpdatedAt:  4,
+		Address:    models.Address{City: "London", Country: "UK", Line1: "1 Dream St", Region: "London"},
+	}
+	_, err := client.UpsertAddressRecords(context.Background(), "addresses_en", []interface{}{
+		client.CreateAddressRecord(&address, nil),
+	})
+	assert.NoError(t, err)
+	result, err := client.SearchAddresses(context.Background(), &SearchAddressesParams{
+		CollectionName: "addresses_en",
+		Keywords:       "street",
+		PageSize:       2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d", "c"}, result.Hits)
+	assert.Equal(t, map[string]models.AddressHighlight{
+		"d": {Name: "<mark>Street</mark> of &lt;Dreams&gt;", BriefIntro: "A <mark>street</mark> in the city"},
+		"c": {BriefIntro: "Famous <mark>street</mark> crossing"},
+	}, result.Highlights)
+	// records of the current version carry the list view fields, older ones don't
+	indexed, ok := result.Records[0].ToAddressItem()
+	assert.True(t, ok)
+	assert.Equal(t, address, indexed)
+	_, ok = result.Records[1].ToAddressItem()
+	assert.False(t, ok)
+}
+
+func TestMemorySearchClient_UpsertAndDeleteRecords(t *testing.T) {
+	t.Parallel()
+	client := newTestMemorySearchClient()
+	ctx := context.Background()
+	result, err := client.UpsertAddressRecords(ctx, "addresses_en", []interface{}{
+		TypesenseAddressRecord{ID: "a", Name: "Baker Street 221B", UpdatedAt: 5},
+		TypesenseAddressRecord{ID: "d", Name: "Penny Lane", UpdatedAt: 4},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Success)
+	deleted, err := client.DeleteAddressRecords(ctx, "addresses_en", []string{"b", "missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	search, err := client.SearchAddresses(ctx, &SearchAddressesParams{CollectionName: "addresses_en"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "d", "c"}, search.Hits)
+}
+
+func TestMemorySearchClient_SearchSimilarNames(t *testing.T) {
+	t.Parallel()
+	client := newTestMemorySearchClient()
+	documents := []interface{}{
+		TypesenseAddressRecord{ID: "museum", Name: "Sherlock Holmes Museum"},
+		TypesenseAddressRecord{ID: "house", Name: "Sherlock Holmes House"},
+		TypesenseAddressRecord{ID: "zoo", Name: "London Zoo"},
+	}
+	_, err := client.UpsertAddressRecords(context.Background(), "addresses_en", documents)
+	assert.NoError(t, err)
+	ids, err := client.SearchSimilarNames(context.Background(), &SearchSimilarNamesParams{
+		CollectionName: "addresses_en",
+		Name:           "The Sherlok Holmes Museum",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"museum", "house"}, ids)
+	ids, err = client.SearchSimilarNames(context.Background(), &SearchSimilarNamesParams{
+		CollectionName: "addresses_en",
+		Name:           "Sherlock Holmes Museum",
+		Limit:          1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"museum"}, ids)
+}
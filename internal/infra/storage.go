@@ -3,8 +3,10 @@ package infra
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -12,6 +14,24 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+// Object storage for music files, R2 in the cloud and a local directory in local mode
+type BucketClient interface {
+	ListObjects(ctx context.Context, bucket string) ([]BucketObject, error)
+	GetObjectRange(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error)
+	PresignGetObject(ctx context.Context, bucket string, key string, expires time.Duration) (string, error)
+}
+
+var (
+	_ BucketClient = (*StorageBucketClient)(nil)
+	_ BucketClient = (*LocalStorageBucket)(nil)
+)
+
+type BucketObject struct {
+	Key          string
+	Size         int64 // bytes
+	LastModified time.Time
+}
+
 type StorageBucketClient struct {
 	R2Storage   *s3.Client
 	R2Presigned *s3.PresignClient
@@ -49,3 +69,61 @@ func NewStorageBucketClient(logger *slog.Logger) (*StorageBucketClient, error) {
 		R2Presigned: presignedClient,
 	}, nil
 }
+
+func (c *StorageBucketClient) ListObjects(ctx context.Context, bucket string) ([]BucketObject, error) {
+	objects := []BucketObject{}
+	paginator := s3.NewListObjectsV2Paginator(c.R2Storage, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list bucket objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, BucketObject{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+func (c *StorageBucketClient) GetObjectRange(
+	ctx context.Context,
+	bucket string,
+	key string,
+	offset int64,
+	length int64) ([]byte, error) {
+	output, err := c.R2Storage.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+	defer output.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(output.Body, length))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (c *StorageBucketClient) PresignGetObject(
+	ctx context.Context,
+	bucket string,
+	key string,
+	expires time.Duration) (string, error) {
+	request, err := c.R2Presigned.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign object %s: %w", key, err)
+	}
+	return request.URL, nil
+}
@@ -0,0 +1,130 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultLocalBucketDir = "local/buckets"
+
+// Serves bucket objects from the filesystem in local mode, each bucket is a sub directory of the root.
+// Presigned URLs point to a static route that main registers on the local root directory.
+type LocalStorageBucket struct {
+	root    string
+	baseURL string
+}
+
+func NewLocalStorageBucket(baseURL string, logger *slog.Logger) (*LocalStorageBucket, error) {
+	root := os.Getenv("LOCAL_BUCKET_DIR")
+	if root == "" {
+		root = defaultLocalBucketDir
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local bucket directory: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local bucket directory: %w", err)
+	}
+	logger.Info("Local storage bucket initialized successfully", "root", root)
+	return &LocalStorageBucket{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+func (b *LocalStorageBucket) Root() string {
+	return b.root
+}
+
+func (b *LocalStorageBucket) ListObjects(ctx context.Context, bucket string) ([]BucketObject, error) {
+	bucketDir := filepath.Join(b.root, bucket)
+	objects := []BucketObject{}
+	err := filepath.WalkDir(bucketDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		key, err := filepath.Rel(bucketDir, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, BucketObject{
+			Key:          filepath.ToSlash(key),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	// a bucket that was never created is empty
+	if errors.Is(err, fs.ErrNotExist) {
+		return objects, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local bucket objects: %w", err)
+	}
+	return objects, nil
+}
+
+func (b *LocalStorageBucket) GetObjectRange(
+	ctx context.Context,
+	bucket string,
+	key string,
+	offset int64,
+	length int64) ([]byte, error) {
+	path, err := b.objectPath(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object %s: %w", key, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.NewSectionReader(file, offset, length))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// Local files never expire, the duration is only kept to match the R2 client
+func (b *LocalStorageBucket) PresignGetObject(
+	ctx context.Context,
+	bucket string,
+	key string,
+	expires time.Duration) (string, error) {
+	if _, err := b.objectPath(bucket, key); err != nil {
+		return "", err
+	}
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s/%s/%s", b.baseURL, url.PathEscape(bucket), strings.Join(segments, "/")), nil
+}
+
+// Resolve the object path and make sure it stays inside the bucket directory
+func (b *LocalStorageBucket) objectPath(bucket string, key string) (string, error) {
+	bucketDir := filepath.Join(b.root, bucket)
+	path := filepath.Join(bucketDir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, bucketDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key %s", key)
+	}
+	return path, nil
+}
@@ -2,11 +2,14 @@ package infra
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"north-post/service/internal/domain/v1/models"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +22,13 @@ import (
 const (
 	defaultPageSize = 20
 	maxPageSize     = 100
+	// keeps the id filter of a bulk delete well below the URL length limit
+	deleteFilterChunkSize = 100
+	// most frequent tags returned per facet
+	maxFacetValues = 50
+	// wrap the matched keywords of the highlights until the text is escaped, see escapeHighlight
+	highlightStartMarker = "\x02"
+	highlightEndMarker   = "\x03"
 )
 
 type TypesenseClient struct {
@@ -51,13 +61,23 @@ func NewTypesenseClient(logger *slog.Logger) (*TypesenseClient, error) {
 	return &TypesenseClient{Client: client, logger: logger}, nil
 }
 
-// The typesense collection schema should be the same as this struct
+// The typesense collection schema should be the same as this struct.
+// Facet and sort fields only reach an index created before them with a full sync
 type TypesenseAddressRecord struct {
-	ID         string   `json:"id"`
-	Name       string   `json:"name"`
-	BriefIntro string   `json:"briefIntro"`
-	Tags       []string `json:"tags"`
-	UpdatedAt  int64    `json:"updatedAt"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	BriefIntro  string   `json:"briefIntro"`
+	Tags        []string `json:"tags"`
+	CountryTags []string `json:"countryTags"`
+	RoleTags    []string `json:"roleTags"`
+	FigureTags  []string `json:"figureTags"`
+	UpdatedAt   int64    `json:"updatedAt"`
+	// stored for the list views but not indexed, so they are left out of the schema
+	CreatedAt          int64          `json:"createdAt"`
+	Address            models.Address `json:"address"`
+	Revision           int64          `json:"revision"`
+	TranslationGroupID string         `json:"translationGroupId,omitempty"`
+	RecordVersion      int            `json:"recordVersion"`
 }
 
 func (c *TypesenseClient) GetAddressCollectionSchema(
@@ -68,22 +88,21 @@ func (c *TypesenseClient) GetAddressCollectionSchema(
 		Name: name,
 		Fields: []api.Field{
 			{Name: "id", Type: "string"},
-			{Name: "name", Type: "string", Locale: pointer.String(language.Get())},
-			{Name: "briefIntro", Type: "string", Locale: pointer.String(language.Get())},
+			{Name: "name", Type: "string", Locale: pointer.String(language.Locale()), Sort: pointer.True()},
+			{Name: "briefIntro", Type: "string", Locale: pointer.String(language.Locale())},
 			{Name: "tags", Type: "string[]"},
+			{Name: tagFacetField(models.TagCategoryCountry), Type: "string[]", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: tagFacetField(models.TagCategoryRole), Type: "string[]", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: tagFacetField(models.TagCategoryFigure), Type: "string[]", Facet: pointer.True(), Optional: pointer.True()},
 			{Name: "updatedAt", Type: "int64"},
 		},
 	}
 }
 
-func (c *TypesenseClient) CreateAddressRecord(addressItem *models.AddressItem) TypesenseAddressRecord {
-	return TypesenseAddressRecord{
-		ID:         addressItem.ID,
-		Name:       addressItem.Name,
-		BriefIntro: addressItem.BriefIntro,
-		Tags:       addressItem.Tags,
-		UpdatedAt:  addressItem.UpdatedAt,
-	}
+func (c *TypesenseClient) CreateAddressRecord(
+	addressItem *models.AddressItem,
+	categorizedTags map[models.TagCategory][]string) TypesenseAddressRecord {
+	return newAddressRecord(addressItem, categorizedTags)
 }
 
 type SyncDatabaseResult struct {
@@ -95,82 +114,60 @@ type SyncDatabaseResult struct {
 type SearchAddressesParams struct {
 	CollectionName string
 	Keywords       string
-	Tags           []string
-	PageSize       int
-	Page           int
+	Tags           []string // any of the tags, whatever their category
+	// any of the tags within a category, every filtered category has to match
+	TagFilters map[models.TagCategory][]string
+	Sort       models.AddressSort
+	PageSize   int
+	Page       int
+}
+
+type SearchSimilarNamesParams struct {
+	CollectionName string
+	Name           string
+	Limit          int
 }
 
 type SearchAddressesResult struct {
-	Hits       []string // file IDs
+	Hits       []string                           // file IDs
+	Records    []TypesenseAddressRecord           // indexed documents in the order of Hits
+	Highlights map[string]models.AddressHighlight // by file ID, only hits matching the keywords
+	Facets     []models.TagFacet                  // one per tag category, counted over every matching address
 	Page       int
 	PageSize   int
 	TotalCount int64
 }
 
+// Rebuild the index into a new versioned collection and swap the alias over to it,
+// so searches keep hitting the previous version until the import is done.
+// collectionName is the alias every other method reads and writes through.
 func (c *TypesenseClient) SyncAddressDatabase(
 	ctx context.Context,
 	language models.Language,
 	collectionName string,
 	documents []interface{}) (*SyncDatabaseResult, error) {
-	// first drop the entire collection to avoid mismatch records
-	_, err := c.Client.Collection(collectionName).Delete(ctx)
-	if err != nil {
-		var httpErr *typesense.HTTPError
-		if !errors.As(err, &httpErr) || httpErr.Status != 404 {
-			c.logger.Error("failed to delete typesense collection when syncing",
-				"collectionName", collectionName,
-				"error", err,
-			)
-			return nil, fmt.Errorf("failed to delete typesense collection when syncing: %w", err)
-		}
-	}
-	// create a new collection
-	schema := c.GetAddressCollectionSchema(collectionName, language)
-	_, err = c.Client.Collections().Create(ctx, schema)
+	versionName := fmt.Sprintf("%s_%d", collectionName, time.Now().UnixMilli())
+	schema := c.GetAddressCollectionSchema(versionName, language)
+	_, err := c.Client.Collections().Create(ctx, schema)
 	if err != nil {
 		c.logger.Error(
 			"failed to create typesense collection",
-			"collectionName", collectionName,
+			"collectionName", versionName,
 			"error", err,
 		)
 		return nil, fmt.Errorf("failed to create typesense collection: %w", err)
 	}
-	// directly return 0 records if no documents were found
-	if len(documents) == 0 {
-		return &SyncDatabaseResult{}, nil
-	}
-	// sync documents
-	action := api.Create
-	params := &api.ImportDocumentsParams{Action: &action}
-	results, err := c.Client.Collection(collectionName).
-		Documents().
-		Import(ctx, documents, params)
+	result, err := c.importDocuments(ctx, versionName, documents, api.Create)
 	if err != nil {
-		c.logger.Error(
-			"failed to import documents to typesense",
-			"collectionName", collectionName,
-			"error", err,
-		)
-		return nil, fmt.Errorf("failed to import documents to typesense: %w", err)
+		c.dropCollection(versionName)
+		return nil, err
 	}
-	// Tally results
-	success := 0
-	for _, result := range results {
-		if result.Success {
-			success++
-		} else {
-			c.logger.Warn(
-				"failed to sync document to typesense",
-				"id", result.Id,
-				"error", result.Error,
-			)
-		}
+	if err := c.swapAlias(ctx, collectionName, versionName); err != nil {
+		c.dropCollection(versionName)
+		return nil, err
 	}
-	return &SyncDatabaseResult{
-		Total:   len(results),
-		Success: success,
-		Failed:  len(results) - success,
-	}, nil
+	c.dropOldVersions(ctx, collectionName, versionName)
+	return result, nil
 }
 
 func (c *TypesenseClient) SearchAddresses(
@@ -179,28 +176,36 @@ func (c *TypesenseClient) SearchAddresses(
 	if params.Keywords != "" {
 		q = params.Keywords
 	}
-	page := params.Page
-	if page <= 0 {
-		page = 1
-	}
-	perPage := params.PageSize
-	if perPage <= 0 {
-		perPage = defaultPageSize
-	} else if perPage > maxPageSize {
-		perPage = maxPageSize
+	page, perPage := normalizePagination(params.Page, params.PageSize)
+	// filtered categories are counted by separate queries without their own filter
+	facetFields := []string{}
+	filteredCategories := []models.TagCategory{}
+	for _, category := range models.TagCategories {
+		if len(params.TagFilters[category]) > 0 {
+			filteredCategories = append(filteredCategories, category)
+		} else {
+			facetFields = append(facetFields, tagFacetField(category))
+		}
 	}
 	searchParams := &api.SearchCollectionParams{
-		Q:       pointer.String(q),
-		QueryBy: pointer.String("name,briefIntro"),
-		SortBy:  pointer.String("updatedAt:desc"),
-		Page:    &page,
-		PerPage: &perPage,
+		Q:              pointer.String(q),
+		QueryBy:        pointer.String("name,briefIntro"),
+		SortBy:         pointer.String(addressSortBy(params.Sort)),
+		MaxFacetValues: pointer.Int(maxFacetValues),
+		// the name is highlighted whole, the brief intro as a snippet around the matches
+		HighlightFullFields: pointer.String("name"),
+		HighlightStartTag:   pointer.String(highlightStartMarker),
+		HighlightEndTag:     pointer.String(highlightEndMarker),
+		Page:                &page,
+		PerPage:             &perPage,
 		// Turn on these two parameters when project go online
 		// UseCache: pointer.True(),
 		// CacheTtl: pointer.Int(60), // 60 seconds cache
 	}
-	if len(params.Tags) > 0 {
-		filterStr := fmt.Sprintf("tags:=[%s]", strings.Join(params.Tags, ","))
+	if len(facetFields) > 0 {
+		searchParams.FacetBy = pointer.String(strings.Join(facetFields, ","))
+	}
+	if filterStr := addressFilterBy(params.Tags, params.TagFilters); filterStr != "" {
 		searchParams.FilterBy = &filterStr
 	}
 	result, err := c.Client.Collection(params.CollectionName).Documents().Search(ctx, searchParams)
@@ -209,50 +214,133 @@ func (c *TypesenseClient) SearchAddresses(
 			"collectionName", params.CollectionName,
 			"keywords", params.Keywords,
 			"tags", params.Tags,
+			"tagFilters", params.TagFilters,
+			"sort", params.Sort,
 			"page", params.Page,
 			"pageSize", params.PageSize,
+			"error", err,
 		)
-		return nil, fmt.Errorf("typesense search failed: %w", err)
+		return nil, searchError(err)
+	}
+	facetCounts := []api.FacetCounts{}
+	if result.FacetCounts != nil {
+		facetCounts = append(facetCounts, *result.FacetCounts...)
 	}
-	var records []string
+	if len(filteredCategories) > 0 {
+		counts, err := c.countFilteredFacets(ctx, params, q, filteredCategories)
+		if err != nil {
+			return nil, err
+		}
+		facetCounts = append(facetCounts, counts...)
+	}
+	var hits []string
+	records := []TypesenseAddressRecord{}
+	highlights := map[string]models.AddressHighlight{}
 	for _, hit := range *result.Hits {
 		doc := *hit.Document
-		records = append(records, doc["id"].(string))
+		id := doc["id"].(string)
+		hits = append(hits, id)
+		records = append(records, c.parseAddressRecord(id, doc))
+		if hit.Highlight != nil {
+			highlight := models.AddressHighlight{
+				Name:       highlightedText(*hit.Highlight, "name"),
+				BriefIntro: highlightedText(*hit.Highlight, "briefIntro"),
+			}
+			if highlight != (models.AddressHighlight{}) {
+				highlights[id] = highlight
+			}
+		}
 	}
 	return &SearchAddressesResult{
-		Hits:       records,
+		Hits:       hits,
+		Records:    records,
+		Highlights: highlights,
+		Facets:     parseTagFacets(&facetCounts),
 		TotalCount: int64(*result.Found),
 		Page:       int(*result.Page),
 		PageSize:   perPage,
 	}, nil
 }
 
-func (c *TypesenseClient) UpsertAddressData(
-	ctx context.Context, collectionName string, addressItem *models.AddressItem) {
-	record := c.CreateAddressRecord(addressItem)
-	_, err := c.Client.
-		Collection(collectionName).
-		Documents().
-		Upsert(ctx, record, &api.DocumentIndexParameters{})
+// IDs of the addresses whose name is closest to params.Name, typos and missing words are tolerated
+func (c *TypesenseClient) SearchSimilarNames(
+	ctx context.Context, params *SearchSimilarNamesParams) ([]string, error) {
+	_, perPage := normalizePagination(1, params.Limit)
+	searchParams := &api.SearchCollectionParams{
+		Q:        pointer.String(params.Name),
+		QueryBy:  pointer.String("name"),
+		SortBy:   pointer.String("_text_match:desc"),
+		NumTypos: pointer.String("2"),
+		// keep dropping words until enough candidates are found, "The Sherlock Holmes Museum" still finds "Sherlock Holmes Museum"
+		DropTokensThreshold: pointer.Int(perPage),
+		PerPage:             &perPage,
+	}
+	result, err := c.Client.Collection(params.CollectionName).Documents().Search(ctx, searchParams)
 	if err != nil {
-		c.logger.Warn("failed to upsert document",
-			"collectionName", collectionName,
-			"id", addressItem.ID,
+		c.logger.Error("typesense similar name search failed",
+			"collectionName", params.CollectionName,
+			"name", params.Name,
 			"error", err,
 		)
+		return nil, fmt.Errorf("typesense similar name search failed: %w", err)
+	}
+	ids := []string{}
+	for _, hit := range *result.Hits {
+		doc := *hit.Document
+		ids = append(ids, doc["id"].(string))
 	}
+	return ids, nil
 }
 
-func (c *TypesenseClient) DeleteAddressData(
-	ctx context.Context, collectionName string, addressID string) {
-	_, err := c.Client.Collection(collectionName).Document(addressID).Delete(ctx)
+// Bulk upsert into the live collection, used by the incremental sync and the outbox
+func (c *TypesenseClient) UpsertAddressRecords(
+	ctx context.Context,
+	collectionName string,
+	documents []interface{}) (*SyncDatabaseResult, error) {
+	return c.importDocuments(ctx, collectionName, documents, api.Upsert)
+}
+
+// Returns the number of documents that were removed, unknown IDs are ignored
+func (c *TypesenseClient) DeleteAddressRecords(
+	ctx context.Context, collectionName string, addressIDs []string) (int, error) {
+	deleted := 0
+	for chunk := range slices.Chunk(addressIDs, deleteFilterChunkSize) {
+		filter := fmt.Sprintf("id:[%s]", strings.Join(chunk, ","))
+		count, err := c.Client.Collection(collectionName).Documents().Delete(ctx, &api.DeleteDocumentsParams{
+			FilterBy: pointer.String(filter),
+		})
+		if err != nil {
+			c.logger.Error("failed to delete documents from typesense",
+				"collectionName", collectionName,
+				"error", err,
+			)
+			return deleted, fmt.Errorf("failed to delete documents from typesense: %w", err)
+		}
+		deleted += count
+	}
+	return deleted, nil
+}
+
+// Compares the fields of the collection behind the alias with the current schema
+func (c *TypesenseClient) IsAddressSchemaCurrent(
+	ctx context.Context, language models.Language, collectionName string) (bool, error) {
+	versionName := collectionName
+	alias, err := c.Client.Alias(collectionName).Retrieve(ctx)
+	if err == nil {
+		versionName = alias.CollectionName
+	} else if !isNotFound(err) {
+		c.logger.Error("failed to get typesense alias", "alias", collectionName, "error", err)
+		return false, fmt.Errorf("failed to get typesense alias: %w", err)
+	}
+	collection, err := c.Client.Collection(versionName).Retrieve(ctx)
+	if isNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
-		c.logger.Warn("failed to delete document",
-			"collectionName", collectionName,
-			"id", addressID,
-			"error", err,
-		)
+		c.logger.Error("failed to get typesense collection", "collectionName", versionName, "error", err)
+		return false, fmt.Errorf("failed to get typesense collection: %w", err)
 	}
+	return hasSchemaFields(collection.Fields, c.GetAddressCollectionSchema(versionName, language).Fields), nil
 }
 
 // Typesense cluster operations
@@ -303,6 +391,305 @@ func (c *TypesenseClient) GetSystemInfo(ctx context.Context) (*TypesenseSystemIn
 }
 
 // Helper functions
+func (c *TypesenseClient) importDocuments(
+	ctx context.Context,
+	collectionName string,
+	documents []interface{},
+	action api.IndexAction) (*SyncDatabaseResult, error) {
+	// directly return 0 records if no documents were found
+	if len(documents) == 0 {
+		return &SyncDatabaseResult{}, nil
+	}
+	params := &api.ImportDocumentsParams{Action: &action}
+	results, err := c.Client.Collection(collectionName).
+		Documents().
+		Import(ctx, documents, params)
+	if err != nil {
+		c.logger.Error(
+			"failed to import documents to typesense",
+			"collectionName", collectionName,
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to import documents to typesense: %w", err)
+	}
+	// Tally results
+	success := 0
+	for _, result := range results {
+		if result.Success {
+			success++
+		} else {
+			c.logger.Warn(
+				"failed to sync document to typesense",
+				"id", result.Id,
+				"error", result.Error,
+			)
+		}
+	}
+	return &SyncDatabaseResult{
+		Total:   len(results),
+		Success: success,
+		Failed:  len(results) - success,
+	}, nil
+}
+
+// A document that doesn't parse is kept with its ID only, so its address document is read instead
+func (c *TypesenseClient) parseAddressRecord(id string, doc map[string]interface{}) TypesenseAddressRecord {
+	record := TypesenseAddressRecord{}
+	data, err := json.Marshal(doc)
+	if err == nil {
+		err = json.Unmarshal(data, &record)
+	}
+	if err != nil {
+		c.logger.Warn("failed to parse typesense document", "id", id, "error", err)
+		return TypesenseAddressRecord{ID: id}
+	}
+	return record
+}
+
+// The highlighted field of a hit, the full value when it is highlighted whole, else the snippet.
+// Empty when none of the keywords matched the field
+func highlightedText(highlight map[string]interface{}, field string) string {
+	entry, ok := highlight[field].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if tokens, _ := entry["matched_tokens"].([]interface{}); len(tokens) == 0 {
+		return ""
+	}
+	if value, ok := entry["value"].(string); ok && value != "" {
+		return escapeHighlight(value)
+	}
+	snippet, _ := entry["snippet"].(string)
+	return escapeHighlight(snippet)
+}
+
+// Facets of the filtered categories in one multi search, each counted with the filters of the other categories
+func (c *TypesenseClient) countFilteredFacets(
+	ctx context.Context,
+	params *SearchAddressesParams,
+	q string,
+	categories []models.TagCategory) ([]api.FacetCounts, error) {
+	searches := make([]api.MultiSearchCollectionParameters, len(categories))
+	for i, category := range categories {
+		searches[i] = api.MultiSearchCollectionParameters{
+			Collection:     pointer.String(params.CollectionName),
+			Q:              pointer.String(q),
+			QueryBy:        pointer.String("name,briefIntro"),
+			FacetBy:        pointer.String(tagFacetField(category)),
+			MaxFacetValues: pointer.Int(maxFacetValues),
+			PerPage:        pointer.Int(0),
+		}
+		filterStr := addressFilterBy(params.Tags, withoutCategoryFilter(params.TagFilters, category))
+		if filterStr != "" {
+			searches[i].FilterBy = &filterStr
+		}
+	}
+	result, err := c.Client.MultiSearch.Perform(
+		ctx, &api.MultiSearchParams{}, api.MultiSearchSearchesParameter{Searches: searches})
+	if err != nil {
+		c.logger.Error("typesense facet search failed", "collectionName", params.CollectionName, "error", err)
+		return nil, searchError(err)
+	}
+	facetCounts := []api.FacetCounts{}
+	for _, item := range result.Results {
+		if item.Error != nil {
+			c.logger.Error("typesense facet search failed", "collectionName", params.CollectionName, "error", *item.Error)
+			err := fmt.Errorf("typesense facet search failed: %s", *item.Error)
+			if item.Code != nil && *item.Code == http.StatusBadRequest {
+				err = fmt.Errorf("%w: %s", ErrInvalidSearchQuery, *item.Error)
+			}
+			return nil, err
+		}
+		if item.FacetCounts != nil {
+			facetCounts = append(facetCounts, *item.FacetCounts...)
+		}
+	}
+	return facetCounts, nil
+}
+
+// A query Typesense rejects as invalid, like a sort on a field the index doesn't have yet, is not a server error
+func searchError(err error) error {
+	var httpErr *typesense.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status == http.StatusBadRequest {
+		return fmt.Errorf("%w: %w", ErrInvalidSearchQuery, err)
+	}
+	return fmt.Errorf("typesense search failed: %w", err)
+}
+
+// Every expected field exists with the same type and is faceted and sortable when it has to be.
+// The id is implicit, Typesense doesn't list it
+func hasSchemaFields(actual []api.Field, expected []api.Field) bool {
+	isSet := func(flag *bool) bool { return flag != nil && *flag }
+	for _, field := range expected {
+		if field.Name == "id" {
+			continue
+		}
+		index := slices.IndexFunc(actual, func(f api.Field) bool { return f.Name == field.Name })
+		if index < 0 {
+			return false
+		}
+		current := actual[index]
+		if current.Type != field.Type ||
+			(isSet(field.Facet) && !isSet(current.Facet)) ||
+			(isSet(field.Sort) && !isSet(current.Sort)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sort clause of the order, ties are broken by the update time
+func addressSortBy(sort models.AddressSort) string {
+	switch sort {
+	case models.AddressSortRelevance:
+		return "_text_match:desc,updatedAt:desc"
+	case models.AddressSortName:
+		return "name:asc,updatedAt:desc"
+	}
+	return "updatedAt:desc"
+}
+
+// Filter clause matching any of the tags and any tag of each filtered category
+func addressFilterBy(tags []string, tagFilters map[models.TagCategory][]string) string {
+	clauses := []string{}
+	if len(tags) > 0 {
+		clauses = append(clauses, fmt.Sprintf("tags:=[%s]", filterValues(tags)))
+	}
+	for _, category := range models.TagCategories {
+		if values := tagFilters[category]; len(values) > 0 {
+			clauses = append(clauses, fmt.Sprintf("%s:=[%s]", tagFacetField(category), filterValues(values)))
+		}
+	}
+	return strings.Join(clauses, " && ")
+}
+
+// Backtick quoted values, so commas and spaces in tags don't split them.
+// Typesense can't escape a backtick inside a value, it is dropped
+func filterValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "`" + strings.ReplaceAll(value, "`", "") + "`"
+	}
+	return strings.Join(quoted, ",")
+}
+
+// One facet per tag category in the category order, a category Typesense didn't count is empty
+func parseTagFacets(facetCounts *[]api.FacetCounts) []models.TagFacet {
+	counted := map[string][]models.TagFacetCount{}
+	if facetCounts != nil {
+		for _, facet := range *facetCounts {
+			if facet.FieldName == nil || facet.Counts == nil {
+				continue
+			}
+			counts := []models.TagFacetCount{}
+			for _, count := range *facet.Counts {
+				if count.Value == nil || count.Count == nil {
+					continue
+				}
+				counts = append(counts, models.TagFacetCount{Value: *count.Value, Count: *count.Count})
+			}
+			counted[*facet.FieldName] = counts
+		}
+	}
+	facets := make([]models.TagFacet, len(models.TagCategories))
+	for i, category := range models.TagCategories {
+		counts := counted[tagFacetField(category)]
+		if counts == nil {
+			counts = []models.TagFacetCount{}
+		}
+		facets[i] = models.TagFacet{Category: category, Counts: counts}
+	}
+	return facets
+}
+
+// Point the alias to the new version, the switch is atomic on the Typesense side
+func (c *TypesenseClient) swapAlias(ctx context.Context, aliasName string, versionName string) error {
+	_, err := c.Client.Alias(aliasName).Retrieve(ctx)
+	if isNotFound(err) {
+		// the index predates aliases and still lives in a plain collection with the alias name,
+		// it has to go before the alias can take over the name
+		if _, err := c.Client.Collection(aliasName).Delete(ctx); err != nil && !isNotFound(err) {
+			c.logger.Error("failed to delete legacy typesense collection",
+				"collectionName", aliasName,
+				"error", err,
+			)
+			return fmt.Errorf("failed to delete legacy typesense collection: %w", err)
+		}
+	} else if err != nil {
+		c.logger.Error("failed to get typesense alias", "alias", aliasName, "error", err)
+		return fmt.Errorf("failed to get typesense alias: %w", err)
+	}
+	_, err = c.Client.Aliases().Upsert(ctx, aliasName, &api.CollectionAliasSchema{CollectionName: versionName})
+	if err != nil {
+		c.logger.Error("failed to point typesense alias to the new collection",
+			"alias", aliasName,
+			"collectionName", versionName,
+			"error", err,
+		)
+		return fmt.Errorf("failed to update typesense alias: %w", err)
+	}
+	return nil
+}
+
+// Failures only leave unused collections behind, the next sync tries again.
+// Versions newer than the current one are kept, a concurrent sync may still be importing into them
+func (c *TypesenseClient) dropOldVersions(ctx context.Context, aliasName string, currentVersion string) {
+	collections, err := c.Client.Collections().Retrieve(ctx, &api.GetCollectionsParams{
+		ExcludeFields: pointer.String("fields"),
+	})
+	if err != nil {
+		c.logger.Warn("failed to list typesense collections", "alias", aliasName, "error", err)
+		return
+	}
+	for _, collection := range collections {
+		if isOlderCollectionVersion(aliasName, collection.Name, currentVersion) {
+			c.dropCollection(collection.Name)
+		}
+	}
+}
+
+// Runs without the request context, cleanup must happen even when the sync was canceled
+func (c *TypesenseClient) dropCollection(collectionName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.Client.Collection(collectionName).Delete(ctx); err != nil && !isNotFound(err) {
+		c.logger.Warn("failed to delete typesense collection",
+			"collectionName", collectionName,
+			"error", err,
+		)
+	}
+}
+
+// Versions are named <alias>_<unix millis>
+func isCollectionVersion(aliasName string, collectionName string) bool {
+	_, ok := collectionVersion(aliasName, collectionName)
+	return ok
+}
+
+// A version created after the current one belongs to a sync still running, possibly on another instance
+func isOlderCollectionVersion(aliasName string, collectionName string, currentVersion string) bool {
+	version, ok := collectionVersion(aliasName, collectionName)
+	if !ok {
+		return false
+	}
+	current, ok := collectionVersion(aliasName, currentVersion)
+	return ok && version < current
+}
+
+func collectionVersion(aliasName string, collectionName string) (int64, bool) {
+	suffix, ok := strings.CutPrefix(collectionName, aliasName+"_")
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(suffix, 10, 64)
+	return version, err == nil
+}
+
+func isNotFound(err error) bool {
+	var httpErr *typesense.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Status == 404
+}
+
 func stringToFloat32(value string) float32 {
 	if f, err := strconv.ParseFloat(value, 32); err == nil {
 		return float32(f)
@@ -0,0 +1,248 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+// Minimal Typesense server that records the calls made during a sync
+type fakeTypesense struct {
+	mu          sync.Mutex
+	calls       []string
+	aliasExists bool
+	failImport  bool
+	rejectQuery bool
+}
+
+func (f *fakeTypesense) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.calls = append(f.calls, r.Method+" "+r.URL.Path)
+	f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/collections":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": body["name"], "fields": []interface{}{}})
+	case strings.HasSuffix(r.URL.Path, "/documents/import"):
+		if f.failImport {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, `{"success":true}`+"\n"+`{"success":false,"error":"bad","id":"b"}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/collections/addresses_en/documents/search" && f.rejectQuery:
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"message":"Could not find a field named `+"`name`"+` in the schema for sorting."}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/multi_search":
+		io.WriteString(w, `{"results":[{"found":3,"hits":[],
+			"facet_counts":[{"field_name":"roleTags","counts":[{"count":2,"value":"Writer"},{"count":1,"value":"Poet"}]}]}]}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/collections/addresses_en/documents/search":
+		io.WriteString(w, `{"found":2,"page":1,"hits":[
+			{"document":{"id":"a","name":"Baker Street","briefIntro":"Home of a detective","createdAt":1,"updatedAt":2,
+				"address":{"city":"London","country":"UK","line1":"221B Baker Street","region":"London"},"recordVersion":1},
+			 "highlight":{"name":{"matched_tokens":["Baker"],"snippet":"\u0002Baker\u0003 Street","value":"\u0002Baker\u0003 Street"},
+				"briefIntro":{"matched_tokens":[],"snippet":"Home of a detective"}}},
+			{"document":{"id":"b","name":"Baker's Row","updatedAt":1},
+			 "highlight":{"name":{"matched_tokens":["Baker"],"snippet":"\u0002Baker\u0003's <Row>"}}}]}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/aliases/addresses_en":
+		if !f.aliasExists {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"Not Found"}`)
+			return
+		}
+		io.WriteString(w, `{"name":"addresses_en","collection_name":"addresses_en_1"}`)
+	case r.Method == http.MethodPut && r.URL.Path == "/aliases/addresses_en":
+		io.WriteString(w, `{"name":"addresses_en","collection_name":"new"}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/collections":
+		io.WriteString(w, `[{"name":"addresses_en_1"},{"name":"addresses_en_old"},{"name":"addresses_zh_2"},`+
+			`{"name":"addresses_en_99999999999999"}]`)
+	case r.Method == http.MethodDelete && r.URL.Path == "/collections/addresses_en":
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"message":"Not Found"}`)
+	case r.Method == http.MethodDelete:
+		io.WriteString(w, `{"name":"deleted"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeTypesense) hasCall(call string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Contains(f.calls, call)
+}
+
+func newFakeTypesenseClient(t *testing.T, fake *fakeTypesense) *TypesenseClient {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return &TypesenseClient{
+		Client: typesense.NewClient(typesense.WithServer(server.URL), typesense.WithAPIKey("test")),
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func TestTypesenseSyncAddressDatabase_SwapsAlias(t *testing.T) {
+	t.Parallel()
+	fake := &fakeTypesense{aliasExists: true}
+	client := newFakeTypesenseClient(t, fake)
+	documents := []interface{}{TypesenseAddressRecord{ID: "a"}, TypesenseAddressRecord{ID: "b"}}
+	result, err := client.SyncAddressDatabase(context.Background(), "en", "addresses_en", documents)
+	assert.NoError(t, err)
+	assert.Equal(t, &SyncDatabaseResult{Total: 2, Success: 1, Failed: 1}, result)
+	assert.True(t, fake.hasCall("POST /collections"))
+	assert.True(t, fake.hasCall("PUT /aliases/addresses_en"))
+	// only the previous version is dropped, the live alias target is never deleted before the swap
+	assert.True(t, fake.hasCall("DELETE /collections/addresses_en_1"))
+	assert.False(t, fake.hasCall("DELETE /collections/addresses_en_old"))
+	assert.False(t, fake.hasCall("DELETE /collections/addresses_zh_2"))
+	assert.False(t, fake.hasCall("DELETE /collections/addresses_en"))
+	// a newer version belongs to a sync that is still running
+	assert.False(t, fake.hasCall("DELETE /collections/addresses_en_99999999999999"))
+}
+
+func TestTypesenseSyncAddressDatabase_MigratesLegacyCollection(t *testing.T) {
+	t.Parallel()
+	fake := &fakeTypesense{aliasExists: false}
+	client := newFakeTypesenseClient(t, fake)
+	_, err := client.SyncAddressDatabase(context.Background(), "en", "addresses_en", nil)
+	assert.NoError(t, err)
+	assert.True(t, fake.hasCall("DELETE /collections/addresses_en"))
+	assert.True(t, fake.hasCall("PUT /aliases/addresses_en"))
+}
+
+func TestTypesenseSyncAddressDatabase_ImportFailureKeepsAlias(t *testing.T) {
+	t.Parallel()
+	fake := &fakeTypesense{aliasExists: true, failImport: true}
+	client := newFakeTypesenseClient(t, fake)
+	documents := []interface{}{TypesenseAddressRecord{ID: "a"}}
+	_, err := client.SyncAddressDatabase(context.Background(), "en", "addresses_en", documents)
+	assert.Error(t, err)
+	assert.False(t, fake.hasCall("PUT /aliases/addresses_en"))
+	assert.False(t, fake.hasCall("DELETE /collections/addresses_en_1"))
+	assert.False(t, fake.hasCall("GET /collections"))
+}
+
+func TestIsCollectionVersion(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isCollectionVersion("addresses_en", "addresses_en_1718000000000"))
+	assert.False(t, isCollectionVersion("addresses_en", "addresses_en"))
+	assert.False(t, isCollectionVersion("addresses_en", "addresses_en_backup"))
+	assert.False(t, isCollectionVersion("addresses_en", "addresses_zh_1718000000000"))
+	assert.True(t, isOlderCollectionVersion("addresses_en", "addresses_en_1", "addresses_en_2"))
+	assert.False(t, isOlderCollectionVersion("addresses_en", "addresses_en_2", "addresses_en_2"))
+	assert.False(t, isOlderCollectionVersion("addresses_en", "addresses_en_3", "addresses_en_2"))
+	assert.False(t, isOlderCollectionVersion("addresses_en", "addresses_en_backup", "addresses_en_2"))
+}
+
+func TestAddressFilterBy(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", addressFilterBy(nil, nil))
+	assert.Equal(t, "tags:=[`uk`,`writer`]", addressFilterBy([]string{"uk", "writer"}, nil))
+	filter := addressFilterBy([]string{"featured"}, map[models.TagCategory][]string{
+		models.TagCategoryRole:    {"Writer", "Poet"},
+		models.TagCategoryCountry: {"United Kingdom", "Korea, South"},
+		models.TagCategoryFigure:  {},
+	})
+	assert.Equal(t, "tags:=[`featured`] && countryTags:=[`United Kingdom`,`Korea, South`] && roleTags:=[`Writer`,`Poet`]", filter)
+}
+
+func TestParseTagFacets(t *testing.T) {
+	t.Parallel()
+	var facetCounts []api.FacetCounts
+	err := json.Unmarshal([]byte(`[{"field_name":"roleTags","counts":[{"count":3,"value":"Writer"}]}]`), &facetCounts)
+	assert.NoError(t, err)
+	facets := parseTagFacets(&facetCounts)
+	assert.Equal(t, []models.TagFacet{
+		{Category: models.TagCategoryCountry, Counts: []models.TagFacetCount{}},
+		{Category: models.TagCategoryRole, Counts: []models.TagFacetCount{{Value: "Writer", Count: 3}}},
+		{Category: models.TagCategoryFigure, Counts: []models.TagFacetCount{}},
+	}, facets)
+}
+
+func TestTypesenseSearchAddresses_HighlightsAndRecords(t *testing.T) {
+	t.Parallel()
+	client := newFakeTypesenseClient(t, &fakeTypesense{})
+	result, err := client.SearchAddresses(context.Background(), &SearchAddressesParams{
+		CollectionName: "addresses_en",
+		Keywords:       "baker",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result.Hits)
+	assert.Equal(t, map[string]models.AddressHighlight{
+		"a": {Name: "<mark>Baker</mark> Street"},
+		"b": {Name: "<mark>Baker</mark>&#39;s &lt;Row&gt;"},
+	}, result.Highlights)
+	address, ok := result.Records[0].ToAddressItem()
+	assert.True(t, ok)
+	assert.Equal(t, models.AddressItem{
+		ID:         "a",
+		Name:       "Baker Street",
+		BriefIntro: "Home of a detective",
+		CreatedAt:  1,
+		UpdatedAt:  2,
+		Address:    models.Address{City: "London", Country: "UK", Line1: "221B Baker Street", Region: "London"},
+	}, address)
+	// indexed before the list view fields, the address document has to be read
+	_, ok = result.Records[1].ToAddressItem()
+	assert.False(t, ok)
+}
+
+func TestTypesenseSearchAddresses_CountsFilteredCategoriesApart(t *testing.T) {
+	t.Parallel()
+	fake := &fakeTypesense{}
+	client := newFakeTypesenseClient(t, fake)
+	result, err := client.SearchAddresses(context.Background(), &SearchAddressesParams{
+		CollectionName: "addresses_en",
+		TagFilters:     map[models.TagCategory][]string{models.TagCategoryRole: {"Writer"}},
+	})
+	assert.NoError(t, err)
+	assert.True(t, fake.hasCall("POST /multi_search"))
+	assert.Equal(t, models.TagFacet{Category: models.TagCategoryRole, Counts: []models.TagFacetCount{
+		{Value: "Writer", Count: 2}, {Value: "Poet", Count: 1}}}, result.Facets[1])
+}
+
+func TestTypesenseSearchAddresses_RejectedQuery(t *testing.T) {
+	t.Parallel()
+	client := newFakeTypesenseClient(t, &fakeTypesense{rejectQuery: true})
+	_, err := client.SearchAddresses(context.Background(), &SearchAddressesParams{
+		CollectionName: "addresses_en",
+		Sort:           models.AddressSortName,
+	})
+	assert.ErrorIs(t, err, ErrInvalidSearchQuery)
+}
+
+func TestHasSchemaFields(t *testing.T) {
+	t.Parallel()
+	client := &TypesenseClient{}
+	expected := client.GetAddressCollectionSchema("addresses_en_1", models.LanguageEN).Fields
+	assert.True(t, hasSchemaFields(expected, expected))
+	// an index created before the name was sortable or the categories were faceted is outdated
+	unsortable := slices.Clone(expected)
+	for i, field := range unsortable {
+		if field.Name == "name" {
+			field.Sort = pointer.False()
+			unsortable[i] = field
+		}
+	}
+	assert.False(t, hasSchemaFields(unsortable, expected))
+	withoutFacets := slices.DeleteFunc(slices.Clone(expected), func(field api.Field) bool {
+		return field.Name == tagFacetField(models.TagCategoryRole)
+	})
+	assert.False(t, hasSchemaFields(withoutFacets, expected))
+}
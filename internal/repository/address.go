@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
@@ -12,24 +13,33 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
-	addressTablePrefix  = "addresses"
-	tagsTablePrefix     = "tags"
-	getByNameLimit      = 10
-	tagsSimilarityLimit = 0.6
+	addressTablePrefix   = "addresses"
+	tombstoneTablePrefix = "address_tombstones"
+	aliasTablePrefix     = "address_aliases"
+	tagsTablePrefix      = "tags"
+	syncStateTable       = "search_sync_state"
+	syncLockTable        = "search_sync_locks"
+	getByNameLimit       = 10
+	tagsSimilarityLimit  = 0.6
+	// re-read changes slightly older than the watermark, writes that were committed late are not missed
+	incrementalSyncOverlap = time.Minute
+	// a sync renews its lock well before it expires, the lock of a crashed instance frees up on its own
+	syncLockTTL     = 5 * time.Minute
+	syncLockRenewal = time.Minute
 )
 
-var tagCategories = []string{"country", "role", "figure"}
-
 type AddressRepository struct {
 	client    *firestore.Client
-	typesense *infra.TypesenseClient
+	typesense infra.SearchClient
 	logger    *slog.Logger
 }
 
-func NewAddressRepository(client *firestore.Client, typesense *infra.TypesenseClient, logger *slog.Logger) *AddressRepository {
+func NewAddressRepository(client *firestore.Client, typesense infra.SearchClient, logger *slog.Logger) *AddressRepository {
 	return &AddressRepository{
 		client:    client,
 		typesense: typesense,
@@ -38,15 +48,22 @@ func NewAddressRepository(client *firestore.Client, typesense *infra.TypesenseCl
 }
 
 type GetAddressesOptions struct {
-	Language models.Language
-	Keywords string
-	Tags     []string
-	PageSize int
-	Page     int
+	Language   models.Language
+	Keywords   string
+	Tags       []string
+	TagFilters map[models.TagCategory][]string // any tag within a category, every category has to match
+	Sort       models.AddressSort
+	// serve the addresses from the search index when it has the list view fields,
+	// they can lag behind the address documents so it is left off where they get edited
+	FromIndex bool
+	PageSize  int
+	Page      int
 }
 
 type GetAddressesResponse struct {
 	Addresses  []models.AddressItem
+	Highlights map[string]models.AddressHighlight // by address ID
+	Facets     []models.TagFacet
 	TotalCount int64
 	Page       int
 	TotalPages int
@@ -54,6 +71,7 @@ type GetAddressesResponse struct {
 
 type RefreshTagsOption struct {
 	Language models.Language
+	Progress ProgressFunc // optional
 }
 
 type GetAllTagsOption struct {
@@ -74,35 +92,82 @@ type UpdateAddressOption struct {
 	Language    models.Language
 	ID          string
 	AddressItem models.AddressItem
+	Actor       string // admin UID recorded in the revision
+	// updatedAt of the version the change is based on, the update fails with ErrConflict when it is stale
+	ExpectedUpdatedAt int64
 }
 
 type DeleteAddressOption struct {
 	Language models.Language
 	ID       string
+	Actor    string
+}
+
+type RestoreAddressOption struct {
+	Language models.Language
+	ID       string
+	Actor    string
+}
+
+type ListDeletedAddressesOption struct {
+	Language models.Language
+	Limit    int
+}
+
+type PurgeDeletedAddressesOption struct {
+	Language      models.Language
+	DeletedBefore int64
 }
 
 type CreateNewAddressOption struct {
 	Language    models.Language
 	AddressItem models.AddressItem
+	Actor       string
 }
 
 type SyncToTypesenseOption struct {
 	Language models.Language
+	// rebuild the whole index, the sync is also full when the language was never synced before
+	Full     bool
+	Progress ProgressFunc // optional
+}
+
+type StreamAddressesOption struct {
+	Language     models.Language
+	Tags         []string // keep addresses with any of the tags
+	UpdatedSince int64    // keep addresses updated after this time, 0 for all
+}
+
+type SyncAddressToSearchOptions struct {
+	Language models.Language
+	ID       string
+	Taxonomy *models.TagTaxonomy // categorizes the tags, loaded when nil
 }
 
 type SyncToTypesenseResult struct {
+	Mode    models.SearchSyncMode
 	Total   int
 	Success int
 	Failed  int
+	Deleted int
 }
 
-// Get All addresses from the repository
+// Held by the sync of one language while it runs
+type searchSyncLock struct {
+	Owner     string `firestore:"owner"`
+	ExpiresAt int64  `firestore:"expiresAt"`
+}
+
+// Get All addresses from the repository. With FromIndex the addresses come from the search index,
+// unless some hit was indexed before the index had every list view field
 func (r *AddressRepository) GetAddresses(ctx context.Context, opts GetAddressesOptions) (*GetAddressesResponse, error) {
 	collectionName := getAddressCollectionName(opts.Language)
 	searchParams := &infra.SearchAddressesParams{
 		CollectionName: collectionName,
 		Keywords:       opts.Keywords,
 		Tags:           opts.Tags,
+		TagFilters:     opts.TagFilters,
+		Sort:           opts.Sort,
 		PageSize:       opts.PageSize,
 		Page:           opts.Page,
 	}
@@ -113,9 +178,14 @@ func (r *AddressRepository) GetAddresses(ctx context.Context, opts GetAddressesO
 			"collectionName", collectionName,
 			"error", err,
 		)
-		return nil, fmt.Errorf("failed to get search addresses through Typesense")
+		return nil, fmt.Errorf("failed to get search addresses through Typesense: %w", err)
+	}
+	addresses, ok := indexedAddresses(result.Records)
+	if opts.FromIndex && ok {
+		addresses, err = r.withoutDeletedAddresses(ctx, opts.Language, addresses)
+	} else {
+		addresses, _, err = r.batchFetchAddresses(ctx, collectionName, result.Hits)
 	}
-	addresses, _, err := r.batchFetchAddresses(ctx, collectionName, result.Hits)
 	if err != nil {
 		return nil, err
 	}
@@ -123,13 +193,15 @@ func (r *AddressRepository) GetAddresses(ctx context.Context, opts GetAddressesO
 	guardedPageSize := math.Max(float64(result.PageSize), 1.0)
 	return &GetAddressesResponse{
 		Addresses:  addresses,
+		Highlights: result.Highlights,
+		Facets:     result.Facets,
 		TotalCount: result.TotalCount,
 		Page:       result.Page,
 		TotalPages: int(math.Ceil(float64(result.TotalCount) / guardedPageSize)),
 	}, nil
 }
 
-// Get a address by ID
+// Get addresses by ID, the ID of a merged address resolves to the address it was merged into
 func (r *AddressRepository) GetAddressesByIDs(
 	ctx context.Context,
 	opts *GetAddressesByIDsOptions) (*GetAddressesByIDsResponse, error) {
@@ -138,104 +210,333 @@ func (r *AddressRepository) GetAddressesByIDs(
 	if err != nil {
 		return nil, err
 	}
+	found := make(map[string]models.AddressItem, len(addresses))
+	for _, address := range addresses {
+		found[address.ID] = address
+	}
+	// IDs of merged addresses resolve to the address they were merged into
+	missing := slices.DeleteFunc(slices.Clone(opts.IDs), func(id string) bool {
+		_, ok := found[id]
+		return ok
+	})
+	aliases, err := r.getAddressAliases(ctx, opts.Language, missing)
+	if err != nil {
+		r.logger.Error("failed to get address aliases", "collectionName", collectionName, "error", err)
+		return nil, fmt.Errorf("failed to get address aliases: %w", err)
+	}
+	if len(aliases) == 0 {
+		return &GetAddressesByIDsResponse{
+			Addresses:  addresses,
+			InvalidIDs: invalidIDs,
+		}, nil
+	}
+	targetIDs := []string{}
+	for _, targetID := range aliases {
+		if _, ok := found[targetID]; !ok && !slices.Contains(targetIDs, targetID) {
+			targetIDs = append(targetIDs, targetID)
+		}
+	}
+	targets, _, err := r.batchFetchAddresses(ctx, collectionName, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range targets {
+		found[address.ID] = address
+	}
+	resolved := make([]models.AddressItem, 0, len(opts.IDs))
+	for _, id := range opts.IDs {
+		if targetID, ok := aliases[id]; ok {
+			id = targetID
+		}
+		address, ok := found[id]
+		if !ok {
+			continue
+		}
+		// an address saved under its old and its new ID is returned once
+		delete(found, id)
+		resolved = append(resolved, address)
+	}
 	return &GetAddressesByIDsResponse{
-		Addresses:  addresses,
-		InvalidIDs: invalidIDs,
+		Addresses: resolved,
+		InvalidIDs: slices.DeleteFunc(invalidIDs, func(id string) bool {
+			_, ok := aliases[id]
+			return ok
+		}),
 	}, nil
 }
 
 func (r *AddressRepository) UpdateAddress(ctx context.Context, opts UpdateAddressOption) (*models.AddressItem, error) {
 	collectionName := getAddressCollectionName(opts.Language)
 	docRef := r.client.Collection(collectionName).Doc(opts.ID)
-	// ensure address exists and retrieve existing data
-	doc, err := docRef.Get(ctx)
-	if err != nil {
-		r.logger.Error("failed to get address document dor update", "addressID", opts.ID, "error", err)
-		return nil, fmt.Errorf("failed to get address with ID %s for update: %w", opts.ID, err)
-	}
-	var existingAddress models.AddressItem
-	if err := doc.DataTo(&existingAddress); err != nil {
-		r.logger.Error("failed to parse existing address document for update", "addressID", opts.ID, "error", err)
-		return nil, fmt.Errorf("failed to parse existing address data with ID %s: %w", opts.ID, err)
-	}
-
 	addressItem := opts.AddressItem
-	addressItem.CreatedAt = existingAddress.CreatedAt
-	addressItem.UpdatedAt = time.Now().UnixMilli() // update timestamp
-	addressItem.ID = opts.ID                       // avoid this value been modified by admin user
-
-	_, err = docRef.Set(ctx, addressItem)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// ensure address exists and retrieve existing data
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("address %s: %w", opts.ID, ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get address with ID %s for update: %w", opts.ID, err)
+		}
+		var existingAddress models.AddressItem
+		if err := doc.DataTo(&existingAddress); err != nil {
+			return fmt.Errorf("failed to parse existing address data with ID %s: %w", opts.ID, err)
+		}
+		// addresses in the trash have to be restored before they can be edited
+		if existingAddress.IsDeleted() {
+			return fmt.Errorf("address %s is in the trash: %w", opts.ID, ErrNotFound)
+		}
+		if existingAddress.UpdatedAt != opts.ExpectedUpdatedAt {
+			return &AddressConflictError{Current: existingAddress}
+		}
+		addressItem.CreatedAt = existingAddress.CreatedAt
+		addressItem.UpdatedAt = time.Now().UnixMilli() // update timestamp
+		addressItem.ID = opts.ID                       // avoid this value been modified by admin user
+		addressItem.DeletedAt = 0
+		addressItem.Revision = existingAddress.Revision + 1
+		addressItem.MergedInto = ""
+		addressItem.TranslationGroupID = existingAddress.TranslationGroupID // changed by linking only
+		addressItem.UpdateDuplicateKeys()
+		if err := tx.Set(docRef, addressItem); err != nil {
+			return err
+		}
+		err = setAddressRevision(tx, docRef, existingAddress, addressItem, models.RevisionActionUpdate, opts.Actor)
+		if err != nil {
+			return err
+		}
+		return setOutboxEntry(r.client, tx, opts.Language, opts.ID, models.OutboxOperationUpsert)
+	})
 	if err != nil {
 		r.logger.Error("failed to update address", "addressID", opts.ID, "error", err)
 		return nil, fmt.Errorf("failed to update address with ID %s: %w", opts.ID, err)
 	}
-	r.typesense.UpsertAddressData(ctx, collectionName, &addressItem)
 	return &addressItem, nil
 }
 
+// Move an address to the trash, it stays restorable until PurgeDeletedAddresses removes it
 func (r *AddressRepository) DeleteAddress(ctx context.Context, opts DeleteAddressOption) (string, error) {
 	collectionName := getAddressCollectionName(opts.Language)
 	docRef := r.client.Collection(collectionName).Doc(opts.ID)
-	_, err := docRef.Delete(ctx)
+	tombstoneRef := r.client.Collection(getTombstoneCollectionName(opts.Language)).Doc(opts.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		address, err := r.getAddressInTransaction(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if address.IsDeleted() {
+			return nil // already in the trash
+		}
+		now := time.Now().UnixMilli()
+		deleted := *address
+		deleted.DeletedAt = now
+		deleted.UpdatedAt = now
+		deleted.Revision = address.Revision + 1
+		err = tx.Update(docRef, []firestore.Update{
+			{Path: "deletedAt", Value: deleted.DeletedAt},
+			{Path: "updatedAt", Value: deleted.UpdatedAt},
+			{Path: "revision", Value: deleted.Revision},
+		})
+		if err != nil {
+			return err
+		}
+		err = setAddressRevision(tx, docRef, *address, deleted, models.RevisionActionDelete, opts.Actor)
+		if err != nil {
+			return err
+		}
+		err = tx.Set(tombstoneRef, models.AddressTombstone{
+			ID:        opts.ID,
+			DeletedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+		return setOutboxEntry(r.client, tx, opts.Language, opts.ID, models.OutboxOperationDelete)
+	})
 	if err != nil {
 		r.logger.Error("failed to delete address", "addressID", opts.ID, "error", err)
 		return "", fmt.Errorf("failed to delete address with ID %s: %w", opts.ID, err)
 	}
-	r.typesense.DeleteAddressData(ctx, collectionName, opts.ID)
 	return opts.ID, nil
 }
 
-// Create a new address
-func (r *AddressRepository) CreateNewAddress(ctx context.Context, opts CreateNewAddressOption) (string, error) {
+// Take an address out of the trash and put it back into search
+func (r *AddressRepository) RestoreAddress(ctx context.Context, opts RestoreAddressOption) (*models.AddressItem, error) {
 	collectionName := getAddressCollectionName(opts.Language)
-	// first check if there exists data with the same name
-	query := r.client.Collection(collectionName).Where("name", "==", opts.AddressItem.Name).Limit(getByNameLimit)
-	iter := query.Documents(ctx)
-	defer iter.Stop()
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
+	docRef := r.client.Collection(collectionName).Doc(opts.ID)
+	tombstoneRef := r.client.Collection(getTombstoneCollectionName(opts.Language)).Doc(opts.ID)
+	aliasRef := r.client.Collection(getAliasCollectionName(opts.Language)).Doc(opts.ID)
+	var restored models.AddressItem
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		address, err := r.getAddressInTransaction(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if !address.IsDeleted() {
+			return fmt.Errorf("address %s: %w", opts.ID, ErrNotInTrash)
+		}
+		before := *address
+		address.DeletedAt = 0
+		address.MergedInto = ""
+		address.UpdatedAt = time.Now().UnixMilli()
+		address.Revision++
+		address.UpdateDuplicateKeys()
+		if err := tx.Set(docRef, *address); err != nil {
+			return err
 		}
+		err = setAddressRevision(tx, docRef, before, *address, models.RevisionActionRestore, opts.Actor)
 		if err != nil {
-			r.logger.Error("failed to check for duplicate records", "error", err)
-			return "", fmt.Errorf("failed to check for duplicate records: %w", err)
+			return err
 		}
-		var existingAddress models.AddressItem
-		if err := doc.DataTo(&existingAddress); err != nil {
-			r.logger.Warn("failed to parse existing address", "docID", doc.Ref.ID, "error", err)
+		// the incremental sync would otherwise remove the restored address again
+		if err := tx.Delete(tombstoneRef); err != nil {
+			return err
+		}
+		// a restored merged address answers for its own ID again
+		if err := tx.Delete(aliasRef); err != nil {
+			return err
+		}
+		restored = *address
+		return setOutboxEntry(r.client, tx, opts.Language, opts.ID, models.OutboxOperationUpsert)
+	})
+	if err != nil {
+		r.logger.Error("failed to restore address", "addressID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to restore address with ID %s: %w", opts.ID, err)
+	}
+	return &restored, nil
+}
+
+// Addresses in the trash, most recently deleted first
+func (r *AddressRepository) ListDeletedAddresses(
+	ctx context.Context, opts ListDeletedAddressesOption) ([]models.AddressItem, error) {
+	collectionName := getAddressCollectionName(opts.Language)
+	docs, err := r.client.Collection(collectionName).
+		Where("deletedAt", ">", 0).
+		OrderBy("deletedAt", firestore.Desc).
+		Limit(opts.Limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to list deleted addresses", "collectionName", collectionName, "error", err)
+		return nil, fmt.Errorf("failed to list deleted addresses: %w", err)
+	}
+	addresses := make([]models.AddressItem, 0, len(docs))
+	for _, doc := range docs {
+		var address models.AddressItem
+		if err := doc.DataTo(&address); err != nil {
+			r.logger.Warn("failed to parse deleted address", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// Permanently remove the addresses that went to the trash before the given time,
+// returns how many were removed
+func (r *AddressRepository) PurgeDeletedAddresses(ctx context.Context, opts PurgeDeletedAddressesOption) (int, error) {
+	collectionName := getAddressCollectionName(opts.Language)
+	docs, err := r.client.Collection(collectionName).
+		Where("deletedAt", ">", 0).
+		Where("deletedAt", "<=", opts.DeletedBefore).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to query expired deleted addresses", "collectionName", collectionName, "error", err)
+		return 0, fmt.Errorf("failed to query expired deleted addresses: %w", err)
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	bulkWriter := r.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(docs))
+	for _, doc := range docs {
+		// deleting a document leaves its subcollections behind, the revisions go first
+		revisionRefs, err := doc.Ref.Collection(revisionsSubcollection).DocumentRefs(ctx).GetAll()
+		if err != nil {
+			r.logger.Warn("failed to list revisions of deleted address", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		for _, revisionRef := range revisionRefs {
+			if _, err := bulkWriter.Delete(revisionRef); err != nil {
+				r.logger.Warn("failed to queue address revision purge", "docID", revisionRef.Path, "error", err)
+			}
+		}
+		// an address restored after the query changed its update time and is kept
+		job, err := bulkWriter.Delete(doc.Ref, firestore.LastUpdateTime(doc.UpdateTime))
+		if err != nil {
+			r.logger.Warn("failed to queue deleted address purge", "docID", doc.Ref.ID, "error", err)
 			continue
 		}
-		similarity := compareTags(opts.AddressItem.Tags, existingAddress.Tags)
-		if similarity > tagsSimilarityLimit {
-			return "", fmt.Errorf("address with name '%s' and similar tags (%.0f%% similarity) already exists", opts.AddressItem.Name, similarity*100)
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+	purged := 0
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			r.logger.Warn("failed to purge deleted address", "error", err)
+			continue
 		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Create a new address
+func (r *AddressRepository) CreateNewAddress(ctx context.Context, opts CreateNewAddressOption) (string, error) {
+	collectionName := getAddressCollectionName(opts.Language)
+	// first check that the place is not in the catalog yet
+	if err := r.checkDuplicateAddress(ctx, opts.Language, opts.AddressItem); err != nil {
+		return "", err
 	}
 	// Auto generate timestamp
 	now := time.Now().UnixMilli()
 	addressItem := opts.AddressItem
 	addressItem.CreatedAt = now
 	addressItem.UpdatedAt = now
+	addressItem.DeletedAt = 0
+	addressItem.Revision = 1
+	addressItem.MergedInto = ""
+	addressItem.UpdateDuplicateKeys()
 	// Create document with auto-generated ID
 	docRef := r.client.Collection(collectionName).NewDoc()
 	addressItem.ID = docRef.ID
-	_, err := docRef.Set(ctx, addressItem)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Set(docRef, addressItem); err != nil {
+			return err
+		}
+		err := setAddressRevision(
+			tx, docRef, models.AddressItem{}, addressItem, models.RevisionActionCreate, opts.Actor)
+		if err != nil {
+			return err
+		}
+		return setOutboxEntry(r.client, tx, opts.Language, docRef.ID, models.OutboxOperationUpsert)
+	})
 	if err != nil {
 		r.logger.Error("failed to create address", "error", err)
 		return "", fmt.Errorf("failed to create address: %w", err)
 	}
-	r.typesense.UpsertAddressData(ctx, collectionName, &addressItem)
 	return docRef.ID, nil
 }
 
+// Rebuild the tags record of the language, every tag is filed under the category of its taxonomy tag
 func (r *AddressRepository) RefreshTags(ctx context.Context, opts RefreshTagsOption) (*models.TagsRecord, error) {
+	taxonomy, err := r.getTagTaxonomy(ctx)
+	if err != nil {
+		return nil, err
+	}
 	collectionName := getAddressCollectionName(opts.Language)
 	iter := r.client.Collection(collectionName).Documents(ctx)
 	defer iter.Stop()
-	tagSet := map[string]map[string]struct{}{}
-	for _, category := range tagCategories {
+	tagSet := map[models.TagCategory]map[string]struct{}{}
+	for _, category := range models.TagCategories {
 		tagSet[category] = make(map[string]struct{})
 	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = noProgress
+	}
+	processed, failed := 0, 0
+	unknown := map[string]int{}
 	// iterate over the database
 	for {
 		doc, err := iter.Next()
@@ -249,25 +550,40 @@ func (r *AddressRepository) RefreshTags(ctx context.Context, opts RefreshTagsOpt
 		var address models.AddressItem
 		if err := doc.DataTo(&address); err != nil {
 			r.logger.Warn("failed to parse document for tags", "docID", doc.Ref.ID, "error", err)
+			processed, failed = processed+1, failed+1
+			progress(processed, failed)
+			continue
+		}
+		if address.IsDeleted() {
+			processed++
+			progress(processed, failed)
 			continue
 		}
-		for i, tag := range address.Tags {
-			if i >= len(tagCategories) {
-				r.logger.Warn("tag index exceeds tagCategories length, skipping tag", "docID", doc.Ref.ID, "tagIndex", i, "tag", tag)
-				continue
+		categorized, unknownTags := categorizeRecordTags(taxonomy, opts.Language, address.Tags)
+		for category, tags := range categorized {
+			for _, tag := range tags {
+				tagSet[category][tag] = struct{}{}
 			}
-			tagSet[tagCategories[i]][tag] = struct{}{}
 		}
+		for _, value := range unknownTags {
+			unknown[value]++
+		}
+		processed++
+		progress(processed, failed)
+	}
+	if len(unknown) > 0 {
+		r.logger.Warn("tags not in the taxonomy were skipped, run the tag migration to review them",
+			"language", opts.Language, "count", len(unknown), "tags", unknown)
 	}
 	// create unique tag set for each field
 	uniqueTagSet := make(map[string][]string)
-	for _, category := range tagCategories {
+	for _, category := range models.TagCategories {
 		uniqueTags := make([]string, 0, len(tagSet[category]))
 		for tag := range tagSet[category] {
 			uniqueTags = append(uniqueTags, tag)
 		}
 		slices.Sort(uniqueTags) // sort tag alphabetically
-		uniqueTagSet[category] = uniqueTags
+		uniqueTagSet[string(category)] = uniqueTags
 	}
 	// save data to "tag" collection
 	tagCollectionName := getTagCollectionName()
@@ -276,7 +592,7 @@ func (r *AddressRepository) RefreshTags(ctx context.Context, opts RefreshTagsOpt
 		Tags:        uniqueTagSet,
 		RefreshedAt: time.Now().UnixMilli(),
 	}
-	_, err := tagDocRef.Set(ctx, tagsRecord)
+	_, err = tagDocRef.Set(ctx, tagsRecord)
 	if err != nil {
 		r.logger.Error("failed to save tags to collection", "error", err)
 		return nil, fmt.Errorf("failed to save tags to collection: %w", err)
@@ -288,6 +604,9 @@ func (r *AddressRepository) GetAllTags(ctx context.Context, opts GetAllTagsOptio
 	collectionName := getTagCollectionName()
 	docRef := r.client.Collection(collectionName).Doc(opts.Language.Get())
 	doc, err := docRef.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("tags of %s were never refreshed: %w", opts.Language.Get(), ErrNotFound)
+	}
 	if err != nil {
 		r.logger.Error("failed to get all tags", "error", err)
 		return nil, fmt.Errorf("failed to get all tags: %w", err)
@@ -303,35 +622,151 @@ func (r *AddressRepository) GetAllTags(ctx context.Context, opts GetAllTagsOptio
 func (r *AddressRepository) SyncToTypesense(
 	ctx context.Context,
 	opts SyncToTypesenseOption) (*SyncToTypesenseResult, error) {
+	progress := opts.Progress
+	if progress == nil {
+		progress = noProgress
+	}
+	release, err := r.lockSearchSync(ctx, opts.Language)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	state, err := r.getSearchSyncState(ctx, opts.Language)
+	if err != nil {
+		return nil, err
+	}
+	// everything written before this point is covered by the run
+	startedAt := time.Now().UnixMilli()
+	var result *SyncToTypesenseResult
+	if opts.Full || state == nil {
+		result, err = r.fullSync(ctx, opts.Language, startedAt, progress)
+	} else {
+		since := state.Watermark - incrementalSyncOverlap.Milliseconds()
+		result, err = r.incrementalSync(ctx, opts.Language, since, progress)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = r.saveSearchSyncState(ctx, opts.Language, models.SearchSyncState{
+		Watermark: startedAt,
+		Mode:      result.Mode,
+		SyncedAt:  time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.purgeTombstones(ctx, opts.Language, startedAt-incrementalSyncOverlap.Milliseconds())
+	return result, nil
+}
+
+// False when the search index of the language was created before the current schema or doesn't exist yet
+func (r *AddressRepository) IsSearchSchemaCurrent(ctx context.Context, language models.Language) (bool, error) {
+	return r.typesense.IsAddressSchemaCurrent(ctx, language, getAddressCollectionName(language))
+}
+
+// Hand every address that matches the filters to yield without loading the collection into memory,
+// addresses in the trash are skipped. Streaming stops at the first error returned by yield
+func (r *AddressRepository) StreamAddresses(
+	ctx context.Context,
+	opts StreamAddressesOption,
+	yield func(models.AddressItem) error) error {
 	collectionName := getAddressCollectionName(opts.Language)
-	iter := r.client.Collection(collectionName).Documents(ctx)
+	query := r.client.Collection(collectionName).Query
+	if opts.UpdatedSince > 0 {
+		query = query.Where("updatedAt", ">", opts.UpdatedSince).OrderBy("updatedAt", firestore.Asc)
+	}
+	iter := query.Documents(ctx)
 	defer iter.Stop()
-	var documents []interface{}
 	for {
 		doc, err := iter.Next()
 		if err == iterator.Done {
-			break
+			return nil
 		}
 		if err != nil {
-			r.logger.Error("failed to iterate documents for typesense sync",
-				"collectionName", collectionName,
-				"error", err)
-			return nil, fmt.Errorf("failed to fetch documents for typesense sync: %w", err)
+			r.logger.Error("failed to iterate addresses for export", "collectionName", collectionName, "error", err)
+			return fmt.Errorf("failed to fetch addresses for export: %w", err)
 		}
 		var address models.AddressItem
 		if err := doc.DataTo(&address); err != nil {
-			r.logger.Warn(
-				"failed to parse document for typesense sync", "docID", doc.Ref.ID,
-				"error", err,
-			)
+			r.logger.Warn("failed to parse address for export", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		if address.IsDeleted() {
 			continue
 		}
-		documents = append(documents, r.typesense.CreateAddressRecord(&address))
+		if len(opts.Tags) > 0 && !slices.ContainsFunc(address.Tags, func(tag string) bool {
+			return slices.Contains(opts.Tags, tag)
+		}) {
+			continue
+		}
+		if err := yield(address); err != nil {
+			return err
+		}
+	}
+}
+
+// Bring the search index in line with the current address document,
+// the address is removed from the index when the document no longer exists or is in the trash
+func (r *AddressRepository) SyncAddressToSearch(ctx context.Context, opts SyncAddressToSearchOptions) error {
+	collectionName := getAddressCollectionName(opts.Language)
+	doc, err := r.client.Collection(collectionName).Doc(opts.ID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		if _, err := r.typesense.DeleteAddressRecords(ctx, collectionName, []string{opts.ID}); err != nil {
+			return fmt.Errorf("failed to remove address %s from search: %w", opts.ID, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get address %s for search: %w", opts.ID, err)
+	}
+	var address models.AddressItem
+	if err := doc.DataTo(&address); err != nil {
+		return fmt.Errorf("failed to parse address %s for search: %w", opts.ID, err)
+	}
+	if address.IsDeleted() {
+		if _, err := r.typesense.DeleteAddressRecords(ctx, collectionName, []string{opts.ID}); err != nil {
+			return fmt.Errorf("failed to remove address %s from search: %w", opts.ID, err)
+		}
+		return nil
+	}
+	taxonomy := opts.Taxonomy
+	if taxonomy == nil {
+		taxonomy, err = r.getTagTaxonomy(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to categorize address %s tags for search: %w", opts.ID, err)
+		}
+	}
+	documents := []interface{}{r.typesense.CreateAddressRecord(&address, taxonomy.Categorize(opts.Language, address.Tags))}
+	result, err := r.typesense.UpsertAddressRecords(ctx, collectionName, documents)
+	if err != nil {
+		return fmt.Errorf("failed to upsert address %s to search: %w", opts.ID, err)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("search engine rejected address %s", opts.ID)
+	}
+	return nil
+}
+
+// =========== Utility Methods ==========
+
+func noProgress(processed int, failed int) {}
+
+// Rebuild the index into a new collection and swap the alias. Changes the outbox applied to the old
+// collection meanwhile are queued again, so they reach the new one
+func (r *AddressRepository) fullSync(
+	ctx context.Context,
+	language models.Language,
+	startedAt int64,
+	progress ProgressFunc) (*SyncToTypesenseResult, error) {
+	collectionName := getAddressCollectionName(language)
+	iter := r.client.Collection(collectionName).Documents(ctx)
+	documents, processed, failed, err := r.collectSearchRecords(ctx, iter, language, progress)
+	if err != nil {
+		return nil, err
 	}
-	// sync with typesense
 	syncTypesenseResult, err := r.typesense.SyncAddressDatabase(
 		ctx,
-		opts.Language,
+		language,
 		collectionName,
 		documents)
 	if err != nil {
@@ -341,14 +776,364 @@ func (r *AddressRepository) SyncToTypesense(
 			"error", err)
 		return nil, fmt.Errorf("failed to sync address database with typesense: %w", err)
 	}
+	r.replaySearchChanges(ctx, language, startedAt-incrementalSyncOverlap.Milliseconds())
+	progress(processed, failed+syncTypesenseResult.Failed)
 	return &SyncToTypesenseResult{
+		Mode:    models.SearchSyncModeFull,
 		Total:   syncTypesenseResult.Total,
 		Success: syncTypesenseResult.Success,
 		Failed:  syncTypesenseResult.Failed,
 	}, nil
 }
 
-// =========== Utility Methods ==========
+// Upsert the addresses updated since the given time and remove the ones deleted since then
+func (r *AddressRepository) incrementalSync(
+	ctx context.Context,
+	language models.Language,
+	since int64,
+	progress ProgressFunc) (*SyncToTypesenseResult, error) {
+	collectionName := getAddressCollectionName(language)
+	iter := r.client.Collection(collectionName).Where("updatedAt", ">", since).Documents(ctx)
+	documents, processed, failed, err := r.collectSearchRecords(ctx, iter, language, progress)
+	if err != nil {
+		return nil, err
+	}
+	upsertResult, err := r.typesense.UpsertAddressRecords(ctx, collectionName, documents)
+	if err != nil {
+		r.logger.Error("failed to upsert updated addresses to typesense",
+			"collectionName", collectionName,
+			"error", err)
+		return nil, fmt.Errorf("failed to upsert updated addresses to typesense: %w", err)
+	}
+	deletedIDs, err := r.getTombstoneIDs(ctx, language, since)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := r.typesense.DeleteAddressRecords(ctx, collectionName, deletedIDs)
+	if err != nil {
+		r.logger.Error("failed to remove deleted addresses from typesense",
+			"collectionName", collectionName,
+			"error", err)
+		return nil, fmt.Errorf("failed to remove deleted addresses from typesense: %w", err)
+	}
+	progress(processed+len(deletedIDs), failed+upsertResult.Failed)
+	return &SyncToTypesenseResult{
+		Mode:    models.SearchSyncModeIncremental,
+		Total:   upsertResult.Total,
+		Success: upsertResult.Success,
+		Failed:  upsertResult.Failed,
+		Deleted: deleted,
+	}, nil
+}
+
+// Returns the search records with the processed and failed document counts,
+// the tags of each record are categorized by the current taxonomy
+func (r *AddressRepository) collectSearchRecords(
+	ctx context.Context,
+	iter *firestore.DocumentIterator,
+	language models.Language,
+	progress ProgressFunc) ([]interface{}, int, int, error) {
+	defer iter.Stop()
+	collectionName := getAddressCollectionName(language)
+	taxonomy, err := r.getTagTaxonomy(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	processed, failed := 0, 0
+	documents := []interface{}{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("failed to iterate documents for typesense sync",
+				"collectionName", collectionName,
+				"error", err)
+			return nil, processed, failed, fmt.Errorf("failed to fetch documents for typesense sync: %w", err)
+		}
+		var address models.AddressItem
+		if err := doc.DataTo(&address); err != nil {
+			r.logger.Warn(
+				"failed to parse document for typesense sync", "docID", doc.Ref.ID,
+				"error", err,
+			)
+			processed, failed = processed+1, failed+1
+			progress(processed, failed)
+			continue
+		}
+		processed++
+		progress(processed, failed)
+		// the tombstone written with the soft delete removes it from the index
+		if address.IsDeleted() {
+			continue
+		}
+		documents = append(documents, r.typesense.CreateAddressRecord(&address, taxonomy.Categorize(language, address.Tags)))
+	}
+	return documents, processed, failed, nil
+}
+
+// Returns nil when the language was never synced
+func (r *AddressRepository) getSearchSyncState(
+	ctx context.Context, language models.Language) (*models.SearchSyncState, error) {
+	doc, err := r.client.Collection(syncStateTable).Doc(language.Get()).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get search sync state", "language", language, "error", err)
+		return nil, fmt.Errorf("failed to get search sync state: %w", err)
+	}
+	var state models.SearchSyncState
+	if err := doc.DataTo(&state); err != nil {
+		r.logger.Error("failed to parse search sync state", "language", language, "error", err)
+		return nil, fmt.Errorf("failed to parse search sync state: %w", err)
+	}
+	return &state, nil
+}
+
+func (r *AddressRepository) saveSearchSyncState(
+	ctx context.Context, language models.Language, state models.SearchSyncState) error {
+	_, err := r.client.Collection(syncStateTable).Doc(language.Get()).Set(ctx, state)
+	if err != nil {
+		r.logger.Error("failed to save search sync state", "language", language, "error", err)
+		return fmt.Errorf("failed to save search sync state: %w", err)
+	}
+	return nil
+}
+
+// Hold the search sync of the language, another sync of it fails with ErrConflict until release is called.
+// The lock lives in Firestore so it holds across instances, it is renewed while the sync runs
+func (r *AddressRepository) lockSearchSync(ctx context.Context, language models.Language) (func(), error) {
+	docRef := r.client.Collection(syncLockTable).Doc(language.Get())
+	owner := r.client.Collection(syncLockTable).NewDoc().ID
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		lock, err := r.getSearchSyncLockInTransaction(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if lock != nil && lock.ExpiresAt > time.Now().UnixMilli() {
+			return fmt.Errorf("search sync of %s is already running: %w", language, ErrConflict)
+		}
+		return tx.Set(docRef, searchSyncLock{Owner: owner, ExpiresAt: time.Now().Add(syncLockTTL).UnixMilli()})
+	})
+	if err != nil {
+		if errors.Is(err, ErrConflict) {
+			return nil, err
+		}
+		r.logger.Error("failed to lock search sync", "language", language, "error", err)
+		return nil, fmt.Errorf("failed to lock search sync: %w", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(syncLockRenewal)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				r.updateSearchSyncLock(docRef, owner, func(tx *firestore.Transaction) error {
+					return tx.Update(docRef, []firestore.Update{
+						{Path: "expiresAt", Value: time.Now().Add(syncLockTTL).UnixMilli()},
+					})
+				})
+			}
+		}
+	}()
+	release := func() {
+		close(done)
+		r.updateSearchSyncLock(docRef, owner, func(tx *firestore.Transaction) error {
+			return tx.Delete(docRef)
+		})
+	}
+	return release, nil
+}
+
+// Runs without the request context, the lock must be released even when the sync was canceled.
+// A lock taken over by another sync after it expired is left alone
+func (r *AddressRepository) updateSearchSyncLock(
+	docRef *firestore.DocumentRef, owner string, update func(tx *firestore.Transaction) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		lock, err := r.getSearchSyncLockInTransaction(tx, docRef)
+		if err != nil || lock == nil || lock.Owner != owner {
+			return err
+		}
+		return update(tx)
+	})
+	if err != nil {
+		r.logger.Warn("failed to update search sync lock", "language", docRef.ID, "error", err)
+	}
+}
+
+// Returns nil when nobody holds the lock
+func (r *AddressRepository) getSearchSyncLockInTransaction(
+	tx *firestore.Transaction, docRef *firestore.DocumentRef) (*searchSyncLock, error) {
+	doc, err := tx.Get(docRef)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lock searchSyncLock
+	if err := doc.DataTo(&lock); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// Queue the addresses changed since the given time for the outbox, they are applied to the current index
+// on top of the rebuild. Failures are only logged, the next incremental sync picks the changes up
+func (r *AddressRepository) replaySearchChanges(ctx context.Context, language models.Language, since int64) {
+	docs, err := r.client.Collection(getAddressCollectionName(language)).
+		Where("updatedAt", ">", since).
+		Select().
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Warn("failed to query addresses changed during the rebuild", "language", language, "error", err)
+		return
+	}
+	updatedIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		updatedIDs = append(updatedIDs, doc.Ref.ID)
+	}
+	deletedIDs, err := r.getTombstoneIDs(ctx, language, since)
+	if err != nil {
+		return
+	}
+	if err := queueOutboxEntries(ctx, r.client, language, updatedIDs, models.OutboxOperationUpsert); err != nil {
+		r.logger.Warn("failed to replay address changes", "language", language, "error", err)
+	}
+	if err := queueOutboxEntries(ctx, r.client, language, deletedIDs, models.OutboxOperationDelete); err != nil {
+		r.logger.Warn("failed to replay address deletions", "language", language, "error", err)
+	}
+}
+
+func (r *AddressRepository) getTombstoneIDs(
+	ctx context.Context, language models.Language, since int64) ([]string, error) {
+	docs, err := r.client.Collection(getTombstoneCollectionName(language)).
+		Where("deletedAt", ">", since).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to get address tombstones", "language", language, "error", err)
+		return nil, fmt.Errorf("failed to get address tombstones: %w", err)
+	}
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.Ref.ID)
+	}
+	return ids, nil
+}
+
+// Tombstones older than the watermark are never read again, failures only leave them for the next run
+func (r *AddressRepository) purgeTombstones(ctx context.Context, language models.Language, before int64) {
+	docs, err := r.client.Collection(getTombstoneCollectionName(language)).
+		Where("deletedAt", "<=", before).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Warn("failed to query old address tombstones", "language", language, "error", err)
+		return
+	}
+	if len(docs) == 0 {
+		return
+	}
+	bulkWriter := r.client.BulkWriter(ctx)
+	for _, doc := range docs {
+		if _, err := bulkWriter.Delete(doc.Ref); err != nil {
+			r.logger.Warn("failed to queue tombstone delete", "docID", doc.Ref.ID, "error", err)
+		}
+	}
+	bulkWriter.End()
+}
+
+func (r *AddressRepository) getAddressInTransaction(
+	tx *firestore.Transaction, docRef *firestore.DocumentRef) (*models.AddressItem, error) {
+	doc, err := tx.Get(docRef)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("address %s: %w", docRef.ID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var address models.AddressItem
+	if err := doc.DataTo(&address); err != nil {
+		return nil, fmt.Errorf("failed to parse address %s: %w", docRef.ID, err)
+	}
+	return &address, nil
+}
+
+// The addresses of the search records, false when any record misses list view fields
+func indexedAddresses(records []infra.TypesenseAddressRecord) ([]models.AddressItem, bool) {
+	addresses := make([]models.AddressItem, 0, len(records))
+	for _, record := range records {
+		address, ok := record.ToAddressItem()
+		if !ok {
+			return nil, false
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, true
+}
+
+// Drop the addresses that have a tombstone, they are in the trash or merged
+// but the search index didn't remove them yet
+func (r *AddressRepository) withoutDeletedAddresses(
+	ctx context.Context,
+	language models.Language,
+	addresses []models.AddressItem) ([]models.AddressItem, error) {
+	if len(addresses) == 0 {
+		return addresses, nil
+	}
+	tombstones := r.client.Collection(getTombstoneCollectionName(language))
+	docRefs := make([]*firestore.DocumentRef, len(addresses))
+	for i, address := range addresses {
+		docRefs[i] = tombstones.Doc(address.ID)
+	}
+	docs, err := r.client.GetAll(ctx, docRefs)
+	if err != nil {
+		r.logger.Error("failed to get address tombstones", "language", language, "error", err)
+		return nil, fmt.Errorf("failed to get address tombstones: %w", err)
+	}
+	live := make([]models.AddressItem, 0, len(addresses))
+	for i, doc := range docs {
+		if !doc.Exists() {
+			live = append(live, addresses[i])
+		}
+	}
+	return live, nil
+}
+
+// Tags of an address by category for the tags record. The category comes from the taxonomy and tags
+// it doesn't know are returned as unknown. Until the taxonomy is seeded the category is the position
+// of the tag, like before the taxonomy, so the record isn't emptied by the first refresh after deploy
+func categorizeRecordTags(
+	taxonomy *models.TagTaxonomy,
+	language models.Language,
+	tags []string) (map[models.TagCategory][]string, []string) {
+	if !taxonomy.IsEmpty() {
+		categorized := taxonomy.Categorize(language, tags)
+		unknown := slices.DeleteFunc(slices.Clone(tags), func(value string) bool {
+			_, ok := taxonomy.Resolve(language, value)
+			return ok
+		})
+		return categorized, unknown
+	}
+	categorized := map[models.TagCategory][]string{}
+	for i, value := range tags {
+		if i >= len(models.LegacyTagCategories) {
+			return categorized, tags[i:]
+		}
+		category := models.LegacyTagCategories[i]
+		categorized[category] = append(categorized[category], value)
+	}
+	return categorized, []string{}
+}
 
 func (r *AddressRepository) batchFetchAddresses(
 	ctx context.Context,
@@ -373,6 +1158,7 @@ func (r *AddressRepository) batchFetchAddresses(
 		if !doc.Exists() {
 			invalidIDs = append(invalidIDs, doc.Ref.ID)
 			r.logger.Warn("address not found", "ID", doc.Ref.ID)
+			continue
 		}
 		var addressItem models.AddressItem
 		if err := doc.DataTo(&addressItem); err != nil {
@@ -381,7 +1167,8 @@ func (r *AddressRepository) batchFetchAddresses(
 				"error", err,
 			)
 			invalidIDs = append(invalidIDs, doc.Ref.ID)
-		} else {
+		} else if !addressItem.IsDeleted() {
+			// addresses in the trash are hidden but not invalid, they come back when restored
 			addresses = append(addresses, addressItem)
 		}
 	}
@@ -397,6 +1184,14 @@ func getAddressCollectionName(language models.Language) string {
 	return fmt.Sprintf("%s_%s", addressTablePrefix, language.Get())
 }
 
+func getTombstoneCollectionName(language models.Language) string {
+	return fmt.Sprintf("%s_%s", tombstoneTablePrefix, language.Get())
+}
+
+func getAliasCollectionName(language models.Language) string {
+	return fmt.Sprintf("%s_%s", aliasTablePrefix, language.Get())
+}
+
 func getTagCollectionName() string {
 	return tagsTablePrefix
 }
@@ -0,0 +1,623 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/infra"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	duplicateCandidateLimit = 10
+	// NameSimilarity a search engine hit needs to count as the same name
+	similarNameLimit = 0.8
+	// app users read and rewritten at a time when the saved addresses of a merge are migrated
+	savedAddressesMigrationBatch = 200
+	// addresses whose duplicate keys are written at a time by the backfill
+	duplicateKeysBackfillBatch = 500
+)
+
+type FindDuplicateAddressesOption struct {
+	Language  models.Language
+	Address   models.AddressItem
+	ExcludeID string // the address itself when an existing address is checked
+}
+
+type ListDuplicateClustersOption struct {
+	Language models.Language
+}
+
+type MergeAddressesOption struct {
+	Language     models.Language
+	SurvivorID   string
+	DuplicateIDs []string
+	Actor        string
+}
+
+type MergeAddressesResult struct {
+	Survivor      models.AddressItem
+	MergedIDs     []string
+	MigratedUsers int // app users whose saved addresses were rewritten
+}
+
+type BackfillDuplicateKeysOption struct {
+	Language models.Language
+	Progress ProgressFunc // optional
+}
+
+type BackfillDuplicateKeysResult struct {
+	Processed int
+	Updated   int
+	Failed    int
+}
+
+// Addresses of the catalog that look like opts.Address, by normalized name, by a close name found
+// through the search engine and by normalized street address. Addresses in the trash are ignored.
+// The search engine is best effort, the Firestore checks still run when it is unavailable
+func (r *AddressRepository) FindDuplicateAddresses(
+	ctx context.Context, opts FindDuplicateAddressesOption) ([]models.DuplicateMatch, error) {
+	collectionName := getAddressCollectionName(opts.Language)
+	collectionRef := r.client.Collection(collectionName)
+	matches := map[string]*models.DuplicateMatch{}
+	addMatch := func(address models.AddressItem, reason models.DuplicateReason) {
+		if address.IsDeleted() || address.ID == opts.ExcludeID {
+			return
+		}
+		match, ok := matches[address.ID]
+		if !ok {
+			match = &models.DuplicateMatch{
+				Address:        address,
+				NameSimilarity: models.NameSimilarity(opts.Address.Name, address.Name),
+				TagSimilarity:  max(compareTags(opts.Address.Tags, address.Tags), compareTags(address.Tags, opts.Address.Tags)),
+			}
+			matches[address.ID] = match
+		}
+		if !slices.Contains(match.Reasons, reason) {
+			match.Reasons = append(match.Reasons, reason)
+		}
+	}
+	queries := []struct {
+		query  firestore.Query
+		reason models.DuplicateReason
+	}{
+		{collectionRef.Where("nameKey", "==", models.NormalizeAddressName(opts.Address.Name)), models.DuplicateReasonName},
+		// addresses written before the normalized keys existed
+		{collectionRef.Where("name", "==", opts.Address.Name), models.DuplicateReasonName},
+	}
+	if postalKey := models.NormalizePostalAddress(opts.Address.Address); postalKey != "" {
+		queries = append(queries, struct {
+			query  firestore.Query
+			reason models.DuplicateReason
+		}{collectionRef.Where("postalKey", "==", postalKey), models.DuplicateReasonPostalAddress})
+	}
+	for _, q := range queries {
+		docs, err := q.query.Limit(duplicateCandidateLimit).Documents(ctx).GetAll()
+		if err != nil {
+			r.logger.Error("failed to check for duplicate records", "collectionName", collectionName, "error", err)
+			return nil, fmt.Errorf("failed to check for duplicate records: %w", err)
+		}
+		for _, doc := range docs {
+			var address models.AddressItem
+			if err := doc.DataTo(&address); err != nil {
+				r.logger.Warn("failed to parse existing address", "docID", doc.Ref.ID, "error", err)
+				continue
+			}
+			addMatch(address, q.reason)
+		}
+	}
+	for _, address := range r.searchSimilarNames(ctx, collectionName, opts.Address.Name) {
+		if models.NameSimilarity(opts.Address.Name, address.Name) >= similarNameLimit {
+			addMatch(address, models.DuplicateReasonFuzzyName)
+		}
+	}
+	result := make([]models.DuplicateMatch, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, *match)
+	}
+	slices.SortFunc(result, func(a, b models.DuplicateMatch) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Reasons), len(a.Reasons)),
+			cmp.Compare(b.NameSimilarity, a.NameSimilarity),
+			cmp.Compare(a.Address.ID, b.Address.ID))
+	})
+	return result, nil
+}
+
+// Suspected duplicates across the whole catalog of a language, grouped by normalized name with
+// similar tags and by normalized street address. Fuzzy names are only checked when addresses are written
+func (r *AddressRepository) ListDuplicateClusters(
+	ctx context.Context, opts ListDuplicateClustersOption) ([]models.DuplicateCluster, error) {
+	addresses, err := r.getLiveAddresses(ctx, opts.Language)
+	if err != nil {
+		return nil, err
+	}
+	return clusterDuplicates(addresses), nil
+}
+
+// Move the duplicates to the trash, pointing at the surviving address, and record an alias from each
+// merged ID. Their search records are removed through the outbox and the saved addresses of the app users
+// are rewritten to the survivor afterwards. The content of the survivor is the one that is kept, it only takes
+// over the translation group of a linked duplicate, ErrTranslationConflict is returned when it is linked already.
+// Duplicates already merged into the same survivor are skipped, so a merge whose user migration
+// failed can be run again
+func (r *AddressRepository) MergeAddresses(ctx context.Context, opts MergeAddressesOption) (*MergeAddressesResult, error) {
+	collectionName := getAddressCollectionName(opts.Language)
+	collectionRef := r.client.Collection(collectionName)
+	tombstones := r.client.Collection(getTombstoneCollectionName(opts.Language))
+	aliases := r.client.Collection(getAliasCollectionName(opts.Language))
+	survivorRef := collectionRef.Doc(opts.SurvivorID)
+	var survivor models.AddressItem
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// a transaction has to read everything before it writes
+		current, err := r.getAddressInTransaction(tx, survivorRef)
+		if err != nil {
+			return err
+		}
+		if current.IsDeleted() {
+			return fmt.Errorf("address %s is in the trash: %w", opts.SurvivorID, ErrNotFound)
+		}
+		survivor = *current
+		duplicates := make([]models.AddressItem, 0, len(opts.DuplicateIDs))
+		for _, id := range opts.DuplicateIDs {
+			duplicate, err := r.getAddressInTransaction(tx, collectionRef.Doc(id))
+			if err != nil {
+				return err
+			}
+			if duplicate.IsDeleted() && duplicate.MergedInto == opts.SurvivorID {
+				continue
+			}
+			if duplicate.IsDeleted() {
+				return fmt.Errorf("address %s is in the trash: %w", id, ErrNotFound)
+			}
+			duplicates = append(duplicates, *duplicate)
+		}
+		// addresses merged into a duplicate earlier now point at the survivor, aliases never chain
+		chained, err := tx.Documents(aliases.Where("targetId", "in", opts.DuplicateIDs)).GetAll()
+		if err != nil {
+			return err
+		}
+		groupID, err := mergedTranslationGroup(survivor, duplicates)
+		if err != nil {
+			return err
+		}
+		var group *models.TranslationGroup
+		var groupRef *firestore.DocumentRef
+		if groupID != survivor.TranslationGroupID {
+			groupRef = r.client.Collection(translationGroupTable).Doc(groupID)
+			if group, err = r.getTranslationGroupInTransaction(tx, groupRef); err != nil {
+				return err
+			}
+		}
+		now := time.Now().UnixMilli()
+		for _, doc := range chained {
+			if err := tx.Update(doc.Ref, []firestore.Update{{Path: "targetId", Value: opts.SurvivorID}}); err != nil {
+				return err
+			}
+		}
+		// the survivor takes the place of the duplicate in its translation group
+		if group != nil {
+			group.Members[opts.Language.Get()] = opts.SurvivorID
+			group.UpdatedAt = now
+			if err := tx.Set(groupRef, *group); err != nil {
+				return err
+			}
+			err := r.setTranslationGroupInTransaction(tx, survivorRef, opts.Language, groupID, now)
+			if err != nil {
+				return err
+			}
+			survivor.TranslationGroupID = groupID
+			survivor.UpdatedAt = now
+		}
+		for _, duplicate := range duplicates {
+			merged := duplicate
+			merged.DeletedAt = now
+			merged.UpdatedAt = now
+			merged.Revision = duplicate.Revision + 1
+			merged.MergedInto = opts.SurvivorID
+			merged.TranslationGroupID = ""
+			docRef := collectionRef.Doc(duplicate.ID)
+			if err := tx.Set(docRef, merged); err != nil {
+				return err
+			}
+			err := setAddressRevision(tx, docRef, duplicate, merged, models.RevisionActionMerge, opts.Actor)
+			if err != nil {
+				return err
+			}
+			err = tx.Set(aliases.Doc(duplicate.ID), models.AddressAlias{
+				ID:        duplicate.ID,
+				TargetID:  opts.SurvivorID,
+				Actor:     opts.Actor,
+				CreatedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+			err = tx.Set(tombstones.Doc(duplicate.ID), models.AddressTombstone{ID: duplicate.ID, DeletedAt: now})
+			if err != nil {
+				return err
+			}
+			if err := setOutboxEntry(r.client, tx, opts.Language, duplicate.ID, models.OutboxOperationDelete); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to merge addresses",
+			"survivorID", opts.SurvivorID, "duplicateIDs", opts.DuplicateIDs, "error", err)
+		return nil, fmt.Errorf("failed to merge addresses into %s: %w", opts.SurvivorID, err)
+	}
+	migrated, err := r.migrateSavedAddresses(ctx, opts.Language, opts.SurvivorID, opts.DuplicateIDs)
+	if err != nil {
+		r.logger.Error("failed to migrate saved addresses",
+			"survivorID", opts.SurvivorID, "duplicateIDs", opts.DuplicateIDs, "error", err)
+		return nil, fmt.Errorf("addresses merged into %s, failed to migrate saved addresses: %w", opts.SurvivorID, err)
+	}
+	return &MergeAddressesResult{Survivor: survivor, MergedIDs: opts.DuplicateIDs, MigratedUsers: migrated}, nil
+}
+
+// Write the normalized name and street address keys on the addresses written before they existed or whose
+// keys are out of date. Only the keys are updated, the content, revision and search record are unchanged.
+// An address written during the run is skipped and counted as failed, it got its keys from that write
+func (r *AddressRepository) BackfillDuplicateKeys(
+	ctx context.Context, opts BackfillDuplicateKeysOption) (*BackfillDuplicateKeysResult, error) {
+	progress := opts.Progress
+	if progress == nil {
+		progress = noProgress
+	}
+	collectionName := getAddressCollectionName(opts.Language)
+	iter := r.client.Collection(collectionName).Documents(ctx)
+	defer iter.Stop()
+	bulkWriter := r.client.BulkWriter(ctx)
+	defer bulkWriter.End()
+	result := &BackfillDuplicateKeysResult{}
+	jobs := []*firestore.BulkWriterJob{}
+	flush := func() {
+		bulkWriter.Flush()
+		for _, job := range jobs {
+			if _, err := job.Results(); err != nil {
+				r.logger.Warn("failed to backfill duplicate keys", "collectionName", collectionName, "error", err)
+				result.Failed++
+			} else {
+				result.Updated++
+			}
+		}
+		jobs = jobs[:0]
+		progress(result.Processed, result.Failed)
+	}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("failed to iterate addresses", "collectionName", collectionName, "error", err)
+			return nil, fmt.Errorf("failed to fetch addresses: %w", err)
+		}
+		result.Processed++
+		var address models.AddressItem
+		if err := doc.DataTo(&address); err != nil {
+			r.logger.Warn("failed to parse address", "docID", doc.Ref.ID, "error", err)
+			result.Failed++
+			continue
+		}
+		nameKey, postalKey := address.NameKey, address.PostalKey
+		address.UpdateDuplicateKeys()
+		if address.NameKey == nameKey && address.PostalKey == postalKey {
+			continue
+		}
+		updates := []firestore.Update{{Path: "nameKey", Value: address.NameKey}, {Path: "postalKey", Value: address.PostalKey}}
+		if address.PostalKey == "" {
+			updates[1].Value = firestore.Delete
+		}
+		job, err := bulkWriter.Update(doc.Ref, updates, firestore.LastUpdateTime(doc.UpdateTime))
+		if err != nil {
+			r.logger.Warn("failed to queue duplicate keys", "docID", doc.Ref.ID, "error", err)
+			result.Failed++
+			continue
+		}
+		jobs = append(jobs, job)
+		if len(jobs) >= duplicateKeysBackfillBatch {
+			flush()
+		}
+	}
+	flush()
+	return result, nil
+}
+
+// =========== Helper methods ==========
+
+// The translation group of the survivor once the duplicates are merged into it. An address belongs to
+// a single group, so the survivor can only take over the group of a duplicate while it is not linked itself
+func mergedTranslationGroup(survivor models.AddressItem, duplicates []models.AddressItem) (string, error) {
+	groupID := survivor.TranslationGroupID
+	for _, duplicate := range duplicates {
+		if duplicate.TranslationGroupID == "" || duplicate.TranslationGroupID == groupID {
+			continue
+		}
+		if groupID != "" {
+			return "", fmt.Errorf("%w: %s is linked to translation group %s, the merged %s to %s",
+				ErrTranslationConflict, survivor.ID, groupID, duplicate.ID, duplicate.TranslationGroupID)
+		}
+		groupID = duplicate.TranslationGroupID
+	}
+	return groupID, nil
+}
+
+// Every address of the language that is not in the trash, for the reports that scan the whole catalog
+func (r *AddressRepository) getLiveAddresses(ctx context.Context, language models.Language) ([]models.AddressItem, error) {
+	collectionName := getAddressCollectionName(language)
+	iter := r.client.Collection(collectionName).Documents(ctx)
+	defer iter.Stop()
+	addresses := []models.AddressItem{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("failed to iterate addresses", "collectionName", collectionName, "error", err)
+			return nil, fmt.Errorf("failed to fetch addresses: %w", err)
+		}
+		var address models.AddressItem
+		if err := doc.DataTo(&address); err != nil {
+			r.logger.Warn("failed to parse address", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		if address.IsDeleted() {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// Reject an address that the duplicate detector considers already in the catalog
+func (r *AddressRepository) checkDuplicateAddress(
+	ctx context.Context, language models.Language, address models.AddressItem) error {
+	matches, err := r.FindDuplicateAddresses(ctx, FindDuplicateAddressesOption{
+		Language: language,
+		Address:  address,
+	})
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if isLikelyDuplicate(match) {
+			return fmt.Errorf("%w: address '%s' matches %s", ErrDuplicate, address.Name, describeDuplicateMatch(match))
+		}
+	}
+	return nil
+}
+
+func describeDuplicateMatch(match models.DuplicateMatch) string {
+	reasons := make([]string, len(match.Reasons))
+	for i, reason := range match.Reasons {
+		reasons[i] = string(reason)
+	}
+	return fmt.Sprintf("'%s' (%s) with %.0f%% similar tags",
+		match.Address.Name, strings.Join(reasons, ", "), match.TagSimilarity*100)
+}
+
+// The same street address is the same place, a similar name also needs similar tags
+// so that namesakes in other cities are kept apart
+func isLikelyDuplicate(match models.DuplicateMatch) bool {
+	if slices.Contains(match.Reasons, models.DuplicateReasonPostalAddress) {
+		return true
+	}
+	return match.TagSimilarity > tagsSimilarityLimit
+}
+
+func (r *AddressRepository) searchSimilarNames(
+	ctx context.Context, collectionName string, name string) []models.AddressItem {
+	ids, err := r.typesense.SearchSimilarNames(ctx, &infra.SearchSimilarNamesParams{
+		CollectionName: collectionName,
+		Name:           name,
+		Limit:          duplicateCandidateLimit,
+	})
+	if err != nil {
+		r.logger.Warn("similar name search failed, checking without it", "collectionName", collectionName, "error", err)
+		return nil
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	addresses, _, err := r.batchFetchAddresses(ctx, collectionName, ids)
+	if err != nil {
+		r.logger.Warn("failed to fetch similar name candidates", "collectionName", collectionName, "error", err)
+		return nil
+	}
+	return addresses
+}
+
+// Group addresses that share a normalized name and have similar tags, or share a normalized street address.
+// Clusters are ordered by size, their addresses and ID by creation time
+func clusterDuplicates(addresses []models.AddressItem) []models.DuplicateCluster {
+	parent := make([]int, len(addresses))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	reasons := map[[2]int]models.DuplicateReason{}
+	union := func(a int, b int, reason models.DuplicateReason) {
+		reasons[[2]int{a, b}] = reason
+		parent[find(a)] = find(b)
+	}
+	byName := map[string][]int{}
+	byPostal := map[string][]int{}
+	for i, address := range addresses {
+		if key := models.NormalizeAddressName(address.Name); key != "" {
+			byName[key] = append(byName[key], i)
+		}
+		if key := models.NormalizePostalAddress(address.Address); key != "" {
+			byPostal[key] = append(byPostal[key], i)
+		}
+	}
+	for _, group := range byName {
+		for x := 0; x < len(group); x++ {
+			for y := x + 1; y < len(group); y++ {
+				a, b := addresses[group[x]], addresses[group[y]]
+				if max(compareTags(a.Tags, b.Tags), compareTags(b.Tags, a.Tags)) > tagsSimilarityLimit {
+					union(group[x], group[y], models.DuplicateReasonName)
+				}
+			}
+		}
+	}
+	for _, group := range byPostal {
+		for _, member := range group[1:] {
+			union(group[0], member, models.DuplicateReasonPostalAddress)
+		}
+	}
+	members := map[int][]int{}
+	for i := range addresses {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+	clusterReasons := map[int][]models.DuplicateReason{}
+	for pair, reason := range reasons {
+		root := find(pair[0])
+		if !slices.Contains(clusterReasons[root], reason) {
+			clusterReasons[root] = append(clusterReasons[root], reason)
+		}
+	}
+	clusters := []models.DuplicateCluster{}
+	for root, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		cluster := models.DuplicateCluster{Reasons: clusterReasons[root]}
+		for _, i := range group {
+			cluster.Addresses = append(cluster.Addresses, addresses[i])
+		}
+		slices.SortFunc(cluster.Addresses, func(a, b models.AddressItem) int {
+			return cmp.Or(cmp.Compare(a.CreatedAt, b.CreatedAt), cmp.Compare(a.ID, b.ID))
+		})
+		slices.Sort(cluster.Reasons)
+		cluster.ID = cluster.Addresses[0].ID
+		clusters = append(clusters, cluster)
+	}
+	slices.SortFunc(clusters, func(a, b models.DuplicateCluster) int {
+		return cmp.Or(cmp.Compare(len(b.Addresses), len(a.Addresses)), cmp.Compare(a.ID, b.ID))
+	})
+	return clusters
+}
+
+// Replace the merged IDs by the survivor in the saved addresses of every app user, keeping the position of
+// the first one. Users are rewritten page by page, a user whose list changed meanwhile is skipped and
+// keeps working through the alias. Returns how many users were rewritten
+func (r *AddressRepository) migrateSavedAddresses(
+	ctx context.Context, language models.Language, survivorID string, mergedIDs []string) (int, error) {
+	path := fmt.Sprintf("addressBook.savedAddresses.%s", language.Get())
+	values := make([]interface{}, len(mergedIDs))
+	for i, id := range mergedIDs {
+		values[i] = id
+	}
+	query := r.client.Collection(appUserTable).
+		Where(path, "array-contains-any", values).
+		OrderBy(firestore.DocumentID, firestore.Asc).
+		Limit(savedAddressesMigrationBatch)
+	migrated := 0
+	var last *firestore.DocumentSnapshot
+	for {
+		page := query
+		if last != nil {
+			page = query.StartAfter(last)
+		}
+		docs, err := page.Documents(ctx).GetAll()
+		if err != nil {
+			return migrated, err
+		}
+		if len(docs) == 0 {
+			return migrated, nil
+		}
+		bulkWriter := r.client.BulkWriter(ctx)
+		jobs := make([]*firestore.BulkWriterJob, 0, len(docs))
+		for _, doc := range docs {
+			var appUser models.AppUser
+			if err := doc.DataTo(&appUser); err != nil || appUser.AddressBook == nil {
+				r.logger.Warn("failed to parse app user, saved addresses not migrated", "uid", doc.Ref.ID, "error", err)
+				continue
+			}
+			saved := replaceSavedAddresses(appUser.AddressBook.SavedAddresses[language.Get()], survivorID, mergedIDs)
+			job, err := bulkWriter.Update(doc.Ref,
+				[]firestore.Update{{Path: path, Value: saved}},
+				firestore.LastUpdateTime(doc.UpdateTime))
+			if err != nil {
+				r.logger.Warn("failed to queue saved addresses migration", "uid", doc.Ref.ID, "error", err)
+				continue
+			}
+			jobs = append(jobs, job)
+		}
+		bulkWriter.End()
+		for _, job := range jobs {
+			if _, err := job.Results(); err != nil {
+				r.logger.Warn("saved addresses not migrated, the alias still resolves them", "error", err)
+				continue
+			}
+			migrated++
+		}
+		if len(docs) < savedAddressesMigrationBatch {
+			return migrated, nil
+		}
+		last = docs[len(docs)-1]
+	}
+}
+
+// Aliases of the given IDs, keyed by the merged ID
+func (r *AddressRepository) getAddressAliases(
+	ctx context.Context, language models.Language, ids []string) (map[string]string, error) {
+	aliases := map[string]string{}
+	if len(ids) == 0 {
+		return aliases, nil
+	}
+	collectionRef := r.client.Collection(getAliasCollectionName(language))
+	docRefs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		docRefs[i] = collectionRef.Doc(id)
+	}
+	docs, err := r.client.GetAll(ctx, docRefs)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var alias models.AddressAlias
+		if err := doc.DataTo(&alias); err != nil {
+			r.logger.Warn("failed to parse address alias", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		aliases[doc.Ref.ID] = alias.TargetID
+	}
+	return aliases, nil
+}
+
+// Saved address IDs with the merged IDs replaced by the survivor, which appears once
+// at the position of the first replaced or already saved ID
+func replaceSavedAddresses(saved []string, survivorID string, mergedIDs []string) []string {
+	replaced := make([]string, 0, len(saved))
+	for _, id := range saved {
+		if slices.Contains(mergedIDs, id) {
+			id = survivorID
+		}
+		if id == survivorID && slices.Contains(replaced, survivorID) {
+			continue
+		}
+		replaced = append(replaced, id)
+	}
+	return replaced
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAddressName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "sherlock holmes museum", models.NormalizeAddressName("The Sherlock Holmes Museum"))
+	assert.Equal(t, "sherlock holmes museum", models.NormalizeAddressName("  Sherlock-Holmes museum."))
+	assert.Equal(t, "the", models.NormalizeAddressName("The"))
+	assert.Equal(t, "故宫博物院", models.NormalizeAddressName("故宫博物院。"))
+}
+
+func TestNormalizePostalAddress(t *testing.T) {
+	t.Parallel()
+	a := models.Address{Line1: "221B Baker St.", PostalCode: "NW1 6XE", City: "London"}
+	b := models.Address{Line1: "221b baker street", PostalCode: "nw16xe", City: "Greater London"}
+	assert.Equal(t, models.NormalizePostalAddress(a), models.NormalizePostalAddress(b))
+	assert.Equal(t, "221b baker street|nw16xe", models.NormalizePostalAddress(a))
+	// the city stands in for a missing postal code
+	assert.Equal(t, "1 main street|springfield", models.NormalizePostalAddress(models.Address{Line1: "1 Main St", City: "Springfield"}))
+	assert.Empty(t, models.NormalizePostalAddress(models.Address{City: "London"}))
+}
+
+func TestNameSimilarity(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 1.0, models.NameSimilarity("The Sherlock Holmes Museum", "Sherlock Holmes Museum"))
+	assert.Greater(t, models.NameSimilarity("Sherlok Holmes Museum", "Sherlock Holmes Museum"), similarNameLimit)
+	assert.Less(t, models.NameSimilarity("Sherlock Holmes Museum", "British Museum"), similarNameLimit)
+	assert.Equal(t, 0.0, models.NameSimilarity("", "British Museum"))
+}
+
+func TestIsLikelyDuplicate(t *testing.T) {
+	t.Parallel()
+	postal := models.DuplicateMatch{Reasons: []models.DuplicateReason{models.DuplicateReasonPostalAddress}}
+	assert.True(t, isLikelyDuplicate(postal))
+	namesake := models.DuplicateMatch{Reasons: []models.DuplicateReason{models.DuplicateReasonName}, TagSimilarity: 0.5}
+	assert.False(t, isLikelyDuplicate(namesake))
+	sameName := models.DuplicateMatch{Reasons: []models.DuplicateReason{models.DuplicateReasonFuzzyName}, TagSimilarity: 1}
+	assert.True(t, isLikelyDuplicate(sameName))
+}
+
+func TestFindAcceptedDuplicate(t *testing.T) {
+	t.Parallel()
+	accepted := []models.AddressItem{
+		{Name: "Sherlock Holmes Museum", Tags: []string{"UK", "museum"}},
+		{Name: "Holmes House", Tags: []string{"UK"}, Address: models.Address{Line1: "221B Baker St", PostalCode: "NW1 6XE"}},
+		{Name: "Central Library", Tags: []string{"UK", "library"}},
+	}
+	match, found := findAcceptedDuplicate(models.AddressItem{Name: "Sherlok Holmes Museum", Tags: []string{"UK", "museum"}}, accepted)
+	assert.True(t, found)
+	assert.Equal(t, []models.DuplicateReason{models.DuplicateReasonFuzzyName}, match.Reasons)
+	// the same street address is enough
+	match, found = findAcceptedDuplicate(models.AddressItem{Name: "Baker Street Flat",
+		Address: models.Address{Line1: "221b baker street", PostalCode: "nw16xe"}}, accepted)
+	assert.True(t, found)
+	assert.Equal(t, "Holmes House", match.Address.Name)
+	// a namesake with other tags is kept
+	_, found = findAcceptedDuplicate(models.AddressItem{Name: "Central Library", Tags: []string{"US", "public"}}, accepted)
+	assert.False(t, found)
+}
+
+func TestClusterDuplicates(t *testing.T) {
+	t.Parallel()
+	addresses := []models.AddressItem{
+		{ID: "museum-2", CreatedAt: 2, Name: "The Sherlock Holmes Museum", Tags: []string{"UK", "museum"}},
+		{ID: "museum-1", CreatedAt: 1, Name: "Sherlock Holmes Museum", Tags: []string{"UK", "museum"},
+			Address: models.Address{Line1: "221B Baker Street", PostalCode: "NW1 6XE"}},
+		{ID: "museum-3", CreatedAt: 3, Name: "Holmes House", Tags: []string{"UK"},
+			Address: models.Address{Line1: "221b Baker St", PostalCode: "NW16XE"}},
+		// same name in another country, the tags tell them apart
+		{ID: "library-1", CreatedAt: 4, Name: "Central Library", Tags: []string{"UK", "library"}},
+		{ID: "library-2", CreatedAt: 5, Name: "Central Library", Tags: []string{"US", "public"}},
+		{ID: "abbey-1", CreatedAt: 6, Name: "Abbey Road", Tags: []string{"UK"}},
+		{ID: "abbey-2", CreatedAt: 7, Name: "abbey road!", Tags: []string{"UK"}},
+	}
+	clusters := clusterDuplicates(addresses)
+	assert.Len(t, clusters, 2)
+	assert.Equal(t, "museum-1", clusters[0].ID)
+	assert.Equal(t, []string{"museum-1", "museum-2", "museum-3"}, clusterIDs(clusters[0]))
+	assert.Equal(t,
+		[]models.DuplicateReason{models.DuplicateReasonName, models.DuplicateReasonPostalAddress},
+		clusters[0].Reasons)
+	assert.Equal(t, []string{"abbey-1", "abbey-2"}, clusterIDs(clusters[1]))
+	assert.Equal(t, []models.DuplicateReason{models.DuplicateReasonName}, clusters[1].Reasons)
+}
+
+func TestReplaceSavedAddresses(t *testing.T) {
+	t.Parallel()
+	merged := []string{"b", "d"}
+	assert.Equal(t, []string{"a", "s", "c"}, replaceSavedAddresses([]string{"a", "b", "c", "d"}, "s", merged))
+	// the survivor was already saved, it keeps its own position
+	assert.Equal(t, []string{"a", "s", "c"}, replaceSavedAddresses([]string{"a", "s", "b", "c"}, "s", merged))
+	assert.Equal(t, []string{"a", "c"}, replaceSavedAddresses([]string{"a", "c"}, "s", merged))
+	assert.Empty(t, replaceSavedAddresses(nil, "s", merged))
+}
+
+func TestMergedTranslationGroup(t *testing.T) {
+	t.Parallel()
+	unlinked := models.AddressItem{ID: "s"}
+	linked := models.AddressItem{ID: "s", TranslationGroupID: "g1"}
+	groupID, err := mergedTranslationGroup(unlinked, []models.AddressItem{{ID: "a"}, {ID: "b"}})
+	assert.NoError(t, err)
+	assert.Empty(t, groupID)
+	// the survivor takes over the group of the linked duplicate
+	groupID, err = mergedTranslationGroup(unlinked, []models.AddressItem{{ID: "a"}, {ID: "b", TranslationGroupID: "g2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "g2", groupID)
+	groupID, err = mergedTranslationGroup(linked, []models.AddressItem{{ID: "a"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "g1", groupID)
+	_, err = mergedTranslationGroup(linked, []models.AddressItem{{ID: "a", TranslationGroupID: "g2"}})
+	assert.ErrorIs(t, err, ErrTranslationConflict)
+	_, err = mergedTranslationGroup(unlinked, []models.AddressItem{
+		{ID: "a", TranslationGroupID: "g2"},
+		{ID: "b", TranslationGroupID: "g3"},
+	})
+	assert.ErrorIs(t, err, ErrTranslationConflict)
+}
+
+func clusterIDs(cluster models.DuplicateCluster) []string {
+	ids := []string{}
+	for _, address := range cluster.Addresses {
+		ids = append(ids, address.ID)
+	}
+	return ids
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/infra"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+const importBatchSize = 500
+
+type ImportAddressItem struct {
+	Row         int
+	AddressItem models.AddressItem
+}
+
+type ImportAddressesOption struct {
+	Language models.Language
+	Items    []ImportAddressItem // validated rows
+	DryRun   bool
+	Actor    string
+}
+
+type ImportAddressesResult struct {
+	Rows []models.AddressImportRow
+	// created addresses that are searchable right away, the others are indexed through the outbox
+	Indexed int
+}
+
+// Create the addresses that pass the duplicate check in batches,
+// a dry run only reports what would happen
+func (r *AddressRepository) ImportAddresses(
+	ctx context.Context, opts ImportAddressesOption) (*ImportAddressesResult, error) {
+	collectionName := getAddressCollectionName(opts.Language)
+	result := &ImportAddressesResult{Rows: make([]models.AddressImportRow, 0, len(opts.Items))}
+	accepted := []models.AddressItem{}
+	acceptedItems := []ImportAddressItem{}
+	for _, item := range opts.Items {
+		match, found, err := r.findImportDuplicate(ctx, opts.Language, item.AddressItem, accepted)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result.Rows = append(result.Rows, models.AddressImportRow{
+				Row:    item.Row,
+				Status: models.AddressImportStatusDuplicate,
+				Name:   item.AddressItem.Name,
+				Error:  "address matches " + describeDuplicateMatch(match),
+			})
+			continue
+		}
+		accepted = append(accepted, item.AddressItem)
+		acceptedItems = append(acceptedItems, item)
+	}
+	if opts.DryRun {
+		for _, item := range acceptedItems {
+			result.Rows = append(result.Rows, models.AddressImportRow{
+				Row:    item.Row,
+				Status: models.AddressImportStatusReady,
+				Name:   item.AddressItem.Name,
+			})
+		}
+		return result, nil
+	}
+	bulkWriter := r.client.BulkWriter(ctx)
+	defer bulkWriter.End()
+	for batch := range slices.Chunk(acceptedItems, importBatchSize) {
+		rows, created := r.writeImportBatch(bulkWriter, collectionName, batch, opts.Actor)
+		result.Rows = append(result.Rows, rows...)
+		result.Indexed += r.indexImportedAddresses(ctx, opts.Language, collectionName, created)
+	}
+	return result, nil
+}
+
+// =========== Helper methods ==========
+
+// The first address the duplicate detector matches, either in the catalog or among the
+// rows of the same import accepted so far
+func (r *AddressRepository) findImportDuplicate(
+	ctx context.Context,
+	language models.Language,
+	address models.AddressItem,
+	accepted []models.AddressItem) (models.DuplicateMatch, bool, error) {
+	matches, err := r.FindDuplicateAddresses(ctx, FindDuplicateAddressesOption{
+		Language: language,
+		Address:  address,
+	})
+	if err != nil {
+		return models.DuplicateMatch{}, false, err
+	}
+	for _, match := range matches {
+		if isLikelyDuplicate(match) {
+			return match, true, nil
+		}
+	}
+	match, found := findAcceptedDuplicate(address, accepted)
+	return match, found, nil
+}
+
+// Returns the report of the batch and the addresses that were written. An address is only
+// reported created together with its revision
+func (r *AddressRepository) writeImportBatch(
+	bulkWriter *firestore.BulkWriter,
+	collectionName string,
+	batch []ImportAddressItem,
+	actor string) ([]models.AddressImportRow, []models.AddressItem) {
+	type pendingWrite struct {
+		row         int
+		address     models.AddressItem
+		docRef      *firestore.DocumentRef
+		job         *firestore.BulkWriterJob
+		err         error
+		revisionJob *firestore.BulkWriterJob
+		revisionErr error
+	}
+	writes := make([]pendingWrite, 0, len(batch))
+	now := time.Now().UnixMilli()
+	for _, item := range batch {
+		docRef := r.client.Collection(collectionName).NewDoc()
+		address := item.AddressItem
+		address.ID = docRef.ID
+		address.CreatedAt = now
+		address.UpdatedAt = now
+		address.DeletedAt = 0
+		address.Revision = 1
+		// an exported catalog carries its links, they point into the catalog it was exported from
+		address.MergedInto = ""
+		address.TranslationGroupID = ""
+		address.UpdateDuplicateKeys()
+		write := pendingWrite{row: item.Row, address: address, docRef: docRef}
+		write.job, write.err = bulkWriter.Create(docRef, address)
+		if write.err == nil {
+			revision := newAddressRevision(models.AddressItem{}, address, models.RevisionActionCreate, actor)
+			write.revisionJob, write.revisionErr = bulkWriter.Create(getRevisionRef(docRef, address.Revision), revision)
+		}
+		writes = append(writes, write)
+	}
+	bulkWriter.Flush()
+	rows := make([]models.AddressImportRow, 0, len(writes))
+	created := []models.AddressItem{}
+	// addresses written without their revision are removed again, the row fails as a whole
+	rollbacks := map[string]*firestore.BulkWriterJob{}
+	for _, write := range writes {
+		err := write.err
+		if err == nil {
+			_, err = write.job.Results()
+		}
+		if err == nil {
+			revisionErr := write.revisionErr
+			if revisionErr == nil {
+				_, revisionErr = write.revisionJob.Results()
+			}
+			if revisionErr != nil {
+				err = fmt.Errorf("failed to record the address revision: %w", revisionErr)
+				if job, deleteErr := bulkWriter.Delete(write.docRef); deleteErr == nil {
+					rollbacks[write.address.ID] = job
+				} else {
+					r.logger.Error("failed to remove imported address without revision",
+						"addressID", write.address.ID, "error", deleteErr)
+				}
+			}
+		}
+		if err != nil {
+			r.logger.Warn("failed to import address", "row", write.row, "error", err)
+			rows = append(rows, models.AddressImportRow{
+				Row:    write.row,
+				Status: models.AddressImportStatusFailed,
+				Name:   write.address.Name,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		rows = append(rows, models.AddressImportRow{
+			Row:    write.row,
+			Status: models.AddressImportStatusCreated,
+			ID:     write.address.ID,
+			Name:   write.address.Name,
+		})
+		created = append(created, write.address)
+	}
+	if len(rollbacks) > 0 {
+		bulkWriter.Flush()
+		for addressID, job := range rollbacks {
+			if _, err := job.Results(); err != nil {
+				r.logger.Error("failed to remove imported address without revision", "addressID", addressID, "error", err)
+			}
+		}
+	}
+	return rows, created
+}
+
+// Index the imported addresses right away, when the search engine fails
+// they are queued in the outbox and indexed by its retries instead
+func (r *AddressRepository) indexImportedAddresses(
+	ctx context.Context,
+	language models.Language,
+	collectionName string,
+	addresses []models.AddressItem) int {
+	if len(addresses) == 0 {
+		return 0
+	}
+	ids := make([]string, len(addresses))
+	for i := range addresses {
+		ids[i] = addresses[i].ID
+	}
+	indexed := 0
+	taxonomy, err := r.getTagTaxonomy(ctx)
+	if err == nil {
+		documents := make([]interface{}, len(addresses))
+		for i := range addresses {
+			documents[i] = r.typesense.CreateAddressRecord(&addresses[i], taxonomy.Categorize(language, addresses[i].Tags))
+		}
+		var result *infra.SyncDatabaseResult
+		result, err = r.typesense.UpsertAddressRecords(ctx, collectionName, documents)
+		if err == nil {
+			if result.Failed == 0 {
+				return result.Success
+			}
+			indexed = result.Success
+		}
+	}
+	r.logger.Warn("failed to index imported addresses, queueing them in the outbox",
+		"collectionName", collectionName,
+		"count", len(ids),
+		"error", err)
+	if err := queueOutboxEntries(ctx, r.client, language, ids, models.OutboxOperationUpsert); err != nil {
+		r.logger.Error("failed to queue imported addresses for indexing", "collectionName", collectionName, "error", err)
+	}
+	return indexed
+}
+
+// The rows accepted so far are not written yet, so they are compared in memory
+// with the same reasons as FindDuplicateAddresses
+func findAcceptedDuplicate(address models.AddressItem, accepted []models.AddressItem) (models.DuplicateMatch, bool) {
+	nameKey := models.NormalizeAddressName(address.Name)
+	postalKey := models.NormalizePostalAddress(address.Address)
+	for _, other := range accepted {
+		match := models.DuplicateMatch{
+			Address:        other,
+			NameSimilarity: models.NameSimilarity(address.Name, other.Name),
+			TagSimilarity:  max(compareTags(address.Tags, other.Tags), compareTags(other.Tags, address.Tags)),
+		}
+		if nameKey == models.NormalizeAddressName(other.Name) {
+			match.Reasons = append(match.Reasons, models.DuplicateReasonName)
+		} else if match.NameSimilarity >= similarNameLimit {
+			match.Reasons = append(match.Reasons, models.DuplicateReasonFuzzyName)
+		}
+		if postalKey != "" && postalKey == models.NormalizePostalAddress(other.Address) {
+			match.Reasons = append(match.Reasons, models.DuplicateReasonPostalAddress)
+		}
+		if len(match.Reasons) > 0 && isLikelyDuplicate(match) {
+			return match, true
+		}
+	}
+	return models.DuplicateMatch{}, false
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"north-post/service/internal/domain/v1/models"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Subcollection of every address document holding its revisions
+const revisionsSubcollection = "revisions"
+
+type ListAddressRevisionsOption struct {
+	Language models.Language
+	ID       string
+	Limit    int
+}
+
+type GetAddressRevisionOption struct {
+	Language models.Language
+	ID       string
+	Revision int64
+}
+
+type RollbackAddressOption struct {
+	Language models.Language
+	ID       string
+	Revision int64
+	Actor    string
+}
+
+// Revisions of an address, newest first
+func (r *AddressRepository) ListAddressRevisions(
+	ctx context.Context, opts ListAddressRevisionsOption) ([]models.AddressRevision, error) {
+	docRef := r.client.Collection(getAddressCollectionName(opts.Language)).Doc(opts.ID)
+	docs, err := docRef.Collection(revisionsSubcollection).
+		OrderBy("number", firestore.Desc).
+		Limit(opts.Limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to list address revisions", "addressID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to list address revisions: %w", err)
+	}
+	revisions := make([]models.AddressRevision, 0, len(docs))
+	for _, doc := range docs {
+		var revision models.AddressRevision
+		if err := doc.DataTo(&revision); err != nil {
+			r.logger.Warn("failed to parse address revision", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+func (r *AddressRepository) GetAddressRevision(
+	ctx context.Context, opts GetAddressRevisionOption) (*models.AddressRevision, error) {
+	docRef := r.client.Collection(getAddressCollectionName(opts.Language)).Doc(opts.ID)
+	doc, err := getRevisionRef(docRef, opts.Revision).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("revision %d of address %s: %w", opts.Revision, opts.ID, ErrNotFound)
+	}
+	if err != nil {
+		r.logger.Error("failed to get address revision", "addressID", opts.ID, "revision", opts.Revision, "error", err)
+		return nil, fmt.Errorf("failed to get address revision: %w", err)
+	}
+	var revision models.AddressRevision
+	if err := doc.DataTo(&revision); err != nil {
+		r.logger.Error("failed to parse address revision", "addressID", opts.ID, "revision", opts.Revision, "error", err)
+		return nil, fmt.Errorf("failed to parse address revision: %w", err)
+	}
+	return &revision, nil
+}
+
+// Bring the address back to the content of an earlier revision, recorded as a new revision
+func (r *AddressRepository) RollbackAddress(
+	ctx context.Context, opts RollbackAddressOption) (*models.AddressItem, error) {
+	docRef := r.client.Collection(getAddressCollectionName(opts.Language)).Doc(opts.ID)
+	var rolledBack models.AddressItem
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		current, err := r.getAddressInTransaction(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if current.IsDeleted() {
+			return fmt.Errorf("address %s is in the trash: %w", opts.ID, ErrNotFound)
+		}
+		revisionDoc, err := tx.Get(getRevisionRef(docRef, opts.Revision))
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("revision %d of address %s: %w", opts.Revision, opts.ID, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		var target models.AddressRevision
+		if err := revisionDoc.DataTo(&target); err != nil {
+			return fmt.Errorf("failed to parse revision %d of address %s: %w", opts.Revision, opts.ID, err)
+		}
+		address := target.Snapshot
+		address.ID = current.ID
+		address.CreatedAt = current.CreatedAt
+		address.UpdatedAt = time.Now().UnixMilli()
+		address.DeletedAt = 0
+		address.Revision = current.Revision + 1
+		address.MergedInto = ""
+		address.TranslationGroupID = current.TranslationGroupID // links are not part of the revisions
+		address.UpdateDuplicateKeys()
+		if err := tx.Set(docRef, address); err != nil {
+			return err
+		}
+		revision := newAddressRevision(*current, address, models.RevisionActionRollback, opts.Actor)
+		revision.RollbackOf = opts.Revision
+		if err := tx.Set(getRevisionRef(docRef, revision.Number), revision); err != nil {
+			return err
+		}
+		rolledBack = address
+		return setOutboxEntry(r.client, tx, opts.Language, opts.ID, models.OutboxOperationUpsert)
+	})
+	if err != nil {
+		r.logger.Error("failed to roll back address", "addressID", opts.ID, "revision", opts.Revision, "error", err)
+		return nil, fmt.Errorf("failed to roll back address with ID %s: %w", opts.ID, err)
+	}
+	return &rolledBack, nil
+}
+
+// =========== Helper methods ==========
+
+// Record the change from before to after inside the transaction that writes after,
+// after.Revision must already hold the number of the new revision
+func setAddressRevision(
+	tx *firestore.Transaction,
+	docRef *firestore.DocumentRef,
+	before models.AddressItem,
+	after models.AddressItem,
+	action models.RevisionAction,
+	actor string) error {
+	for _, revision := range newAddressRevisions(before, after, action, actor) {
+		if err := tx.Set(getRevisionRef(docRef, revision.Number), revision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The revisions recording a change. Addresses written before revisions existed have revision 0
+// and no history, their content before the first recorded change is kept as baseline revision 0
+// so it stays visible and can be rolled back to.
+func newAddressRevisions(
+	before models.AddressItem,
+	after models.AddressItem,
+	action models.RevisionAction,
+	actor string) []models.AddressRevision {
+	revision := newAddressRevision(before, after, action, actor)
+	if before.Revision != 0 || action == models.RevisionActionCreate {
+		return []models.AddressRevision{revision}
+	}
+	baseline := models.AddressRevision{
+		Number:    0,
+		Action:    models.RevisionActionBaseline,
+		CreatedAt: before.UpdatedAt,
+		Changes:   []models.FieldChange{},
+		Snapshot:  before,
+	}
+	return []models.AddressRevision{baseline, revision}
+}
+
+func newAddressRevision(
+	before models.AddressItem,
+	after models.AddressItem,
+	action models.RevisionAction,
+	actor string) models.AddressRevision {
+	return models.AddressRevision{
+		Number:    after.Revision,
+		Action:    action,
+		Actor:     actor,
+		CreatedAt: after.UpdatedAt,
+		Changes:   models.DiffAddressItems(before, after),
+		Snapshot:  after,
+	}
+}
+
+func getRevisionRef(docRef *firestore.DocumentRef, revision int64) *firestore.DocumentRef {
+	return docRef.Collection(revisionsSubcollection).Doc(strconv.FormatInt(revision, 10))
+}
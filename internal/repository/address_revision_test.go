@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAddressRevisions(t *testing.T) {
+	t.Parallel()
+	legacy := models.AddressItem{ID: "a1", Name: "Old name", UpdatedAt: 100}
+	updated := legacy
+	updated.Name = "New name"
+	updated.UpdatedAt = 200
+	updated.Revision = 1
+
+	revisions := newAddressRevisions(legacy, updated, models.RevisionActionUpdate, "admin-1")
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, models.AddressRevision{
+		Number:    0,
+		Action:    models.RevisionActionBaseline,
+		CreatedAt: 100,
+		Changes:   []models.FieldChange{},
+		Snapshot:  legacy,
+	}, revisions[0])
+	assert.Equal(t, int64(1), revisions[1].Number)
+	assert.Equal(t, models.RevisionActionUpdate, revisions[1].Action)
+	assert.Equal(t, "admin-1", revisions[1].Actor)
+
+	// addresses that already have a history only get the new revision
+	next := updated
+	next.Revision = 2
+	revisions = newAddressRevisions(updated, next, models.RevisionActionDelete, "admin-1")
+	assert.Len(t, revisions, 1)
+	assert.Equal(t, int64(2), revisions[0].Number)
+
+	// a created address has nothing before it
+	revisions = newAddressRevisions(models.AddressItem{}, updated, models.RevisionActionCreate, "admin-1")
+	assert.Len(t, revisions, 1)
+	assert.Equal(t, models.RevisionActionCreate, revisions[0].Action)
+}
@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"north-post/service/internal/domain/v1/models"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const tagTaxonomyTable = "tag_taxonomy"
+
+type CreateTaxonomyTagOption struct {
+	Tag models.Tag
+}
+
+type UpdateTaxonomyTagOption struct {
+	Tag models.Tag
+}
+
+type DeleteTaxonomyTagOption struct {
+	Slug string
+}
+
+type MigrateAddressTagsOption struct {
+	Language models.Language
+	// create taxonomy tags for the tags that don't resolve, categorized by their position in the address
+	Seed     bool
+	Actor    string
+	Progress ProgressFunc // optional
+}
+
+type MigrateAddressTagsResult struct {
+	Processed int
+	Updated   int
+	Failed    int
+	Seeded    []string // slugs of the created or extended taxonomy tags
+	Unknown   []string // tags that don't resolve, they are kept on the addresses as they are
+}
+
+// Every tag of the taxonomy ordered by category, then by slug
+func (r *AddressRepository) ListTaxonomyTags(ctx context.Context) ([]models.Tag, error) {
+	docs, err := r.client.Collection(tagTaxonomyTable).Documents(ctx).GetAll()
+	if err != nil {
+		r.logger.Error("failed to list taxonomy tags", "error", err)
+		return nil, fmt.Errorf("failed to list taxonomy tags: %w", err)
+	}
+	return models.NewTagTaxonomy(r.parseTaxonomyTags(docs)).All(), nil
+}
+
+// Add a tag to the taxonomy. It is rejected with ErrConflict when the slug is taken,
+// and with ErrDuplicate when one of its names or synonyms already resolves to another tag.
+// Addresses that already carry one of its names are queued for search indexing, they gain its category
+func (r *AddressRepository) CreateTaxonomyTag(ctx context.Context, opts CreateTaxonomyTagOption) (*models.Tag, error) {
+	tag := opts.Tag
+	collection := r.client.Collection(tagTaxonomyTable)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(collection).GetAll()
+		if err != nil {
+			return err
+		}
+		taxonomy := models.NewTagTaxonomy(r.parseTaxonomyTags(docs))
+		if _, exists := taxonomy.Get(tag.Slug); exists {
+			return fmt.Errorf("tag %s: %w", tag.Slug, ErrConflict)
+		}
+		if conflicts := taxonomy.Conflicts(tag); len(conflicts) > 0 {
+			return fmt.Errorf("%s: %w", strings.Join(conflicts, ", "), ErrDuplicate)
+		}
+		now := time.Now().UnixMilli()
+		tag.CreatedAt = now
+		tag.UpdatedAt = now
+		return tx.Create(collection.Doc(tag.Slug), tag)
+	})
+	if err != nil {
+		if errors.Is(err, ErrConflict) || errors.Is(err, ErrDuplicate) {
+			return nil, err
+		}
+		r.logger.Error("failed to create taxonomy tag", "slug", tag.Slug, "error", err)
+		return nil, fmt.Errorf("failed to create taxonomy tag: %w", err)
+	}
+	r.reindexTaggedAddresses(ctx, tag)
+	return &tag, nil
+}
+
+// Replace the category, names and synonyms of a tag. A replaced display name becomes a synonym,
+// so addresses that still carry it keep resolving until they are migrated.
+// The addresses carrying the tag are queued for search indexing, their facets follow the new category
+func (r *AddressRepository) UpdateTaxonomyTag(ctx context.Context, opts UpdateTaxonomyTagOption) (*models.Tag, error) {
+	tag := opts.Tag
+	var previous models.Tag
+	collection := r.client.Collection(tagTaxonomyTable)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(collection).GetAll()
+		if err != nil {
+			return err
+		}
+		taxonomy := models.NewTagTaxonomy(r.parseTaxonomyTags(docs))
+		existing, exists := taxonomy.Get(tag.Slug)
+		if !exists {
+			return fmt.Errorf("tag %s: %w", tag.Slug, ErrNotFound)
+		}
+		previous = existing
+		keepReplacedNames(&tag, existing)
+		if conflicts := taxonomy.Conflicts(tag); len(conflicts) > 0 {
+			return fmt.Errorf("%s: %w", strings.Join(conflicts, ", "), ErrDuplicate)
+		}
+		tag.CreatedAt = existing.CreatedAt
+		tag.UpdatedAt = time.Now().UnixMilli()
+		return tx.Set(collection.Doc(tag.Slug), tag)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrDuplicate) {
+			return nil, err
+		}
+		r.logger.Error("failed to update taxonomy tag", "slug", tag.Slug, "error", err)
+		return nil, fmt.Errorf("failed to update taxonomy tag: %w", err)
+	}
+	r.reindexTaggedAddresses(ctx, previous, tag)
+	return &tag, nil
+}
+
+// Remove a tag from the taxonomy, addresses that carry it are left alone and show up as unknown tags.
+// They are queued for search indexing so the tag leaves their facets
+func (r *AddressRepository) DeleteTaxonomyTag(ctx context.Context, opts DeleteTaxonomyTagOption) error {
+	var deleted models.Tag
+	docRef := r.client.Collection(tagTaxonomyTable).Doc(opts.Slug)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("tag %s: %w", opts.Slug, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&deleted); err != nil {
+			r.logger.Warn("failed to parse taxonomy tag", "docID", doc.Ref.ID, "error", err)
+		}
+		deleted.Slug = doc.Ref.ID
+		return tx.Delete(docRef)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		r.logger.Error("failed to delete taxonomy tag", "slug", opts.Slug, "error", err)
+		return fmt.Errorf("failed to delete taxonomy tag: %w", err)
+	}
+	r.reindexTaggedAddresses(ctx, deleted)
+	return nil
+}
+
+// Rewrite the tags of every live address of the language to the display names of their taxonomy tags.
+// Each address is updated in its own transaction with a revision, an address edited during the run is
+// skipped and counted as failed, running the migration again picks it up
+func (r *AddressRepository) MigrateAddressTags(
+	ctx context.Context, opts MigrateAddressTagsOption) (*MigrateAddressTagsResult, error) {
+	progress := opts.Progress
+	if progress == nil {
+		progress = noProgress
+	}
+	tags, err := r.ListTaxonomyTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taxonomy := models.NewTagTaxonomy(tags)
+	addresses, err := r.getLiveAddresses(ctx, opts.Language)
+	if err != nil {
+		return nil, err
+	}
+	result := &MigrateAddressTagsResult{Seeded: []string{}, Unknown: []string{}}
+	if opts.Seed {
+		seeded := seedTaxonomyTags(taxonomy, opts.Language, addresses, time.Now().UnixMilli())
+		if err := r.saveTaxonomyTags(ctx, seeded); err != nil {
+			return nil, err
+		}
+		for _, tag := range seeded {
+			result.Seeded = append(result.Seeded, tag.Slug)
+		}
+	}
+	for _, address := range addresses {
+		canonical, unknown := taxonomy.Canonicalize(opts.Language, address.Tags)
+		for _, tag := range unknown {
+			if !slices.Contains(result.Unknown, tag) {
+				result.Unknown = append(result.Unknown, tag)
+			}
+		}
+		// nothing is lost, unknown tags stay behind the resolved ones
+		migrated := append(canonical, unknown...)
+		if !slices.Equal(migrated, address.Tags) {
+			if err := r.updateAddressTags(ctx, opts.Language, address, migrated, opts.Actor); err != nil {
+				r.logger.Warn("failed to migrate address tags", "addressID", address.ID, "error", err)
+				result.Failed++
+			} else {
+				result.Updated++
+			}
+		}
+		result.Processed++
+		progress(result.Processed, result.Failed)
+	}
+	slices.Sort(result.Unknown)
+	if len(result.Unknown) > 0 {
+		r.logger.Warn("tags not in the taxonomy",
+			"language", opts.Language, "count", len(result.Unknown), "tags", result.Unknown)
+	}
+	return result, nil
+}
+
+// =========== Helper methods ==========
+
+func (r *AddressRepository) parseTaxonomyTags(docs []*firestore.DocumentSnapshot) []models.Tag {
+	tags := make([]models.Tag, 0, len(docs))
+	for _, doc := range docs {
+		var tag models.Tag
+		if err := doc.DataTo(&tag); err != nil {
+			r.logger.Warn("failed to parse taxonomy tag", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		tag.Slug = doc.Ref.ID
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// The taxonomy used to categorize tags, loaded once per run
+func (r *AddressRepository) getTagTaxonomy(ctx context.Context) (*models.TagTaxonomy, error) {
+	tags, err := r.ListTaxonomyTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewTagTaxonomy(tags), nil
+}
+
+func (r *AddressRepository) saveTaxonomyTags(ctx context.Context, tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	collection := r.client.Collection(tagTaxonomyTable)
+	bulkWriter := r.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(tags))
+	for _, tag := range tags {
+		job, err := bulkWriter.Set(collection.Doc(tag.Slug), tag)
+		if err != nil {
+			bulkWriter.End()
+			r.logger.Error("failed to queue taxonomy tag", "slug", tag.Slug, "error", err)
+			return fmt.Errorf("failed to save taxonomy tags: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			r.logger.Error("failed to save taxonomy tag", "error", err)
+			return fmt.Errorf("failed to save taxonomy tags: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *AddressRepository) updateAddressTags(
+	ctx context.Context, language models.Language, address models.AddressItem, tags []string, actor string) error {
+	docRef := r.client.Collection(getAddressCollectionName(language)).Doc(address.ID)
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		current, err := r.getAddressInTransaction(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if current.UpdatedAt != address.UpdatedAt {
+			return &AddressConflictError{Current: *current}
+		}
+		updated := *current
+		updated.Tags = tags
+		updated.UpdatedAt = time.Now().UnixMilli()
+		updated.Revision = current.Revision + 1
+		if err := tx.Set(docRef, updated); err != nil {
+			return err
+		}
+		err = setAddressRevision(tx, docRef, *current, updated, models.RevisionActionUpdate, actor)
+		if err != nil {
+			return err
+		}
+		return setOutboxEntry(r.client, tx, language, address.ID, models.OutboxOperationUpsert)
+	})
+}
+
+// Queue the addresses carrying one of the spellings of the tags for search indexing, in every language.
+// The tag change is already saved, a failure is only logged and the next full sync catches up
+func (r *AddressRepository) reindexTaggedAddresses(ctx context.Context, tags ...models.Tag) {
+	for _, language := range models.Languages().Codes() {
+		terms := taggedTerms(language, tags)
+		if len(terms) == 0 {
+			continue
+		}
+		collection := r.client.Collection(getAddressCollectionName(language))
+		ids := []string{}
+		for chunk := range slices.Chunk(terms, arrayContainsAnyLimit) {
+			values := make([]interface{}, len(chunk))
+			for i, term := range chunk {
+				values[i] = term
+			}
+			docs, err := collection.Where("tags", "array-contains-any", values).Documents(ctx).GetAll()
+			if err != nil {
+				r.logger.Error("failed to query tagged addresses", "language", language, "error", err)
+				break
+			}
+			for _, doc := range docs {
+				if !slices.Contains(ids, doc.Ref.ID) {
+					ids = append(ids, doc.Ref.ID)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if err := queueOutboxEntries(ctx, r.client, language, ids, models.OutboxOperationUpsert); err != nil {
+			r.logger.Error("failed to queue tagged addresses for search indexing",
+				"language", language, "count", len(ids), "error", err)
+		}
+	}
+}
+
+// Display names replaced by the update are kept as synonyms of their language
+func keepReplacedNames(tag *models.Tag, existing models.Tag) {
+	for language, name := range existing.Names {
+		if models.NormalizeTagTerm(tag.Names[language]) == models.NormalizeTagTerm(name) {
+			continue
+		}
+		if slices.ContainsFunc(tag.Synonyms[language], func(synonym string) bool {
+			return models.NormalizeTagTerm(synonym) == models.NormalizeTagTerm(name)
+		}) {
+			continue
+		}
+		if tag.Synonyms == nil {
+			tag.Synonyms = map[string][]string{}
+		}
+		tag.Synonyms[language] = append(tag.Synonyms[language], name)
+	}
+}
+
+// The values an address of the language may carry for the tags: display names, synonyms and slugs
+func taggedTerms(language models.Language, tags []models.Tag) []string {
+	terms := []string{}
+	add := func(term string) {
+		if term != "" && !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	for _, tag := range tags {
+		add(tag.Name(language))
+		for _, synonym := range tag.Synonyms[language.Lower().Get()] {
+			add(synonym)
+		}
+		add(tag.Slug)
+	}
+	return terms
+}
+
+// Taxonomy tags for the tags of the addresses that don't resolve yet, categorized by their position like
+// before the taxonomy. A tag whose slug is taken gets the name, or a synonym when it already has a name in the
+// language. Tags past the legacy categories or with a slug of another category are left unknown.
+// The seeded tags are added to taxonomy
+func seedTaxonomyTags(
+	taxonomy *models.TagTaxonomy, language models.Language, addresses []models.AddressItem, now int64) []models.Tag {
+	seeded := map[string]models.Tag{}
+	for _, address := range addresses {
+		for i, value := range address.Tags {
+			if i >= len(models.LegacyTagCategories) {
+				break
+			}
+			if _, ok := taxonomy.Resolve(language, value); ok {
+				continue
+			}
+			name := strings.Join(strings.Fields(value), " ")
+			category := models.LegacyTagCategories[i]
+			tag, exists := taxonomy.Get(models.NewTagSlug(name))
+			if !exists {
+				tag = models.Tag{Slug: models.NewTagSlug(name), Category: category, CreatedAt: now}
+			} else if tag.Category != category {
+				continue
+			}
+			tag.Names = maps.Clone(tag.Names)
+			if tag.Names == nil {
+				tag.Names = map[string]string{}
+			}
+			if tag.Name(language) == "" {
+				tag.Names[language.Get()] = name
+			} else {
+				tag.Synonyms = maps.Clone(tag.Synonyms)
+				if tag.Synonyms == nil {
+					tag.Synonyms = map[string][]string{}
+				}
+				tag.Synonyms[language.Get()] = append(slices.Clone(tag.Synonyms[language.Get()]), name)
+			}
+			if err := tag.Validate(); err != nil {
+				continue
+			}
+			tag.UpdatedAt = now
+			taxonomy.Add(tag)
+			seeded[tag.Slug] = tag
+		}
+	}
+	tags := slices.Collect(maps.Values(seeded))
+	slices.SortFunc(tags, func(a, b models.Tag) int {
+		return strings.Compare(a.Slug, b.Slug)
+	})
+	return tags
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedTaxonomyTags(t *testing.T) {
+	t.Parallel()
+	taxonomy := models.NewTagTaxonomy([]models.Tag{
+		{Slug: "uk", Category: models.TagCategoryCountry, Names: map[string]string{"en": "UK"}},
+		{Slug: "writer", Category: models.TagCategoryRole, Names: map[string]string{"zh": "作家"}},
+		{Slug: "poet", Category: models.TagCategoryFigure, Names: map[string]string{"zh": "诗人"}},
+	})
+	addresses := []models.AddressItem{
+		{ID: "a1", Tags: []string{"united kingdom", "Writer", "Novelist", "extra"}},
+		{ID: "a2", Tags: []string{"UK", "poet", "novelist"}},
+	}
+	seeded := seedTaxonomyTags(taxonomy, models.LanguageEN, addresses, 100)
+	assert.Equal(t, []models.Tag{
+		{
+			Slug:      "novelist",
+			Category:  models.TagCategoryFigure,
+			Names:     map[string]string{"en": "Novelist"},
+			CreatedAt: 100,
+			UpdatedAt: 100,
+		},
+		{
+			Slug:      "united-kingdom",
+			Category:  models.TagCategoryCountry,
+			Names:     map[string]string{"en": "united kingdom"},
+			CreatedAt: 100,
+			UpdatedAt: 100,
+		},
+		{
+			Slug:      "writer",
+			Category:  models.TagCategoryRole,
+			Names:     map[string]string{"en": "Writer", "zh": "作家"},
+			UpdatedAt: 100,
+		},
+	}, seeded)
+	// the seeded tags resolve right away, a slug of another category and tags past the last category don't
+	canonical, unknown := taxonomy.Canonicalize(models.LanguageEN, []string{"novelist", "WRITER", "poet", "extra"})
+	assert.Equal(t, []string{"Novelist", "Writer"}, canonical)
+	assert.Equal(t, []string{"poet", "extra"}, unknown)
+}
+
+func TestKeepReplacedNames(t *testing.T) {
+	t.Parallel()
+	existing := models.Tag{
+		Slug:     "uk",
+		Names:    map[string]string{"en": "UK", "zh": "英国"},
+		Synonyms: map[string][]string{"en": {"Britain"}},
+	}
+	tag := models.Tag{
+		Slug:     "uk",
+		Names:    map[string]string{"en": "United Kingdom", "zh": "英国"},
+		Synonyms: map[string][]string{"en": {"Britain", "uk"}},
+	}
+	keepReplacedNames(&tag, existing)
+	assert.Equal(t, map[string][]string{"en": {"Britain", "uk"}}, tag.Synonyms)
+
+	tag = models.Tag{Slug: "uk", Names: map[string]string{"en": "United Kingdom"}}
+	keepReplacedNames(&tag, existing)
+	assert.Equal(t, map[string][]string{"en": {"UK"}, "zh": {"英国"}}, tag.Synonyms)
+	// the taxonomy rejects a tag whose names or synonyms belong to another tag
+	taxonomy := models.NewTagTaxonomy([]models.Tag{existing, {Slug: "gb", Names: map[string]string{"en": "GB"}}})
+	assert.Empty(t, taxonomy.Conflicts(tag))
+	tag.Synonyms["en"] = append(tag.Synonyms["en"], "gb")
+	assert.Equal(t, []string{`en "gb" is used by tag gb`}, taxonomy.Conflicts(tag))
+}
+
+func TestTaggedTerms(t *testing.T) {
+	t.Parallel()
+	previous := models.Tag{Slug: "uk", Names: map[string]string{"en": "UK", "zh": "英国"}}
+	updated := models.Tag{
+		Slug:     "uk",
+		Names:    map[string]string{"en": "United Kingdom", "zh": "英国"},
+		Synonyms: map[string][]string{"en": {"UK", "Britain"}},
+	}
+	assert.Equal(t, []string{"UK", "uk", "United Kingdom", "Britain"},
+		taggedTerms(models.LanguageEN, []models.Tag{previous, updated}))
+	assert.Equal(t, []string{"英国", "uk"}, taggedTerms(models.LanguageZH, []models.Tag{previous, updated}))
+}
+
+func TestCategorizeRecordTags(t *testing.T) {
+	t.Parallel()
+	// before the taxonomy is seeded the position tells the category
+	categorized, unknown := categorizeRecordTags(models.NewTagTaxonomy(nil), models.LanguageEN,
+		[]string{"UK", "Writer", "Novelist", "extra"})
+	assert.Equal(t, map[models.TagCategory][]string{
+		models.TagCategoryCountry: {"UK"},
+		models.TagCategoryRole:    {"Writer"},
+		models.TagCategoryFigure:  {"Novelist"},
+	}, categorized)
+	assert.Equal(t, []string{"extra"}, unknown)
+
+	taxonomy := models.NewTagTaxonomy([]models.Tag{
+		{Slug: "uk", Category: models.TagCategoryCountry, Names: map[string]string{"en": "UK"},
+			Synonyms: map[string][]string{"en": {"Britain"}}},
+	})
+	categorized, unknown = categorizeRecordTags(taxonomy, models.LanguageEN, []string{"Writer", "britain"})
+	assert.Equal(t, map[models.TagCategory][]string{models.TagCategoryCountry: {"UK"}}, categorized)
+	assert.Equal(t, []string{"Writer"}, unknown)
+}
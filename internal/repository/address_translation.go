@@ -0,0 +1,410 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"north-post/service/internal/domain/v1/models"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const translationGroupTable = "address_translation_groups"
+
+type LinkAddressTranslationsOption struct {
+	Entries []models.AddressRef
+}
+
+type UnlinkAddressTranslationOption struct {
+	Language models.Language
+	ID       string
+}
+
+type ListMissingTranslationsOption struct {
+	Language models.Language
+}
+
+type MapAddressTranslationsOption struct {
+	From models.Language
+	To   models.Language
+	IDs  []string
+}
+
+type MapAddressTranslationsResult struct {
+	Addresses    []models.AddressItem // entries in the target language, in the order of the source IDs
+	Untranslated []string             // source addresses without an entry in the target language
+}
+
+// Put the entries into one translation group. Groups the entries already belong to are joined,
+// as long as every language ends up with a single address, otherwise ErrTranslationConflict is returned
+func (r *AddressRepository) LinkAddressTranslations(
+	ctx context.Context, opts LinkAddressTranslationsOption) (*models.TranslationGroup, error) {
+	groups := r.client.Collection(translationGroupTable)
+	var linked models.TranslationGroup
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// a transaction has to read everything before it writes
+		entries := make([]models.AddressItem, len(opts.Entries))
+		for i, entry := range opts.Entries {
+			docRef := r.client.Collection(getAddressCollectionName(entry.Language)).Doc(entry.ID)
+			address, err := r.getAddressInTransaction(tx, docRef)
+			if err != nil {
+				return err
+			}
+			if address.IsDeleted() {
+				return fmt.Errorf("address %s is in the trash: %w", entry.ID, ErrNotFound)
+			}
+			entries[i] = *address
+		}
+		existing := []models.TranslationGroup{}
+		for _, entry := range entries {
+			if entry.TranslationGroupID == "" || slices.ContainsFunc(existing, func(g models.TranslationGroup) bool {
+				return g.ID == entry.TranslationGroupID
+			}) {
+				continue
+			}
+			group, err := r.getTranslationGroupInTransaction(tx, groups.Doc(entry.TranslationGroupID))
+			if err != nil {
+				return err
+			}
+			existing = append(existing, *group)
+		}
+		// the oldest group is kept, the others are folded into it
+		slices.SortFunc(existing, func(a, b models.TranslationGroup) int {
+			return cmp.Compare(a.CreatedAt, b.CreatedAt)
+		})
+		now := time.Now().UnixMilli()
+		linked = models.TranslationGroup{ID: groups.NewDoc().ID, Members: map[string]string{}, CreatedAt: now}
+		if len(existing) > 0 {
+			linked.ID = existing[0].ID
+			linked.CreatedAt = cmp.Or(existing[0].CreatedAt, now)
+		}
+		for _, group := range existing {
+			for language, id := range group.Members {
+				if err := addTranslationMember(linked.Members, models.Language(language), id); err != nil {
+					return err
+				}
+			}
+		}
+		for _, entry := range opts.Entries {
+			if err := addTranslationMember(linked.Members, entry.Language, entry.ID); err != nil {
+				return err
+			}
+		}
+		linked.UpdatedAt = now
+		members, err := r.getTranslationMembersInTransaction(tx, linked.Members)
+		if err != nil {
+			return err
+		}
+		for language, doc := range members {
+			if !doc.Exists() {
+				// permanently removed since it was linked
+				delete(linked.Members, language)
+				continue
+			}
+			if groupID, _ := doc.DataAt("translationGroupId"); groupID == linked.ID {
+				continue
+			}
+			err := r.setTranslationGroupInTransaction(tx, doc.Ref, models.Language(language), linked.ID, now)
+			if err != nil {
+				return err
+			}
+		}
+		for _, group := range existing[min(1, len(existing)):] {
+			if err := tx.Delete(groups.Doc(group.ID)); err != nil {
+				return err
+			}
+		}
+		return tx.Set(groups.Doc(linked.ID), linked)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrTranslationConflict) {
+			return nil, err
+		}
+		r.logger.Error("failed to link address translations", "entries", opts.Entries, "error", err)
+		return nil, fmt.Errorf("failed to link address translations: %w", err)
+	}
+	return &linked, nil
+}
+
+// Take an address out of its translation group, a group left with a single address is removed
+func (r *AddressRepository) UnlinkAddressTranslation(ctx context.Context, opts UnlinkAddressTranslationOption) error {
+	docRef := r.client.Collection(getAddressCollectionName(opts.Language)).Doc(opts.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		address, err := r.getAddressInTransaction(tx, docRef)
+		if err != nil {
+			return err
+		}
+		if address.TranslationGroupID == "" {
+			return fmt.Errorf("address %s: %w", opts.ID, ErrNotLinked)
+		}
+		groupRef := r.client.Collection(translationGroupTable).Doc(address.TranslationGroupID)
+		group, err := r.getTranslationGroupInTransaction(tx, groupRef)
+		if err != nil {
+			return err
+		}
+		delete(group.Members, opts.Language.Get())
+		members, err := r.getTranslationMembersInTransaction(tx, group.Members)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		if err := r.setTranslationGroupInTransaction(tx, docRef, opts.Language, "", now); err != nil {
+			return err
+		}
+		if len(group.Members) > 1 {
+			group.UpdatedAt = now
+			return tx.Set(groupRef, *group)
+		}
+		for language, doc := range members {
+			if !doc.Exists() {
+				continue
+			}
+			if err := r.setTranslationGroupInTransaction(tx, doc.Ref, models.Language(language), "", now); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(groupRef)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrNotLinked) {
+			return err
+		}
+		r.logger.Error("failed to unlink address translation", "addressID", opts.ID, "error", err)
+		return fmt.Errorf("failed to unlink address translation: %w", err)
+	}
+	return nil
+}
+
+// Addresses of the language that miss a linked entry in at least one other enabled language,
+// oldest first. A linked entry that went to the trash counts as missing
+func (r *AddressRepository) ListMissingTranslations(
+	ctx context.Context, opts ListMissingTranslationsOption) ([]models.MissingTranslation, error) {
+	addresses, err := r.getLiveAddresses(ctx, opts.Language)
+	if err != nil {
+		return nil, err
+	}
+	others := slices.DeleteFunc(models.Languages().Enabled(), func(language models.Language) bool {
+		return language == opts.Language.Lower()
+	})
+	live := map[models.Language]map[string]bool{}
+	for _, language := range others {
+		items, err := r.getLiveAddresses(ctx, language)
+		if err != nil {
+			return nil, err
+		}
+		live[language] = map[string]bool{}
+		for _, item := range items {
+			live[language][item.ID] = true
+		}
+	}
+	groups, err := r.getTranslationGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// members merged before merges moved the membership to the survivor resolve through their alias
+	for _, language := range others {
+		unresolved := []string{}
+		for _, members := range groups {
+			if id, ok := members[language.Get()]; ok && !live[language][id] {
+				unresolved = append(unresolved, id)
+			}
+		}
+		aliases, err := r.getAddressAliases(ctx, language, unresolved)
+		if err != nil {
+			r.logger.Error("failed to get address aliases", "language", language, "error", err)
+			return nil, fmt.Errorf("failed to get address aliases: %w", err)
+		}
+		for id, targetID := range aliases {
+			if live[language][targetID] {
+				live[language][id] = true
+			}
+		}
+	}
+	missing := []models.MissingTranslation{}
+	for _, address := range addresses {
+		members := groups[address.TranslationGroupID]
+		languages := []models.Language{}
+		for _, language := range others {
+			if id, ok := members[language.Get()]; !ok || !live[language][id] {
+				languages = append(languages, language)
+			}
+		}
+		if len(languages) > 0 {
+			missing = append(missing, models.MissingTranslation{Address: address, MissingLanguages: languages})
+		}
+	}
+	slices.SortFunc(missing, func(a, b models.MissingTranslation) int {
+		return cmp.Compare(a.Address.CreatedAt, b.Address.CreatedAt)
+	})
+	return missing, nil
+}
+
+// Entries in the target language of the given addresses, through their translation groups.
+// IDs of merged addresses resolve to the survivor first
+func (r *AddressRepository) MapAddressTranslations(
+	ctx context.Context, opts *MapAddressTranslationsOption) (*MapAddressTranslationsResult, error) {
+	sources, err := r.GetAddressesByIDs(ctx, &GetAddressesByIDsOptions{Language: opts.From, IDs: opts.IDs})
+	if err != nil {
+		return nil, err
+	}
+	groupRefs := []*firestore.DocumentRef{}
+	for _, address := range sources.Addresses {
+		if address.TranslationGroupID != "" {
+			groupRefs = append(groupRefs, r.client.Collection(translationGroupTable).Doc(address.TranslationGroupID))
+		}
+	}
+	docs, err := r.client.GetAll(ctx, groupRefs)
+	if err != nil {
+		r.logger.Error("failed to get translation groups", "error", err)
+		return nil, fmt.Errorf("failed to get translation groups: %w", err)
+	}
+	targetIDs := map[string]string{}
+	for _, doc := range docs {
+		var group models.TranslationGroup
+		if !doc.Exists() || doc.DataTo(&group) != nil {
+			continue
+		}
+		if id, ok := group.Members[opts.To.Get()]; ok {
+			targetIDs[group.ID] = id
+		}
+	}
+	ids := []string{}
+	for _, id := range targetIDs {
+		ids = append(ids, id)
+	}
+	// members merged before merges moved the membership to the survivor resolve through their alias
+	aliases, err := r.getAddressAliases(ctx, opts.To, ids)
+	if err != nil {
+		r.logger.Error("failed to get address aliases", "language", opts.To, "error", err)
+		return nil, fmt.Errorf("failed to get address aliases: %w", err)
+	}
+	for groupID, id := range targetIDs {
+		if targetID, ok := aliases[id]; ok {
+			targetIDs[groupID] = targetID
+			ids = append(ids, targetID)
+		}
+	}
+	// a missing or trashed entry is untranslated
+	targets, _, err := r.batchFetchAddresses(ctx, getAddressCollectionName(opts.To), ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]models.AddressItem{}
+	for _, target := range targets {
+		byID[target.ID] = target
+	}
+	result := &MapAddressTranslationsResult{Addresses: []models.AddressItem{}, Untranslated: []string{}}
+	for _, address := range sources.Addresses {
+		target, ok := byID[targetIDs[address.TranslationGroupID]]
+		if !ok || address.TranslationGroupID == "" {
+			result.Untranslated = append(result.Untranslated, address.ID)
+			continue
+		}
+		if !slices.ContainsFunc(result.Addresses, func(a models.AddressItem) bool { return a.ID == target.ID }) {
+			result.Addresses = append(result.Addresses, target)
+		}
+	}
+	return result, nil
+}
+
+// =========== Helper methods ==========
+
+func addTranslationMember(members map[string]string, language models.Language, id string) error {
+	if current, ok := members[language.Get()]; ok && current != id {
+		return fmt.Errorf("%w: %s is linked to %s, not %s", ErrTranslationConflict, language.Get(), current, id)
+	}
+	members[language.Get()] = id
+	return nil
+}
+
+func (r *AddressRepository) getTranslationGroupInTransaction(
+	tx *firestore.Transaction, docRef *firestore.DocumentRef) (*models.TranslationGroup, error) {
+	doc, err := tx.Get(docRef)
+	if status.Code(err) == codes.NotFound {
+		// the addresses still point at a group that is gone, it is written again when they are linked
+		return &models.TranslationGroup{ID: docRef.ID, Members: map[string]string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation group %s: %w", docRef.ID, err)
+	}
+	var group models.TranslationGroup
+	if err := doc.DataTo(&group); err != nil {
+		return nil, fmt.Errorf("failed to parse translation group %s: %w", docRef.ID, err)
+	}
+	if group.Members == nil {
+		group.Members = map[string]string{}
+	}
+	return &group, nil
+}
+
+// Point the address to the group, or take it out of its group when groupID is empty.
+// The update time is bumped and the index change queued so the search record follows
+func (r *AddressRepository) setTranslationGroupInTransaction(
+	tx *firestore.Transaction,
+	docRef *firestore.DocumentRef,
+	language models.Language,
+	groupID string,
+	now int64) error {
+	var value interface{} = groupID
+	if groupID == "" {
+		value = firestore.Delete
+	}
+	updates := []firestore.Update{
+		{Path: "translationGroupId", Value: value},
+		{Path: "updatedAt", Value: now},
+	}
+	if err := tx.Update(docRef, updates); err != nil {
+		return err
+	}
+	return setOutboxEntry(r.client, tx, language, docRef.ID, models.OutboxOperationUpsert)
+}
+
+// Address documents of the group members keyed by language, missing documents included
+func (r *AddressRepository) getTranslationMembersInTransaction(
+	tx *firestore.Transaction, members map[string]string) (map[string]*firestore.DocumentSnapshot, error) {
+	languages := make([]string, 0, len(members))
+	docRefs := make([]*firestore.DocumentRef, 0, len(members))
+	for language, id := range members {
+		languages = append(languages, language)
+		docRefs = append(docRefs, r.client.Collection(getAddressCollectionName(models.Language(language))).Doc(id))
+	}
+	docs, err := tx.GetAll(docRefs)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make(map[string]*firestore.DocumentSnapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[languages[i]] = doc
+	}
+	return snapshots, nil
+}
+
+// Members of every translation group keyed by group ID
+func (r *AddressRepository) getTranslationGroups(ctx context.Context) (map[string]map[string]string, error) {
+	iter := r.client.Collection(translationGroupTable).Documents(ctx)
+	defer iter.Stop()
+	groups := map[string]map[string]string{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("failed to read translation groups", "error", err)
+			return nil, fmt.Errorf("failed to read translation groups: %w", err)
+		}
+		var group models.TranslationGroup
+		if err := doc.DataTo(&group); err != nil {
+			r.logger.Warn("failed to parse translation group", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		groups[doc.Ref.ID] = group.Members
+	}
+	return groups, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTranslationMember(t *testing.T) {
+	t.Parallel()
+	members := map[string]string{"en": "a1"}
+	assert.NoError(t, addTranslationMember(members, "ZH", "b1"))
+	assert.NoError(t, addTranslationMember(members, "en", "a1"))
+	assert.Equal(t, map[string]string{"en": "a1", "zh": "b1"}, members)
+	err := addTranslationMember(members, "zh", "b2")
+	assert.ErrorIs(t, err, ErrTranslationConflict)
+	assert.Equal(t, "b1", members["zh"])
+}
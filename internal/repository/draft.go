@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"north-post/service/internal/domain/v1/models"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	draftTable       = "drafts"
+	maxDraftsPerUser = 100
+)
+
+var ErrDraftLimitReached = fmt.Errorf("a user can keep at most %d drafts", maxDraftsPerUser)
+
+type DraftRepository struct {
+	client *firestore.Client
+	logger *slog.Logger
+}
+
+func NewDraftRepository(client *firestore.Client, logger *slog.Logger) *DraftRepository {
+	return &DraftRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+type ListDraftsOptions struct {
+	Uid string
+}
+
+type GetDraftOptions struct {
+	Uid string
+	ID  string
+}
+
+type CreateDraftOptions struct {
+	Uid   string
+	Draft models.Draft
+}
+
+// Nil fields are left unchanged
+type UpdateDraftOptions struct {
+	Uid                string
+	ID                 string
+	RecipientAddressID *string
+	Language           *models.Language
+	Body               *string
+	MusicFilename      *string
+}
+
+type DeleteDraftOptions struct {
+	Uid string
+	ID  string
+}
+
+// List drafts through the IDs saved on the app user document, newest first.
+// IDs that no longer point to an owned draft are removed from the user document.
+func (r *DraftRepository) ListDrafts(ctx context.Context, opts ListDraftsOptions) ([]models.Draft, error) {
+	userRef := r.client.Collection(appUserTable).Doc(opts.Uid)
+	userDoc, err := userRef.Get(ctx)
+	if err != nil {
+		r.logger.Error("failed to get app user document", "uid", opts.Uid, "error", err)
+		return nil, fmt.Errorf("failed to get app user document: %w", err)
+	}
+	var appUser models.AppUser
+	if err := userDoc.DataTo(&appUser); err != nil {
+		r.logger.Error("failed to parse app user document", "uid", opts.Uid, "error", err)
+		return nil, fmt.Errorf("failed to parse app user document: %w", err)
+	}
+	if len(appUser.Drafts) == 0 {
+		return []models.Draft{}, nil
+	}
+	collectionRef := r.client.Collection(draftTable)
+	docRefs := make([]*firestore.DocumentRef, len(appUser.Drafts))
+	for i, id := range appUser.Drafts {
+		docRefs[i] = collectionRef.Doc(id)
+	}
+	docs, err := r.client.GetAll(ctx, docRefs)
+	if err != nil {
+		r.logger.Error("failed to batch fetch drafts", "uid", opts.Uid, "error", err)
+		return nil, fmt.Errorf("failed to batch fetch drafts: %w", err)
+	}
+	drafts := []models.Draft{}
+	staleIDs := []interface{}{}
+	for _, doc := range docs {
+		if !doc.Exists() {
+			staleIDs = append(staleIDs, doc.Ref.ID)
+			continue
+		}
+		var draft models.Draft
+		if err := doc.DataTo(&draft); err != nil || draft.OwnerID != opts.Uid {
+			r.logger.Warn("invalid draft linked to app user", "uid", opts.Uid, "draftID", doc.Ref.ID, "error", err)
+			staleIDs = append(staleIDs, doc.Ref.ID)
+			continue
+		}
+		drafts = append(drafts, draft)
+	}
+	if len(staleIDs) > 0 {
+		_, err := userRef.Update(ctx, []firestore.Update{
+			{Path: "drafts", Value: firestore.ArrayRemove(staleIDs...)},
+		})
+		if err != nil {
+			r.logger.Warn("failed to remove stale draft IDs", "uid", opts.Uid, "error", err)
+		}
+	}
+	slices.SortFunc(drafts, func(a, b models.Draft) int {
+		return cmp.Compare(b.UpdatedAt, a.UpdatedAt)
+	})
+	return drafts, nil
+}
+
+func (r *DraftRepository) GetDraft(ctx context.Context, opts GetDraftOptions) (*models.Draft, error) {
+	doc, err := r.client.Collection(draftTable).Doc(opts.ID).Get(ctx)
+	if err != nil {
+		return nil, r.handleGetDraftError(opts.Uid, opts.ID, err)
+	}
+	return r.parseOwnedDraft(doc, opts.Uid)
+}
+
+// Create a draft and link it to the app user in the same transaction
+func (r *DraftRepository) CreateDraft(ctx context.Context, opts CreateDraftOptions) (*models.Draft, error) {
+	userRef := r.client.Collection(appUserTable).Doc(opts.Uid)
+	docRef := r.client.Collection(draftTable).NewDoc()
+	now := time.Now().UnixMilli()
+	draft := opts.Draft
+	draft.ID = docRef.ID
+	draft.OwnerID = opts.Uid
+	draft.Language = draft.Language.Lower()
+	draft.CreatedAt = now
+	draft.UpdatedAt = now
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		userDoc, err := tx.Get(userRef)
+		if err != nil {
+			return fmt.Errorf("failed to get app user document: %w", err)
+		}
+		var appUser models.AppUser
+		if err := userDoc.DataTo(&appUser); err != nil {
+			return fmt.Errorf("failed to parse app user document: %w", err)
+		}
+		if len(appUser.Drafts) >= maxDraftsPerUser {
+			return ErrDraftLimitReached
+		}
+		if err := tx.Create(docRef, draft); err != nil {
+			return err
+		}
+		return tx.Update(userRef, []firestore.Update{
+			{Path: "drafts", Value: firestore.ArrayUnion(draft.ID)},
+		})
+	})
+	if err != nil {
+		r.logger.Error("failed to create draft", "uid", opts.Uid, "error", err)
+		if errors.Is(err, ErrDraftLimitReached) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create draft: %w", err)
+	}
+	return &draft, nil
+}
+
+func (r *DraftRepository) UpdateDraft(ctx context.Context, opts UpdateDraftOptions) (*models.Draft, error) {
+	docRef := r.client.Collection(draftTable).Doc(opts.ID)
+	var draft *models.Draft
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return r.handleGetDraftError(opts.Uid, opts.ID, err)
+		}
+		draft, err = r.parseOwnedDraft(doc, opts.Uid)
+		if err != nil {
+			return err
+		}
+		if opts.RecipientAddressID != nil {
+			draft.RecipientAddressID = *opts.RecipientAddressID
+		}
+		if opts.Language != nil {
+			draft.Language = opts.Language.Lower()
+		}
+		if opts.Body != nil {
+			draft.Body = *opts.Body
+		}
+		if opts.MusicFilename != nil {
+			draft.MusicFilename = *opts.MusicFilename
+		}
+		draft.UpdatedAt = time.Now().UnixMilli()
+		return tx.Set(docRef, draft)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		r.logger.Error("failed to update draft", "uid", opts.Uid, "draftID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to update draft: %w", err)
+	}
+	return draft, nil
+}
+
+// Delete a draft and unlink it from the app user in the same transaction
+func (r *DraftRepository) DeleteDraft(ctx context.Context, opts DeleteDraftOptions) (string, error) {
+	userRef := r.client.Collection(appUserTable).Doc(opts.Uid)
+	docRef := r.client.Collection(draftTable).Doc(opts.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return r.handleGetDraftError(opts.Uid, opts.ID, err)
+		}
+		if _, err := r.parseOwnedDraft(doc, opts.Uid); err != nil {
+			return err
+		}
+		if err := tx.Delete(docRef); err != nil {
+			return err
+		}
+		return tx.Update(userRef, []firestore.Update{
+			{Path: "drafts", Value: firestore.ArrayRemove(opts.ID)},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+		r.logger.Error("failed to delete draft", "uid", opts.Uid, "draftID", opts.ID, "error", err)
+		return "", fmt.Errorf("failed to delete draft: %w", err)
+	}
+	return opts.ID, nil
+}
+
+// =========== Helper methods ==========
+
+func (r *DraftRepository) handleGetDraftError(uid string, id string, err error) error {
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("draft %s: %w", id, ErrNotFound)
+	}
+	r.logger.Error("failed to get draft", "uid", uid, "draftID", id, "error", err)
+	return fmt.Errorf("failed to get draft: %w", err)
+}
+
+// Drafts owned by someone else are reported as not found so IDs can't be probed
+func (r *DraftRepository) parseOwnedDraft(doc *firestore.DocumentSnapshot, uid string) (*models.Draft, error) {
+	var draft models.Draft
+	if err := doc.DataTo(&draft); err != nil {
+		r.logger.Error("failed to parse draft", "draftID", doc.Ref.ID, "error", err)
+		return nil, fmt.Errorf("failed to parse draft: %w", err)
+	}
+	if draft.OwnerID != uid {
+		r.logger.Warn("user requested a draft owned by someone else", "uid", uid, "draftID", doc.Ref.ID)
+		return nil, fmt.Errorf("draft %s: %w", doc.Ref.ID, ErrNotFound)
+	}
+	return &draft, nil
+}
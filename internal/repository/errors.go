@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"north-post/service/internal/domain/v1/models"
+)
+
+// Sentinel errors that the transport layer maps to HTTP status codes
+var (
+	ErrNotFound   = errors.New("resource not found")
+	ErrNotInTrash = errors.New("resource is not in the trash")
+	ErrConflict   = errors.New("resource was changed since it was read")
+	ErrDuplicate  = errors.New("a similar address already exists")
+	ErrNotLinked  = errors.New("address has no linked translations")
+	// a translation group holds one address per language
+	ErrTranslationConflict = errors.New("language is already linked to another address")
+)
+
+// The update was based on an outdated version of the address, Current holds the stored version
+type AddressConflictError struct {
+	Current models.AddressItem
+}
+
+func (e *AddressConflictError) Error() string {
+	return fmt.Sprintf("address %s was updated at %d: %s", e.Current.ID, e.Current.UpdatedAt, ErrConflict)
+}
+
+func (e *AddressConflictError) Unwrap() error {
+	return ErrConflict
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"north-post/service/internal/domain/v1/models"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const jobTable = "jobs"
+
+var ErrJobFinished = errors.New("job has already finished")
+
+// Reports how many documents a long running operation has handled so far
+type ProgressFunc func(processed int, failed int)
+
+type JobRepository struct {
+	client *firestore.Client
+	logger *slog.Logger
+}
+
+func NewJobRepository(client *firestore.Client, logger *slog.Logger) *JobRepository {
+	return &JobRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+type ListJobsOptions struct {
+	Limit int
+}
+
+// Nil fields are left unchanged, updatedAt is always refreshed
+type UpdateJobOptions struct {
+	ID         string
+	Status     *models.JobStatus
+	Processed  *int64
+	Failed     *int64
+	Error      *string
+	StartedAt  *int64
+	FinishedAt *int64
+}
+
+type FailStaleJobsOptions struct {
+	UpdatedBefore int64
+	Reason        string
+}
+
+func (r *JobRepository) CreateJob(ctx context.Context, job models.Job) (*models.Job, error) {
+	docRef := r.client.Collection(jobTable).NewDoc()
+	now := time.Now().UnixMilli()
+	job.ID = docRef.ID
+	job.Status = models.JobStatusQueued
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	if _, err := docRef.Create(ctx, job); err != nil {
+		r.logger.Error("failed to create job", "type", job.Type, "error", err)
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+	return &job, nil
+}
+
+func (r *JobRepository) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	doc, err := r.client.Collection(jobTable).Doc(id).Get(ctx)
+	if err != nil {
+		return nil, r.handleGetJobError(id, err)
+	}
+	return r.parseJob(doc)
+}
+
+// Newest jobs first
+func (r *JobRepository) ListJobs(ctx context.Context, opts ListJobsOptions) ([]models.Job, error) {
+	docs, err := r.client.Collection(jobTable).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(opts.Limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to list jobs", "error", err)
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	jobs := make([]models.Job, 0, len(docs))
+	for _, doc := range docs {
+		job, err := r.parseJob(doc)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, nil
+}
+
+func (r *JobRepository) UpdateJob(ctx context.Context, opts UpdateJobOptions) (*models.Job, error) {
+	updates := []firestore.Update{
+		{Path: "updatedAt", Value: time.Now().UnixMilli()},
+	}
+	if opts.Status != nil {
+		updates = append(updates, firestore.Update{Path: "status", Value: *opts.Status})
+	}
+	if opts.Processed != nil {
+		updates = append(updates, firestore.Update{Path: "processed", Value: *opts.Processed})
+	}
+	if opts.Failed != nil {
+		updates = append(updates, firestore.Update{Path: "failed", Value: *opts.Failed})
+	}
+	if opts.Error != nil {
+		updates = append(updates, firestore.Update{Path: "error", Value: *opts.Error})
+	}
+	if opts.StartedAt != nil {
+		updates = append(updates, firestore.Update{Path: "startedAt", Value: *opts.StartedAt})
+	}
+	if opts.FinishedAt != nil {
+		updates = append(updates, firestore.Update{Path: "finishedAt", Value: *opts.FinishedAt})
+	}
+	docRef := r.client.Collection(jobTable).Doc(opts.ID)
+	if _, err := docRef.Update(ctx, updates); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("job %s: %w", opts.ID, ErrNotFound)
+		}
+		r.logger.Error("failed to update job", "jobID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to update job: %w", err)
+	}
+	// read back so the runner sees cancel requests made by other instances
+	return r.GetJob(ctx, opts.ID)
+}
+
+// Queued jobs are canceled right away, running jobs are flagged and stopped by their runner
+func (r *JobRepository) RequestJobCancel(ctx context.Context, id string) (*models.Job, error) {
+	docRef := r.client.Collection(jobTable).Doc(id)
+	var job *models.Job
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return r.handleGetJobError(id, err)
+		}
+		job, err = r.parseJob(doc)
+		if err != nil {
+			return err
+		}
+		if job.Status.IsFinished() {
+			return ErrJobFinished
+		}
+		now := time.Now().UnixMilli()
+		job.CancelRequested = true
+		job.UpdatedAt = now
+		if job.Status == models.JobStatusQueued {
+			job.Status = models.JobStatusCanceled
+			job.FinishedAt = now
+		}
+		return tx.Set(docRef, job)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrJobFinished) {
+			return nil, err
+		}
+		r.logger.Error("failed to request job cancel", "jobID", id, "error", err)
+		return nil, fmt.Errorf("failed to request job cancel: %w", err)
+	}
+	return job, nil
+}
+
+// Mark unfinished jobs whose heartbeat stopped as failed, their runner is gone.
+// A job whose heartbeat comes in meanwhile is left alone
+func (r *JobRepository) FailStaleJobs(ctx context.Context, opts FailStaleJobsOptions) (int, error) {
+	docs, err := r.client.Collection(jobTable).
+		Where("status", "in", []models.JobStatus{models.JobStatusQueued, models.JobStatusRunning}).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to query unfinished jobs", "error", err)
+		return 0, fmt.Errorf("failed to query unfinished jobs: %w", err)
+	}
+	now := time.Now().UnixMilli()
+	count := 0
+	for _, doc := range docs {
+		job, err := r.parseJob(doc)
+		if err != nil || job.UpdatedAt >= opts.UpdatedBefore {
+			continue
+		}
+		_, err = doc.Ref.Update(ctx, []firestore.Update{
+			{Path: "status", Value: models.JobStatusFailed},
+			{Path: "error", Value: opts.Reason},
+			{Path: "finishedAt", Value: now},
+			{Path: "updatedAt", Value: now},
+		}, firestore.LastUpdateTime(doc.UpdateTime))
+		if err != nil {
+			r.logger.Warn("failed to mark stale job as failed", "jobID", job.ID, "error", err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// =========== Helper methods ==========
+
+func (r *JobRepository) handleGetJobError(id string, err error) error {
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("job %s: %w", id, ErrNotFound)
+	}
+	r.logger.Error("failed to get job", "jobID", id, "error", err)
+	return fmt.Errorf("failed to get job: %w", err)
+}
+
+func (r *JobRepository) parseJob(doc *firestore.DocumentSnapshot) (*models.Job, error) {
+	var job models.Job
+	if err := doc.DataTo(&job); err != nil {
+		r.logger.Error("failed to parse job", "jobID", doc.Ref.ID, "error", err)
+		return nil, fmt.Errorf("failed to parse job: %w", err)
+	}
+	return &job, nil
+}
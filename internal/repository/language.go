@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"north-post/service/internal/domain/v1/models"
+
+	"cloud.google.com/go/firestore"
+)
+
+const languageTable = "languages"
+
+type LanguageRepository struct {
+	client *firestore.Client
+	logger *slog.Logger
+}
+
+func NewLanguageRepository(client *firestore.Client, logger *slog.Logger) *LanguageRepository {
+	return &LanguageRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Stored language configurations, one document per language code
+func (r *LanguageRepository) ListLanguages(ctx context.Context) ([]models.LanguageConfig, error) {
+	docs, err := r.client.Collection(languageTable).Documents(ctx).GetAll()
+	if err != nil {
+		r.logger.Error("failed to list languages", "error", err)
+		return nil, fmt.Errorf("failed to list languages: %w", err)
+	}
+	configs := make([]models.LanguageConfig, 0, len(docs))
+	for _, doc := range docs {
+		var config models.LanguageConfig
+		if err := doc.DataTo(&config); err != nil {
+			r.logger.Warn("failed to parse language", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		config.Code = models.Language(doc.Ref.ID)
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+func (r *LanguageRepository) SetLanguage(ctx context.Context, config models.LanguageConfig) error {
+	_, err := r.client.Collection(languageTable).Doc(config.Code.Get()).Set(ctx, config)
+	if err != nil {
+		r.logger.Error("failed to set language", "language", config.Code, "error", err)
+		return fmt.Errorf("failed to set language: %w", err)
+	}
+	return nil
+}
@@ -2,40 +2,45 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/infra"
+	"slices"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	musicBucketName     = "northpost-music"
 	musicCollectionName = "music_list"
+	// firestore allows at most 30 values in an array-contains-any filter
+	arrayContainsAnyLimit = 30
+	// bytes downloaded to read the ID3 tag and the first audio frame
+	metadataProbeSize = 64 * 1024
+	audioProbeSize    = 8 * 1024
 )
 
 type MusicRepository struct {
-	client          *s3.Client
-	presignedClient *s3.PresignClient
+	bucket          infra.BucketClient
 	firestoreClient *firestore.Client
 	logger          *slog.Logger
 }
 
 func NewMusicRepository(
-	client *s3.Client,
-	presignedClient *s3.PresignClient,
+	bucket infra.BucketClient,
 	firestoreClient *firestore.Client,
 	logger *slog.Logger,
 ) *MusicRepository {
 	return &MusicRepository{
-		client:          client,
-		presignedClient: presignedClient,
+		bucket:          bucket,
 		firestoreClient: firestoreClient,
 		logger:          logger,
 	}
@@ -53,6 +58,20 @@ type RefreshMusicListResponse struct {
 	Data []models.Music
 }
 
+type ReportMusicDurationOptions struct {
+	Filename    string
+	DurationSec int64
+}
+
+type GetMusicByFilenamesOptions struct {
+	Filenames []string
+}
+
+type GetMusicByFilenamesResponse struct {
+	Music            []models.Music
+	InvalidFilenames []string
+}
+
 type GetAllMusicListResponse struct {
 	Data []models.Music
 }
@@ -61,15 +80,12 @@ type GetAllMusicListResponse struct {
 func (r *MusicRepository) GetPresignedMusicURL(
 	ctx context.Context,
 	opts GetPresignedMusicURLOptions) (*GetPresignedMusicURLResponse, error) {
-	request, err := r.presignedClient.PresignGetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(musicBucketName),
-		Key:    aws.String(opts.Filename),
-	}, s3.WithPresignExpires(15*time.Minute))
+	url, err := r.bucket.PresignGetObject(ctx, musicBucketName, opts.Filename, 15*time.Minute)
 	if err != nil {
 		r.logger.Error("failed to get music url", "error", err)
 		return nil, fmt.Errorf("failed to get music url: %w", err)
 	}
-	return &GetPresignedMusicURLResponse{URL: request.URL}, nil
+	return &GetPresignedMusicURLResponse{URL: url}, nil
 }
 
 // Currently we don't implement pagination because the list is still small
@@ -104,63 +120,133 @@ func (r *MusicRepository) GetAllMusicList(
 	return &GetAllMusicListResponse{Data: musicList}, nil
 }
 
+// Get music documents by their bucket keys, keys without a matching document are returned as invalid
+func (r *MusicRepository) GetMusicByFilenames(
+	ctx context.Context,
+	opts *GetMusicByFilenamesOptions) (*GetMusicByFilenamesResponse, error) {
+	musicList := []models.Music{}
+	invalidFilenames := []string{}
+	if len(opts.Filenames) == 0 {
+		return &GetMusicByFilenamesResponse{Music: musicList, InvalidFilenames: invalidFilenames}, nil
+	}
+	collection := r.firestoreClient.Collection(musicCollectionName)
+	docRefs := make([]*firestore.DocumentRef, len(opts.Filenames))
+	for i, filename := range opts.Filenames {
+		docRefs[i] = collection.Doc(getDocId(splitMusicKey(filename)))
+	}
+	docs, err := r.firestoreClient.GetAll(ctx, docRefs)
+	if err != nil {
+		r.logger.Error("failed to batch fetch music documents", "error", err)
+		return nil, fmt.Errorf("failed to batch fetch music documents: %w", err)
+	}
+	// GetAll returns the snapshots in the same order as the refs
+	for i, doc := range docs {
+		if !doc.Exists() {
+			invalidFilenames = append(invalidFilenames, opts.Filenames[i])
+			continue
+		}
+		var music models.Music
+		if err := doc.DataTo(&music); err != nil {
+			r.logger.Warn("failed to parse music document", "docID", doc.Ref.ID, "error", err)
+			invalidFilenames = append(invalidFilenames, opts.Filenames[i])
+			continue
+		}
+		musicList = append(musicList, music)
+	}
+	return &GetMusicByFilenamesResponse{Music: musicList, InvalidFilenames: invalidFilenames}, nil
+}
+
 // Refresh he music list and store it in the database
 func (r *MusicRepository) RefreshMusicList(
 	ctx context.Context) (*RefreshMusicListResponse, error) {
 	var musicList []models.Music
-	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
-		Bucket: aws.String(musicBucketName),
-	})
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(ctx)
-		if err != nil {
-			r.logger.Error("failed to list bucket objects", "error", err)
-			return nil, fmt.Errorf("failed to list bucket objects: %w", err)
-		}
-
-		for _, obj := range page.Contents {
-			key := aws.ToString(obj.Key)
-			fileSize := float64(aws.ToInt64(obj.Size)) / (1024 * 1024)
-			lastModified := aws.ToTime(obj.LastModified).UnixMilli()
-			genre, title := splitMusicKey(key)
-			// continue if either genre or title is missing
-			if genre == "" || title == "" {
-				continue
-			}
-			musicList = append(musicList, models.Music{
-				Filename:     key,
-				Title:        title,
-				Genre:        genre,
-				Size:         roundFilesize(fileSize, 2),
-				LastModified: lastModified,
-				DurationSec:  -1, // default unset, will update when user plays it the first time
-			})
+	// object sizes in bytes, used to estimate durations of constant bitrate files
+	objectSizes := make(map[string]int64)
+	objects, err := r.bucket.ListObjects(ctx, musicBucketName)
+	if err != nil {
+		r.logger.Error("failed to list bucket objects", "error", err)
+		return nil, fmt.Errorf("failed to list bucket objects: %w", err)
+	}
+	for _, obj := range objects {
+		fileSize := float64(obj.Size) / (1024 * 1024)
+		lastModified := obj.LastModified.UnixMilli()
+		genre, title := splitMusicKey(obj.Key)
+		// continue if either genre or title is missing
+		if genre == "" || title == "" {
+			continue
 		}
+		objectSizes[obj.Key] = obj.Size
+		musicList = append(musicList, models.Music{
+			Filename:     obj.Key,
+			Title:        title,
+			Genre:        genre,
+			Size:         roundFilesize(fileSize, 2),
+			LastModified: lastModified,
+			DurationSec:  unknownDuration, // filled from the file metadata when the document is written
+		})
 	}
 	// write data to database
-	if err := r.updateMusicList(ctx, musicList); err != nil {
+	deletedFilenames, err := r.updateMusicList(ctx, musicList, objectSizes)
+	if err != nil {
 		return nil, err
 	}
+	// the refresh already succeeded, stale likes are also pruned when a user lists them
+	if err := r.removeLikedMusics(ctx, deletedFilenames); err != nil {
+		r.logger.Warn("failed to remove deleted music from user likes", "error", err)
+	}
 	return &RefreshMusicListResponse{Data: musicList}, nil
 }
 
-func (r *MusicRepository) updateMusicList(ctx context.Context, musicList []models.Music) error {
-	identifierFields := []string{"size", "lastModified"}
-	type identifier struct {
-		Size         float64 `firestore:"size"`
-		LastModified int64   `firestore:"lastModified"`
+// Set the duration reported by a client, durations already stored are kept
+func (r *MusicRepository) ReportMusicDuration(
+	ctx context.Context,
+	opts ReportMusicDurationOptions) (*models.Music, error) {
+	docRef := r.firestoreClient.Collection(musicCollectionName).Doc(getDocId(splitMusicKey(opts.Filename)))
+	var music models.Music
+	err := r.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return fmt.Errorf("music %s: %w", opts.Filename, ErrNotFound)
+			}
+			return err
+		}
+		if err := doc.DataTo(&music); err != nil {
+			return fmt.Errorf("failed to parse music document: %w", err)
+		}
+		if music.DurationSec > 0 {
+			return nil
+		}
+		music.DurationSec = opts.DurationSec
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "durationSec", Value: opts.DurationSec},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		r.logger.Error("failed to report music duration", "filename", opts.Filename, "error", err)
+		return nil, fmt.Errorf("failed to report music duration: %w", err)
 	}
+	return &music, nil
+}
 
+// Sync the music collection with the bucket and return the filenames of deleted documents.
+// Metadata is only read from the bucket for new or changed files, and for files without a duration yet.
+func (r *MusicRepository) updateMusicList(
+	ctx context.Context,
+	musicList []models.Music,
+	objectSizes map[string]int64) ([]string, error) {
 	collection := r.firestoreClient.Collection(musicCollectionName)
-	// fetch existing doc IDs only (with only size and lastModifies parts as identifiers)
-	existingDocs, err := collection.Select(identifierFields...).Documents(ctx).GetAll()
+	existingDocs, err := collection.Documents(ctx).GetAll()
 	if err != nil {
 		r.logger.Error("failed to get existing music documents", "error", err)
-		return fmt.Errorf("failed to get existing music documents: %w", err)
+		return nil, fmt.Errorf("failed to get existing music documents: %w", err)
 	}
-	existingDocsData := make(map[string]identifier, len(existingDocs))
+	existingDocsData := make(map[string]models.Music, len(existingDocs))
 	for _, doc := range existingDocs {
-		var tempData identifier
+		var tempData models.Music
 		if err := doc.DataTo(&tempData); err != nil {
 			r.logger.Error(
 				"failed to decode music document",
@@ -178,27 +264,122 @@ func (r *MusicRepository) updateMusicList(ctx context.Context, musicList []model
 	filesAdded, filesUpdated, filesDeleted := 0, 0, 0
 	bulkWriter := r.firestoreClient.BulkWriter(ctx)
 	// add new docs or update existing docs
-	for _, music := range musicList {
-		docId := getDocId(music.Genre, music.Title)
+	for i, music := range musicList {
+		docId := getDocId(splitMusicKey(music.Filename))
 		newIDs[docId] = struct{}{}
-		if _, exists := existingDocsData[docId]; !exists {
-			bulkWriter.Set(collection.Doc(docId), music)
+		existing, exists := existingDocsData[docId]
+		changed := exists && (music.Size != existing.Size || music.LastModified != existing.LastModified)
+		if exists && !changed && existing.DurationSec > 0 {
+			musicList[i] = existing
+			continue
+		}
+		metadataErr := r.applyMusicMetadata(ctx, &musicList[i], objectSizes[music.Filename])
+		switch {
+		case !exists:
+			bulkWriter.Set(collection.Doc(docId), musicList[i])
 			filesAdded += 1
-		} else if music.Size != existingDocsData[docId].Size ||
-			music.LastModified != existingDocsData[docId].LastModified {
-			bulkWriter.Set(collection.Doc(docId), music)
+		case changed:
+			bulkWriter.Set(collection.Doc(docId), musicList[i])
+			filesUpdated += 1
+		case metadataErr == nil:
+			// unchanged file that had no duration yet
+			bulkWriter.Set(collection.Doc(docId), musicList[i])
 			filesUpdated += 1
+		default:
+			musicList[i] = existing
 		}
 	}
 	// delete non-existing removed docs
+	deletedFilenames := []string{}
 	for _, doc := range existingDocs {
 		if _, exists := newIDs[doc.Ref.ID]; !exists {
 			bulkWriter.Delete(doc.Ref)
 			filesDeleted += 1
+			if data, ok := existingDocsData[doc.Ref.ID]; ok && data.Filename != "" {
+				deletedFilenames = append(deletedFilenames, data.Filename)
+			}
 		}
 	}
 	bulkWriter.Flush()
 	r.logger.Info("Music list refresh completed: ", "added", filesAdded, "updated", filesUpdated, "deleted", filesDeleted)
+	return deletedFilenames, nil
+}
+
+// Read title, artist and duration from the file in the bucket, the music keeps its defaults on failure
+func (r *MusicRepository) applyMusicMetadata(ctx context.Context, music *models.Music, fileSize int64) error {
+	metadata, err := r.readMusicMetadata(ctx, music.Filename, fileSize)
+	if err != nil {
+		r.logger.Warn("failed to read music metadata", "filename", music.Filename, "error", err)
+		return err
+	}
+	if metadata.Title != "" {
+		music.Title = metadata.Title
+	}
+	music.Artist = metadata.Artist
+	music.DurationSec = metadata.DurationSec
+	return nil
+}
+
+// Only the start of the file is downloaded: the ID3 tag and the first audio frame are enough
+func (r *MusicRepository) readMusicMetadata(ctx context.Context, key string, fileSize int64) (*musicMetadata, error) {
+	head, err := r.bucket.GetObjectRange(ctx, musicBucketName, key, 0, metadataProbeSize)
+	if err != nil {
+		return nil, err
+	}
+	tag := parseID3Tag(head)
+	metadata := &musicMetadata{Title: tag.title, Artist: tag.artist, DurationSec: unknownDuration}
+	if tag.lengthMs > 0 {
+		metadata.DurationSec = int64(math.Round(float64(tag.lengthMs) / 1000))
+		return metadata, nil
+	}
+	var audio []byte
+	if tag.size+audioProbeSize <= int64(len(head)) || int64(len(head)) >= fileSize {
+		audio = head[min(tag.size, int64(len(head))):]
+	} else {
+		// large tags, usually embedded cover art, push the first audio frame out of the probe
+		audio, err = r.bucket.GetObjectRange(ctx, musicBucketName, key, tag.size, audioProbeSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	duration, err := parseMPEGDuration(audio, tag.size, fileSize)
+	if err != nil {
+		return nil, err
+	}
+	metadata.DurationSec = duration
+	return metadata, nil
+}
+
+// Remove deleted music from the liked music of every app user
+func (r *MusicRepository) removeLikedMusics(ctx context.Context, filenames []string) error {
+	if len(filenames) == 0 {
+		return nil
+	}
+	users := r.firestoreClient.Collection(appUserTable)
+	bulkWriter := r.firestoreClient.BulkWriter(ctx)
+	usersUpdated := 0
+	for chunk := range slices.Chunk(filenames, arrayContainsAnyLimit) {
+		values := make([]interface{}, len(chunk))
+		for i, filename := range chunk {
+			values[i] = filename
+		}
+		docs, err := users.Where("likedMusics", "array-contains-any", values).Documents(ctx).GetAll()
+		if err != nil {
+			bulkWriter.End()
+			return fmt.Errorf("failed to query users with deleted liked music: %w", err)
+		}
+		for _, doc := range docs {
+			if _, err := bulkWriter.Update(doc.Ref, []firestore.Update{
+				{Path: "likedMusics", Value: firestore.ArrayRemove(values...)},
+			}); err != nil {
+				r.logger.Warn("failed to enqueue liked music removal", "uid", doc.Ref.ID, "error", err)
+				continue
+			}
+			usersUpdated += 1
+		}
+	}
+	bulkWriter.End()
+	r.logger.Info("Removed deleted music from user likes", "music", len(filenames), "users", usersUpdated)
 	return nil
 }
 
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	id3HeaderSize   = 10
+	mpegHeaderSize  = 4
+	unknownDuration = -1
+)
+
+var errNoMPEGFrame = errors.New("no mpeg audio frame found")
+
+type musicMetadata struct {
+	Title       string
+	Artist      string
+	DurationSec int64
+}
+
+// Metadata read from the ID3v2 tag, only the frames we store are kept
+type id3Tag struct {
+	size     int64 // total tag size including header and footer
+	title    string
+	artist   string
+	lengthMs int64
+}
+
+type mpegFrame struct {
+	bitrate         int64 // bits per second
+	sampleRate      int64
+	samplesPerFrame int64
+	sideInfoSize    int
+}
+
+// Bitrates in kbps indexed by [version row][layer][bitrate index]
+var mpegBitrates = [2][3][16]int64{
+	// MPEG 1
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	},
+	// MPEG 2 and 2.5
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+}
+
+var mpegSampleRates = map[byte][3]int64{
+	3: {44100, 48000, 32000}, // MPEG 1
+	2: {22050, 24000, 16000}, // MPEG 2
+	0: {11025, 12000, 8000},  // MPEG 2.5
+}
+
+// Size of the ID3v2 tag at the start of data, 0 if there is none
+func id3TagSize(data []byte) int64 {
+	if len(data) < id3HeaderSize || string(data[:3]) != "ID3" {
+		return 0
+	}
+	size := id3HeaderSize + syncsafeInt(data[6:10])
+	// footer present flag
+	if data[5]&0x10 != 0 {
+		size += id3HeaderSize
+	}
+	return size
+}
+
+// Parse the text frames of an ID3v2 tag, data may be cut off before the end of the tag
+func parseID3Tag(data []byte) id3Tag {
+	tag := id3Tag{size: id3TagSize(data)}
+	if tag.size == 0 {
+		return tag
+	}
+	version := data[3]
+	idLen, headerLen := 4, 10
+	if version == 2 {
+		idLen, headerLen = 3, 6
+	}
+	end := int(min(tag.size, int64(len(data))))
+	pos := id3HeaderSize
+	// skip the extended header
+	if data[5]&0x40 != 0 && version >= 3 && pos+4 <= end {
+		extSize := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		if version == 4 {
+			extSize = int(syncsafeInt(data[pos : pos+4]))
+		} else {
+			extSize += 4
+		}
+		pos += extSize
+	}
+	for pos+headerLen <= end {
+		id := string(data[pos : pos+idLen])
+		// the rest of the tag is padding
+		if id[0] == 0 {
+			break
+		}
+		var size int
+		switch version {
+		case 2:
+			size = int(data[pos+3])<<16 | int(data[pos+4])<<8 | int(data[pos+5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(data[pos+4 : pos+8]))
+		default:
+			size = int(syncsafeInt(data[pos+4 : pos+8]))
+		}
+		pos += headerLen
+		if size <= 0 || pos+size > end {
+			break
+		}
+		body := data[pos : pos+size]
+		pos += size
+		switch id {
+		case "TIT2", "TT2":
+			tag.title = decodeID3Text(body)
+		case "TPE1", "TP1":
+			tag.artist = decodeID3Text(body)
+		case "TLEN", "TLE":
+			if ms, err := strconv.ParseInt(decodeID3Text(body), 10, 64); err == nil && ms > 0 {
+				tag.lengthMs = ms
+			}
+		}
+	}
+	return tag
+}
+
+// Find the first valid MPEG audio frame and compute the duration from it.
+// fileSize and audioOffset are used to estimate constant bitrate files.
+func parseMPEGDuration(data []byte, audioOffset int64, fileSize int64) (int64, error) {
+	for i := 0; i+mpegHeaderSize <= len(data); i++ {
+		if data[i] != 0xFF || data[i+1]&0xE0 != 0xE0 {
+			continue
+		}
+		frame, ok := parseMPEGHeader(data[i : i+mpegHeaderSize])
+		if !ok {
+			continue
+		}
+		// VBR files carry the total frame count in a Xing/Info or VBRI header
+		if frames := vbrFrameCount(data[i:], frame); frames > 0 {
+			seconds := float64(frames*frame.samplesPerFrame) / float64(frame.sampleRate)
+			return int64(math.Round(seconds)), nil
+		}
+		audioSize := fileSize - audioOffset - int64(i)
+		if audioSize <= 0 {
+			return 0, errNoMPEGFrame
+		}
+		seconds := float64(audioSize*8) / float64(frame.bitrate)
+		return int64(math.Round(seconds)), nil
+	}
+	return 0, errNoMPEGFrame
+}
+
+func parseMPEGHeader(header []byte) (mpegFrame, bool) {
+	versionBits := (header[1] >> 3) & 0x03
+	layerBits := (header[1] >> 1) & 0x03
+	bitrateIndex := header[2] >> 4
+	sampleRateIndex := (header[2] >> 2) & 0x03
+	channelMode := header[3] >> 6
+	rates, ok := mpegSampleRates[versionBits]
+	if !ok || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		return mpegFrame{}, false
+	}
+	// layer bits are 3 for layer I, 2 for layer II and 1 for layer III
+	layer := 4 - int(layerBits)
+	row := 1
+	if versionBits == 3 {
+		row = 0
+	}
+	frame := mpegFrame{
+		bitrate:    mpegBitrates[row][layer-1][bitrateIndex] * 1000,
+		sampleRate: rates[sampleRateIndex],
+	}
+	switch {
+	case layer == 1:
+		frame.samplesPerFrame = 384
+	case layer == 3 && row == 1:
+		frame.samplesPerFrame = 576
+	default:
+		frame.samplesPerFrame = 1152
+	}
+	mono := channelMode == 3
+	switch {
+	case row == 0 && mono:
+		frame.sideInfoSize = 17
+	case row == 0:
+		frame.sideInfoSize = 32
+	case mono:
+		frame.sideInfoSize = 9
+	default:
+		frame.sideInfoSize = 17
+	}
+	return frame, true
+}
+
+func vbrFrameCount(data []byte, frame mpegFrame) int64 {
+	xing := mpegHeaderSize + frame.sideInfoSize
+	if len(data) >= xing+12 {
+		id := string(data[xing : xing+4])
+		flags := binary.BigEndian.Uint32(data[xing+4 : xing+8])
+		// first flag bit marks that the frame count is present
+		if (id == "Xing" || id == "Info") && flags&0x01 != 0 {
+			return int64(binary.BigEndian.Uint32(data[xing+8 : xing+12]))
+		}
+	}
+	// VBRI header always sits 32 bytes after the frame header
+	vbri := mpegHeaderSize + 32
+	if len(data) >= vbri+18 && string(data[vbri:vbri+4]) == "VBRI" {
+		return int64(binary.BigEndian.Uint32(data[vbri+14 : vbri+18]))
+	}
+	return 0
+}
+
+func decodeID3Text(body []byte) string {
+	if len(body) < 2 {
+		return ""
+	}
+	encoding, text := body[0], body[1:]
+	var decoded string
+	switch encoding {
+	case 0: // ISO-8859-1
+		runes := make([]rune, len(text))
+		for i, b := range text {
+			runes[i] = rune(b)
+		}
+		decoded = string(runes)
+	case 1, 2: // UTF-16 with BOM, UTF-16BE
+		bigEndian := encoding == 2
+		if len(text) >= 2 && encoding == 1 {
+			bigEndian = text[0] == 0xFE && text[1] == 0xFF
+			text = text[2:]
+		}
+		units := make([]uint16, 0, len(text)/2)
+		for i := 0; i+1 < len(text); i += 2 {
+			if bigEndian {
+				units = append(units, uint16(text[i])<<8|uint16(text[i+1]))
+			} else {
+				units = append(units, uint16(text[i+1])<<8|uint16(text[i]))
+			}
+		}
+		decoded = string(utf16.Decode(units))
+	default: // UTF-8
+		decoded = string(text)
+	}
+	// frames may hold several null separated values, only the first one is used
+	if i := strings.IndexRune(decoded, 0); i >= 0 {
+		decoded = decoded[:i]
+	}
+	return strings.TrimSpace(decoded)
+}
+
+func syncsafeInt(b []byte) int64 {
+	return int64(b[0]&0x7F)<<21 | int64(b[1]&0x7F)<<14 | int64(b[2]&0x7F)<<7 | int64(b[3]&0x7F)
+}
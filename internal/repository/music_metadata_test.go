@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func id3Frame(id string, text string) []byte {
+	body := append([]byte{3}, []byte(text)...) // UTF-8
+	frame := make([]byte, 10, 10+len(body))
+	copy(frame, id)
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(body)))
+	return append(frame, body...)
+}
+
+func id3v23Tag(frames ...[]byte) []byte {
+	var body []byte
+	for _, frame := range frames {
+		body = append(body, frame...)
+	}
+	size := len(body)
+	header := []byte{'I', 'D', '3', 3, 0, 0,
+		byte(size>>21) & 0x7F, byte(size>>14) & 0x7F, byte(size>>7) & 0x7F, byte(size) & 0x7F}
+	return append(header, body...)
+}
+
+// MPEG 1 layer III, 128 kbps, 44.1 kHz, stereo
+var cbrFrameHeader = []byte{0xFF, 0xFB, 0x90, 0x00}
+
+func TestParseID3Tag(t *testing.T) {
+	t.Parallel()
+	data := id3v23Tag(
+		id3Frame("TIT2", "Moonlight"),
+		id3Frame("TPE1", "North Post Band"),
+		id3Frame("TLEN", "215400"),
+	)
+	tag := parseID3Tag(data)
+	assert.Equal(t, int64(len(data)), tag.size)
+	assert.Equal(t, "Moonlight", tag.title)
+	assert.Equal(t, "North Post Band", tag.artist)
+	assert.Equal(t, int64(215400), tag.lengthMs)
+}
+
+func TestParseID3Tag_Truncated(t *testing.T) {
+	t.Parallel()
+	data := id3v23Tag(id3Frame("TIT2", "Moonlight"), id3Frame("TPE1", "North Post Band"))
+	// the probe only covers the first frame
+	tag := parseID3Tag(data[:len(data)-5])
+	assert.Equal(t, int64(len(data)), tag.size)
+	assert.Equal(t, "Moonlight", tag.title)
+	assert.Empty(t, tag.artist)
+}
+
+func TestParseID3Tag_NoTag(t *testing.T) {
+	t.Parallel()
+	tag := parseID3Tag(cbrFrameHeader)
+	assert.Equal(t, int64(0), tag.size)
+}
+
+func TestDecodeID3Text(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		body     []byte
+		expected string
+	}{
+		{name: "latin1", body: []byte{0, 'C', 'a', 'f', 0xE9}, expected: "Café"},
+		{name: "utf16 with bom", body: []byte{1, 0xFF, 0xFE, 'H', 0, 'i', 0}, expected: "Hi"},
+		{name: "utf16 big endian", body: []byte{2, 0, 'H', 0, 'i'}, expected: "Hi"},
+		{name: "utf8 with terminator", body: []byte{3, 'H', 'i', 0, 'x'}, expected: "Hi"},
+		{name: "empty", body: []byte{3}, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, decodeID3Text(tt.body))
+		})
+	}
+}
+
+func TestParseMPEGDuration_CBR(t *testing.T) {
+	t.Parallel()
+	// 128 kbps means 16000 bytes per second of audio
+	fileSize := int64(16000 * 180)
+	data := append([]byte{0, 0}, cbrFrameHeader...)
+	data = append(data, make([]byte, 64)...)
+	duration, err := parseMPEGDuration(data, 0, fileSize+2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(180), duration)
+}
+
+func TestParseMPEGDuration_Xing(t *testing.T) {
+	t.Parallel()
+	data := append([]byte{}, cbrFrameHeader...)
+	data = append(data, make([]byte, 32)...) // stereo MPEG 1 side info
+	data = append(data, 'X', 'i', 'n', 'g', 0, 0, 0, 1)
+	frames := make([]byte, 4)
+	// 1152 samples per frame at 44.1 kHz, 7656 frames is about 200 seconds
+	binary.BigEndian.PutUint32(frames, 7656)
+	data = append(data, frames...)
+	duration, err := parseMPEGDuration(data, 0, 1<<30)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(200), duration)
+}
+
+func TestParseMPEGDuration_NoFrame(t *testing.T) {
+	t.Parallel()
+	_, err := parseMPEGDuration([]byte{0xFF, 0x00, 0x12, 0x34, 0x56}, 0, 1000)
+	assert.ErrorIs(t, err, errNoMPEGFrame)
+}
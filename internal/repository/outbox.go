@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"north-post/service/internal/domain/v1/models"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	outboxTable       = "search_outbox"
+	failedOutboxTable = "search_outbox_failed"
+)
+
+// The entry was replaced by a newer change while it was being processed
+var errOutboxEntryChanged = errors.New("outbox entry changed")
+
+type OutboxRepository struct {
+	client *firestore.Client
+	logger *slog.Logger
+}
+
+func NewOutboxRepository(client *firestore.Client, logger *slog.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+type ListDueOutboxEntriesOptions struct {
+	Now   int64
+	Limit int
+}
+
+type ListOutboxEntriesOptions struct {
+	Status models.OutboxStatus
+	Limit  int
+}
+
+type RescheduleOutboxEntryOptions struct {
+	Entry         models.OutboxEntry
+	LastError     string
+	NextAttemptAt int64
+}
+
+type FailOutboxEntryOptions struct {
+	Entry     models.OutboxEntry
+	LastError string
+}
+
+// Pending entries whose next attempt is due, oldest first
+func (r *OutboxRepository) ListDueOutboxEntries(
+	ctx context.Context, opts ListDueOutboxEntriesOptions) ([]models.OutboxEntry, error) {
+	docs, err := r.client.Collection(outboxTable).
+		Where("nextAttemptAt", "<=", opts.Now).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(opts.Limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to list due outbox entries", "error", err)
+		return nil, fmt.Errorf("failed to list due outbox entries: %w", err)
+	}
+	return r.parseOutboxEntries(docs), nil
+}
+
+func (r *OutboxRepository) ListOutboxEntries(
+	ctx context.Context, opts ListOutboxEntriesOptions) ([]models.OutboxEntry, error) {
+	table := outboxTable
+	if opts.Status == models.OutboxStatusFailed {
+		table = failedOutboxTable
+	}
+	docs, err := r.client.Collection(table).
+		OrderBy("updatedAt", firestore.Desc).
+		Limit(opts.Limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to list outbox entries", "status", opts.Status, "error", err)
+		return nil, fmt.Errorf("failed to list outbox entries: %w", err)
+	}
+	return r.parseOutboxEntries(docs), nil
+}
+
+// Remove an applied entry, a newer change written in the meantime is kept
+func (r *OutboxRepository) CompleteOutboxEntry(ctx context.Context, entry models.OutboxEntry) error {
+	docRef := r.client.Collection(outboxTable).Doc(entry.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := r.checkUnchanged(tx, docRef, entry); err != nil {
+			return err
+		}
+		return tx.Delete(docRef)
+	})
+	return r.handleOutboxWriteError(entry.ID, "complete", err)
+}
+
+func (r *OutboxRepository) RescheduleOutboxEntry(ctx context.Context, opts RescheduleOutboxEntryOptions) error {
+	docRef := r.client.Collection(outboxTable).Doc(opts.Entry.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := r.checkUnchanged(tx, docRef, opts.Entry); err != nil {
+			return err
+		}
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "attempts", Value: opts.Entry.Attempts + 1},
+			{Path: "lastError", Value: opts.LastError},
+			{Path: "nextAttemptAt", Value: opts.NextAttemptAt},
+			{Path: "updatedAt", Value: time.Now().UnixMilli()},
+		})
+	})
+	return r.handleOutboxWriteError(opts.Entry.ID, "reschedule", err)
+}
+
+// Move an entry that ran out of retries to the failed collection
+func (r *OutboxRepository) FailOutboxEntry(ctx context.Context, opts FailOutboxEntryOptions) error {
+	docRef := r.client.Collection(outboxTable).Doc(opts.Entry.ID)
+	failedRef := r.client.Collection(failedOutboxTable).Doc(opts.Entry.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := r.checkUnchanged(tx, docRef, opts.Entry); err != nil {
+			return err
+		}
+		failed := opts.Entry
+		failed.Status = models.OutboxStatusFailed
+		failed.Attempts++
+		failed.LastError = opts.LastError
+		failed.UpdatedAt = time.Now().UnixMilli()
+		if err := tx.Delete(docRef); err != nil {
+			return err
+		}
+		return tx.Set(failedRef, failed)
+	})
+	return r.handleOutboxWriteError(opts.Entry.ID, "fail", err)
+}
+
+// =========== Helper methods ==========
+
+// Queue a search index change inside the transaction that changes the address
+func setOutboxEntry(
+	client *firestore.Client,
+	tx *firestore.Transaction,
+	language models.Language,
+	addressID string,
+	operation models.OutboxOperation) error {
+	entry := newOutboxEntry(language, addressID, operation)
+	return tx.Set(client.Collection(outboxTable).Doc(entry.ID), entry)
+}
+
+// Queue search index changes outside of a transaction, for addresses that were already written
+func queueOutboxEntries(
+	ctx context.Context,
+	client *firestore.Client,
+	language models.Language,
+	addressIDs []string,
+	operation models.OutboxOperation) error {
+	bulkWriter := client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(addressIDs))
+	for _, addressID := range addressIDs {
+		entry := newOutboxEntry(language, addressID, operation)
+		job, err := bulkWriter.Set(client.Collection(outboxTable).Doc(entry.ID), entry)
+		if err != nil {
+			bulkWriter.End()
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newOutboxEntry(language models.Language, addressID string, operation models.OutboxOperation) models.OutboxEntry {
+	now := time.Now().UnixMilli()
+	return models.OutboxEntry{
+		ID:            fmt.Sprintf("%s_%s", language.Get(), addressID),
+		Operation:     operation,
+		Language:      language.Lower(),
+		AddressID:     addressID,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (r *OutboxRepository) checkUnchanged(
+	tx *firestore.Transaction, docRef *firestore.DocumentRef, entry models.OutboxEntry) error {
+	doc, err := tx.Get(docRef)
+	if status.Code(err) == codes.NotFound {
+		return errOutboxEntryChanged
+	}
+	if err != nil {
+		return err
+	}
+	var current models.OutboxEntry
+	if err := doc.DataTo(&current); err != nil {
+		return err
+	}
+	if current.UpdatedAt != entry.UpdatedAt {
+		return errOutboxEntryChanged
+	}
+	return nil
+}
+
+// A replaced entry is not an error, the newer change is processed on its own
+func (r *OutboxRepository) handleOutboxWriteError(id string, action string, err error) error {
+	if err == nil || errors.Is(err, errOutboxEntryChanged) {
+		return nil
+	}
+	r.logger.Error("failed to update outbox entry", "entryID", id, "action", action, "error", err)
+	return fmt.Errorf("failed to %s outbox entry: %w", action, err)
+}
+
+func (r *OutboxRepository) parseOutboxEntries(docs []*firestore.DocumentSnapshot) []models.OutboxEntry {
+	entries := make([]models.OutboxEntry, 0, len(docs))
+	for _, doc := range docs {
+		var entry models.OutboxEntry
+		if err := doc.DataTo(&entry); err != nil {
+			r.logger.Warn("failed to parse outbox entry", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
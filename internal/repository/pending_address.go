@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"north-post/service/internal/domain/v1/models"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const pendingAddressTable = "pending_addresses"
+
+type CreatePendingAddressesOption struct {
+	Language   models.Language
+	Addresses  []models.AddressItem
+	Sources    []models.AddressRef // optional, the address each one was translated from in the same order
+	Generation models.GenerationMetadata
+}
+
+type ListPendingAddressesOption struct {
+	Language models.Language
+	Limit    int
+}
+
+type GetPendingAddressOption struct {
+	ID string
+}
+
+type UpdatePendingAddressOption struct {
+	ID      string
+	Address models.AddressItem
+}
+
+type ApprovePendingAddressOption struct {
+	ID    string
+	Tags  []string // canonical tags replacing the ones of the pending address, optional
+	Actor string
+}
+
+type RejectPendingAddressOption struct {
+	ID string
+}
+
+// Stage generated addresses for review, all of them or none are written
+func (r *AddressRepository) CreatePendingAddresses(
+	ctx context.Context, opts CreatePendingAddressesOption) ([]models.PendingAddress, error) {
+	collectionRef := r.client.Collection(pendingAddressTable)
+	now := time.Now().UnixMilli()
+	pending := make([]models.PendingAddress, len(opts.Addresses))
+	for i, address := range opts.Addresses {
+		address.ID = ""
+		pending[i] = models.PendingAddress{
+			ID:         collectionRef.NewDoc().ID,
+			Language:   opts.Language.Lower(),
+			Address:    address,
+			Generation: opts.Generation,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if i < len(opts.Sources) {
+			pending[i].Source = &opts.Sources[i]
+		}
+	}
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, item := range pending {
+			if err := tx.Create(collectionRef.Doc(item.ID), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to create pending addresses", "runID", opts.Generation.RunID, "error", err)
+		return nil, fmt.Errorf("failed to create pending addresses: %w", err)
+	}
+	return pending, nil
+}
+
+// Pending addresses of a language, newest first.
+// The review queue stays small, so it is sorted here instead of needing a composite index
+func (r *AddressRepository) ListPendingAddresses(
+	ctx context.Context, opts ListPendingAddressesOption) ([]models.PendingAddress, error) {
+	docs, err := r.client.Collection(pendingAddressTable).
+		Where("language", "==", opts.Language.Lower()).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		r.logger.Error("failed to list pending addresses", "language", opts.Language, "error", err)
+		return nil, fmt.Errorf("failed to list pending addresses: %w", err)
+	}
+	pending := make([]models.PendingAddress, 0, len(docs))
+	for _, doc := range docs {
+		var item models.PendingAddress
+		if err := doc.DataTo(&item); err != nil {
+			r.logger.Warn("failed to parse pending address", "docID", doc.Ref.ID, "error", err)
+			continue
+		}
+		pending = append(pending, item)
+	}
+	slices.SortFunc(pending, func(a, b models.PendingAddress) int {
+		return cmp.Compare(b.CreatedAt, a.CreatedAt)
+	})
+	if len(pending) > opts.Limit {
+		pending = pending[:opts.Limit]
+	}
+	return pending, nil
+}
+
+func (r *AddressRepository) GetPendingAddress(
+	ctx context.Context, opts GetPendingAddressOption) (*models.PendingAddress, error) {
+	doc, err := r.client.Collection(pendingAddressTable).Doc(opts.ID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("pending address %s: %w", opts.ID, ErrNotFound)
+	}
+	if err != nil {
+		r.logger.Error("failed to get pending address", "pendingID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to get pending address: %w", err)
+	}
+	var item models.PendingAddress
+	if err := doc.DataTo(&item); err != nil {
+		r.logger.Error("failed to parse pending address", "pendingID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to parse pending address: %w", err)
+	}
+	return &item, nil
+}
+
+// Replace the address content of a pending item, the generation metadata is kept
+func (r *AddressRepository) UpdatePendingAddress(
+	ctx context.Context, opts UpdatePendingAddressOption) (*models.PendingAddress, error) {
+	docRef := r.client.Collection(pendingAddressTable).Doc(opts.ID)
+	var updated models.PendingAddress
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		item, err := r.getPendingAddressInTransaction(tx, docRef)
+		if err != nil {
+			return err
+		}
+		item.Address.Name = opts.Address.Name
+		item.Address.BriefIntro = opts.Address.BriefIntro
+		item.Address.Tags = opts.Address.Tags
+		item.Address.Address = opts.Address.Address
+		item.UpdatedAt = time.Now().UnixMilli()
+		updated = *item
+		return tx.Set(docRef, *item)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		r.logger.Error("failed to update pending address", "pendingID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to update pending address: %w", err)
+	}
+	return &updated, nil
+}
+
+// Move a pending address into the catalog of its language, a translation is linked to its source in the
+// same transaction. It is rejected with ErrDuplicate like a new address, with ErrConflict when it was edited
+// during the check or its source left the catalog, and with ErrTranslationConflict when the source is linked
+// to an entry of the language already
+func (r *AddressRepository) ApprovePendingAddress(
+	ctx context.Context, opts ApprovePendingAddressOption) (*models.AddressItem, error) {
+	item, err := r.GetPendingAddress(ctx, GetPendingAddressOption{ID: opts.ID})
+	if err != nil {
+		return nil, err
+	}
+	collectionName := getAddressCollectionName(item.Language)
+	if err := r.checkDuplicateAddress(ctx, item.Language, item.Address); err != nil {
+		return nil, err
+	}
+	pendingRef := r.client.Collection(pendingAddressTable).Doc(opts.ID)
+	docRef := r.client.Collection(collectionName).NewDoc()
+	var approved models.AddressItem
+	err = r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// a transaction has to read everything before it writes
+		current, err := r.getPendingAddressInTransaction(tx, pendingRef)
+		if err != nil {
+			return err
+		}
+		if current.UpdatedAt != item.UpdatedAt {
+			return fmt.Errorf("pending address %s: %w", opts.ID, ErrConflict)
+		}
+		var link *translationLink
+		if current.Source != nil {
+			link, err = r.getTranslationLinkInTransaction(tx, *current.Source, current.Language)
+			if err != nil {
+				return err
+			}
+		}
+		now := time.Now().UnixMilli()
+		approved = current.Address
+		if opts.Tags != nil {
+			approved.Tags = opts.Tags
+		}
+		approved.ID = docRef.ID
+		approved.CreatedAt = now
+		approved.UpdatedAt = now
+		approved.DeletedAt = 0
+		approved.Revision = 1
+		approved.UpdateDuplicateKeys()
+		if link != nil {
+			approved.TranslationGroupID = link.group.ID
+		}
+		if err := tx.Create(docRef, approved); err != nil {
+			return err
+		}
+		err = setAddressRevision(
+			tx, docRef, models.AddressItem{}, approved, models.RevisionActionCreate, opts.Actor)
+		if err != nil {
+			return err
+		}
+		if err := setOutboxEntry(r.client, tx, item.Language, docRef.ID, models.OutboxOperationUpsert); err != nil {
+			return err
+		}
+		if link != nil {
+			if err := r.setTranslationLinkInTransaction(tx, link, approved.ID, now); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(pendingRef)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) || errors.Is(err, ErrTranslationConflict) {
+			return nil, err
+		}
+		r.logger.Error("failed to approve pending address", "pendingID", opts.ID, "error", err)
+		return nil, fmt.Errorf("failed to approve pending address: %w", err)
+	}
+	return &approved, nil
+}
+
+func (r *AddressRepository) RejectPendingAddress(ctx context.Context, opts RejectPendingAddressOption) error {
+	docRef := r.client.Collection(pendingAddressTable).Doc(opts.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if _, err := r.getPendingAddressInTransaction(tx, docRef); err != nil {
+			return err
+		}
+		return tx.Delete(docRef)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		r.logger.Error("failed to reject pending address", "pendingID", opts.ID, "error", err)
+		return fmt.Errorf("failed to reject pending address: %w", err)
+	}
+	return nil
+}
+
+// =========== Helper methods ==========
+
+func (r *AddressRepository) getPendingAddressInTransaction(
+	tx *firestore.Transaction, docRef *firestore.DocumentRef) (*models.PendingAddress, error) {
+	doc, err := tx.Get(docRef)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("pending address %s: %w", docRef.ID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var item models.PendingAddress
+	if err := doc.DataTo(&item); err != nil {
+		return nil, fmt.Errorf("failed to parse pending address %s: %w", docRef.ID, err)
+	}
+	return &item, nil
+}
+
+// The translation group an approved translation joins, read before the transaction writes
+type translationLink struct {
+	source   models.AddressRef
+	language models.Language // of the translation
+	group    models.TranslationGroup
+	newGroup bool // the source is not linked to other entries yet
+}
+
+func (r *AddressRepository) getTranslationLinkInTransaction(
+	tx *firestore.Transaction, source models.AddressRef, language models.Language) (*translationLink, error) {
+	sourceRef := r.client.Collection(getAddressCollectionName(source.Language)).Doc(source.ID)
+	address, err := r.getAddressInTransaction(tx, sourceRef)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%w: source address %s is no longer in the catalog", ErrConflict, source.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if address.IsDeleted() {
+		return nil, fmt.Errorf("%w: source address %s is in the trash", ErrConflict, source.ID)
+	}
+	link := &translationLink{source: source, language: language}
+	if address.TranslationGroupID == "" {
+		groupRef := r.client.Collection(translationGroupTable).NewDoc()
+		link.group = models.TranslationGroup{ID: groupRef.ID, Members: map[string]string{}}
+		link.newGroup = true
+	} else {
+		group, err := r.getTranslationGroupInTransaction(
+			tx, r.client.Collection(translationGroupTable).Doc(address.TranslationGroupID))
+		if err != nil {
+			return nil, err
+		}
+		link.group = *group
+	}
+	if id, ok := link.group.Members[language.Get()]; ok {
+		return nil, fmt.Errorf("%w: source address %s is linked to the %s entry %s",
+			ErrTranslationConflict, source.ID, language.Get(), id)
+	}
+	return link, nil
+}
+
+// Add the approved translation to the group of its source
+func (r *AddressRepository) setTranslationLinkInTransaction(
+	tx *firestore.Transaction, link *translationLink, id string, now int64) error {
+	group := link.group
+	group.Members = maps.Clone(group.Members)
+	group.Members[link.source.Language.Get()] = link.source.ID
+	group.Members[link.language.Get()] = id
+	if group.CreatedAt == 0 {
+		group.CreatedAt = now
+	}
+	group.UpdatedAt = now
+	if link.newGroup {
+		sourceRef := r.client.Collection(getAddressCollectionName(link.source.Language)).Doc(link.source.ID)
+		if err := r.setTranslationGroupInTransaction(tx, sourceRef, link.source.Language, group.ID, now); err != nil {
+			return err
+		}
+	}
+	return tx.Set(r.client.Collection(translationGroupTable).Doc(group.ID), group)
+}
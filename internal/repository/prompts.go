@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"log/slog"
 	"north-post/service/internal/domain/v1/models"
+	"slices"
 
 	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -35,29 +38,42 @@ type GetSystemAddressGenerationPromptOptions struct {
 	Language models.Language
 }
 
-// get prompt by language and key
-func (r *PromptRepository) GetSystemPrompt(ctx context.Context, opts GetSystemPromptOptions) (string, error) {
+type SystemPrompt struct {
+	Language models.Language // language of the prompt document, differs from the requested one after a fallback
+	Prompt   string
+}
+
+// get prompt by language and key, languages without the prompt use the one of their fallback languages
+// and then of the default language chain
+func (r *PromptRepository) GetSystemPrompt(ctx context.Context, opts GetSystemPromptOptions) (*SystemPrompt, error) {
 	language := opts.Language
 	key := opts.Key
-	docRef := r.client.Collection(promptTable).Doc(getPromptLanguage(language))
-	doc, err := docRef.Get(ctx)
-	if err != nil {
-		r.logger.Error("failed to get prompt", "language", language)
-		return "", fmt.Errorf("failed to get prompt")
-	}
-	data := doc.Data()
-	prompt, ok := data[key].(string)
-	if !ok {
-		r.logger.Warn("prompt key missing or not a string", "language", language, "key", key)
-		return "", fmt.Errorf("prompt key missing or not a string")
+	for _, promptLanguage := range getPromptLanguages(language) {
+		doc, err := r.client.Collection(promptTable).Doc(promptLanguage.Get()).Get(ctx)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			r.logger.Error("failed to get prompt", "language", promptLanguage)
+			return nil, fmt.Errorf("failed to get prompt")
+		}
+		prompt, ok := doc.Data()[key].(string)
+		if !ok {
+			continue
+		}
+		if promptLanguage != language.Lower() {
+			r.logger.Info("prompt served in a fallback language", "language", language, "fallback", promptLanguage, "key", key)
+		}
+		return &SystemPrompt{Language: promptLanguage, Prompt: prompt}, nil
 	}
-	return prompt, nil
+	r.logger.Warn("prompt key missing or not a string", "language", language, "key", key)
+	return nil, fmt.Errorf("prompt key missing or not a string")
 }
 
 // get address generation system prompt
 func (r *PromptRepository) GetSystemAddressGenerationPrompt(
 	ctx context.Context,
-	opts GetSystemAddressGenerationPromptOptions) (string, error) {
+	opts GetSystemAddressGenerationPromptOptions) (*SystemPrompt, error) {
 	getPromptOpts := GetSystemPromptOptions{
 		Language: opts.Language,
 		Key:      addressGenerationKey,
@@ -66,9 +82,17 @@ func (r *PromptRepository) GetSystemAddressGenerationPrompt(
 }
 
 // ============ Helper functions ===========
-func getPromptLanguage(language models.Language) string {
-	if err := language.Validate(); err != nil {
-		return models.LanguageEN.Get() // set fallback language as english
+
+// Prompt documents to try in order: the language, its fallbacks, the default chain, then english
+func getPromptLanguages(language models.Language) []models.Language {
+	chain := []models.Language{}
+	if language.Validate() == nil {
+		chain = language.FallbackChain()
+	}
+	for _, fallback := range append(models.Languages().DefaultChain(), models.LanguageEN) {
+		if !slices.Contains(chain, fallback) {
+			chain = append(chain, fallback)
+		}
 	}
-	return language.Get()
+	return chain
 }
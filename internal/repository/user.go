@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"firebase.google.com/go/v4/auth"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -41,6 +42,11 @@ type GetUserByIdOptions struct {
 	Uid string
 }
 
+type SetAdminUserRoleOptions struct {
+	Uid  string
+	Role models.AdminRole
+}
+
 type GetUserSavedAddressesOptions struct {
 	Language models.Language
 	Uid      string
@@ -53,6 +59,21 @@ type UpdateUserSavedAddressesOptions struct {
 	Action     UpdateSavedAddressesAction
 }
 
+type UpdateUserLanguageOptions struct {
+	UserID   string
+	Language models.Language
+}
+
+type GetUserLikedMusicsOptions struct {
+	Uid string
+}
+
+type UpdateUserLikedMusicsOptions struct {
+	UserID    string
+	Filenames []string
+	Action    UpdateSavedAddressesAction
+}
+
 /* ---- Admin user repository ---- */
 
 func (u *UserRepository) SignInAdminUserById(ctx context.Context, opts GetUserByIdOptions) (*models.AdminUser, error) {
@@ -70,6 +91,10 @@ func (u *UserRepository) SignInAdminUserById(ctx context.Context, opts GetUserBy
 		u.logger.Error("failed to parse admin user document", "uid", opts.Uid, "error", err)
 		return nil, fmt.Errorf("failed to parse admin user data: %w", err)
 	}
+	adminUser.Uid = opts.Uid
+	if err := adminUser.Role.Validate(); err != nil {
+		adminUser.Role = models.AdminRoleViewer
+	}
 	now := time.Now().UnixMilli()
 	adminUser.LastLogin = now
 	_, err = docRef.Update(ctx, []firestore.Update{
@@ -82,6 +107,114 @@ func (u *UserRepository) SignInAdminUserById(ctx context.Context, opts GetUserBy
 	return &adminUser, nil
 }
 
+// Get the role of an admin user, an empty role means the user is not an admin.
+// Admin documents created before roles existed default to the viewer role.
+func (u *UserRepository) GetAdminRole(ctx context.Context, uid string) (models.AdminRole, error) {
+	doc, err := u.client.Firestore.Collection(adminUserTable).Doc(uid).Get(ctx)
+	if err != nil && status.Code(err) == codes.NotFound {
+		return "", nil
+	}
+	if err != nil {
+		u.logger.Error("failed to get admin user document", "uid", uid, "error", err)
+		return "", fmt.Errorf("failed to get admin user document: %w", err)
+	}
+	var adminUser models.AdminUser
+	if err := doc.DataTo(&adminUser); err != nil {
+		u.logger.Error("failed to parse admin user document", "uid", uid, "error", err)
+		return "", fmt.Errorf("failed to parse admin user data: %w", err)
+	}
+	if err := adminUser.Role.Validate(); err != nil {
+		u.logger.Warn("admin user has no valid role, falling back to viewer", "uid", uid, "role", adminUser.Role)
+		return models.AdminRoleViewer, nil
+	}
+	return adminUser.Role, nil
+}
+
+func (u *UserRepository) ListAdminUsers(ctx context.Context) ([]models.AdminUser, error) {
+	docs, err := u.client.Firestore.Collection(adminUserTable).Documents(ctx).GetAll()
+	if err != nil {
+		u.logger.Error("failed to list admin users", "error", err)
+		return nil, fmt.Errorf("failed to list admin users: %w", err)
+	}
+	adminUsers := make([]models.AdminUser, 0, len(docs))
+	for _, doc := range docs {
+		var adminUser models.AdminUser
+		if err := doc.DataTo(&adminUser); err != nil {
+			u.logger.Warn("failed to parse admin user document", "uid", doc.Ref.ID, "error", err)
+			continue
+		}
+		adminUser.Uid = doc.Ref.ID
+		if err := adminUser.Role.Validate(); err != nil {
+			adminUser.Role = models.AdminRoleViewer
+		}
+		adminUsers = append(adminUsers, adminUser)
+	}
+	return adminUsers, nil
+}
+
+// Grant a role to a user, the admin document is created from the auth record if it doesn't exist.
+// A user without an auth record is rejected with ErrNotFound
+func (u *UserRepository) SetAdminUserRole(ctx context.Context, opts SetAdminUserRoleOptions) (*models.AdminUser, error) {
+	docRef := u.client.Firestore.Collection(adminUserTable).Doc(opts.Uid)
+	doc, err := docRef.Get(ctx)
+	if err != nil && status.Code(err) == codes.NotFound {
+		userRecord, err := u.client.Auth.GetUser(ctx, opts.Uid)
+		if auth.IsUserNotFound(err) {
+			return nil, fmt.Errorf("user %s: %w", opts.Uid, ErrNotFound)
+		}
+		if err != nil {
+			u.logger.Error("failed to retrieve user info from auth service", "uid", opts.Uid, "error", err)
+			return nil, fmt.Errorf("failed to retrieve user info from auth service: %w", err)
+		}
+		adminUser := &models.AdminUser{
+			Uid:         opts.Uid,
+			Email:       userRecord.Email,
+			DisplayName: userRecord.DisplayName,
+			CreatedAt:   time.Now().UnixMilli(),
+			ImageUrl:    userRecord.PhotoURL,
+			Role:        opts.Role,
+		}
+		if _, err := docRef.Set(ctx, adminUser); err != nil {
+			u.logger.Error("failed to create admin user", "uid", opts.Uid, "error", err)
+			return nil, fmt.Errorf("failed to create admin user: %w", err)
+		}
+		return adminUser, nil
+	}
+	if err != nil {
+		u.logger.Error("failed to get admin user document", "uid", opts.Uid, "error", err)
+		return nil, fmt.Errorf("failed to get admin user document: %w", err)
+	}
+	var adminUser models.AdminUser
+	if err := doc.DataTo(&adminUser); err != nil {
+		u.logger.Error("failed to parse admin user document", "uid", opts.Uid, "error", err)
+		return nil, fmt.Errorf("failed to parse admin user data: %w", err)
+	}
+	_, err = docRef.Update(ctx, []firestore.Update{
+		{Path: "role", Value: opts.Role},
+	})
+	if err != nil {
+		u.logger.Error("failed to update admin user role", "uid", opts.Uid, "error", err)
+		return nil, fmt.Errorf("failed to update admin user role: %w", err)
+	}
+	adminUser.Uid = opts.Uid
+	adminUser.Role = opts.Role
+	return &adminUser, nil
+}
+
+// Revoke admin access by removing the admin user document
+func (u *UserRepository) DeleteAdminUser(ctx context.Context, uid string) error {
+	docRef := u.client.Firestore.Collection(adminUserTable).Doc(uid)
+	_, err := docRef.Delete(ctx, firestore.Exists)
+	if err != nil && status.Code(err) == codes.NotFound {
+		return fmt.Errorf("admin user %s: %w", uid, ErrNotFound)
+	}
+	if err != nil {
+		u.logger.Error("failed to delete admin user", "uid", uid, "error", err)
+		return fmt.Errorf("failed to delete admin user: %w", err)
+	}
+	return nil
+}
+
 /* ---- App user repository ---- */
 
 func (u *UserRepository) AuthenticateAppUserById(
@@ -151,6 +284,41 @@ func (u *UserRepository) CreateAppUser(
 	return newUser, nil
 }
 
+/* ---- User Language ---- */
+
+// Preferred language of the user, empty when the user has none or doesn't exist yet
+func (u *UserRepository) GetUserLanguage(ctx context.Context, uid string) (models.Language, error) {
+	doc, err := u.client.Firestore.Collection(appUserTable).Doc(uid).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return "", nil
+	}
+	if err != nil {
+		u.logger.Error("failed to get app user document", "uid", uid, "error", err)
+		return "", fmt.Errorf("failed to get app user document: %w", err)
+	}
+	var appUser models.AppUser
+	if err := doc.DataTo(&appUser); err != nil {
+		u.logger.Error("failed to parse app user document", "uid", uid, "error", err)
+		return "", fmt.Errorf("failed to parse app user document: %w", err)
+	}
+	return appUser.Language, nil
+}
+
+func (u *UserRepository) UpdateUserLanguage(
+	ctx context.Context,
+	opts *UpdateUserLanguageOptions,
+) (string, error) {
+	docRef := u.client.Firestore.Collection(appUserTable).Doc(opts.UserID)
+	result, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "language", Value: opts.Language.Get()},
+	})
+	if err != nil {
+		u.logger.Error("failed to update user language", "uid", opts.UserID, "language", opts.Language, "error", err)
+		return "", fmt.Errorf("failed to update user language: %w", err)
+	}
+	return fmt.Sprintf("%d", result.UpdateTime.UnixMilli()), nil
+}
+
 /* ---- User Address Book ---- */
 
 func (u *UserRepository) GetUserSavedAddresses(
@@ -213,3 +381,65 @@ func (u *UserRepository) UpdateUserSavedAddresses(
 	}
 	return fmt.Sprintf("%d", result.UpdateTime.UnixMilli()), nil
 }
+
+/* ---- User Liked Music ---- */
+
+func (u *UserRepository) GetUserLikedMusics(
+	ctx context.Context,
+	opts *GetUserLikedMusicsOptions,
+) ([]string, error) {
+	tableName := appUserTable
+	docRef := u.client.Firestore.Collection(tableName).Doc(opts.Uid)
+	doc, err := docRef.Get(ctx)
+	if err != nil {
+		u.logger.Error("failed to get app user document",
+			"uid", opts.Uid,
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to get app user document: %w", err)
+	}
+	var appUser models.AppUser
+	if err := doc.DataTo(&appUser); err != nil {
+		u.logger.Error("failed to parse app user document",
+			"uid", opts.Uid,
+			"error", err,
+		)
+		return nil, fmt.Errorf("failed to parse app user document: %w", err)
+	}
+	if appUser.LikedMusics == nil {
+		return []string{}, nil
+	}
+	return appUser.LikedMusics, nil
+}
+
+func (u *UserRepository) UpdateUserLikedMusics(
+	ctx context.Context,
+	opts *UpdateUserLikedMusicsOptions,
+) (string, error) {
+	tableName := appUserTable
+	docRef := u.client.Firestore.Collection(tableName).Doc(opts.UserID)
+	var updateValue any
+	filenames := make([]interface{}, len(opts.Filenames))
+	for i, v := range opts.Filenames {
+		filenames[i] = v
+	}
+	switch opts.Action {
+	case Add:
+		updateValue = firestore.ArrayUnion(filenames...)
+	case Delete:
+		updateValue = firestore.ArrayRemove(filenames...)
+	default:
+		return "", fmt.Errorf("unsupported update action")
+	}
+	result, err := docRef.Update(ctx, []firestore.Update{
+		{Path: "likedMusics", Value: updateValue},
+	})
+	if err != nil {
+		u.logger.Error(
+			"failed to update liked musics",
+			"uid", opts.UserID,
+			"error", err)
+		return "", fmt.Errorf("failed to update liked musics: %w", err)
+	}
+	return fmt.Sprintf("%d", result.UpdateTime.UnixMilli()), nil
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/infra"
@@ -11,15 +14,54 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	defaultListDeletedAddresses = 50
+	maxListDeletedAddresses     = 200
+	defaultListRevisions        = 20
+	maxListRevisions            = 100
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
 type addressRepository interface {
 	GetAddresses(context.Context, repository.GetAddressesOptions) (
 		*repository.GetAddressesResponse, error)
+	GetAddressesByIDs(context.Context, *repository.GetAddressesByIDsOptions) (
+		*repository.GetAddressesByIDsResponse, error)
+	MapAddressTranslations(context.Context, *repository.MapAddressTranslationsOption) (
+		*repository.MapAddressTranslationsResult, error)
 	CreateNewAddress(context.Context, repository.CreateNewAddressOption) (string, error)
 	UpdateAddress(context.Context, repository.UpdateAddressOption) (*models.AddressItem, error)
 	DeleteAddress(context.Context, repository.DeleteAddressOption) (string, error)
+	RestoreAddress(context.Context, repository.RestoreAddressOption) (*models.AddressItem, error)
+	ListDeletedAddresses(context.Context, repository.ListDeletedAddressesOption) ([]models.AddressItem, error)
+	ListAddressRevisions(context.Context, repository.ListAddressRevisionsOption) ([]models.AddressRevision, error)
+	GetAddressRevision(context.Context, repository.GetAddressRevisionOption) (*models.AddressRevision, error)
+	RollbackAddress(context.Context, repository.RollbackAddressOption) (*models.AddressItem, error)
+	ImportAddresses(context.Context, repository.ImportAddressesOption) (*repository.ImportAddressesResult, error)
+	StreamAddresses(context.Context, repository.StreamAddressesOption, func(models.AddressItem) error) error
+	CreatePendingAddresses(context.Context, repository.CreatePendingAddressesOption) ([]models.PendingAddress, error)
+	ListPendingAddresses(context.Context, repository.ListPendingAddressesOption) ([]models.PendingAddress, error)
+	GetPendingAddress(context.Context, repository.GetPendingAddressOption) (*models.PendingAddress, error)
+	UpdatePendingAddress(context.Context, repository.UpdatePendingAddressOption) (*models.PendingAddress, error)
+	ApprovePendingAddress(context.Context, repository.ApprovePendingAddressOption) (*models.AddressItem, error)
+	RejectPendingAddress(context.Context, repository.RejectPendingAddressOption) error
+	ListDuplicateClusters(context.Context, repository.ListDuplicateClustersOption) ([]models.DuplicateCluster, error)
+	MergeAddresses(context.Context, repository.MergeAddressesOption) (*repository.MergeAddressesResult, error)
+	BackfillDuplicateKeys(
+		context.Context, repository.BackfillDuplicateKeysOption) (*repository.BackfillDuplicateKeysResult, error)
+	LinkAddressTranslations(context.Context, repository.LinkAddressTranslationsOption) (*models.TranslationGroup, error)
+	UnlinkAddressTranslation(context.Context, repository.UnlinkAddressTranslationOption) error
+	ListMissingTranslations(context.Context, repository.ListMissingTranslationsOption) ([]models.MissingTranslation, error)
 	RefreshTags(context.Context, repository.RefreshTagsOption) (*models.TagsRecord, error)
 	GetAllTags(context.Context, repository.GetAllTagsOption) (*models.TagsRecord, error)
+	ListTaxonomyTags(context.Context) ([]models.Tag, error)
+	CreateTaxonomyTag(context.Context, repository.CreateTaxonomyTagOption) (*models.Tag, error)
+	UpdateTaxonomyTag(context.Context, repository.UpdateTaxonomyTagOption) (*models.Tag, error)
+	DeleteTaxonomyTag(context.Context, repository.DeleteTaxonomyTagOption) error
+	MigrateAddressTags(context.Context, repository.MigrateAddressTagsOption) (*repository.MigrateAddressTagsResult, error)
 	SyncToTypesense(context.Context, repository.SyncToTypesenseOption) (*repository.SyncToTypesenseResult, error)
+	IsSearchSchemaCurrent(ctx context.Context, language models.Language) (bool, error)
 }
 
 type llmClient interface {
@@ -43,15 +85,20 @@ func NewAddressService(repo addressRepository, llm llmClient) *AddressService {
 }
 
 type GetAddressesInput struct {
-	Language models.Language
-	Keywords string
-	Tags     []string
-	PageSize int
-	Page     int
+	Language   models.Language
+	Keywords   string
+	Tags       []string
+	TagFilters map[models.TagCategory][]string // any tag within a category, every category has to match
+	Sort       models.AddressSort
+	FromIndex  bool // see repository.GetAddressesOptions
+	PageSize   int
+	Page       int
 }
 
 type GetAddressesOutput struct {
 	Addresses  []models.AddressItem
+	Highlights map[string]models.AddressHighlight // by address ID, only addresses matching the keywords
+	Facets     []models.TagFacet                  // tag counts per category over every matching address
 	TotalCount int64
 	Page       int
 	TotalPages int
@@ -60,6 +107,7 @@ type GetAddressesOutput struct {
 type CreateNewAddressInput struct {
 	Language models.Language
 	Address  models.AddressItem
+	Actor    string
 }
 
 type CreateNewAddressOutput struct {
@@ -70,6 +118,9 @@ type UpdateAddressInput struct {
 	Language models.Language
 	ID       string
 	Address  models.AddressItem
+	Actor    string
+	// updatedAt of the version the client edited
+	ExpectedUpdatedAt int64
 }
 
 type UpdateAddressOutput struct {
@@ -79,12 +130,66 @@ type UpdateAddressOutput struct {
 type DeleteAddressInput struct {
 	Language models.Language
 	ID       string
+	Actor    string
 }
 
 type DeleteAddressOutput struct {
 	ID string
 }
 
+type RestoreAddressInput struct {
+	Language models.Language
+	ID       string
+	Actor    string
+}
+
+type RestoreAddressOutput struct {
+	Address models.AddressItem
+}
+
+type ListDeletedAddressesInput struct {
+	Language models.Language
+	Limit    int
+}
+
+type ListDeletedAddressesOutput struct {
+	Addresses []models.AddressItem
+}
+
+type ListAddressRevisionsInput struct {
+	Language models.Language
+	ID       string
+	Limit    int
+}
+
+type ListAddressRevisionsOutput struct {
+	Revisions []models.AddressRevision
+}
+
+type DiffAddressRevisionsInput struct {
+	Language models.Language
+	ID       string
+	From     int64
+	To       int64
+}
+
+type DiffAddressRevisionsOutput struct {
+	From    int64
+	To      int64
+	Changes []models.FieldChange
+}
+
+type RollbackAddressInput struct {
+	Language models.Language
+	ID       string
+	Revision int64
+	Actor    string
+}
+
+type RollbackAddressOutput struct {
+	Address models.AddressItem
+}
+
 type GenerateAddressInput struct {
 	SystemPrompt    string
 	Prompt          string
@@ -92,10 +197,11 @@ type GenerateAddressInput struct {
 	Model           string
 	ReasoningEffort string
 	ThinkingLevel   string
+	Actor           string
 }
 
 type GenerateAddressOutput struct {
-	Addresses []models.AddressItem
+	Pending []models.PendingAddress
 }
 
 type RefreshTagsInput struct {
@@ -116,32 +222,53 @@ type GetAllTagsOutput struct {
 
 type SyncToTypesenseInput struct {
 	Language models.Language
+	Full     bool
 }
 
 type SyncToTypesenseOutput struct {
+	Mode    models.SearchSyncMode
 	Total   int
 	Success int
 	Failed  int
+	Deleted int
 }
 
+// Search addresses, it is rejected with ErrInvalidSearch for an unknown sort or tag category
+// and for a query the search index can't run, until a full sync brings it to the current schema
 func (s *AddressService) GetAddresses(
 	ctx context.Context,
 	input GetAddressesInput,
 ) (*GetAddressesOutput, error) {
+	if err := input.Sort.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+	}
+	for category := range input.TagFilters {
+		if err := category.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+		}
+	}
 	pageSize := input.PageSize
 	opts := repository.GetAddressesOptions{
-		Language: input.Language,
-		Keywords: input.Keywords,
-		Tags:     input.Tags,
-		PageSize: pageSize,
-		Page:     input.Page,
+		Language:   input.Language,
+		Keywords:   input.Keywords,
+		Tags:       input.Tags,
+		TagFilters: input.TagFilters,
+		Sort:       input.Sort,
+		FromIndex:  input.FromIndex,
+		PageSize:   pageSize,
+		Page:       input.Page,
 	}
 	response, err := s.repo.GetAddresses(ctx, opts)
+	if errors.Is(err, infra.ErrInvalidSearchQuery) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
 	return &GetAddressesOutput{
 			Addresses:  response.Addresses,
+			Highlights: response.Highlights,
+			Facets:     response.Facets,
 			TotalCount: response.TotalCount,
 			Page:       response.Page,
 			TotalPages: response.TotalPages,
@@ -149,10 +276,19 @@ func (s *AddressService) GetAddresses(
 		nil
 }
 
+// Create an address, its tags are replaced by the display names of their taxonomy tags.
+// It is rejected with ErrUnknownTags when a tag isn't in the taxonomy
 func (s *AddressService) CreateNewAddress(ctx context.Context, input CreateNewAddressInput) (*CreateNewAddressOutput, error) {
+	address := input.Address
+	tags, err := s.canonicalizeTags(ctx, input.Language, address.Tags)
+	if err != nil {
+		return nil, err
+	}
+	address.Tags = tags
 	opts := repository.CreateNewAddressOption{
 		Language:    input.Language,
-		AddressItem: input.Address,
+		AddressItem: address,
+		Actor:       input.Actor,
 	}
 	id, err := s.repo.CreateNewAddress(ctx, opts)
 	if err != nil {
@@ -161,11 +297,20 @@ func (s *AddressService) CreateNewAddress(ctx context.Context, input CreateNewAd
 	return &CreateNewAddressOutput{ID: id}, nil
 }
 
+// Update an address, its tags are checked against the taxonomy like in CreateNewAddress
 func (s *AddressService) UpdateAddress(ctx context.Context, input UpdateAddressInput) (*UpdateAddressOutput, error) {
+	address := input.Address
+	tags, err := s.canonicalizeTags(ctx, input.Language, address.Tags)
+	if err != nil {
+		return nil, err
+	}
+	address.Tags = tags
 	opts := repository.UpdateAddressOption{
-		Language:    input.Language,
-		ID:          input.ID,
-		AddressItem: input.Address,
+		Language:          input.Language,
+		ID:                input.ID,
+		AddressItem:       address,
+		Actor:             input.Actor,
+		ExpectedUpdatedAt: input.ExpectedUpdatedAt,
 	}
 	addressItem, err := s.repo.UpdateAddress(ctx, opts)
 	if err != nil {
@@ -178,6 +323,7 @@ func (s *AddressService) DeleteAddress(ctx context.Context, input DeleteAddressI
 	opts := repository.DeleteAddressOption{
 		Language: input.Language,
 		ID:       input.ID,
+		Actor:    input.Actor,
 	}
 	deletedId, err := s.repo.DeleteAddress(ctx, opts)
 	if err != nil {
@@ -186,6 +332,99 @@ func (s *AddressService) DeleteAddress(ctx context.Context, input DeleteAddressI
 	return &DeleteAddressOutput{ID: deletedId}, nil
 }
 
+func (s *AddressService) RestoreAddress(ctx context.Context, input RestoreAddressInput) (*RestoreAddressOutput, error) {
+	opts := repository.RestoreAddressOption{
+		Language: input.Language,
+		ID:       input.ID,
+		Actor:    input.Actor,
+	}
+	address, err := s.repo.RestoreAddress(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreAddressOutput{Address: *address}, nil
+}
+
+func (s *AddressService) ListDeletedAddresses(
+	ctx context.Context,
+	input ListDeletedAddressesInput) (*ListDeletedAddressesOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListDeletedAddresses
+	}
+	opts := repository.ListDeletedAddressesOption{
+		Language: input.Language,
+		Limit:    min(limit, maxListDeletedAddresses),
+	}
+	addresses, err := s.repo.ListDeletedAddresses(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ListDeletedAddressesOutput{Addresses: addresses}, nil
+}
+
+func (s *AddressService) ListAddressRevisions(
+	ctx context.Context,
+	input ListAddressRevisionsInput) (*ListAddressRevisionsOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListRevisions
+	}
+	opts := repository.ListAddressRevisionsOption{
+		Language: input.Language,
+		ID:       input.ID,
+		Limit:    min(limit, maxListRevisions),
+	}
+	revisions, err := s.repo.ListAddressRevisions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ListAddressRevisionsOutput{Revisions: revisions}, nil
+}
+
+// Field-level changes between the snapshots of two revisions
+func (s *AddressService) DiffAddressRevisions(
+	ctx context.Context,
+	input DiffAddressRevisionsInput) (*DiffAddressRevisionsOutput, error) {
+	from, err := s.repo.GetAddressRevision(ctx, repository.GetAddressRevisionOption{
+		Language: input.Language,
+		ID:       input.ID,
+		Revision: input.From,
+	})
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.repo.GetAddressRevision(ctx, repository.GetAddressRevisionOption{
+		Language: input.Language,
+		ID:       input.ID,
+		Revision: input.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DiffAddressRevisionsOutput{
+		From:    input.From,
+		To:      input.To,
+		Changes: models.DiffAddressItems(from.Snapshot, to.Snapshot),
+	}, nil
+}
+
+func (s *AddressService) RollbackAddress(
+	ctx context.Context,
+	input RollbackAddressInput) (*RollbackAddressOutput, error) {
+	opts := repository.RollbackAddressOption{
+		Language: input.Language,
+		ID:       input.ID,
+		Revision: input.Revision,
+		Actor:    input.Actor,
+	}
+	address, err := s.repo.RollbackAddress(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &RollbackAddressOutput{Address: *address}, nil
+}
+
 func (s *AddressService) GenerateNewAddress(ctx context.Context, input GenerateAddressInput) (*GenerateAddressOutput, error) {
 	// validate input
 	if input.Prompt == "" {
@@ -208,7 +447,6 @@ func (s *AddressService) GenerateNewAddress(ctx context.Context, input GenerateA
 	addresses := []models.AddressItem{}
 	for _, address := range result.Addresses {
 		addressItem := models.AddressItem{
-			ID:         uuid.NewString(),
 			Name:       address.Name,
 			BriefIntro: address.BriefIntro,
 			Tags:       address.Tags,
@@ -216,7 +454,28 @@ func (s *AddressService) GenerateNewAddress(ctx context.Context, input GenerateA
 		}
 		addresses = append(addresses, addressItem)
 	}
-	return &GenerateAddressOutput{Addresses: addresses}, nil
+	if len(addresses) == 0 {
+		return &GenerateAddressOutput{Pending: []models.PendingAddress{}}, nil
+	}
+	// generated addresses wait in the review queue until an admin approves them
+	pending, err := s.repo.CreatePendingAddresses(ctx, repository.CreatePendingAddressesOption{
+		Language:  input.Language,
+		Addresses: addresses,
+		Generation: models.GenerationMetadata{
+			RunID:           uuid.NewString(),
+			Model:           input.Model,
+			SystemPrompt:    input.SystemPrompt,
+			Prompt:          input.Prompt,
+			ReasoningEffort: input.ReasoningEffort,
+			ThinkingLevel:   input.ThinkingLevel,
+			GeneratedBy:     input.Actor,
+			GeneratedAt:     time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GenerateAddressOutput{Pending: pending}, nil
 }
 
 func (s *AddressService) RefreshTags(
@@ -246,14 +505,61 @@ func (s *AddressService) GetAllTags(
 func (s *AddressService) SyncToTypesense(
 	ctx context.Context,
 	input SyncToTypesenseInput) (*SyncToTypesenseOutput, error) {
-	opts := repository.SyncToTypesenseOption{Language: input.Language}
+	opts := repository.SyncToTypesenseOption{Language: input.Language, Full: input.Full}
 	result, err := s.repo.SyncToTypesense(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 	return &SyncToTypesenseOutput{
+		Mode:    result.Mode,
 		Total:   result.Total,
 		Success: result.Success,
 		Failed:  result.Failed,
+		Deleted: result.Deleted,
 	}, nil
 }
+
+// Job runner for models.JobTypeTypesenseSync
+func (s *AddressService) RunSyncToTypesenseJob(
+	ctx context.Context,
+	params map[string]string,
+	progress repository.ProgressFunc) error {
+	language := models.Language(params[models.JobParamLanguage])
+	if err := language.Validate(); err != nil {
+		return err
+	}
+	full, _ := strconv.ParseBool(params[models.JobParamFullSync])
+	opts := repository.SyncToTypesenseOption{Language: language, Full: full, Progress: progress}
+	_, err := s.repo.SyncToTypesense(ctx, opts)
+	return err
+}
+
+// Languages whose search index misses fields of the current schema, the facets and the name sort
+// only work on them after a full sync
+func (s *AddressService) ListOutdatedSearchIndexes(ctx context.Context) ([]models.Language, error) {
+	outdated := []models.Language{}
+	for _, language := range models.Languages().Codes() {
+		current, err := s.repo.IsSearchSchemaCurrent(ctx, language)
+		if err != nil {
+			return nil, err
+		}
+		if !current {
+			outdated = append(outdated, language)
+		}
+	}
+	return outdated, nil
+}
+
+// Job runner for models.JobTypeTagsRefresh
+func (s *AddressService) RunRefreshTagsJob(
+	ctx context.Context,
+	params map[string]string,
+	progress repository.ProgressFunc) error {
+	language := models.Language(params[models.JobParamLanguage])
+	if err := language.Validate(); err != nil {
+		return err
+	}
+	opts := repository.RefreshTagsOption{Language: language, Progress: progress}
+	_, err := s.repo.RefreshTags(ctx, opts)
+	return err
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+// Upper bound of the addresses merged into a survivor at once, one transaction handles all of them
+const maxMergeDuplicates = 20
+
+var ErrInvalidMerge = errors.New("invalid merge")
+
+type ListDuplicateClustersInput struct {
+	Language models.Language
+}
+
+type ListDuplicateClustersOutput struct {
+	Clusters []models.DuplicateCluster
+}
+
+type MergeAddressesInput struct {
+	Language     models.Language
+	SurvivorID   string
+	DuplicateIDs []string
+	Actor        string
+}
+
+type MergeAddressesOutput struct {
+	Survivor      models.AddressItem
+	MergedIDs     []string
+	MigratedUsers int
+}
+
+func (s *AddressService) ListDuplicateClusters(
+	ctx context.Context,
+	input ListDuplicateClustersInput) (*ListDuplicateClustersOutput, error) {
+	clusters, err := s.repo.ListDuplicateClusters(ctx, repository.ListDuplicateClustersOption{
+		Language: input.Language,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ListDuplicateClustersOutput{Clusters: clusters}, nil
+}
+
+// Merge duplicates into the surviving address, the duplicates end up in the trash
+func (s *AddressService) MergeAddresses(ctx context.Context, input MergeAddressesInput) (*MergeAddressesOutput, error) {
+	duplicateIDs := []string{}
+	for _, id := range input.DuplicateIDs {
+		if !slices.Contains(duplicateIDs, id) {
+			duplicateIDs = append(duplicateIDs, id)
+		}
+	}
+	switch {
+	case input.SurvivorID == "":
+		return nil, fmt.Errorf("%w: survivor ID is required", ErrInvalidMerge)
+	case len(duplicateIDs) == 0:
+		return nil, fmt.Errorf("%w: at least one duplicate ID is required", ErrInvalidMerge)
+	case len(duplicateIDs) > maxMergeDuplicates:
+		return nil, fmt.Errorf("%w: at most %d addresses can be merged at once", ErrInvalidMerge, maxMergeDuplicates)
+	case slices.Contains(duplicateIDs, input.SurvivorID):
+		return nil, fmt.Errorf("%w: the survivor can't be merged into itself", ErrInvalidMerge)
+	}
+	result, err := s.repo.MergeAddresses(ctx, repository.MergeAddressesOption{
+		Language:     input.Language,
+		SurvivorID:   input.SurvivorID,
+		DuplicateIDs: duplicateIDs,
+		Actor:        input.Actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MergeAddressesOutput{
+		Survivor:      result.Survivor,
+		MergedIDs:     result.MergedIDs,
+		MigratedUsers: result.MigratedUsers,
+	}, nil
+}
+
+// Job runner for models.JobTypeDuplicateKeysBackfill
+func (s *AddressService) RunBackfillDuplicateKeysJob(
+	ctx context.Context,
+	params map[string]string,
+	progress repository.ProgressFunc) error {
+	language := models.Language(params[models.JobParamLanguage])
+	if err := language.Validate(); err != nil {
+		return err
+	}
+	_, err := s.repo.BackfillDuplicateKeys(ctx, repository.BackfillDuplicateKeysOption{
+		Language: language,
+		Progress: progress,
+	})
+	return err
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddressService_MergeAddresses(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("MergeAddresses", mock.Anything, repository.MergeAddressesOption{
+		Language:     "en",
+		SurvivorID:   "a1",
+		DuplicateIDs: []string{"a2", "a3"},
+		Actor:        "admin-uid",
+	}).Return(&repository.MergeAddressesResult{
+		Survivor:      models.AddressItem{ID: "a1"},
+		MergedIDs:     []string{"a2", "a3"},
+		MigratedUsers: 4,
+	}, nil).Once()
+	output, err := service.MergeAddresses(context.Background(), MergeAddressesInput{
+		Language:     "en",
+		SurvivorID:   "a1",
+		DuplicateIDs: []string{"a2", "a3", "a2"},
+		Actor:        "admin-uid",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a1", output.Survivor.ID)
+	assert.Equal(t, []string{"a2", "a3"}, output.MergedIDs)
+	assert.Equal(t, 4, output.MigratedUsers)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_MergeAddresses_Invalid(t *testing.T) {
+	t.Parallel()
+	tooMany := make([]string, maxMergeDuplicates+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+	tests := []struct {
+		name  string
+		input MergeAddressesInput
+	}{
+		{name: "missing survivor", input: MergeAddressesInput{DuplicateIDs: []string{"a2"}}},
+		{name: "no duplicates", input: MergeAddressesInput{SurvivorID: "a1"}},
+		{name: "survivor among duplicates", input: MergeAddressesInput{SurvivorID: "a1", DuplicateIDs: []string{"a1", "a2"}}},
+		{name: "too many duplicates", input: MergeAddressesInput{SurvivorID: "z1", DuplicateIDs: tooMany}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupAddressService()
+			output, err := service.MergeAddresses(context.Background(), tt.input)
+			assert.ErrorIs(t, err, ErrInvalidMerge)
+			assert.Nil(t, output)
+			repo.AssertNotCalled(t, "MergeAddresses", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAddressService_ListDuplicateClusters(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	clusters := []models.DuplicateCluster{{ID: "a1", Addresses: []models.AddressItem{{ID: "a1"}, {ID: "a2"}}}}
+	repo.On("ListDuplicateClusters", mock.Anything, repository.ListDuplicateClustersOption{Language: "en"}).
+		Return(clusters, nil).Once()
+	output, err := service.ListDuplicateClusters(context.Background(), ListDuplicateClustersInput{Language: "en"})
+	assert.NoError(t, err)
+	assert.Equal(t, clusters, output.Clusters)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_RunBackfillDuplicateKeysJob(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("BackfillDuplicateKeys", mock.Anything, mock.MatchedBy(func(opts repository.BackfillDuplicateKeysOption) bool {
+		return opts.Language == "en" && opts.Progress != nil
+	})).Return(&repository.BackfillDuplicateKeysResult{Processed: 3, Updated: 2}, nil).Once()
+	err := service.RunBackfillDuplicateKeysJob(context.Background(), map[string]string{
+		models.JobParamLanguage: "en",
+	}, func(processed int, failed int) {})
+	assert.NoError(t, err)
+
+	err = service.RunBackfillDuplicateKeysJob(context.Background(), map[string]string{models.JobParamLanguage: "xx"}, nil)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
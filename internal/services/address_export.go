@@ -0,0 +1,135 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+type ExportAddressesInput struct {
+	Language     models.Language // ignored by the zip format, which covers every language
+	Format       models.AddressExportFormat
+	Tags         []string
+	UpdatedSince int64
+}
+
+type ExportAddressesOutput struct {
+	Count int
+}
+
+// Stream the addresses matching the filters to w in the requested format
+func (s *AddressService) ExportAddresses(
+	ctx context.Context,
+	input ExportAddressesInput,
+	w io.Writer) (*ExportAddressesOutput, error) {
+	if err := input.Format.Validate(); err != nil {
+		return nil, err
+	}
+	count := 0
+	var err error
+	switch input.Format {
+	case models.AddressExportFormatJSONL:
+		count, err = s.exportJSONL(ctx, input, input.Language, w)
+	case models.AddressExportFormatCSV:
+		count, err = s.exportCSV(ctx, input, w)
+	case models.AddressExportFormatZip:
+		count, err = s.exportZip(ctx, input, w)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ExportAddressesOutput{Count: count}, nil
+}
+
+// Name of the exported file or of the zip entry of a language
+func ExportFileName(language models.Language, format models.AddressExportFormat) string {
+	if format == models.AddressExportFormatZip {
+		return "addresses.zip"
+	}
+	return fmt.Sprintf("addresses_%s.%s", language.Get(), format)
+}
+
+// ---------- Helper methods ----------
+
+func (s *AddressService) exportJSONL(
+	ctx context.Context,
+	input ExportAddressesInput,
+	language models.Language,
+	w io.Writer) (int, error) {
+	encoder := json.NewEncoder(w)
+	count := 0
+	err := s.repo.StreamAddresses(ctx, getStreamAddressesOption(input, language), func(address models.AddressItem) error {
+		count++
+		return encoder.Encode(address)
+	})
+	return count, err
+}
+
+func (s *AddressService) exportCSV(ctx context.Context, input ExportAddressesInput, w io.Writer) (int, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(addressCSVColumns); err != nil {
+		return 0, err
+	}
+	count := 0
+	err := s.repo.StreamAddresses(ctx, getStreamAddressesOption(input, input.Language), func(address models.AddressItem) error {
+		count++
+		return writer.Write(getAddressCSVRow(address))
+	})
+	if err != nil {
+		return count, err
+	}
+	writer.Flush()
+	return count, writer.Error()
+}
+
+func (s *AddressService) exportZip(ctx context.Context, input ExportAddressesInput, w io.Writer) (int, error) {
+	archive := zip.NewWriter(w)
+	count := 0
+	for _, language := range models.Languages().Codes() {
+		entry, err := archive.Create(ExportFileName(language, models.AddressExportFormatJSONL))
+		if err != nil {
+			return count, err
+		}
+		exported, err := s.exportJSONL(ctx, input, language, entry)
+		count += exported
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, archive.Close()
+}
+
+func getStreamAddressesOption(input ExportAddressesInput, language models.Language) repository.StreamAddressesOption {
+	return repository.StreamAddressesOption{
+		Language:     language,
+		Tags:         input.Tags,
+		UpdatedSince: input.UpdatedSince,
+	}
+}
+
+// Values in the order of addressCSVColumns
+func getAddressCSVRow(address models.AddressItem) []string {
+	return []string{
+		address.ID,
+		address.Name,
+		address.BriefIntro,
+		strings.Join(address.Tags, csvTagSeparator),
+		address.Address.City,
+		address.Address.Country,
+		address.Address.Line1,
+		address.Address.Line2,
+		address.Address.BuildingName,
+		address.Address.PostalCode,
+		address.Address.Region,
+		strconv.FormatInt(address.CreatedAt, 10),
+		strconv.FormatInt(address.UpdatedAt, 10),
+	}
+}
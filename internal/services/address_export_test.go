@@ -0,0 +1,139 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var exportedAddress = models.AddressItem{
+	ID:         "a1",
+	Name:       "Jane Austen",
+	BriefIntro: "Novelist, author of Emma",
+	Tags:       []string{"UK", "writer"},
+	Address: models.Address{
+		City:       "Bath",
+		Country:    "UK",
+		Line1:      "4 Sydney Place",
+		Region:     "Somerset",
+		PostalCode: "BA2 6NF",
+	},
+	CreatedAt: 100,
+	UpdatedAt: 200,
+	Revision:  2,
+}
+
+func TestAddressService_ExportAddresses_JSONL(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	second := exportedAddress
+	second.ID = "a2"
+	repo.On("StreamAddresses", mock.Anything, repository.StreamAddressesOption{
+		Language:     "en",
+		Tags:         []string{"writer"},
+		UpdatedSince: 150,
+	}).Return([]models.AddressItem{exportedAddress, second}, nil).Once()
+	var buf bytes.Buffer
+	output, err := service.ExportAddresses(context.Background(), ExportAddressesInput{
+		Language:     "en",
+		Format:       models.AddressExportFormatJSONL,
+		Tags:         []string{"writer"},
+		UpdatedSince: 150,
+	}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, output.Count)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	var decoded models.AddressItem
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+	assert.Equal(t, exportedAddress, decoded)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ExportAddresses_CSVRoundTrip(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
+	repo.On("StreamAddresses", mock.Anything, mock.Anything).
+		Return([]models.AddressItem{exportedAddress}, nil).Once()
+	var buf bytes.Buffer
+	output, err := service.ExportAddresses(context.Background(), ExportAddressesInput{
+		Language: "en",
+		Format:   models.AddressExportFormatCSV,
+	}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, output.Count)
+	assert.True(t, strings.HasPrefix(buf.String(), strings.Join(addressCSVColumns, ",")+"\n"))
+	assert.Contains(t, buf.String(), `a1,Jane Austen,"Novelist, author of Emma",UK|writer,Bath,UK,4 Sydney Place,,,BA2 6NF,Somerset,100,200`)
+
+	// an export can be imported again, the server managed columns are ignored
+	expected := exportedAddress
+	expected.ID, expected.CreatedAt, expected.UpdatedAt, expected.Revision = "", 0, 0, 0
+	repo.On("ImportAddresses", mock.Anything, mock.MatchedBy(func(opts repository.ImportAddressesOption) bool {
+		return len(opts.Items) == 1 && assert.ObjectsAreEqual(expected, opts.Items[0].AddressItem)
+	})).Return(&repository.ImportAddressesResult{}, nil).Once()
+	_, err = service.ImportAddresses(context.Background(), ImportAddressesInput{
+		Language: "en",
+		Format:   models.AddressImportFormatCSV,
+		Data:     &buf,
+		DryRun:   true,
+	})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ExportAddresses_Zip(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("StreamAddresses", mock.Anything, mock.MatchedBy(func(opts repository.StreamAddressesOption) bool {
+		return opts.Language == models.LanguageZH
+	})).Return([]models.AddressItem{exportedAddress}, nil).Once()
+	repo.On("StreamAddresses", mock.Anything, mock.MatchedBy(func(opts repository.StreamAddressesOption) bool {
+		return opts.Language == models.LanguageEN
+	})).Return([]models.AddressItem{exportedAddress, exportedAddress}, nil).Once()
+	var buf bytes.Buffer
+	output, err := service.ExportAddresses(context.Background(), ExportAddressesInput{
+		Format: models.AddressExportFormatZip,
+	}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, output.Count)
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	files := map[string]int{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+		files[file.Name] = strings.Count(string(content), "\n")
+	}
+	assert.Equal(t, map[string]int{"addresses_zh.jsonl": 1, "addresses_en.jsonl": 2}, files)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ExportAddresses_Errors(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	_, err := service.ExportAddresses(context.Background(), ExportAddressesInput{Format: "xml"}, io.Discard)
+	assert.Error(t, err)
+
+	repo.On("StreamAddresses", mock.Anything, mock.Anything).
+		Return([]models.AddressItem{exportedAddress}, errors.New("firestore unavailable")).Once()
+	_, err = service.ExportAddresses(context.Background(), ExportAddressesInput{
+		Language: "en",
+		Format:   models.AddressExportFormatJSONL,
+	}, io.Discard)
+	assert.EqualError(t, err, "firestore unavailable")
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+const (
+	maxImportRows = 5000
+	// tags of a CSV row are separated by this character
+	csvTagSeparator = "|"
+)
+
+// The import file as a whole can't be read, no row was imported
+var ErrInvalidImport = errors.New("invalid import file")
+
+// CSV columns, named after the JSON fields of models.AddressItem.
+// The server managed id, createdAt and updatedAt columns of an export are ignored by the import
+var (
+	addressCSVColumns = []string{
+		"id", "name", "briefIntro", "tags",
+		"address.city", "address.country", "address.line1", "address.line2",
+		"address.buildingName", "address.postalCode", "address.region",
+		"createdAt", "updatedAt",
+	}
+	csvRequiredColumns = []string{
+		"name", "briefIntro", "tags", "address.city", "address.country", "address.line1", "address.region",
+	}
+)
+
+type ImportAddressesInput struct {
+	Language models.Language
+	Format   models.AddressImportFormat
+	Data     io.Reader
+	DryRun   bool
+	Actor    string
+}
+
+type ImportAddressesSummary struct {
+	Total     int
+	Ready     int
+	Created   int
+	Invalid   int
+	Duplicate int
+	Failed    int
+	Indexed   int
+}
+
+type ImportAddressesOutput struct {
+	DryRun  bool
+	Rows    []models.AddressImportRow // ordered by row
+	Summary ImportAddressesSummary
+}
+
+type importRecord struct {
+	row     int
+	address models.AddressItem
+	err     error
+}
+
+// Validate every record of a CSV or JSONL file and create the valid ones that are not duplicates
+func (s *AddressService) ImportAddresses(
+	ctx context.Context,
+	input ImportAddressesInput) (*ImportAddressesOutput, error) {
+	if err := input.Format.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	var (
+		records []importRecord
+		err     error
+	)
+	if input.Format == models.AddressImportFormatCSV {
+		records, err = parseCSVImport(input.Data)
+	} else {
+		records, err = parseJSONLImport(input.Data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	taxonomy, err := s.getTagTaxonomy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := []models.AddressImportRow{}
+	items := []repository.ImportAddressItem{}
+	for _, record := range records {
+		if record.err == nil {
+			record.err = validateImportAddress(record.address)
+		}
+		if record.err == nil {
+			record.address.Tags, record.err = canonicalizeTags(taxonomy, input.Language, record.address.Tags)
+		}
+		if record.err != nil {
+			rows = append(rows, models.AddressImportRow{
+				Row:    record.row,
+				Status: models.AddressImportStatusInvalid,
+				Name:   record.address.Name,
+				Error:  record.err.Error(),
+			})
+			continue
+		}
+		items = append(items, repository.ImportAddressItem{Row: record.row, AddressItem: record.address})
+	}
+	indexed := 0
+	if len(items) > 0 {
+		result, err := s.repo.ImportAddresses(ctx, repository.ImportAddressesOption{
+			Language: input.Language,
+			Items:    items,
+			DryRun:   input.DryRun,
+			Actor:    input.Actor,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, result.Rows...)
+		indexed = result.Indexed
+	}
+	slices.SortFunc(rows, func(a, b models.AddressImportRow) int {
+		return a.Row - b.Row
+	})
+	return &ImportAddressesOutput{
+		DryRun:  input.DryRun,
+		Rows:    rows,
+		Summary: summarizeImport(rows, indexed),
+	}, nil
+}
+
+// ---------- Helper methods ----------
+
+func parseCSVImport(data io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(data)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read CSV header: %w", ErrInvalidImport, err)
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if !slices.Contains(addressCSVColumns, column) {
+			return nil, fmt.Errorf("%w: unknown CSV column %q", ErrInvalidImport, column)
+		}
+		columns[column] = i
+	}
+	for _, column := range csvRequiredColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("%w: missing CSV column %q", ErrInvalidImport, column)
+		}
+	}
+	records := []importRecord{}
+	for row := 1; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if row > maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, maxImportRows)
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// a malformed record only invalidates its own row
+			records = append(records, importRecord{row: row, err: err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+		tags := []string{}
+		for _, tag := range strings.Split(value("tags"), csvTagSeparator) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		records = append(records, importRecord{row: row, address: models.AddressItem{
+			Name:       value("name"),
+			BriefIntro: value("briefIntro"),
+			Tags:       tags,
+			Address: models.Address{
+				City:         value("address.city"),
+				Country:      value("address.country"),
+				Line1:        value("address.line1"),
+				Line2:        value("address.line2"),
+				BuildingName: value("address.buildingName"),
+				PostalCode:   value("address.postalCode"),
+				Region:       value("address.region"),
+			},
+		}})
+	}
+	return records, nil
+}
+
+func parseJSONLImport(data io.Reader) ([]importRecord, error) {
+	scanner := bufio.NewScanner(data)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	records := []importRecord{}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if len(records) >= maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, maxImportRows)
+		}
+		var address models.AddressItem
+		if err := json.Unmarshal([]byte(text), &address); err != nil {
+			records = append(records, importRecord{row: line, err: fmt.Errorf("invalid JSON: %w", err)})
+			continue
+		}
+		records = append(records, importRecord{row: line, address: address})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	return records, nil
+}
+
+// The same fields CreateNewAddress requires. Server managed fields are not checked,
+// the repository resets them when it writes the rows
+func validateImportAddress(address models.AddressItem) error {
+	missing := []string{}
+	required := map[string]string{
+		"name":            address.Name,
+		"briefIntro":      address.BriefIntro,
+		"address.city":    address.Address.City,
+		"address.country": address.Address.Country,
+		"address.line1":   address.Address.Line1,
+		"address.region":  address.Address.Region,
+	}
+	for _, field := range csvRequiredColumns {
+		if value, ok := required[field]; ok && strings.TrimSpace(value) == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(address.Tags) == 0 {
+		missing = append(missing, "tags")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func summarizeImport(rows []models.AddressImportRow, indexed int) ImportAddressesSummary {
+	summary := ImportAddressesSummary{Total: len(rows), Indexed: indexed}
+	for _, row := range rows {
+		switch row.Status {
+		case models.AddressImportStatusReady:
+			summary.Ready++
+		case models.AddressImportStatusCreated:
+			summary.Created++
+		case models.AddressImportStatusInvalid:
+			summary.Invalid++
+		case models.AddressImportStatusDuplicate:
+			summary.Duplicate++
+		case models.AddressImportStatusFailed:
+			summary.Failed++
+		}
+	}
+	return summary
+}
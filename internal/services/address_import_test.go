@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddressService_ImportAddresses_CSV(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
+	data := strings.Join([]string{
+		"name,briefIntro,tags,address.city,address.country,address.line1,address.region,address.postalCode",
+		`Jane Austen,"Novelist, author of Emma",UK|writer|novelist,Bath,UK,4 Sydney Place,Somerset,BA2 6NF`,
+		"Missing Intro,,UK|writer,Bath,UK,1 Street,Somerset,",
+		"Charles Dickens,Novelist,UK|writer,London,UK,48 Doughty Street,London",
+		`Bare "quote,x,y,z,z,z,z,z`,
+	}, "\n")
+	jane := models.AddressItem{
+		Name:       "Jane Austen",
+		BriefIntro: "Novelist, author of Emma",
+		Tags:       []string{"UK", "writer", "novelist"},
+		Address: models.Address{
+			City:       "Bath",
+			Country:    "UK",
+			Line1:      "4 Sydney Place",
+			Region:     "Somerset",
+			PostalCode: "BA2 6NF",
+		},
+	}
+	repo.On("ImportAddresses", mock.Anything, mock.MatchedBy(func(opts repository.ImportAddressesOption) bool {
+		// only the valid rows reach the repository, rows with a wrong column count are invalid
+		return opts.Language == "en" &&
+			opts.DryRun &&
+			opts.Actor == "admin-uid" &&
+			len(opts.Items) == 1 &&
+			opts.Items[0].Row == 1 &&
+			assert.ObjectsAreEqual(jane, opts.Items[0].AddressItem)
+	})).Return(&repository.ImportAddressesResult{Rows: []models.AddressImportRow{
+		{Row: 1, Status: models.AddressImportStatusReady, Name: "Jane Austen"},
+	}}, nil).Once()
+	output, err := service.ImportAddresses(context.Background(), ImportAddressesInput{
+		Language: "en",
+		Format:   models.AddressImportFormatCSV,
+		Data:     strings.NewReader(data),
+		DryRun:   true,
+		Actor:    "admin-uid",
+	})
+	assert.NoError(t, err)
+	assert.True(t, output.DryRun)
+	assert.Len(t, output.Rows, 4)
+	assert.Equal(t, []int{1, 2, 3, 4}, []int{output.Rows[0].Row, output.Rows[1].Row, output.Rows[2].Row, output.Rows[3].Row})
+	assert.Equal(t, models.AddressImportStatusReady, output.Rows[0].Status)
+	assert.Equal(t, models.AddressImportStatusInvalid, output.Rows[1].Status)
+	assert.Contains(t, output.Rows[1].Error, "briefIntro")
+	assert.Equal(t, models.AddressImportStatusInvalid, output.Rows[2].Status)
+	assert.Equal(t, ImportAddressesSummary{Total: 4, Ready: 1, Invalid: 3}, output.Summary)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ImportAddresses_JSONL(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
+	data := strings.Join([]string{
+		`{"name":"Jane Austen","briefIntro":"Novelist","tags":["UK","writer"],` +
+			`"address":{"city":"Bath","country":"UK","line1":"4 Sydney Place","region":"Somerset"}}`,
+		``,
+		`{"name": "Broken"`,
+		`{"name":"Jane Austen","briefIntro":"Novelist","tags":["UK","writer"],` +
+			`"address":{"city":"Bath","country":"UK","line1":"4 Sydney Place","region":"Somerset"}}`,
+	}, "\n")
+	repo.On("ImportAddresses", mock.Anything, mock.MatchedBy(func(opts repository.ImportAddressesOption) bool {
+		return !opts.DryRun && len(opts.Items) == 2 && opts.Items[0].Row == 1 && opts.Items[1].Row == 4
+	})).Return(&repository.ImportAddressesResult{
+		Rows: []models.AddressImportRow{
+			{Row: 1, Status: models.AddressImportStatusCreated, ID: "a1", Name: "Jane Austen"},
+			{Row: 4, Status: models.AddressImportStatusDuplicate, Name: "Jane Austen"},
+		},
+		Indexed: 1,
+	}, nil).Once()
+	output, err := service.ImportAddresses(context.Background(), ImportAddressesInput{
+		Language: "en",
+		Format:   models.AddressImportFormatJSONL,
+		Data:     strings.NewReader(data),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, output.Rows, 3)
+	assert.Equal(t, 3, output.Rows[1].Row)
+	assert.Contains(t, output.Rows[1].Error, "invalid JSON")
+	assert.Equal(t, ImportAddressesSummary{Total: 3, Created: 1, Invalid: 1, Duplicate: 1, Indexed: 1}, output.Summary)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ImportAddresses_InvalidFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		format models.AddressImportFormat
+		data   string
+	}{
+		{name: "unknown format", format: "xlsx", data: ""},
+		{name: "empty csv", format: models.AddressImportFormatCSV, data: ""},
+		{name: "unknown column", format: models.AddressImportFormatCSV, data: "name,email\n"},
+		{name: "missing column", format: models.AddressImportFormatCSV, data: "name,briefIntro,tags\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupAddressService()
+			output, err := service.ImportAddresses(context.Background(), ImportAddressesInput{
+				Language: "en",
+				Format:   tt.format,
+				Data:     strings.NewReader(tt.data),
+			})
+			assert.ErrorIs(t, err, ErrInvalidImport)
+			assert.Nil(t, output)
+			repo.AssertNotCalled(t, "ImportAddresses", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+var (
+	ErrInvalidTag = errors.New("invalid tag")
+	// an address carries tags the taxonomy doesn't know
+	ErrUnknownTags = errors.New("unknown tags")
+)
+
+type ListTaxonomyTagsInput struct {
+	Category models.TagCategory // optional
+}
+
+type ListTaxonomyTagsOutput struct {
+	Tags []models.Tag
+}
+
+type CreateTaxonomyTagInput struct {
+	Tag models.Tag
+}
+
+type CreateTaxonomyTagOutput struct {
+	Tag models.Tag
+}
+
+type UpdateTaxonomyTagInput struct {
+	Tag models.Tag
+}
+
+type UpdateTaxonomyTagOutput struct {
+	Tag models.Tag
+}
+
+type DeleteTaxonomyTagInput struct {
+	Slug string
+}
+
+type DeleteTaxonomyTagOutput struct {
+	Slug string
+}
+
+func (s *AddressService) ListTaxonomyTags(
+	ctx context.Context, input ListTaxonomyTagsInput) (*ListTaxonomyTagsOutput, error) {
+	if input.Category != "" {
+		if err := input.Category.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTag, err)
+		}
+	}
+	tags, err := s.repo.ListTaxonomyTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if input.Category != "" {
+		tags = slices.DeleteFunc(tags, func(tag models.Tag) bool {
+			return tag.Category != input.Category
+		})
+	}
+	return &ListTaxonomyTagsOutput{Tags: tags}, nil
+}
+
+// Add a tag to the taxonomy, the slug is derived from the English name or else the first name when it is missing
+func (s *AddressService) CreateTaxonomyTag(
+	ctx context.Context, input CreateTaxonomyTagInput) (*CreateTaxonomyTagOutput, error) {
+	tag := input.Tag
+	tag.Normalize()
+	if tag.Slug == "" {
+		tag.Slug = defaultTagSlug(tag)
+	}
+	if err := tag.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTag, err)
+	}
+	created, err := s.repo.CreateTaxonomyTag(ctx, repository.CreateTaxonomyTagOption{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	return &CreateTaxonomyTagOutput{Tag: *created}, nil
+}
+
+// Replace the category, names and synonyms of a tag. Addresses keep the name they carry,
+// run the tag migration to rewrite them to a changed display name. Their search records are refreshed
+func (s *AddressService) UpdateTaxonomyTag(
+	ctx context.Context, input UpdateTaxonomyTagInput) (*UpdateTaxonomyTagOutput, error) {
+	tag := input.Tag
+	tag.Normalize()
+	if err := tag.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTag, err)
+	}
+	updated, err := s.repo.UpdateTaxonomyTag(ctx, repository.UpdateTaxonomyTagOption{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	return &UpdateTaxonomyTagOutput{Tag: *updated}, nil
+}
+
+func (s *AddressService) DeleteTaxonomyTag(
+	ctx context.Context, input DeleteTaxonomyTagInput) (*DeleteTaxonomyTagOutput, error) {
+	slug := strings.ToLower(strings.TrimSpace(input.Slug))
+	if err := s.repo.DeleteTaxonomyTag(ctx, repository.DeleteTaxonomyTagOption{Slug: slug}); err != nil {
+		return nil, err
+	}
+	return &DeleteTaxonomyTagOutput{Slug: slug}, nil
+}
+
+// Job runner for models.JobTypeTagsMigration, the tags record is rebuilt once the addresses are migrated
+func (s *AddressService) RunMigrateTagsJob(
+	ctx context.Context,
+	params map[string]string,
+	progress repository.ProgressFunc) error {
+	language := models.Language(params[models.JobParamLanguage])
+	if err := language.Validate(); err != nil {
+		return err
+	}
+	seed, _ := strconv.ParseBool(params[models.JobParamSeedTags])
+	opts := repository.MigrateAddressTagsOption{
+		Language: language,
+		Seed:     seed,
+		Actor:    params[models.JobParamActor],
+		Progress: progress,
+	}
+	if _, err := s.repo.MigrateAddressTags(ctx, opts); err != nil {
+		return err
+	}
+	_, err := s.repo.RefreshTags(ctx, repository.RefreshTagsOption{Language: language})
+	return err
+}
+
+// ---------- Helper methods ----------
+
+func (s *AddressService) getTagTaxonomy(ctx context.Context) (*models.TagTaxonomy, error) {
+	tags, err := s.repo.ListTaxonomyTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewTagTaxonomy(tags), nil
+}
+
+// Tags of an address written by an admin, replaced by the display names of their taxonomy tags.
+// Any tag is accepted while the taxonomy is empty, so the catalog keeps working until it is seeded
+func (s *AddressService) canonicalizeTags(
+	ctx context.Context, language models.Language, tags []string) ([]string, error) {
+	taxonomy, err := s.getTagTaxonomy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalizeTags(taxonomy, language, tags)
+}
+
+func canonicalizeTags(taxonomy *models.TagTaxonomy, language models.Language, tags []string) ([]string, error) {
+	if taxonomy.IsEmpty() {
+		return tags, nil
+	}
+	canonical, unknown := taxonomy.Canonicalize(language, tags)
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTags, strings.Join(unknown, ", "))
+	}
+	return canonical, nil
+}
+
+func defaultTagSlug(tag models.Tag) string {
+	if name := tag.Name(models.LanguageEN); name != "" {
+		return models.NewTagSlug(name)
+	}
+	languages := make([]string, 0, len(tag.Names))
+	for language := range tag.Names {
+		languages = append(languages, language)
+	}
+	slices.Sort(languages)
+	if len(languages) == 0 {
+		return ""
+	}
+	return models.NewTagSlug(tag.Names[languages[0]])
+}
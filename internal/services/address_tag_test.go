@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var taxonomyTags = []models.Tag{
+	{Slug: "uk", Category: models.TagCategoryCountry, Names: map[string]string{"en": "UK", "zh": "英国"},
+		Synonyms: map[string][]string{"en": {"United Kingdom", "Britain"}}},
+	{Slug: "writer", Category: models.TagCategoryRole, Names: map[string]string{"en": "Writer", "zh": "作家"},
+		Synonyms: map[string][]string{"en": {"Author"}}},
+	{Slug: "novelist", Category: models.TagCategoryFigure, Names: map[string]string{"en": "Novelist"}},
+}
+
+func TestAddressService_CreateNewAddress_CanonicalTags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		language      models.Language
+		tags          []string
+		taxonomy      []models.Tag
+		expectedTags  []string
+		expectedError error
+	}{
+		{
+			name:         "names, synonyms and slugs resolve to display names",
+			language:     models.LanguageEN,
+			tags:         []string{" united   KINGDOM", "author", "novelist", "Writer"},
+			taxonomy:     taxonomyTags,
+			expectedTags: []string{"UK", "Writer", "Novelist"},
+		},
+		{
+			name:         "names of the address language",
+			language:     models.LanguageZH,
+			tags:         []string{"英国", "作家"},
+			taxonomy:     taxonomyTags,
+			expectedTags: []string{"英国", "作家"},
+		},
+		{
+			name:          "tag without a name in the language",
+			language:      models.LanguageZH,
+			tags:          []string{"英国", "Novelist"},
+			taxonomy:      taxonomyTags,
+			expectedError: ErrUnknownTags,
+		},
+		{
+			name:          "unknown tag",
+			language:      models.LanguageEN,
+			tags:          []string{"UK", "poet"},
+			taxonomy:      taxonomyTags,
+			expectedError: ErrUnknownTags,
+		},
+		{
+			name:         "any tag before the taxonomy is seeded",
+			language:     models.LanguageEN,
+			tags:         []string{"UK", "poet"},
+			taxonomy:     []models.Tag{},
+			expectedTags: []string{"UK", "poet"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupAddressService()
+			repo.On("ListTaxonomyTags", mock.Anything).Return(tt.taxonomy, nil).Once()
+			if tt.expectedError == nil {
+				repo.On("CreateNewAddress", mock.Anything, mock.MatchedBy(func(opts repository.CreateNewAddressOption) bool {
+					return assert.ObjectsAreEqual(tt.expectedTags, opts.AddressItem.Tags)
+				})).Return("a1", nil).Once()
+			}
+			output, err := service.CreateNewAddress(context.Background(), CreateNewAddressInput{
+				Language: tt.language,
+				Address:  models.AddressItem{Name: "Jane Austen", Tags: tt.tags},
+			})
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, output)
+				repo.AssertNotCalled(t, "CreateNewAddress", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "a1", output.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddressService_ImportAddresses_UnknownTags(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return(taxonomyTags, nil).Once()
+	data := strings.Join([]string{
+		`{"name":"Jane Austen","briefIntro":"Novelist","tags":["Britain","author"],"address":{"city":"Bath","country":"UK","line1":"4 Sydney Place","region":"Somerset"}}`,
+		`{"name":"John Keats","briefIntro":"Poet","tags":["UK","poet"],"address":{"city":"London","country":"UK","line1":"Keats Grove","region":"London"}}`,
+	}, "\n")
+	repo.On("ImportAddresses", mock.Anything, mock.MatchedBy(func(opts repository.ImportAddressesOption) bool {
+		return len(opts.Items) == 1 &&
+			assert.ObjectsAreEqual([]string{"UK", "Writer"}, opts.Items[0].AddressItem.Tags)
+	})).Return(&repository.ImportAddressesResult{Rows: []models.AddressImportRow{
+		{Row: 1, Status: models.AddressImportStatusReady, Name: "Jane Austen"},
+	}}, nil).Once()
+	output, err := service.ImportAddresses(context.Background(), ImportAddressesInput{
+		Language: "en",
+		Format:   models.AddressImportFormatJSONL,
+		Data:     strings.NewReader(data),
+		DryRun:   true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, output.Rows, 2)
+	assert.Equal(t, models.AddressImportStatusInvalid, output.Rows[1].Status)
+	assert.Contains(t, output.Rows[1].Error, "poet")
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ApprovePendingAddress_UnknownTags(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	pending := reviewedAddress
+	pending.Tags = []string{"UK", "poet"}
+	repo.On("GetPendingAddress", mock.Anything, repository.GetPendingAddressOption{ID: "p1"}).
+		Return(&models.PendingAddress{ID: "p1", Language: "en", Address: pending}, nil).Once()
+	repo.On("ListTaxonomyTags", mock.Anything).Return(taxonomyTags, nil).Once()
+	output, err := service.ApprovePendingAddress(context.Background(), ApprovePendingAddressInput{ID: "p1"})
+	assert.ErrorIs(t, err, ErrInvalidPendingAddress)
+	assert.Contains(t, err.Error(), "poet")
+	assert.Nil(t, output)
+	repo.AssertNotCalled(t, "ApprovePendingAddress", mock.Anything, mock.Anything)
+}
+
+func TestAddressService_CreateTaxonomyTag(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		tag           models.Tag
+		expectedTag   *models.Tag
+		expectedError error
+	}{
+		{
+			name: "slug from the English name",
+			tag: models.Tag{
+				Category: " Role ",
+				Names:    map[string]string{"EN": " Nobel   Laureate ", "zh": "诺贝尔奖得主", "fr": ""},
+				Synonyms: map[string][]string{"en": {"nobel laureate", "Nobelist", "nobelist", " "}},
+			},
+			expectedTag: &models.Tag{
+				Slug:     "nobel-laureate",
+				Category: models.TagCategoryRole,
+				Names:    map[string]string{"en": "Nobel Laureate", "zh": "诺贝尔奖得主"},
+				Synonyms: map[string][]string{"en": {"Nobelist"}},
+			},
+		},
+		{
+			name: "slug from the first name",
+			tag:  models.Tag{Category: models.TagCategoryCountry, Names: map[string]string{"zh": "英国"}},
+			expectedTag: &models.Tag{
+				Slug:     "英国",
+				Category: models.TagCategoryCountry,
+				Names:    map[string]string{"zh": "英国"},
+				Synonyms: map[string][]string{},
+			},
+		},
+		{
+			name:          "unknown category",
+			tag:           models.Tag{Slug: "paris", Category: "city", Names: map[string]string{"en": "Paris"}},
+			expectedError: ErrInvalidTag,
+		},
+		{
+			name:          "unknown language",
+			tag:           models.Tag{Slug: "uk", Category: models.TagCategoryCountry, Names: map[string]string{"xx": "UK"}},
+			expectedError: ErrInvalidTag,
+		},
+		{
+			name:          "invalid slug",
+			tag:           models.Tag{Slug: "uk/gb", Category: models.TagCategoryCountry, Names: map[string]string{"en": "UK"}},
+			expectedError: ErrInvalidTag,
+		},
+		{
+			name:          "no name",
+			tag:           models.Tag{Slug: "uk", Category: models.TagCategoryCountry},
+			expectedError: ErrInvalidTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupAddressService()
+			if tt.expectedTag != nil {
+				repo.On("CreateTaxonomyTag", mock.Anything, repository.CreateTaxonomyTagOption{Tag: *tt.expectedTag}).
+					Return(tt.expectedTag, nil).Once()
+			}
+			output, err := service.CreateTaxonomyTag(context.Background(), CreateTaxonomyTagInput{Tag: tt.tag})
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, output)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, *tt.expectedTag, output.Tag)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddressService_ListTaxonomyTags(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return(append([]models.Tag{}, taxonomyTags...), nil).Once()
+	output, err := service.ListTaxonomyTags(context.Background(), ListTaxonomyTagsInput{Category: models.TagCategoryRole})
+	assert.NoError(t, err)
+	assert.Len(t, output.Tags, 1)
+	assert.Equal(t, "writer", output.Tags[0].Slug)
+
+	_, err = service.ListTaxonomyTags(context.Background(), ListTaxonomyTagsInput{Category: "city"})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_RunMigrateTagsJob(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("MigrateAddressTags", mock.Anything, mock.MatchedBy(func(opts repository.MigrateAddressTagsOption) bool {
+		return opts.Language == "zh" && opts.Seed && opts.Actor == "admin-uid" && opts.Progress != nil
+	})).Return(&repository.MigrateAddressTagsResult{Processed: 2, Updated: 1}, nil).Once()
+	repo.On("RefreshTags", mock.Anything, repository.RefreshTagsOption{Language: "zh"}).
+		Return(&models.TagsRecord{}, nil).Once()
+	err := service.RunMigrateTagsJob(context.Background(), map[string]string{
+		models.JobParamLanguage: "zh",
+		models.JobParamSeedTags: "true",
+		models.JobParamActor:    "admin-uid",
+	}, func(processed int, failed int) {})
+	assert.NoError(t, err)
+
+	err = service.RunMigrateTagsJob(context.Background(), map[string]string{models.JobParamLanguage: "xx"}, nil)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"north-post/service/internal/domain/v1/models"
@@ -62,6 +63,228 @@ func (m *mockAddressRepository) DeleteAddress(
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockAddressRepository) RestoreAddress(
+	ctx context.Context,
+	opts repository.RestoreAddressOption,
+) (*models.AddressItem, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AddressItem), args.Error(1)
+}
+
+func (m *mockAddressRepository) ListDeletedAddresses(
+	ctx context.Context,
+	opts repository.ListDeletedAddressesOption,
+) ([]models.AddressItem, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AddressItem), args.Error(1)
+}
+
+func (m *mockAddressRepository) ListAddressRevisions(
+	ctx context.Context,
+	opts repository.ListAddressRevisionsOption,
+) ([]models.AddressRevision, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AddressRevision), args.Error(1)
+}
+
+func (m *mockAddressRepository) GetAddressRevision(
+	ctx context.Context,
+	opts repository.GetAddressRevisionOption,
+) (*models.AddressRevision, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AddressRevision), args.Error(1)
+}
+
+func (m *mockAddressRepository) RollbackAddress(
+	ctx context.Context,
+	opts repository.RollbackAddressOption,
+) (*models.AddressItem, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AddressItem), args.Error(1)
+}
+
+func (m *mockAddressRepository) ImportAddresses(
+	ctx context.Context,
+	opts repository.ImportAddressesOption,
+) (*repository.ImportAddressesResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ImportAddressesResult), args.Error(1)
+}
+
+func (m *mockAddressRepository) CreatePendingAddresses(
+	ctx context.Context,
+	opts repository.CreatePendingAddressesOption,
+) ([]models.PendingAddress, error) {
+	args := m.Called(ctx, opts)
+	pending, _ := args.Get(0).([]models.PendingAddress)
+	return pending, args.Error(1)
+}
+
+func (m *mockAddressRepository) ListPendingAddresses(
+	ctx context.Context,
+	opts repository.ListPendingAddressesOption,
+) ([]models.PendingAddress, error) {
+	args := m.Called(ctx, opts)
+	pending, _ := args.Get(0).([]models.PendingAddress)
+	return pending, args.Error(1)
+}
+
+func (m *mockAddressRepository) GetPendingAddress(
+	ctx context.Context,
+	opts repository.GetPendingAddressOption,
+) (*models.PendingAddress, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PendingAddress), args.Error(1)
+}
+
+func (m *mockAddressRepository) UpdatePendingAddress(
+	ctx context.Context,
+	opts repository.UpdatePendingAddressOption,
+) (*models.PendingAddress, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PendingAddress), args.Error(1)
+}
+
+func (m *mockAddressRepository) ApprovePendingAddress(
+	ctx context.Context,
+	opts repository.ApprovePendingAddressOption,
+) (*models.AddressItem, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AddressItem), args.Error(1)
+}
+
+func (m *mockAddressRepository) RejectPendingAddress(
+	ctx context.Context,
+	opts repository.RejectPendingAddressOption,
+) error {
+	args := m.Called(ctx, opts)
+	return args.Error(0)
+}
+
+func (m *mockAddressRepository) ListDuplicateClusters(
+	ctx context.Context,
+	opts repository.ListDuplicateClustersOption,
+) ([]models.DuplicateCluster, error) {
+	args := m.Called(ctx, opts)
+	clusters, _ := args.Get(0).([]models.DuplicateCluster)
+	return clusters, args.Error(1)
+}
+
+func (m *mockAddressRepository) MergeAddresses(
+	ctx context.Context,
+	opts repository.MergeAddressesOption,
+) (*repository.MergeAddressesResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.MergeAddressesResult), args.Error(1)
+}
+
+func (m *mockAddressRepository) BackfillDuplicateKeys(
+	ctx context.Context,
+	opts repository.BackfillDuplicateKeysOption,
+) (*repository.BackfillDuplicateKeysResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.BackfillDuplicateKeysResult), args.Error(1)
+}
+
+func (m *mockAddressRepository) GetAddressesByIDs(
+	ctx context.Context,
+	opts *repository.GetAddressesByIDsOptions,
+) (*repository.GetAddressesByIDsResponse, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.GetAddressesByIDsResponse), args.Error(1)
+}
+
+func (m *mockAddressRepository) MapAddressTranslations(
+	ctx context.Context,
+	opts *repository.MapAddressTranslationsOption,
+) (*repository.MapAddressTranslationsResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.MapAddressTranslationsResult), args.Error(1)
+}
+
+func (m *mockAddressRepository) LinkAddressTranslations(
+	ctx context.Context,
+	opts repository.LinkAddressTranslationsOption,
+) (*models.TranslationGroup, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranslationGroup), args.Error(1)
+}
+
+func (m *mockAddressRepository) UnlinkAddressTranslation(
+	ctx context.Context,
+	opts repository.UnlinkAddressTranslationOption,
+) error {
+	args := m.Called(ctx, opts)
+	return args.Error(0)
+}
+
+func (m *mockAddressRepository) ListMissingTranslations(
+	ctx context.Context,
+	opts repository.ListMissingTranslationsOption,
+) ([]models.MissingTranslation, error) {
+	args := m.Called(ctx, opts)
+	missing, _ := args.Get(0).([]models.MissingTranslation)
+	return missing, args.Error(1)
+}
+
+// Yields the addresses given to Return, then returns the error
+func (m *mockAddressRepository) StreamAddresses(
+	ctx context.Context,
+	opts repository.StreamAddressesOption,
+	yield func(models.AddressItem) error,
+) error {
+	args := m.Called(ctx, opts)
+	addresses, _ := args.Get(0).([]models.AddressItem)
+	for _, address := range addresses {
+		if err := yield(address); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *mockAddressRepository) RefreshTags(
 	ctx context.Context,
 	opts repository.RefreshTagsOption,
@@ -86,6 +309,59 @@ func (m *mockAddressRepository) GetAllTags(
 	return record, args.Error(1)
 }
 
+func (m *mockAddressRepository) ListTaxonomyTags(ctx context.Context) ([]models.Tag, error) {
+	args := m.Called(ctx)
+	var tags []models.Tag
+	if value := args.Get(0); value != nil {
+		tags, _ = value.([]models.Tag)
+	}
+	return tags, args.Error(1)
+}
+
+func (m *mockAddressRepository) CreateTaxonomyTag(
+	ctx context.Context,
+	opts repository.CreateTaxonomyTagOption,
+) (*models.Tag, error) {
+	args := m.Called(ctx, opts)
+	var tag *models.Tag
+	if value := args.Get(0); value != nil {
+		tag, _ = value.(*models.Tag)
+	}
+	return tag, args.Error(1)
+}
+
+func (m *mockAddressRepository) UpdateTaxonomyTag(
+	ctx context.Context,
+	opts repository.UpdateTaxonomyTagOption,
+) (*models.Tag, error) {
+	args := m.Called(ctx, opts)
+	var tag *models.Tag
+	if value := args.Get(0); value != nil {
+		tag, _ = value.(*models.Tag)
+	}
+	return tag, args.Error(1)
+}
+
+func (m *mockAddressRepository) DeleteTaxonomyTag(
+	ctx context.Context,
+	opts repository.DeleteTaxonomyTagOption,
+) error {
+	args := m.Called(ctx, opts)
+	return args.Error(0)
+}
+
+func (m *mockAddressRepository) MigrateAddressTags(
+	ctx context.Context,
+	opts repository.MigrateAddressTagsOption,
+) (*repository.MigrateAddressTagsResult, error) {
+	args := m.Called(ctx, opts)
+	var result *repository.MigrateAddressTagsResult
+	if value := args.Get(0); value != nil {
+		result, _ = value.(*repository.MigrateAddressTagsResult)
+	}
+	return result, args.Error(1)
+}
+
 func (m *mockAddressRepository) SyncToTypesense(
 	ctx context.Context,
 	opts repository.SyncToTypesenseOption,
@@ -98,6 +374,11 @@ func (m *mockAddressRepository) SyncToTypesense(
 	return result, args.Error(1)
 }
 
+func (m *mockAddressRepository) IsSearchSchemaCurrent(ctx context.Context, language models.Language) (bool, error) {
+	args := m.Called(ctx, language)
+	return args.Bool(0), args.Error(1)
+}
+
 type mockLLMClient struct {
 	mock.Mock
 }
@@ -109,8 +390,11 @@ func (m *mockLLMClient) StructuredCompletion(
 	result interface{}) error {
 	args := m.Called(ctx, opts, schemaInstance, result)
 	if args.Get(0) != nil && result != nil {
-		if out, ok := result.(*models.BatchAddressGenerationSchema); ok {
+		switch out := result.(type) {
+		case *models.BatchAddressGenerationSchema:
 			*out = args.Get(0).(models.BatchAddressGenerationSchema)
+		case *models.BatchAddressTranslationSchema:
+			*out = args.Get(0).(models.BatchAddressTranslationSchema)
 		}
 	}
 	return args.Error(1)
@@ -146,7 +430,10 @@ func TestAddressService_GetAddresses(t *testing.T) {
 		{ID: "2", Name: "Address Two"},
 	}
 	expectedResponse := &repository.GetAddressesResponse{
-		Addresses:  expectedAddresses,
+		Addresses: expectedAddresses,
+		Facets: []models.TagFacet{
+			{Category: models.TagCategoryCountry, Counts: []models.TagFacetCount{{Value: "UK", Count: 2}}},
+		},
 		TotalCount: 2,
 		Page:       1,
 		TotalPages: 1,
@@ -174,6 +461,7 @@ func TestAddressService_GetAddresses(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, output)
 	assert.Equal(t, expectedResponse.Addresses, output.Addresses)
+	assert.Equal(t, expectedResponse.Facets, output.Facets)
 	assert.Equal(t, expectedResponse.TotalCount, output.TotalCount)
 }
 
@@ -198,10 +486,70 @@ func TestAddressService_GetAddresses_Error(t *testing.T) {
 	assert.Nil(t, output)
 }
 
+func TestAddressService_GetAddresses_RejectedQuery(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("GetAddresses", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("failed to search addresses: %w", infra.ErrInvalidSearchQuery)).Once()
+	output, err := service.GetAddresses(context.Background(), GetAddressesInput{
+		Language: models.LanguageEN,
+		Sort:     models.AddressSortName,
+		PageSize: 20,
+		Page:     1,
+	})
+	assert.ErrorIs(t, err, ErrInvalidSearch)
+	assert.Nil(t, output)
+}
+
+func TestAddressService_ListOutdatedSearchIndexes(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("IsSearchSchemaCurrent", mock.Anything, models.LanguageEN).Return(true, nil).Once()
+	repo.On("IsSearchSchemaCurrent", mock.Anything, models.LanguageZH).Return(false, nil).Once()
+	outdated, err := service.ListOutdatedSearchIndexes(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Language{models.LanguageZH}, outdated)
+	repo.AssertExpectations(t)
+
+	service, repo, _ = setupAddressService()
+	repo.On("IsSearchSchemaCurrent", mock.Anything, models.LanguageEN).Return(false, assert.AnError).Once()
+	_, err = service.ListOutdatedSearchIndexes(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestAddressService_GetAddresses_InvalidSearch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input GetAddressesInput
+	}{
+		{
+			name:  "unknown sort",
+			input: GetAddressesInput{Language: models.LanguageEN, Sort: "popular"},
+		},
+		{
+			name: "unknown tag category",
+			input: GetAddressesInput{
+				Language:   models.LanguageEN,
+				TagFilters: map[models.TagCategory][]string{"city": {"London"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupAddressService()
+			output, err := service.GetAddresses(context.Background(), tt.input)
+			assert.ErrorIs(t, err, ErrInvalidSearch)
+			assert.Nil(t, output)
+			repo.AssertNotCalled(t, "GetAddresses", mock.Anything, mock.Anything)
+		})
+	}
+}
 func TestAddressService_CreateNewAddress(t *testing.T) {
 	t.Parallel()
 	repo := new(mockAddressRepository)
 	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
 	expectedID := "expected_id"
 	input := CreateNewAddressInput{
 		Language: models.LanguageZH,
@@ -222,6 +570,7 @@ func TestAddressService_CreateNewAddress_Error(t *testing.T) {
 	t.Parallel()
 	repo := new(mockAddressRepository)
 	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
 	input := CreateNewAddressInput{
 		Language: models.LanguageZH,
 		Address:  models.AddressItem{Name: "test", ID: "test"},
@@ -261,6 +610,8 @@ func TestAddressService_GenerateNewAddress(t *testing.T) {
 		Prompt:          "generate an address",
 		Model:           "gpt-5-mini",
 		ReasoningEffort: "minimum",
+		Language:        "en",
+		Actor:           "admin-uid",
 	}
 	expectedBatch := models.BatchAddressGenerationSchema{
 		Addresses: []models.AddressGenerationSchema{
@@ -274,12 +625,29 @@ func TestAddressService_GenerateNewAddress(t *testing.T) {
 		mock.Anything,
 		mock.Anything,
 	).Return(expectedBatch, nil).Once()
+	// the generated addresses are staged with the settings of the run
+	repo.On("CreatePendingAddresses", mock.Anything, mock.MatchedBy(func(opts repository.CreatePendingAddressesOption) bool {
+		generation := opts.Generation
+		return opts.Language == "en" &&
+			len(opts.Addresses) == 1 &&
+			opts.Addresses[0].Name == "test" &&
+			opts.Addresses[0].ID == "" &&
+			generation.RunID != "" &&
+			generation.Model == "gpt-5-mini" &&
+			generation.Prompt == "generate an address" &&
+			generation.SystemPrompt == "sys" &&
+			generation.GeneratedBy == "admin-uid"
+	})).Return([]models.PendingAddress{
+		{ID: "p1", Language: "en", Address: models.AddressItem{Name: "test"}},
+	}, nil).Once()
 	output, err := service.GenerateNewAddress(context.Background(), input)
 	llm.AssertExpectations(t)
+	repo.AssertExpectations(t)
 	assert.NoError(t, err)
 	assert.NotNil(t, output)
-	assert.Equal(t, 1, len(output.Addresses))
-	assert.Equal(t, "test", output.Addresses[0].Name)
+	assert.Equal(t, 1, len(output.Pending))
+	assert.Equal(t, "p1", output.Pending[0].ID)
+	assert.Equal(t, "test", output.Pending[0].Address.Name)
 }
 
 func TestAddressService_GenerateNewAddress_Error(t *testing.T) {
@@ -319,12 +687,41 @@ func TestAddressService_UpdateAddress(t *testing.T) {
 		ID:       "123",
 		Address:  models.AddressItem{ID: "123", Name: "Test", BriefIntro: "Brief introduction"},
 	}
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
 	repo.On("UpdateAddress", mock.Anything, mock.Anything).Return(&addressItem, nil).Once()
 	output, err := service.UpdateAddress(context.Background(), input)
 	assert.Nil(t, err)
 	assert.NotNil(t, output)
 }
 
+func TestAddressService_UpdateAddress_Conflict(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
+	input := UpdateAddressInput{
+		Language:          "en",
+		ID:                "123",
+		Address:           models.AddressItem{Name: "Mine"},
+		Actor:             "admin-uid",
+		ExpectedUpdatedAt: 100,
+	}
+	conflict := &repository.AddressConflictError{Current: models.AddressItem{ID: "123", Name: "Theirs", UpdatedAt: 200}}
+	repo.On("UpdateAddress", mock.Anything, repository.UpdateAddressOption{
+		Language:          "en",
+		ID:                "123",
+		AddressItem:       models.AddressItem{Name: "Mine"},
+		Actor:             "admin-uid",
+		ExpectedUpdatedAt: 100,
+	}).Return(nil, fmt.Errorf("failed to update address with ID 123: %w", conflict)).Once()
+	output, err := service.UpdateAddress(context.Background(), input)
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	var target *repository.AddressConflictError
+	assert.ErrorAs(t, err, &target)
+	assert.Equal(t, "Theirs", target.Current.Name)
+	repo.AssertExpectations(t)
+}
+
 func TestAddressService_UpdateAddress_Error(t *testing.T) {
 	t.Parallel()
 	repo := new(mockAddressRepository)
@@ -335,6 +732,7 @@ func TestAddressService_UpdateAddress_Error(t *testing.T) {
 		ID:       "123",
 		Address:  models.AddressItem{ID: "123", Name: "Test", BriefIntro: "Brief introduction"},
 	}
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
 	repo.On("UpdateAddress", mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
 	output, err := service.UpdateAddress(context.Background(), input)
 	assert.Nil(t, output)
@@ -365,6 +763,132 @@ func TestAddressService_DeleteAddress_Error(t *testing.T) {
 	assert.Nil(t, output)
 }
 
+func TestAddressService_RestoreAddress(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	opts := repository.RestoreAddressOption{Language: "en", ID: "1"}
+	repo.On("RestoreAddress", mock.Anything, opts).Return(&models.AddressItem{ID: "1", Name: "restored"}, nil).Once()
+	output, err := service.RestoreAddress(context.Background(), RestoreAddressInput{Language: "en", ID: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "restored", output.Address.Name)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_RestoreAddress_Error(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("RestoreAddress", mock.Anything, mock.Anything).Return(nil, repository.ErrNotInTrash).Once()
+	output, err := service.RestoreAddress(context.Background(), RestoreAddressInput{Language: "en", ID: "1"})
+	assert.ErrorIs(t, err, repository.ErrNotInTrash)
+	assert.Nil(t, output)
+}
+
+func TestAddressService_ListDeletedAddresses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		limit         int
+		expectedLimit int
+	}{
+		{name: "default limit", limit: 0, expectedLimit: defaultListDeletedAddresses},
+		{name: "custom limit", limit: 10, expectedLimit: 10},
+		{name: "capped limit", limit: 1000, expectedLimit: maxListDeletedAddresses},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupAddressService()
+			opts := repository.ListDeletedAddressesOption{Language: "en", Limit: tt.expectedLimit}
+			deleted := []models.AddressItem{{ID: "1", DeletedAt: 100}}
+			repo.On("ListDeletedAddresses", mock.Anything, opts).Return(deleted, nil).Once()
+			output, err := service.ListDeletedAddresses(context.Background(), ListDeletedAddressesInput{
+				Language: "en",
+				Limit:    tt.limit,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, deleted, output.Addresses)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddressService_ListAddressRevisions(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	opts := repository.ListAddressRevisionsOption{Language: "en", ID: "1", Limit: defaultListRevisions}
+	revisions := []models.AddressRevision{{Number: 2}, {Number: 1}}
+	repo.On("ListAddressRevisions", mock.Anything, opts).Return(revisions, nil).Once()
+	output, err := service.ListAddressRevisions(context.Background(), ListAddressRevisionsInput{Language: "en", ID: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, revisions, output.Revisions)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_DiffAddressRevisions(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	from := models.AddressItem{
+		Name:    "Old name",
+		Tags:    []string{"uk", "writer"},
+		Address: models.Address{City: "London", Country: "UK", Line1: "1 Street"},
+		// bookkeeping fields are not part of the diff
+		UpdatedAt: 1,
+		Revision:  1,
+	}
+	to := from
+	to.Name = "New name"
+	to.Tags = []string{"uk", "poet"}
+	to.Address.Line2 = "Flat 2"
+	to.UpdatedAt = 2
+	to.Revision = 3
+	repo.On("GetAddressRevision", mock.Anything, repository.GetAddressRevisionOption{Language: "en", ID: "1", Revision: 1}).
+		Return(&models.AddressRevision{Number: 1, Snapshot: from}, nil).Once()
+	repo.On("GetAddressRevision", mock.Anything, repository.GetAddressRevisionOption{Language: "en", ID: "1", Revision: 3}).
+		Return(&models.AddressRevision{Number: 3, Snapshot: to}, nil).Once()
+	output, err := service.DiffAddressRevisions(context.Background(), DiffAddressRevisionsInput{
+		Language: "en",
+		ID:       "1",
+		From:     1,
+		To:       3,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{
+		{Field: "name", Before: "Old name", After: "New name"},
+		{Field: "tags", Before: []string{"uk", "writer"}, After: []string{"uk", "poet"}},
+		{Field: "address.line2", Before: "", After: "Flat 2"},
+	}, output.Changes)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_DiffAddressRevisions_NotFound(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("GetAddressRevision", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound).Once()
+	output, err := service.DiffAddressRevisions(context.Background(), DiffAddressRevisionsInput{
+		Language: "en",
+		ID:       "1",
+		From:     1,
+		To:       2,
+	})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Nil(t, output)
+}
+
+func TestAddressService_RollbackAddress(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	opts := repository.RollbackAddressOption{Language: "en", ID: "1", Revision: 2, Actor: "admin-uid"}
+	repo.On("RollbackAddress", mock.Anything, opts).Return(&models.AddressItem{ID: "1", Revision: 5}, nil).Once()
+	output, err := service.RollbackAddress(context.Background(), RollbackAddressInput{
+		Language: "en",
+		ID:       "1",
+		Revision: 2,
+		Actor:    "admin-uid",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), output.Address.Revision)
+	repo.AssertExpectations(t)
+}
+
 func TestAddressService_RefreshTags(t *testing.T) {
 	t.Parallel()
 	repo := new(mockAddressRepository)
@@ -454,3 +978,44 @@ func TestAddressService_SyncToTypesense_Error(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, output)
 }
+
+func TestAddressService_RunSyncToTypesenseJob(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		params   map[string]string
+		expected repository.SyncToTypesenseOption
+	}{
+		{
+			name:     "incremental by default",
+			params:   map[string]string{models.JobParamLanguage: "en"},
+			expected: repository.SyncToTypesenseOption{Language: "en"},
+		},
+		{
+			name:     "full sync",
+			params:   map[string]string{models.JobParamLanguage: "zh", models.JobParamFullSync: "true"},
+			expected: repository.SyncToTypesenseOption{Language: "zh", Full: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockAddressRepository)
+			service := NewAddressService(repo, new(mockLLMClient))
+			repo.On("SyncToTypesense", mock.Anything, mock.MatchedBy(func(opts repository.SyncToTypesenseOption) bool {
+				return opts.Language == tt.expected.Language && opts.Full == tt.expected.Full && opts.Progress != nil
+			})).Return(&repository.SyncToTypesenseResult{}, nil).Once()
+			err := service.RunSyncToTypesenseJob(context.Background(), tt.params, func(int, int) {})
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddressService_RunSyncToTypesenseJob_InvalidLanguage(t *testing.T) {
+	t.Parallel()
+	repo := new(mockAddressRepository)
+	service := NewAddressService(repo, new(mockLLMClient))
+	err := service.RunSyncToTypesenseJob(context.Background(), map[string]string{}, func(int, int) {})
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "SyncToTypesense", mock.Anything, mock.Anything)
+}
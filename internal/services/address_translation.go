@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/infra"
+	"north-post/service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultListMissingTranslations = 100
+	maxListMissingTranslations     = 500
+	// addresses translated by one LLM request
+	maxTranslateAddresses = 20
+)
+
+var (
+	ErrInvalidTranslationLink = errors.New("invalid translation link")
+	ErrInvalidTranslation     = errors.New("invalid translation request")
+)
+
+const translateSystemPrompt = `You translate entries of a catalog of postal addresses from %s to %s.
+For every entry return its sourceId unchanged with the translated name, briefIntro and tags.
+Use the established %s name of the place when there is one instead of a literal translation.
+Keep the length and tone of the briefIntro.
+%s`
+
+const translateTagsPrompt = `Tags have to be taken from this vocabulary of the target language, pick the tag with the same meaning
+for every source tag and leave out the tags that have no equivalent:
+%s`
+
+type LinkAddressTranslationsInput struct {
+	Entries []models.AddressRef
+}
+
+type LinkAddressTranslationsOutput struct {
+	Group models.TranslationGroup
+}
+
+type UnlinkAddressTranslationInput struct {
+	Language models.Language
+	ID       string
+}
+
+type UnlinkAddressTranslationOutput struct {
+	ID string
+}
+
+type ListMissingTranslationsInput struct {
+	Language models.Language
+	Limit    int
+}
+
+type ListMissingTranslationsOutput struct {
+	Total   int // all addresses with missing translations, Missing is cut at the limit
+	Missing []models.MissingTranslation
+}
+
+type TranslateAddressesInput struct {
+	From            models.Language
+	To              models.Language
+	IDs             []string
+	Model           string
+	ReasoningEffort string
+	ThinkingLevel   string
+	Actor           string
+}
+
+type TranslateAddressesOutput struct {
+	Pending      []models.PendingAddress
+	Untranslated []string // source addresses the model returned no translation for
+	Translated   []string // source addresses that are linked to an entry in the target language already
+}
+
+// Link entries of different languages that describe the same place, one entry per language
+func (s *AddressService) LinkAddressTranslations(
+	ctx context.Context,
+	input LinkAddressTranslationsInput) (*LinkAddressTranslationsOutput, error) {
+	if len(input.Entries) < 2 {
+		return nil, fmt.Errorf("%w: at least two entries are required", ErrInvalidTranslationLink)
+	}
+	languages := []models.Language{}
+	entries := make([]models.AddressRef, len(input.Entries))
+	for i, entry := range input.Entries {
+		if err := entry.Language.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTranslationLink, err)
+		}
+		if entry.ID == "" {
+			return nil, fmt.Errorf("%w: entry %d has no ID", ErrInvalidTranslationLink, i)
+		}
+		if slices.Contains(languages, entry.Language.Lower()) {
+			return nil, fmt.Errorf("%w: more than one %s entry", ErrInvalidTranslationLink, entry.Language.Get())
+		}
+		languages = append(languages, entry.Language.Lower())
+		entries[i] = models.AddressRef{Language: entry.Language.Lower(), ID: entry.ID}
+	}
+	group, err := s.repo.LinkAddressTranslations(ctx, repository.LinkAddressTranslationsOption{Entries: entries})
+	if err != nil {
+		return nil, err
+	}
+	return &LinkAddressTranslationsOutput{Group: *group}, nil
+}
+
+func (s *AddressService) UnlinkAddressTranslation(
+	ctx context.Context,
+	input UnlinkAddressTranslationInput) (*UnlinkAddressTranslationOutput, error) {
+	err := s.repo.UnlinkAddressTranslation(ctx, repository.UnlinkAddressTranslationOption{
+		Language: input.Language,
+		ID:       input.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UnlinkAddressTranslationOutput{ID: input.ID}, nil
+}
+
+func (s *AddressService) ListMissingTranslations(
+	ctx context.Context,
+	input ListMissingTranslationsInput) (*ListMissingTranslationsOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListMissingTranslations
+	}
+	missing, err := s.repo.ListMissingTranslations(ctx, repository.ListMissingTranslationsOption{
+		Language: input.Language,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := &ListMissingTranslationsOutput{Total: len(missing), Missing: missing}
+	if len(missing) > min(limit, maxListMissingTranslations) {
+		output.Missing = missing[:min(limit, maxListMissingTranslations)]
+	}
+	return output, nil
+}
+
+// Translate addresses into drafts of another language that wait in the review queue.
+// Names, intros and tags are translated, tags through the vocabulary of the target language,
+// the postal fields are copied so they stay in the local script. Addresses that are linked to
+// an entry in the target language already are reported instead of translated again
+func (s *AddressService) TranslateAddresses(
+	ctx context.Context,
+	input TranslateAddressesInput) (*TranslateAddressesOutput, error) {
+	ids := []string{}
+	for _, id := range input.IDs {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	switch {
+	case input.From.Lower() == input.To.Lower():
+		return nil, fmt.Errorf("%w: the source and target language are the same", ErrInvalidTranslation)
+	case len(ids) == 0:
+		return nil, fmt.Errorf("%w: at least one address ID is required", ErrInvalidTranslation)
+	case len(ids) > maxTranslateAddresses:
+		return nil, fmt.Errorf("%w: at most %d addresses can be translated at once", ErrInvalidTranslation, maxTranslateAddresses)
+	}
+	sources, err := s.repo.GetAddressesByIDs(ctx, &repository.GetAddressesByIDsOptions{Language: input.From, IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	if len(sources.Addresses) == 0 {
+		return nil, fmt.Errorf("addresses %s: %w", strings.Join(ids, ", "), repository.ErrNotFound)
+	}
+	output := &TranslateAddressesOutput{Pending: []models.PendingAddress{}, Untranslated: []string{}, Translated: []string{}}
+	existing, err := s.repo.MapAddressTranslations(ctx, &repository.MapAddressTranslationsOption{
+		From: input.From,
+		To:   input.To,
+		IDs:  ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	untranslated := []models.AddressItem{}
+	for _, source := range sources.Addresses {
+		if slices.Contains(existing.Untranslated, source.ID) {
+			untranslated = append(untranslated, source)
+		} else {
+			output.Translated = append(output.Translated, source.ID)
+		}
+	}
+	if len(untranslated) == 0 {
+		return output, nil
+	}
+	vocabulary := []string{}
+	record, err := s.repo.GetAllTags(ctx, repository.GetAllTagsOption{Language: input.To})
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if record != nil {
+		for _, tags := range record.Tags {
+			vocabulary = append(vocabulary, tags...)
+		}
+		slices.Sort(vocabulary)
+		vocabulary = slices.Compact(vocabulary)
+	}
+	systemPrompt, prompt, err := getTranslatePrompts(input.From, input.To, untranslated, vocabulary)
+	if err != nil {
+		return nil, err
+	}
+	opts := infra.StructuredCompletionOptions{
+		Prompt:          prompt,
+		SystemPrompt:    systemPrompt,
+		Model:           input.Model,
+		ReasoningEffort: input.ReasoningEffort,
+		ThinkingLevel:   input.ThinkingLevel,
+	}
+	var result models.BatchAddressTranslationSchema
+	if err := s.llm.StructuredCompletion(ctx, opts, models.BatchAddressTranslationSchema{}, &result); err != nil {
+		return nil, fmt.Errorf("failed to translate addresses: %w", err)
+	}
+	translations := map[string]models.AddressTranslationSchema{}
+	for _, translation := range result.Translations {
+		translations[translation.SourceID] = translation
+	}
+	addresses := []models.AddressItem{}
+	refs := []models.AddressRef{}
+	for _, source := range untranslated {
+		translation, ok := translations[source.ID]
+		if !ok || strings.TrimSpace(translation.Name) == "" {
+			output.Untranslated = append(output.Untranslated, source.ID)
+			continue
+		}
+		addresses = append(addresses, models.AddressItem{
+			Name:       strings.TrimSpace(translation.Name),
+			BriefIntro: strings.TrimSpace(translation.BriefIntro),
+			Tags:       filterVocabularyTags(translation.Tags, vocabulary),
+			Address:    source.Address,
+		})
+		refs = append(refs, models.AddressRef{Language: input.From.Lower(), ID: source.ID})
+	}
+	if len(addresses) == 0 {
+		return output, nil
+	}
+	pending, err := s.repo.CreatePendingAddresses(ctx, repository.CreatePendingAddressesOption{
+		Language:  input.To,
+		Addresses: addresses,
+		Sources:   refs,
+		Generation: models.GenerationMetadata{
+			RunID:           uuid.NewString(),
+			Model:           input.Model,
+			SystemPrompt:    systemPrompt,
+			Prompt:          prompt,
+			ReasoningEffort: input.ReasoningEffort,
+			ThinkingLevel:   input.ThinkingLevel,
+			GeneratedBy:     input.Actor,
+			GeneratedAt:     time.Now().UnixMilli(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	output.Pending = pending
+	return output, nil
+}
+
+// ---------- Helper methods ----------
+
+// The sources are sent as JSON, the postal fields are left out since they are not translated
+func getTranslatePrompts(
+	from models.Language,
+	to models.Language,
+	sources []models.AddressItem,
+	vocabulary []string) (string, string, error) {
+	tagsPrompt := "Translate the tags as short lower case keywords."
+	if len(vocabulary) > 0 {
+		tagsPrompt = fmt.Sprintf(translateTagsPrompt, strings.Join(vocabulary, ", "))
+	}
+	systemPrompt := fmt.Sprintf(translateSystemPrompt,
+		from.Name(), to.Name(), to.Name(), tagsPrompt)
+	entries := make([]models.AddressTranslationSchema, len(sources))
+	for i, source := range sources {
+		entries[i] = models.AddressTranslationSchema{
+			SourceID:   source.ID,
+			Name:       source.Name,
+			BriefIntro: source.BriefIntro,
+			Tags:       source.Tags,
+		}
+	}
+	prompt, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode the addresses to translate: %w", err)
+	}
+	return systemPrompt, string(prompt), nil
+}
+
+// Tags of the vocabulary without repeats, all of them when the language has no vocabulary yet
+func filterVocabularyTags(tags []string, vocabulary []string) []string {
+	filtered := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(filtered, tag) {
+			continue
+		}
+		if len(vocabulary) > 0 {
+			if _, found := slices.BinarySearch(vocabulary, tag); !found {
+				continue
+			}
+		}
+		filtered = append(filtered, tag)
+	}
+	return filtered
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/infra"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddressService_LinkAddressTranslations(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	group := &models.TranslationGroup{ID: "g1", Members: map[string]string{"en": "a1", "zh": "b1"}}
+	repo.On("LinkAddressTranslations", mock.Anything, repository.LinkAddressTranslationsOption{
+		Entries: []models.AddressRef{{Language: "en", ID: "a1"}, {Language: "zh", ID: "b1"}},
+	}).Return(group, nil).Once()
+	output, err := service.LinkAddressTranslations(context.Background(), LinkAddressTranslationsInput{
+		Entries: []models.AddressRef{{Language: "EN", ID: "a1"}, {Language: "zh", ID: "b1"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, *group, output.Group)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_LinkAddressTranslations_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		entries []models.AddressRef
+	}{
+		{name: "single entry", entries: []models.AddressRef{{Language: "en", ID: "a1"}}},
+		{name: "same language", entries: []models.AddressRef{{Language: "en", ID: "a1"}, {Language: "EN", ID: "a2"}}},
+		{name: "unsupported language", entries: []models.AddressRef{{Language: "en", ID: "a1"}, {Language: "fr", ID: "c1"}}},
+		{name: "missing ID", entries: []models.AddressRef{{Language: "en", ID: "a1"}, {Language: "zh"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := setupAddressService()
+			output, err := service.LinkAddressTranslations(context.Background(), LinkAddressTranslationsInput{
+				Entries: tt.entries,
+			})
+			assert.ErrorIs(t, err, ErrInvalidTranslationLink)
+			assert.Nil(t, output)
+			repo.AssertNotCalled(t, "LinkAddressTranslations", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAddressService_ListMissingTranslations(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	missing := []models.MissingTranslation{
+		{Address: models.AddressItem{ID: "a1"}, MissingLanguages: []models.Language{"zh"}},
+		{Address: models.AddressItem{ID: "a2"}, MissingLanguages: []models.Language{"zh"}},
+		{Address: models.AddressItem{ID: "a3"}, MissingLanguages: []models.Language{"zh"}},
+	}
+	repo.On("ListMissingTranslations", mock.Anything, repository.ListMissingTranslationsOption{Language: "en"}).
+		Return(missing, nil)
+	output, err := service.ListMissingTranslations(context.Background(), ListMissingTranslationsInput{
+		Language: "en",
+		Limit:    2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, output.Total)
+	assert.Equal(t, missing[:2], output.Missing)
+
+	output, err = service.ListMissingTranslations(context.Background(), ListMissingTranslationsInput{Language: "en"})
+	assert.NoError(t, err)
+	assert.Equal(t, missing, output.Missing)
+}
+
+func TestAddressService_TranslateAddresses(t *testing.T) {
+	t.Parallel()
+	service, repo, llm := setupAddressService()
+	sources := []models.AddressItem{
+		{
+			ID:         "a1",
+			Name:       "The Palace Museum",
+			BriefIntro: "Imperial palace of the Ming and Qing dynasties",
+			Tags:       []string{"China", "museum"},
+			Address:    models.Address{Line1: "景山前街4号", City: "北京", Country: "中国"},
+		},
+		{ID: "a2", Name: "Temple of Heaven", Tags: []string{"China"}},
+		{ID: "a3", Name: "Great Wall", Tags: []string{"China"}},
+	}
+	repo.On("GetAddressesByIDs", mock.Anything, &repository.GetAddressesByIDsOptions{
+		Language: "en",
+		IDs:      []string{"a1", "a2", "a3"},
+	}).Return(&repository.GetAddressesByIDsResponse{Addresses: sources}, nil).Once()
+	// the Great Wall has a Chinese entry already
+	repo.On("MapAddressTranslations", mock.Anything, &repository.MapAddressTranslationsOption{
+		From: "en",
+		To:   "zh",
+		IDs:  []string{"a1", "a2", "a3"},
+	}).Return(&repository.MapAddressTranslationsResult{
+		Addresses:    []models.AddressItem{{ID: "z3", Name: "长城"}},
+		Untranslated: []string{"a1", "a2"},
+	}, nil).Once()
+	repo.On("GetAllTags", mock.Anything, repository.GetAllTagsOption{Language: "zh"}).
+		Return(&models.TagsRecord{Tags: map[string][]string{"country": {"中国"}, "role": {"博物馆"}}}, nil).Once()
+	llm.On("StructuredCompletion", mock.Anything, mock.MatchedBy(func(opts infra.StructuredCompletionOptions) bool {
+		// the vocabulary is part of the instructions, the postal fields are not sent
+		return opts.Model == "gpt-5-mini" &&
+			strings.Contains(opts.SystemPrompt, "中国, 博物馆") &&
+			strings.Contains(opts.Prompt, "The Palace Museum") &&
+			!strings.Contains(opts.Prompt, "Great Wall") &&
+			!strings.Contains(opts.Prompt, "景山前街")
+	}), mock.Anything, mock.Anything).Return(models.BatchAddressTranslationSchema{
+		Translations: []models.AddressTranslationSchema{{
+			SourceID:   "a1",
+			Name:       " 故宫博物院 ",
+			BriefIntro: "明清两代的皇家宫殿",
+			Tags:       []string{"中国", "博物馆", "宫殿", "中国"},
+		}},
+	}, nil).Once()
+	repo.On("CreatePendingAddresses", mock.Anything, mock.MatchedBy(func(opts repository.CreatePendingAddressesOption) bool {
+		address := opts.Addresses[0]
+		return opts.Language == "zh" &&
+			len(opts.Addresses) == 1 &&
+			address.Name == "故宫博物院" &&
+			assert.ObjectsAreEqual([]string{"中国", "博物馆"}, address.Tags) &&
+			address.Address == sources[0].Address &&
+			assert.ObjectsAreEqual([]models.AddressRef{{Language: "en", ID: "a1"}}, opts.Sources) &&
+			opts.Generation.GeneratedBy == "admin-uid" &&
+			opts.Generation.RunID != ""
+	})).Return([]models.PendingAddress{{ID: "p1", Language: "zh"}}, nil).Once()
+	output, err := service.TranslateAddresses(context.Background(), TranslateAddressesInput{
+		From:  "en",
+		To:    "zh",
+		IDs:   []string{"a1", "a2", "a1", "a3"},
+		Model: "gpt-5-mini",
+		Actor: "admin-uid",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, output.Pending, 1)
+	assert.Equal(t, []string{"a2"}, output.Untranslated)
+	assert.Equal(t, []string{"a3"}, output.Translated)
+	repo.AssertExpectations(t)
+	llm.AssertExpectations(t)
+}
+
+func TestAddressService_TranslateAddresses_Invalid(t *testing.T) {
+	t.Parallel()
+	service, repo, llm := setupAddressService()
+	_, err := service.TranslateAddresses(context.Background(), TranslateAddressesInput{
+		From: "en", To: "EN", IDs: []string{"a1"},
+	})
+	assert.ErrorIs(t, err, ErrInvalidTranslation)
+	_, err = service.TranslateAddresses(context.Background(), TranslateAddressesInput{From: "en", To: "zh"})
+	assert.ErrorIs(t, err, ErrInvalidTranslation)
+	repo.On("GetAddressesByIDs", mock.Anything, mock.Anything).
+		Return(&repository.GetAddressesByIDsResponse{Addresses: []models.AddressItem{}}, nil).Once()
+	_, err = service.TranslateAddresses(context.Background(), TranslateAddressesInput{
+		From: "en", To: "zh", IDs: []string{"missing"},
+	})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	llm.AssertNotCalled(t, "StructuredCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFilterVocabularyTags(t *testing.T) {
+	t.Parallel()
+	vocabulary := []string{"museum", "palace"}
+	assert.Equal(t, []string{"palace"}, filterVocabularyTags([]string{"palace", "imperial", " palace"}, vocabulary))
+	// a language without refreshed tags keeps what the model returned
+	assert.Equal(t, []string{"imperial", "palace"}, filterVocabularyTags([]string{"imperial", "", "palace"}, nil))
+}
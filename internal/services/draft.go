@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+const maxDraftBodyLength = 10000
+
+var ErrInvalidDraft = errors.New("invalid draft")
+
+type draftRepository interface {
+	ListDrafts(ctx context.Context, opts repository.ListDraftsOptions) ([]models.Draft, error)
+	GetDraft(ctx context.Context, opts repository.GetDraftOptions) (*models.Draft, error)
+	CreateDraft(ctx context.Context, opts repository.CreateDraftOptions) (*models.Draft, error)
+	UpdateDraft(ctx context.Context, opts repository.UpdateDraftOptions) (*models.Draft, error)
+	DeleteDraft(ctx context.Context, opts repository.DeleteDraftOptions) (string, error)
+}
+
+type draftAddressRepository interface {
+	GetAddressesByIDs(
+		ctx context.Context,
+		opts *repository.GetAddressesByIDsOptions,
+	) (*repository.GetAddressesByIDsResponse, error)
+}
+
+type draftMusicRepository interface {
+	GetMusicByFilenames(
+		ctx context.Context,
+		opts *repository.GetMusicByFilenamesOptions,
+	) (*repository.GetMusicByFilenamesResponse, error)
+}
+
+type DraftService struct {
+	repo        draftRepository
+	addressRepo draftAddressRepository
+	musicRepo   draftMusicRepository
+}
+
+func NewDraftService(
+	repo draftRepository,
+	addressRepo draftAddressRepository,
+	musicRepo draftMusicRepository,
+) *DraftService {
+	return &DraftService{
+		repo:        repo,
+		addressRepo: addressRepo,
+		musicRepo:   musicRepo,
+	}
+}
+
+type ListDraftsInput struct {
+	Uid string
+}
+
+type ListDraftsOutput struct {
+	Drafts []models.Draft
+}
+
+type GetDraftInput struct {
+	Uid string
+	ID  string
+}
+
+type GetDraftOutput struct {
+	Draft models.Draft
+}
+
+type CreateDraftInput struct {
+	Uid                string
+	RecipientAddressID string
+	Language           models.Language
+	Body               string
+	MusicFilename      string
+}
+
+type CreateDraftOutput struct {
+	Draft models.Draft
+}
+
+// Nil fields are left unchanged
+type UpdateDraftInput struct {
+	Uid                string
+	ID                 string
+	RecipientAddressID *string
+	Language           *models.Language
+	Body               *string
+	MusicFilename      *string
+}
+
+type UpdateDraftOutput struct {
+	Draft models.Draft
+}
+
+type DeleteDraftInput struct {
+	Uid string
+	ID  string
+}
+
+type DeleteDraftOutput struct {
+	ID string
+}
+
+func (s *DraftService) ListDrafts(ctx context.Context, input ListDraftsInput) (*ListDraftsOutput, error) {
+	drafts, err := s.repo.ListDrafts(ctx, repository.ListDraftsOptions{Uid: input.Uid})
+	if err != nil {
+		return nil, err
+	}
+	return &ListDraftsOutput{Drafts: drafts}, nil
+}
+
+func (s *DraftService) GetDraft(ctx context.Context, input GetDraftInput) (*GetDraftOutput, error) {
+	draft, err := s.repo.GetDraft(ctx, repository.GetDraftOptions{Uid: input.Uid, ID: input.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &GetDraftOutput{Draft: *draft}, nil
+}
+
+func (s *DraftService) CreateDraft(ctx context.Context, input CreateDraftInput) (*CreateDraftOutput, error) {
+	if err := validateDraftBody(input.Body); err != nil {
+		return nil, err
+	}
+	if err := s.validateRecipient(ctx, input.Language, input.RecipientAddressID); err != nil {
+		return nil, err
+	}
+	if err := s.validateMusic(ctx, input.MusicFilename); err != nil {
+		return nil, err
+	}
+	opts := repository.CreateDraftOptions{
+		Uid: input.Uid,
+		Draft: models.Draft{
+			RecipientAddressID: input.RecipientAddressID,
+			Language:           input.Language,
+			Body:               input.Body,
+			MusicFilename:      input.MusicFilename,
+		},
+	}
+	draft, err := s.repo.CreateDraft(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateDraftOutput{Draft: *draft}, nil
+}
+
+func (s *DraftService) UpdateDraft(ctx context.Context, input UpdateDraftInput) (*UpdateDraftOutput, error) {
+	if input.Body != nil {
+		if err := validateDraftBody(*input.Body); err != nil {
+			return nil, err
+		}
+	}
+	if input.MusicFilename != nil {
+		if err := s.validateMusic(ctx, *input.MusicFilename); err != nil {
+			return nil, err
+		}
+	}
+	// the recipient has to be checked against the language the draft will end up with
+	if input.RecipientAddressID != nil || input.Language != nil {
+		current, err := s.repo.GetDraft(ctx, repository.GetDraftOptions{Uid: input.Uid, ID: input.ID})
+		if err != nil {
+			return nil, err
+		}
+		language, recipientID := current.Language, current.RecipientAddressID
+		if input.Language != nil {
+			language = *input.Language
+		}
+		if input.RecipientAddressID != nil {
+			recipientID = *input.RecipientAddressID
+		}
+		if err := s.validateRecipient(ctx, language, recipientID); err != nil {
+			return nil, err
+		}
+	}
+	opts := repository.UpdateDraftOptions{
+		Uid:                input.Uid,
+		ID:                 input.ID,
+		RecipientAddressID: input.RecipientAddressID,
+		Language:           input.Language,
+		Body:               input.Body,
+		MusicFilename:      input.MusicFilename,
+	}
+	draft, err := s.repo.UpdateDraft(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &UpdateDraftOutput{Draft: *draft}, nil
+}
+
+func (s *DraftService) DeleteDraft(ctx context.Context, input DeleteDraftInput) (*DeleteDraftOutput, error) {
+	id, err := s.repo.DeleteDraft(ctx, repository.DeleteDraftOptions{Uid: input.Uid, ID: input.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &DeleteDraftOutput{ID: id}, nil
+}
+
+// ---------- Helper methods ----------
+func (s *DraftService) validateRecipient(ctx context.Context, language models.Language, recipientID string) error {
+	if err := language.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDraft, err)
+	}
+	// a draft can be saved before the user picks a recipient
+	if recipientID == "" {
+		return nil
+	}
+	opts := &repository.GetAddressesByIDsOptions{Language: language, IDs: []string{recipientID}}
+	result, err := s.addressRepo.GetAddressesByIDs(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if len(result.Addresses) == 0 {
+		return fmt.Errorf("%w: recipient address %s not found", ErrInvalidDraft, recipientID)
+	}
+	return nil
+}
+
+func (s *DraftService) validateMusic(ctx context.Context, filename string) error {
+	// an empty filename means the draft has no music
+	if filename == "" {
+		return nil
+	}
+	opts := &repository.GetMusicByFilenamesOptions{Filenames: []string{filename}}
+	result, err := s.musicRepo.GetMusicByFilenames(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if len(result.Music) == 0 {
+		return fmt.Errorf("%w: music %s not found", ErrInvalidDraft, filename)
+	}
+	return nil
+}
+
+func validateDraftBody(body string) error {
+	if utf8.RuneCountInString(body) > maxDraftBodyLength {
+		return fmt.Errorf("%w: body exceeds %d characters", ErrInvalidDraft, maxDraftBodyLength)
+	}
+	return nil
+}
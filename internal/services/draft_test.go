@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockDraftRepository struct {
+	mock.Mock
+}
+
+func (m *mockDraftRepository) ListDrafts(ctx context.Context, opts repository.ListDraftsOptions) ([]models.Draft, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Draft), args.Error(1)
+}
+
+func (m *mockDraftRepository) GetDraft(ctx context.Context, opts repository.GetDraftOptions) (*models.Draft, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *mockDraftRepository) CreateDraft(ctx context.Context, opts repository.CreateDraftOptions) (*models.Draft, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *mockDraftRepository) UpdateDraft(ctx context.Context, opts repository.UpdateDraftOptions) (*models.Draft, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+
+func (m *mockDraftRepository) DeleteDraft(ctx context.Context, opts repository.DeleteDraftOptions) (string, error) {
+	args := m.Called(ctx, opts)
+	return args.String(0), args.Error(1)
+}
+
+type mockDraftAddressRepository struct {
+	mock.Mock
+}
+
+func (m *mockDraftAddressRepository) GetAddressesByIDs(
+	ctx context.Context,
+	opts *repository.GetAddressesByIDsOptions,
+) (*repository.GetAddressesByIDsResponse, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.GetAddressesByIDsResponse), args.Error(1)
+}
+
+type mockDraftMusicRepository struct {
+	mock.Mock
+}
+
+func (m *mockDraftMusicRepository) GetMusicByFilenames(
+	ctx context.Context,
+	opts *repository.GetMusicByFilenamesOptions,
+) (*repository.GetMusicByFilenamesResponse, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.GetMusicByFilenamesResponse), args.Error(1)
+}
+
+func setupDraftService() (*DraftService, *mockDraftRepository, *mockDraftAddressRepository) {
+	service, repo, addressRepo, _ := setupDraftServiceWithMusic()
+	return service, repo, addressRepo
+}
+
+func setupDraftServiceWithMusic() (
+	*DraftService,
+	*mockDraftRepository,
+	*mockDraftAddressRepository,
+	*mockDraftMusicRepository,
+) {
+	repo := new(mockDraftRepository)
+	addressRepo := new(mockDraftAddressRepository)
+	musicRepo := new(mockDraftMusicRepository)
+	return NewDraftService(repo, addressRepo, musicRepo), repo, addressRepo, musicRepo
+}
+
+func TestDraftService_ListDrafts(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupDraftService()
+	drafts := []models.Draft{{ID: "draft-1", OwnerID: "user-1"}}
+	repo.On("ListDrafts", mock.Anything, repository.ListDraftsOptions{Uid: "user-1"}).Return(drafts, nil).Once()
+	output, err := service.ListDrafts(context.Background(), ListDraftsInput{Uid: "user-1"})
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, drafts, output.Drafts)
+}
+
+func TestDraftService_CreateDraft(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		input           CreateDraftInput
+		foundAddresses  []models.AddressItem
+		expectLookup    bool
+		expectCreate    bool
+		expectedErrorIs error
+	}{
+		{
+			name: "success with recipient",
+			input: CreateDraftInput{
+				Uid:                "user-1",
+				RecipientAddressID: "address-1",
+				Language:           models.LanguageEN,
+				Body:               "Dear Sherlock",
+			},
+			foundAddresses: []models.AddressItem{{ID: "address-1"}},
+			expectLookup:   true,
+			expectCreate:   true,
+		},
+		{
+			name:         "success without recipient",
+			input:        CreateDraftInput{Uid: "user-1", Language: models.LanguageZH, Body: "你好"},
+			expectLookup: false,
+			expectCreate: true,
+		},
+		{
+			name: "recipient not found",
+			input: CreateDraftInput{
+				Uid:                "user-1",
+				RecipientAddressID: "missing",
+				Language:           models.LanguageEN,
+			},
+			foundAddresses:  []models.AddressItem{},
+			expectLookup:    true,
+			expectCreate:    false,
+			expectedErrorIs: ErrInvalidDraft,
+		},
+		{
+			name: "body too long",
+			input: CreateDraftInput{
+				Uid:      "user-1",
+				Language: models.LanguageEN,
+				Body:     strings.Repeat("a", maxDraftBodyLength+1),
+			},
+			expectLookup:    false,
+			expectCreate:    false,
+			expectedErrorIs: ErrInvalidDraft,
+		},
+		{
+			name:            "invalid language",
+			input:           CreateDraftInput{Uid: "user-1", Language: "fr"},
+			expectLookup:    false,
+			expectCreate:    false,
+			expectedErrorIs: ErrInvalidDraft,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, addressRepo := setupDraftService()
+			if tt.expectLookup {
+				addressRepo.On("GetAddressesByIDs", mock.Anything, &repository.GetAddressesByIDsOptions{
+					Language: tt.input.Language,
+					IDs:      []string{tt.input.RecipientAddressID},
+				}).Return(&repository.GetAddressesByIDsResponse{Addresses: tt.foundAddresses}, nil).Once()
+			}
+			if tt.expectCreate {
+				repo.On("CreateDraft", mock.Anything, mock.MatchedBy(func(opts repository.CreateDraftOptions) bool {
+					return opts.Uid == tt.input.Uid && opts.Draft.Body == tt.input.Body
+				})).Return(&models.Draft{ID: "draft-1", OwnerID: tt.input.Uid, Body: tt.input.Body}, nil).Once()
+			}
+			output, err := service.CreateDraft(context.Background(), tt.input)
+			repo.AssertExpectations(t)
+			addressRepo.AssertExpectations(t)
+			if tt.expectedErrorIs != nil {
+				assert.ErrorIs(t, err, tt.expectedErrorIs)
+				assert.Nil(t, output)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "draft-1", output.Draft.ID)
+		})
+	}
+}
+
+func TestDraftService_UpdateDraft(t *testing.T) {
+	t.Parallel()
+	service, repo, addressRepo := setupDraftService()
+	recipient := "address-2"
+	body := "updated body"
+	current := &models.Draft{ID: "draft-1", OwnerID: "user-1", Language: models.LanguageEN}
+	repo.On("GetDraft", mock.Anything, repository.GetDraftOptions{Uid: "user-1", ID: "draft-1"}).
+		Return(current, nil).Once()
+	addressRepo.On("GetAddressesByIDs", mock.Anything, &repository.GetAddressesByIDsOptions{
+		Language: models.LanguageEN,
+		IDs:      []string{recipient},
+	}).Return(&repository.GetAddressesByIDsResponse{Addresses: []models.AddressItem{{ID: recipient}}}, nil).Once()
+	repo.On("UpdateDraft", mock.Anything, repository.UpdateDraftOptions{
+		Uid:                "user-1",
+		ID:                 "draft-1",
+		RecipientAddressID: &recipient,
+		Body:               &body,
+	}).Return(&models.Draft{ID: "draft-1", RecipientAddressID: recipient, Body: body}, nil).Once()
+	output, err := service.UpdateDraft(context.Background(), UpdateDraftInput{
+		Uid:                "user-1",
+		ID:                 "draft-1",
+		RecipientAddressID: &recipient,
+		Body:               &body,
+	})
+	repo.AssertExpectations(t)
+	addressRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, recipient, output.Draft.RecipientAddressID)
+}
+
+func TestDraftService_DraftMusic(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		filename        string
+		foundMusic      []models.Music
+		expectLookup    bool
+		expectedErrorIs error
+	}{
+		{
+			name:         "music in the music list",
+			filename:     "jazz/blue.mp3",
+			foundMusic:   []models.Music{{Filename: "jazz/blue.mp3"}},
+			expectLookup: true,
+		},
+		{
+			name:            "music not in the music list",
+			filename:        "jazz/missing.mp3",
+			foundMusic:      []models.Music{},
+			expectLookup:    true,
+			expectedErrorIs: ErrInvalidDraft,
+		},
+		{
+			name:         "empty filename clears the music",
+			filename:     "",
+			expectLookup: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _, musicRepo := setupDraftServiceWithMusic()
+			if tt.expectLookup {
+				musicRepo.On("GetMusicByFilenames", mock.Anything, &repository.GetMusicByFilenamesOptions{
+					Filenames: []string{tt.filename},
+				}).Return(&repository.GetMusicByFilenamesResponse{Music: tt.foundMusic}, nil).Times(2)
+			}
+			if tt.expectedErrorIs == nil {
+				repo.On("CreateDraft", mock.Anything, mock.Anything).
+					Return(&models.Draft{ID: "draft-1", MusicFilename: tt.filename}, nil).Once()
+				repo.On("UpdateDraft", mock.Anything, mock.Anything).
+					Return(&models.Draft{ID: "draft-1", MusicFilename: tt.filename}, nil).Once()
+			}
+			_, createErr := service.CreateDraft(context.Background(), CreateDraftInput{
+				Uid:           "user-1",
+				Language:      models.LanguageEN,
+				MusicFilename: tt.filename,
+			})
+			_, updateErr := service.UpdateDraft(context.Background(), UpdateDraftInput{
+				Uid:           "user-1",
+				ID:            "draft-1",
+				MusicFilename: &tt.filename,
+			})
+			repo.AssertExpectations(t)
+			musicRepo.AssertExpectations(t)
+			if tt.expectedErrorIs != nil {
+				assert.ErrorIs(t, createErr, tt.expectedErrorIs)
+				assert.ErrorIs(t, updateErr, tt.expectedErrorIs)
+				return
+			}
+			assert.NoError(t, createErr)
+			assert.NoError(t, updateErr)
+		})
+	}
+}
+
+func TestDraftService_UpdateDraft_NotFound(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupDraftService()
+	language := models.LanguageZH
+	repo.On("GetDraft", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound).Once()
+	output, err := service.UpdateDraft(context.Background(), UpdateDraftInput{
+		Uid:      "user-1",
+		ID:       "draft-1",
+		Language: &language,
+	})
+	repo.AssertExpectations(t)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Nil(t, output)
+}
+
+func TestDraftService_DeleteDraft(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupDraftService()
+	repo.On("DeleteDraft", mock.Anything, repository.DeleteDraftOptions{Uid: "user-1", ID: "draft-1"}).
+		Return("draft-1", nil).Once()
+	repo.On("DeleteDraft", mock.Anything, repository.DeleteDraftOptions{Uid: "user-1", ID: "draft-2"}).
+		Return("", errors.New("firestore unavailable")).Once()
+	output, err := service.DeleteDraft(context.Background(), DeleteDraftInput{Uid: "user-1", ID: "draft-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "draft-1", output.ID)
+	output, err = service.DeleteDraft(context.Background(), DeleteDraftInput{Uid: "user-1", ID: "draft-2"})
+	assert.Error(t, err)
+	assert.Nil(t, output)
+	repo.AssertExpectations(t)
+}
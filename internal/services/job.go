@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+const (
+	defaultJobHeartbeat = 5 * time.Second
+	// jobs without a heartbeat for this long lost their runner
+	staleJobTimeout       = 2 * time.Minute
+	staleJobSweepInterval = time.Minute
+	jobWriteTimeout       = 10 * time.Second
+	defaultListJobSize    = 20
+	maxListJobSize        = 100
+)
+
+var ErrUnknownJobType = errors.New("unknown job type")
+
+type jobRepository interface {
+	CreateJob(ctx context.Context, job models.Job) (*models.Job, error)
+	GetJob(ctx context.Context, id string) (*models.Job, error)
+	ListJobs(ctx context.Context, opts repository.ListJobsOptions) ([]models.Job, error)
+	UpdateJob(ctx context.Context, opts repository.UpdateJobOptions) (*models.Job, error)
+	RequestJobCancel(ctx context.Context, id string) (*models.Job, error)
+	FailStaleJobs(ctx context.Context, opts repository.FailStaleJobsOptions) (int, error)
+}
+
+// Does the work of a job, it must stop when ctx is canceled
+type JobRunner func(ctx context.Context, params map[string]string, progress repository.ProgressFunc) error
+
+// Runs jobs in the background of this instance and keeps their status in the repository.
+// Progress is flushed on every heartbeat, which also picks up cancel requests from other instances.
+type JobService struct {
+	repo      jobRepository
+	runners   map[models.JobType]JobRunner
+	heartbeat time.Duration
+	mu        sync.Mutex
+	cancels   map[string]context.CancelFunc
+	wg        sync.WaitGroup
+	logger    *slog.Logger
+}
+
+func NewJobService(repo jobRepository, logger *slog.Logger) *JobService {
+	return &JobService{
+		repo:      repo,
+		runners:   make(map[models.JobType]JobRunner),
+		heartbeat: defaultJobHeartbeat,
+		cancels:   make(map[string]context.CancelFunc),
+		logger:    logger,
+	}
+}
+
+type SubmitJobInput struct {
+	Type      models.JobType
+	Params    map[string]string
+	CreatedBy string
+}
+
+type SubmitJobOutput struct {
+	Job models.Job
+}
+
+type GetJobInput struct {
+	ID string
+}
+
+type GetJobOutput struct {
+	Job models.Job
+}
+
+type ListJobsInput struct {
+	Limit int
+}
+
+type ListJobsOutput struct {
+	Jobs []models.Job
+}
+
+type CancelJobInput struct {
+	ID string
+}
+
+type CancelJobOutput struct {
+	Job models.Job
+}
+
+func (s *JobService) Register(jobType models.JobType, runner JobRunner) {
+	s.runners[jobType] = runner
+}
+
+// Mark stale jobs as failed periodically until ctx is canceled. Jobs of a previous process can still
+// have a recent heartbeat on startup, they are caught by a later sweep
+func (s *JobService) Run(ctx context.Context) {
+	ticker := time.NewTicker(staleJobSweepInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.RecoverStaleJobs(ctx); err != nil {
+			s.logger.Warn("failed to recover stale jobs", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Jobs whose runner stopped sending heartbeats, after a restart or a crash of the instance
+// that ran them, are marked as failed
+func (s *JobService) RecoverStaleJobs(ctx context.Context) error {
+	count, err := s.repo.FailStaleJobs(ctx, repository.FailStaleJobsOptions{
+		UpdatedBefore: time.Now().Add(-staleJobTimeout).UnixMilli(),
+		Reason:        "interrupted, the job stopped sending heartbeats",
+	})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		s.logger.Warn("marked interrupted jobs as failed", "count", count)
+	}
+	return nil
+}
+
+func (s *JobService) SubmitJob(ctx context.Context, input SubmitJobInput) (*SubmitJobOutput, error) {
+	runner, ok := s.runners[input.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, input.Type)
+	}
+	job, err := s.repo.CreateJob(ctx, models.Job{
+		Type:      input.Type,
+		Params:    input.Params,
+		CreatedBy: input.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancels[job.ID] = cancel
+	s.mu.Unlock()
+	s.wg.Add(1)
+	go s.run(runCtx, *job, runner)
+	return &SubmitJobOutput{Job: *job}, nil
+}
+
+func (s *JobService) GetJob(ctx context.Context, input GetJobInput) (*GetJobOutput, error) {
+	job, err := s.repo.GetJob(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetJobOutput{Job: *job}, nil
+}
+
+func (s *JobService) ListJobs(ctx context.Context, input ListJobsInput) (*ListJobsOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListJobSize
+	}
+	jobs, err := s.repo.ListJobs(ctx, repository.ListJobsOptions{Limit: min(limit, maxListJobSize)})
+	if err != nil {
+		return nil, err
+	}
+	return &ListJobsOutput{Jobs: jobs}, nil
+}
+
+// The job may run on another instance, that runner stops on its next heartbeat
+func (s *JobService) CancelJob(ctx context.Context, input CancelJobInput) (*CancelJobOutput, error) {
+	job, err := s.repo.RequestJobCancel(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if cancel, ok := s.cancels[input.ID]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+	return &CancelJobOutput{Job: *job}, nil
+}
+
+// Blocks until every job started by this instance has finished
+func (s *JobService) Wait() {
+	s.wg.Wait()
+}
+
+// ---------- Helper methods ----------
+
+func (s *JobService) run(ctx context.Context, job models.Job, runner JobRunner) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		s.cancels[job.ID]()
+		delete(s.cancels, job.ID)
+		s.mu.Unlock()
+	}()
+	running := models.JobStatusRunning
+	startedAt := time.Now().UnixMilli()
+	current, err := s.updateJob(repository.UpdateJobOptions{ID: job.ID, Status: &running, StartedAt: &startedAt})
+	if err == nil && current.CancelRequested {
+		s.finish(job.ID, 0, 0, context.Canceled)
+		return
+	}
+	var processed, failed atomic.Int64
+	progress := func(p int, f int) {
+		processed.Store(int64(p))
+		failed.Store(int64(f))
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- runner(ctx, job.Params, progress)
+	}()
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil && ctx.Err() != nil {
+				err = context.Canceled
+			}
+			s.finish(job.ID, processed.Load(), failed.Load(), err)
+			return
+		case <-ticker.C:
+			p, f := processed.Load(), failed.Load()
+			current, err := s.updateJob(repository.UpdateJobOptions{ID: job.ID, Processed: &p, Failed: &f})
+			if err == nil && current.CancelRequested {
+				s.mu.Lock()
+				s.cancels[job.ID]()
+				s.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (s *JobService) finish(id string, processed int64, failed int64, runErr error) {
+	status := models.JobStatusSucceeded
+	message := ""
+	switch {
+	case errors.Is(runErr, context.Canceled):
+		status = models.JobStatusCanceled
+	case runErr != nil:
+		status = models.JobStatusFailed
+		message = runErr.Error()
+		s.logger.Error("job failed", "jobID", id, "error", runErr)
+	}
+	finishedAt := time.Now().UnixMilli()
+	s.updateJob(repository.UpdateJobOptions{
+		ID:         id,
+		Status:     &status,
+		Processed:  &processed,
+		Failed:     &failed,
+		Error:      &message,
+		FinishedAt: &finishedAt,
+	})
+}
+
+// Job writes must not depend on the job context, they also record the cancellation
+func (s *JobService) updateJob(opts repository.UpdateJobOptions) (*models.Job, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jobWriteTimeout)
+	defer cancel()
+	job, err := s.repo.UpdateJob(ctx, opts)
+	if err != nil {
+		s.logger.Warn("failed to update job", "jobID", opts.ID, "error", err)
+	}
+	return job, err
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockJobRepository struct {
+	mock.Mock
+	mu      sync.Mutex
+	updates []repository.UpdateJobOptions
+	cancel  bool // reported back by UpdateJob like a cancel request from another instance
+}
+
+func (m *mockJobRepository) CreateJob(ctx context.Context, job models.Job) (*models.Job, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *mockJobRepository) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *mockJobRepository) ListJobs(ctx context.Context, opts repository.ListJobsOptions) ([]models.Job, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Job), args.Error(1)
+}
+
+func (m *mockJobRepository) UpdateJob(ctx context.Context, opts repository.UpdateJobOptions) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updates = append(m.updates, opts)
+	return &models.Job{ID: opts.ID, CancelRequested: m.cancel}, nil
+}
+
+func (m *mockJobRepository) RequestJobCancel(ctx context.Context, id string) (*models.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *mockJobRepository) FailStaleJobs(ctx context.Context, opts repository.FailStaleJobsOptions) (int, error) {
+	args := m.Called(ctx, opts)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockJobRepository) setCancel() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancel = true
+}
+
+func (m *mockJobRepository) lastUpdate() repository.UpdateJobOptions {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updates[len(m.updates)-1]
+}
+
+func setupJobService() (*JobService, *mockJobRepository) {
+	repo := new(mockJobRepository)
+	service := NewJobService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.heartbeat = 5 * time.Millisecond
+	return service, repo
+}
+
+func submitTestJob(t *testing.T, service *JobService, repo *mockJobRepository) {
+	params := map[string]string{models.JobParamLanguage: "en"}
+	repo.On("CreateJob", mock.Anything, models.Job{Type: models.JobTypeTagsRefresh, Params: params}).
+		Return(&models.Job{
+			ID:     "job_1",
+			Type:   models.JobTypeTagsRefresh,
+			Status: models.JobStatusQueued,
+			Params: params,
+		}, nil).Once()
+	output, err := service.SubmitJob(context.Background(), SubmitJobInput{
+		Type:   models.JobTypeTagsRefresh,
+		Params: params,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "job_1", output.Job.ID)
+}
+
+func TestJobService_SubmitJob_Succeeded(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	service.Register(models.JobTypeTagsRefresh, func(
+		ctx context.Context, params map[string]string, progress repository.ProgressFunc) error {
+		assert.Equal(t, "en", params[models.JobParamLanguage])
+		progress(10, 1)
+		return nil
+	})
+	submitTestJob(t, service, repo)
+	service.Wait()
+	last := repo.lastUpdate()
+	assert.Equal(t, models.JobStatusSucceeded, *last.Status)
+	assert.Equal(t, int64(10), *last.Processed)
+	assert.Equal(t, int64(1), *last.Failed)
+	assert.NotNil(t, last.FinishedAt)
+}
+
+func TestJobService_SubmitJob_Failed(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	service.Register(models.JobTypeTagsRefresh, func(
+		ctx context.Context, params map[string]string, progress repository.ProgressFunc) error {
+		return errors.New("firestore unavailable")
+	})
+	submitTestJob(t, service, repo)
+	service.Wait()
+	last := repo.lastUpdate()
+	assert.Equal(t, models.JobStatusFailed, *last.Status)
+	assert.Equal(t, "firestore unavailable", *last.Error)
+}
+
+func TestJobService_SubmitJob_UnknownType(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	_, err := service.SubmitJob(context.Background(), SubmitJobInput{Type: models.JobTypeTypesenseSync})
+	assert.ErrorIs(t, err, ErrUnknownJobType)
+	repo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+}
+
+func TestJobService_CancelJob(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	started := make(chan struct{})
+	service.Register(models.JobTypeTagsRefresh, func(
+		ctx context.Context, params map[string]string, progress repository.ProgressFunc) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	submitTestJob(t, service, repo)
+	<-started
+	repo.On("RequestJobCancel", mock.Anything, "job_1").
+		Return(&models.Job{ID: "job_1", Status: models.JobStatusRunning, CancelRequested: true}, nil).Once()
+	output, err := service.CancelJob(context.Background(), CancelJobInput{ID: "job_1"})
+	assert.NoError(t, err)
+	assert.True(t, output.Job.CancelRequested)
+	service.Wait()
+	assert.Equal(t, models.JobStatusCanceled, *repo.lastUpdate().Status)
+	repo.AssertExpectations(t)
+}
+
+func TestJobService_CancelJob_FromHeartbeat(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	service.Register(models.JobTypeTagsRefresh, func(
+		ctx context.Context, params map[string]string, progress repository.ProgressFunc) error {
+		// the cancel request was made on another instance
+		repo.setCancel()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("job was not canceled")
+		}
+	})
+	submitTestJob(t, service, repo)
+	service.Wait()
+	assert.Equal(t, models.JobStatusCanceled, *repo.lastUpdate().Status)
+}
+
+func TestJobService_CancelJob_Finished(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	repo.On("RequestJobCancel", mock.Anything, "job_1").Return(nil, repository.ErrJobFinished).Once()
+	_, err := service.CancelJob(context.Background(), CancelJobInput{ID: "job_1"})
+	assert.ErrorIs(t, err, repository.ErrJobFinished)
+}
+
+func TestJobService_ListJobs_Limit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		limit    int
+		expected int
+	}{
+		{name: "default", limit: 0, expected: defaultListJobSize},
+		{name: "custom", limit: 5, expected: 5},
+		{name: "capped", limit: 1000, expected: maxListJobSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupJobService()
+			repo.On("ListJobs", mock.Anything, repository.ListJobsOptions{Limit: tt.expected}).
+				Return([]models.Job{}, nil).Once()
+			_, err := service.ListJobs(context.Background(), ListJobsInput{Limit: tt.limit})
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobService_RecoverStaleJobs(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	repo.On("FailStaleJobs", mock.Anything, mock.MatchedBy(func(opts repository.FailStaleJobsOptions) bool {
+		return opts.UpdatedBefore < time.Now().UnixMilli() && opts.Reason != ""
+	})).Return(2, nil).Once()
+	assert.NoError(t, service.RecoverStaleJobs(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestJobService_Run(t *testing.T) {
+	t.Parallel()
+	service, repo := setupJobService()
+	repo.On("FailStaleJobs", mock.Anything, mock.Anything).Return(0, errors.New("firestore unavailable")).Once()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// the first sweep runs right away, a failed sweep doesn't stop the loop
+	service.Run(ctx)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+)
+
+const languageRefreshInterval = time.Minute
+
+var ErrInvalidLanguageConfig = errors.New("invalid language config")
+
+type languageRepository interface {
+	ListLanguages(ctx context.Context) ([]models.LanguageConfig, error)
+	SetLanguage(ctx context.Context, config models.LanguageConfig) error
+}
+
+// Keeps the language registry in line with the stored language configurations.
+// Stored languages are added to the built-in ones or override them, languages are disabled instead of removed
+// since their collections and saved addresses stay around.
+type LanguageService struct {
+	repo     languageRepository
+	registry *models.LanguageRegistry
+	logger   *slog.Logger
+}
+
+func NewLanguageService(
+	repo languageRepository, registry *models.LanguageRegistry, logger *slog.Logger) *LanguageService {
+	return &LanguageService{
+		repo:     repo,
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+type ListLanguagesOutput struct {
+	Languages []models.LanguageConfig
+}
+
+type UpsertLanguageInput struct {
+	Config models.LanguageConfig
+}
+
+type UpsertLanguageOutput struct {
+	Language models.LanguageConfig
+}
+
+// Load the stored languages into the registry, it is left unchanged when they are invalid
+func (s *LanguageService) Load(ctx context.Context) error {
+	stored, err := s.repo.ListLanguages(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.registry.Replace(mergeLanguageConfigs(models.DefaultLanguageConfigs, stored)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLanguageConfig, err)
+	}
+	return nil
+}
+
+// Reload the stored languages periodically until ctx is canceled, so changes made on another instance apply
+func (s *LanguageService) Run(ctx context.Context) {
+	ticker := time.NewTicker(languageRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.Load(ctx); err != nil {
+			s.logger.Warn("failed to reload languages", "error", err)
+		}
+	}
+}
+
+// Every language with its settings, refreshed from the store first
+func (s *LanguageService) ListLanguages(ctx context.Context) (*ListLanguagesOutput, error) {
+	if err := s.Load(ctx); err != nil {
+		return nil, err
+	}
+	return &ListLanguagesOutput{Languages: s.registry.All()}, nil
+}
+
+// Add a language or change its settings. The whole set is checked before it is stored,
+// so a fallback to an unknown language or a cycle of fallbacks is rejected
+func (s *LanguageService) UpsertLanguage(
+	ctx context.Context, input UpsertLanguageInput) (*UpsertLanguageOutput, error) {
+	config := input.Config
+	config.Code = models.Language(strings.TrimSpace(string(config.Code))).Lower()
+	config.Fallback = models.Language(strings.TrimSpace(string(config.Fallback))).Lower()
+	config.Name = strings.TrimSpace(config.Name)
+	config.Locale = strings.TrimSpace(config.Locale)
+	if config.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidLanguageConfig)
+	}
+	if config.Locale == "" {
+		config.Locale = config.Code.Get()
+	}
+	stored, err := s.repo.ListLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	configs := mergeLanguageConfigs(models.DefaultLanguageConfigs, stored, []models.LanguageConfig{config})
+	if _, err := models.NewLanguageRegistry(configs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLanguageConfig, err)
+	}
+	if err := s.repo.SetLanguage(ctx, config); err != nil {
+		return nil, err
+	}
+	if err := s.registry.Replace(configs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLanguageConfig, err)
+	}
+	return &UpsertLanguageOutput{Language: config}, nil
+}
+
+// ---------- Helper methods ----------
+
+// Configs of the later sets replace the ones with the same code, the order of first appearance is kept
+func mergeLanguageConfigs(sets ...[]models.LanguageConfig) []models.LanguageConfig {
+	merged := []models.LanguageConfig{}
+	index := map[models.Language]int{}
+	for _, set := range sets {
+		for _, config := range set {
+			code := config.Code.Lower()
+			if i, ok := index[code]; ok {
+				merged[i] = config
+				continue
+			}
+			index[code] = len(merged)
+			merged = append(merged, config)
+		}
+	}
+	return merged
+}
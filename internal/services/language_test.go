@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLanguageRepository struct {
+	mock.Mock
+}
+
+func (m *mockLanguageRepository) ListLanguages(ctx context.Context) ([]models.LanguageConfig, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LanguageConfig), args.Error(1)
+}
+
+func (m *mockLanguageRepository) SetLanguage(ctx context.Context, config models.LanguageConfig) error {
+	args := m.Called(ctx, config)
+	return args.Error(0)
+}
+
+func setupLanguageService(t *testing.T) (*LanguageService, *mockLanguageRepository, *models.LanguageRegistry) {
+	registry, err := models.NewLanguageRegistry(models.DefaultLanguageConfigs)
+	assert.NoError(t, err)
+	repo := new(mockLanguageRepository)
+	service := NewLanguageService(repo, registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return service, repo, registry
+}
+
+func TestLanguageService_Load(t *testing.T) {
+	t.Parallel()
+	t.Run("stored languages extend the built-in ones", func(t *testing.T) {
+		service, repo, registry := setupLanguageService(t)
+		repo.On("ListLanguages", mock.Anything).Return([]models.LanguageConfig{
+			{Code: "ja", Name: "Japanese", Locale: "ja", Fallback: models.LanguageEN, Enabled: true},
+			{Code: models.LanguageZH, Name: "Simplified Chinese", Locale: "zh", Enabled: false},
+		}, nil).Once()
+		assert.NoError(t, service.Load(context.Background()))
+		assert.Equal(t, []models.Language{"en", "ja", "zh"}, registry.Codes())
+		assert.Equal(t, []models.Language{"en", "ja"}, registry.Enabled())
+		repo.AssertExpectations(t)
+	})
+	t.Run("invalid stored languages keep the registry", func(t *testing.T) {
+		service, repo, registry := setupLanguageService(t)
+		repo.On("ListLanguages", mock.Anything).Return([]models.LanguageConfig{
+			{Code: "fr", Name: "French", Locale: "fr", Fallback: "de", Enabled: true},
+		}, nil).Once()
+		err := service.Load(context.Background())
+		assert.ErrorIs(t, err, ErrInvalidLanguageConfig)
+		assert.Equal(t, []models.Language{"en", "zh"}, registry.Codes())
+		repo.AssertExpectations(t)
+	})
+	t.Run("repository error", func(t *testing.T) {
+		service, repo, _ := setupLanguageService(t)
+		repo.On("ListLanguages", mock.Anything).Return(nil, errors.New("firestore unavailable")).Once()
+		assert.Error(t, service.Load(context.Background()))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestLanguageService_UpsertLanguage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		stored        []models.LanguageConfig
+		config        models.LanguageConfig
+		expectedSaved *models.LanguageConfig
+		expectedCodes []models.Language
+		expectedError error
+	}{
+		{
+			name:          "new language with default locale",
+			config:        models.LanguageConfig{Code: " JA ", Name: "Japanese", Fallback: "EN", Enabled: true},
+			expectedSaved: &models.LanguageConfig{Code: "ja", Name: "Japanese", Locale: "ja", Fallback: "en", Enabled: true},
+			expectedCodes: []models.Language{"en", "ja", "zh"},
+		},
+		{
+			name: "fallback to a stored language",
+			stored: []models.LanguageConfig{
+				{Code: "fr", Name: "French", Locale: "fr", Fallback: models.LanguageEN},
+			},
+			config:        models.LanguageConfig{Code: "fr-ca", Name: "Canadian French", Locale: "fr", Fallback: "fr"},
+			expectedSaved: &models.LanguageConfig{Code: "fr-ca", Name: "Canadian French", Locale: "fr", Fallback: "fr"},
+			expectedCodes: []models.Language{"en", "fr", "fr-ca", "zh"},
+		},
+		{
+			name:          "missing name",
+			config:        models.LanguageConfig{Code: "ja", Enabled: true},
+			expectedError: ErrInvalidLanguageConfig,
+		},
+		{
+			name:          "unknown fallback",
+			config:        models.LanguageConfig{Code: "ja", Name: "Japanese", Fallback: "ko"},
+			expectedError: ErrInvalidLanguageConfig,
+		},
+		{
+			name:          "fallback cycle",
+			config:        models.LanguageConfig{Code: "en", Name: "English", Fallback: "zh", Enabled: true},
+			expectedError: ErrInvalidLanguageConfig,
+		},
+		{
+			name:          "invalid code",
+			config:        models.LanguageConfig{Code: "../en", Name: "English"},
+			expectedError: ErrInvalidLanguageConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, registry := setupLanguageService(t)
+			if tt.config.Name != "" {
+				repo.On("ListLanguages", mock.Anything).Return(tt.stored, nil).Once()
+			}
+			if tt.expectedSaved != nil {
+				repo.On("SetLanguage", mock.Anything, *tt.expectedSaved).Return(nil).Once()
+			}
+			output, err := service.UpsertLanguage(context.Background(), UpsertLanguageInput{Config: tt.config})
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, output)
+				assert.Equal(t, []models.Language{"en", "zh"}, registry.Codes())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, *tt.expectedSaved, output.Language)
+				assert.Equal(t, tt.expectedCodes, registry.Codes())
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMergeLanguageConfigs(t *testing.T) {
+	t.Parallel()
+	merged := mergeLanguageConfigs(
+		[]models.LanguageConfig{{Code: "en", Name: "English"}, {Code: "zh", Name: "Chinese"}},
+		[]models.LanguageConfig{{Code: "ja", Name: "Japanese"}, {Code: "ZH", Name: "Simplified Chinese"}},
+	)
+	assert.Equal(t, []models.LanguageConfig{
+		{Code: "en", Name: "English"},
+		{Code: "ZH", Name: "Simplified Chinese"},
+		{Code: "ja", Name: "Japanese"},
+	}, merged)
+}
@@ -2,15 +2,23 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/repository"
 )
 
+// longest track a client may report, anything above is treated as a bogus value
+const maxMusicDurationSec = 6 * 60 * 60
+
+var ErrInvalidMusicDuration = errors.New("invalid music duration")
+
 type musicRepository interface {
 	GetPresignedMusicURL(ctx context.Context, opts repository.GetPresignedMusicURLOptions) (*repository.GetPresignedMusicURLResponse, error)
 	GetAllMusicList(ctx context.Context) (*repository.GetAllMusicListResponse, error)
 	RefreshMusicList(ctx context.Context) (*repository.RefreshMusicListResponse, error)
+	ReportMusicDuration(ctx context.Context, opts repository.ReportMusicDurationOptions) (*models.Music, error)
 }
 
 type MusicService struct {
@@ -36,6 +44,15 @@ type GetPresignedMusicURLOutput struct {
 	URL string
 }
 
+type ReportMusicDurationInput struct {
+	Filename    string
+	DurationSec int64
+}
+
+type ReportMusicDurationOutput struct {
+	Music models.Music
+}
+
 func (s *MusicService) RefreshMusicList(
 	ctx context.Context) (*RefreshMusicListOutput, error) {
 	musicList, err := s.repo.RefreshMusicList(ctx)
@@ -67,3 +84,22 @@ func (s *MusicService) GetPresignedMusicURL(
 	}
 	return &GetPresignedMusicURLOutput{URL: output.URL}, nil
 }
+
+// Fallback for tracks whose duration could not be read from the file during the refresh
+func (s *MusicService) ReportMusicDuration(
+	ctx context.Context,
+	input ReportMusicDurationInput,
+) (*ReportMusicDurationOutput, error) {
+	if input.DurationSec <= 0 || input.DurationSec > maxMusicDurationSec {
+		return nil, fmt.Errorf("%w: %d seconds", ErrInvalidMusicDuration, input.DurationSec)
+	}
+	opts := repository.ReportMusicDurationOptions{
+		Filename:    input.Filename,
+		DurationSec: input.DurationSec,
+	}
+	music, err := s.repo.ReportMusicDuration(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ReportMusicDurationOutput{Music: *music}, nil
+}
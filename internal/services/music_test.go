@@ -40,6 +40,15 @@ func (m *mockMusicRepository) GetPresignedMusicURL(ctx context.Context, opts rep
 	return args.Get(0).(*repository.GetPresignedMusicURLResponse), nil
 }
 
+func (m *mockMusicRepository) ReportMusicDuration(ctx context.Context, opts repository.ReportMusicDurationOptions) (
+	*models.Music, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Music), args.Error(1)
+}
+
 func setupMusicService() (*MusicService, *mockMusicRepository) {
 	repo := new(mockMusicRepository)
 	service := NewMusicService(repo)
@@ -146,3 +155,52 @@ func TestMusicService_GetPresignedMusicURL_ERROR(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, output)
 }
+
+func TestMusicService_ReportMusicDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		durationSec     int64
+		mockError       error
+		expectCall      bool
+		expectedErrorIs error
+	}{
+		{name: "success", durationSec: 215, expectCall: true},
+		{name: "zero duration", durationSec: 0, expectedErrorIs: ErrInvalidMusicDuration},
+		{name: "negative duration", durationSec: -1, expectedErrorIs: ErrInvalidMusicDuration},
+		{name: "too long", durationSec: maxMusicDurationSec + 1, expectedErrorIs: ErrInvalidMusicDuration},
+		{
+			name:            "music not found",
+			durationSec:     215,
+			mockError:       repository.ErrNotFound,
+			expectCall:      true,
+			expectedErrorIs: repository.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, repo := setupMusicService()
+			opts := repository.ReportMusicDurationOptions{Filename: "jazz/track.mp3", DurationSec: tt.durationSec}
+			if tt.expectCall {
+				if tt.mockError != nil {
+					repo.On("ReportMusicDuration", mock.Anything, opts).Return(nil, tt.mockError).Once()
+				} else {
+					repo.On("ReportMusicDuration", mock.Anything, opts).
+						Return(&models.Music{Filename: opts.Filename, DurationSec: tt.durationSec}, nil).Once()
+				}
+			}
+			output, err := services.ReportMusicDuration(context.Background(), ReportMusicDurationInput{
+				Filename:    "jazz/track.mp3",
+				DurationSec: tt.durationSec,
+			})
+			repo.AssertExpectations(t)
+			if tt.expectedErrorIs != nil {
+				assert.ErrorIs(t, err, tt.expectedErrorIs)
+				assert.Nil(t, output)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.durationSec, output.Music.DurationSec)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+const (
+	outboxPollInterval   = 3 * time.Second
+	outboxBatchSize      = 50
+	outboxMaxAttempts    = 8
+	outboxBaseBackoff    = 5 * time.Second
+	outboxMaxBackoff     = 10 * time.Minute
+	defaultListOutbox    = 50
+	maxListOutboxEntries = 200
+)
+
+type outboxRepository interface {
+	ListDueOutboxEntries(
+		ctx context.Context, opts repository.ListDueOutboxEntriesOptions) ([]models.OutboxEntry, error)
+	ListOutboxEntries(ctx context.Context, opts repository.ListOutboxEntriesOptions) ([]models.OutboxEntry, error)
+	CompleteOutboxEntry(ctx context.Context, entry models.OutboxEntry) error
+	RescheduleOutboxEntry(ctx context.Context, opts repository.RescheduleOutboxEntryOptions) error
+	FailOutboxEntry(ctx context.Context, opts repository.FailOutboxEntryOptions) error
+}
+
+type searchIndexer interface {
+	SyncAddressToSearch(ctx context.Context, opts repository.SyncAddressToSearchOptions) error
+	ListTaxonomyTags(ctx context.Context) ([]models.Tag, error)
+}
+
+// Drains the search outbox written by address changes into the search index.
+// Entries are applied by re-reading the address, so running it on several instances at once is harmless.
+type OutboxService struct {
+	repo    outboxRepository
+	indexer searchIndexer
+	logger  *slog.Logger
+}
+
+func NewOutboxService(repo outboxRepository, indexer searchIndexer, logger *slog.Logger) *OutboxService {
+	return &OutboxService{
+		repo:    repo,
+		indexer: indexer,
+		logger:  logger,
+	}
+}
+
+type ListOutboxEntriesInput struct {
+	Status models.OutboxStatus
+	Limit  int
+}
+
+type ListOutboxEntriesOutput struct {
+	Entries []models.OutboxEntry
+}
+
+func (s *OutboxService) ListOutboxEntries(
+	ctx context.Context, input ListOutboxEntriesInput) (*ListOutboxEntriesOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListOutbox
+	}
+	entries, err := s.repo.ListOutboxEntries(ctx, repository.ListOutboxEntriesOptions{
+		Status: input.Status,
+		Limit:  min(limit, maxListOutboxEntries),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ListOutboxEntriesOutput{Entries: entries}, nil
+}
+
+// Poll the outbox until ctx is canceled
+func (s *OutboxService) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	for {
+		s.DrainOutbox(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply one batch of due entries, returns how many were applied.
+// The tag taxonomy is loaded once for the batch
+func (s *OutboxService) DrainOutbox(ctx context.Context) int {
+	entries, err := s.repo.ListDueOutboxEntries(ctx, repository.ListDueOutboxEntriesOptions{
+		Now:   time.Now().UnixMilli(),
+		Limit: outboxBatchSize,
+	})
+	if err != nil {
+		s.logger.Warn("failed to read search outbox", "error", err)
+		return 0
+	}
+	if len(entries) == 0 {
+		return 0
+	}
+	tags, err := s.indexer.ListTaxonomyTags(ctx)
+	if err != nil {
+		s.logger.Warn("failed to read the tag taxonomy for the search outbox", "error", err)
+		return 0
+	}
+	taxonomy := models.NewTagTaxonomy(tags)
+	applied := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if s.applyEntry(ctx, entry, taxonomy) {
+			applied++
+		}
+	}
+	return applied
+}
+
+// ---------- Helper methods ----------
+
+func (s *OutboxService) applyEntry(ctx context.Context, entry models.OutboxEntry, taxonomy *models.TagTaxonomy) bool {
+	err := s.indexer.SyncAddressToSearch(ctx, repository.SyncAddressToSearchOptions{
+		Language: entry.Language,
+		ID:       entry.AddressID,
+		Taxonomy: taxonomy,
+	})
+	if err == nil {
+		if err := s.repo.CompleteOutboxEntry(ctx, entry); err != nil {
+			s.logger.Warn("failed to complete outbox entry", "entryID", entry.ID, "error", err)
+		}
+		return true
+	}
+	if entry.Attempts+1 >= outboxMaxAttempts {
+		s.logger.Error("giving up on search outbox entry", "entryID", entry.ID, "attempts", entry.Attempts+1, "error", err)
+		failErr := s.repo.FailOutboxEntry(ctx, repository.FailOutboxEntryOptions{Entry: entry, LastError: err.Error()})
+		if failErr != nil {
+			s.logger.Warn("failed to move outbox entry to failed", "entryID", entry.ID, "error", failErr)
+		}
+		return false
+	}
+	s.logger.Warn("failed to apply search outbox entry", "entryID", entry.ID, "attempts", entry.Attempts+1, "error", err)
+	rescheduleErr := s.repo.RescheduleOutboxEntry(ctx, repository.RescheduleOutboxEntryOptions{
+		Entry:         entry,
+		LastError:     err.Error(),
+		NextAttemptAt: time.Now().Add(outboxBackoff(entry.Attempts + 1)).UnixMilli(),
+	})
+	if rescheduleErr != nil {
+		s.logger.Warn("failed to reschedule outbox entry", "entryID", entry.ID, "error", rescheduleErr)
+	}
+	return false
+}
+
+// Exponential backoff after the given number of failed attempts
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *mockOutboxRepository) ListDueOutboxEntries(
+	ctx context.Context, opts repository.ListDueOutboxEntriesOptions) ([]models.OutboxEntry, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxEntry), args.Error(1)
+}
+
+func (m *mockOutboxRepository) ListOutboxEntries(
+	ctx context.Context, opts repository.ListOutboxEntriesOptions) ([]models.OutboxEntry, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxEntry), args.Error(1)
+}
+
+func (m *mockOutboxRepository) CompleteOutboxEntry(ctx context.Context, entry models.OutboxEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *mockOutboxRepository) RescheduleOutboxEntry(
+	ctx context.Context, opts repository.RescheduleOutboxEntryOptions) error {
+	args := m.Called(ctx, opts)
+	return args.Error(0)
+}
+
+func (m *mockOutboxRepository) FailOutboxEntry(ctx context.Context, opts repository.FailOutboxEntryOptions) error {
+	args := m.Called(ctx, opts)
+	return args.Error(0)
+}
+
+type mockSearchIndexer struct {
+	mock.Mock
+}
+
+func (m *mockSearchIndexer) SyncAddressToSearch(ctx context.Context, opts repository.SyncAddressToSearchOptions) error {
+	args := m.Called(ctx, opts)
+	return args.Error(0)
+}
+
+func (m *mockSearchIndexer) ListTaxonomyTags(ctx context.Context) ([]models.Tag, error) {
+	args := m.Called(ctx)
+	tags, _ := args.Get(0).([]models.Tag)
+	return tags, args.Error(1)
+}
+
+func setupOutboxService() (*OutboxService, *mockOutboxRepository, *mockSearchIndexer) {
+	repo := new(mockOutboxRepository)
+	indexer := new(mockSearchIndexer)
+	service := NewOutboxService(repo, indexer, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return service, repo, indexer
+}
+
+func TestOutboxService_DrainOutbox(t *testing.T) {
+	t.Parallel()
+	service, repo, indexer := setupOutboxService()
+	applied := models.OutboxEntry{ID: "en_1", Language: "en", AddressID: "1", UpdatedAt: 10}
+	retried := models.OutboxEntry{ID: "en_2", Language: "en", AddressID: "2", Attempts: 2, UpdatedAt: 11}
+	exhausted := models.OutboxEntry{ID: "zh_3", Language: "zh", AddressID: "3", Attempts: outboxMaxAttempts - 1}
+	repo.On("ListDueOutboxEntries", mock.Anything, mock.MatchedBy(func(opts repository.ListDueOutboxEntriesOptions) bool {
+		return opts.Limit == outboxBatchSize && opts.Now > 0
+	})).Return([]models.OutboxEntry{applied, retried, exhausted}, nil).Once()
+	// the taxonomy is read once for the batch
+	tags := []models.Tag{{Slug: "writer", Category: models.TagCategoryRole, Names: map[string]string{"en": "Writer"}}}
+	indexer.On("ListTaxonomyTags", mock.Anything).Return(tags, nil).Once()
+	taxonomy := models.NewTagTaxonomy(tags)
+	indexer.On("SyncAddressToSearch", mock.Anything,
+		repository.SyncAddressToSearchOptions{Language: "en", ID: "1", Taxonomy: taxonomy}).Return(nil).Once()
+	indexer.On("SyncAddressToSearch", mock.Anything,
+		repository.SyncAddressToSearchOptions{Language: "en", ID: "2", Taxonomy: taxonomy}).
+		Return(errors.New("typesense unavailable")).Once()
+	indexer.On("SyncAddressToSearch", mock.Anything,
+		repository.SyncAddressToSearchOptions{Language: "zh", ID: "3", Taxonomy: taxonomy}).
+		Return(errors.New("typesense unavailable")).Once()
+	repo.On("CompleteOutboxEntry", mock.Anything, applied).Return(nil).Once()
+	before := time.Now()
+	repo.On("RescheduleOutboxEntry", mock.Anything, mock.MatchedBy(func(opts repository.RescheduleOutboxEntryOptions) bool {
+		// third attempt waits four times the base backoff
+		next := time.UnixMilli(opts.NextAttemptAt)
+		return opts.Entry.ID == "en_2" &&
+			opts.LastError == "typesense unavailable" &&
+			!next.Before(before.Add(4*outboxBaseBackoff).Truncate(time.Millisecond))
+	})).Return(nil).Once()
+	repo.On("FailOutboxEntry", mock.Anything, repository.FailOutboxEntryOptions{
+		Entry:     exhausted,
+		LastError: "typesense unavailable",
+	}).Return(nil).Once()
+	assert.Equal(t, 1, service.DrainOutbox(context.Background()))
+	repo.AssertExpectations(t)
+	indexer.AssertExpectations(t)
+}
+
+func TestOutboxService_DrainOutbox_ReadError(t *testing.T) {
+	t.Parallel()
+	service, repo, indexer := setupOutboxService()
+	repo.On("ListDueOutboxEntries", mock.Anything, mock.Anything).Return(nil, errors.New("firestore unavailable")).Once()
+	assert.Equal(t, 0, service.DrainOutbox(context.Background()))
+	indexer.AssertNotCalled(t, "SyncAddressToSearch", mock.Anything, mock.Anything)
+}
+
+func TestOutboxService_DrainOutbox_TaxonomyError(t *testing.T) {
+	t.Parallel()
+	service, repo, indexer := setupOutboxService()
+	entry := models.OutboxEntry{ID: "en_1", Language: "en", AddressID: "1"}
+	repo.On("ListDueOutboxEntries", mock.Anything, mock.Anything).Return([]models.OutboxEntry{entry}, nil).Once()
+	indexer.On("ListTaxonomyTags", mock.Anything).Return(nil, errors.New("firestore unavailable")).Once()
+	// the entries stay due for the next drain
+	assert.Equal(t, 0, service.DrainOutbox(context.Background()))
+	indexer.AssertNotCalled(t, "SyncAddressToSearch", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "RescheduleOutboxEntry", mock.Anything, mock.Anything)
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, outboxBaseBackoff, outboxBackoff(1))
+	assert.Equal(t, 2*outboxBaseBackoff, outboxBackoff(2))
+	assert.Equal(t, 8*outboxBaseBackoff, outboxBackoff(4))
+	assert.Equal(t, outboxMaxBackoff, outboxBackoff(30))
+}
+
+func TestOutboxService_ListOutboxEntries(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupOutboxService()
+	repo.On("ListOutboxEntries", mock.Anything, repository.ListOutboxEntriesOptions{
+		Status: models.OutboxStatusFailed,
+		Limit:  maxListOutboxEntries,
+	}).Return([]models.OutboxEntry{{ID: "en_1"}}, nil).Once()
+	output, err := service.ListOutboxEntries(context.Background(), ListOutboxEntriesInput{
+		Status: models.OutboxStatusFailed,
+		Limit:  1000,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, output.Entries, 1)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+const (
+	defaultListPendingAddresses = 50
+	maxListPendingAddresses     = 200
+)
+
+var ErrInvalidPendingAddress = errors.New("invalid pending address")
+
+type ListPendingAddressesInput struct {
+	Language models.Language
+	Limit    int
+}
+
+type ListPendingAddressesOutput struct {
+	Pending []models.PendingAddress
+}
+
+type GetPendingAddressInput struct {
+	ID string
+}
+
+type GetPendingAddressOutput struct {
+	Pending models.PendingAddress
+}
+
+type UpdatePendingAddressInput struct {
+	ID      string
+	Address models.AddressItem
+}
+
+type UpdatePendingAddressOutput struct {
+	Pending models.PendingAddress
+}
+
+type ApprovePendingAddressInput struct {
+	ID    string
+	Actor string
+}
+
+type ApprovePendingAddressOutput struct {
+	Address models.AddressItem
+}
+
+type RejectPendingAddressInput struct {
+	ID string
+}
+
+type RejectPendingAddressOutput struct {
+	ID string
+}
+
+func (s *AddressService) ListPendingAddresses(
+	ctx context.Context,
+	input ListPendingAddressesInput) (*ListPendingAddressesOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListPendingAddresses
+	}
+	pending, err := s.repo.ListPendingAddresses(ctx, repository.ListPendingAddressesOption{
+		Language: input.Language,
+		Limit:    min(limit, maxListPendingAddresses),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ListPendingAddressesOutput{Pending: pending}, nil
+}
+
+func (s *AddressService) GetPendingAddress(
+	ctx context.Context,
+	input GetPendingAddressInput) (*GetPendingAddressOutput, error) {
+	pending, err := s.repo.GetPendingAddress(ctx, repository.GetPendingAddressOption{ID: input.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &GetPendingAddressOutput{Pending: *pending}, nil
+}
+
+func (s *AddressService) UpdatePendingAddress(
+	ctx context.Context,
+	input UpdatePendingAddressInput) (*UpdatePendingAddressOutput, error) {
+	pending, err := s.repo.UpdatePendingAddress(ctx, repository.UpdatePendingAddressOption{
+		ID:      input.ID,
+		Address: input.Address,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UpdatePendingAddressOutput{Pending: *pending}, nil
+}
+
+// Move a reviewed address into the catalog, generated output missing required fields or with tags
+// the taxonomy doesn't know has to be edited first
+func (s *AddressService) ApprovePendingAddress(
+	ctx context.Context,
+	input ApprovePendingAddressInput) (*ApprovePendingAddressOutput, error) {
+	pending, err := s.repo.GetPendingAddress(ctx, repository.GetPendingAddressOption{ID: input.ID})
+	if err != nil {
+		return nil, err
+	}
+	if err := validateImportAddress(pending.Address); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPendingAddress, err)
+	}
+	tags, err := s.canonicalizeTags(ctx, pending.Language, pending.Address.Tags)
+	if errors.Is(err, ErrUnknownTags) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPendingAddress, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	address, err := s.repo.ApprovePendingAddress(ctx, repository.ApprovePendingAddressOption{
+		ID:    input.ID,
+		Tags:  tags,
+		Actor: input.Actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ApprovePendingAddressOutput{Address: *address}, nil
+}
+
+func (s *AddressService) RejectPendingAddress(
+	ctx context.Context,
+	input RejectPendingAddressInput) (*RejectPendingAddressOutput, error) {
+	err := s.repo.RejectPendingAddress(ctx, repository.RejectPendingAddressOption{ID: input.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &RejectPendingAddressOutput{ID: input.ID}, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var reviewedAddress = models.AddressItem{
+	Name:       "Jane Austen",
+	BriefIntro: "Novelist",
+	Tags:       []string{"UK", "writer"},
+	Address: models.Address{
+		City:    "Bath",
+		Country: "UK",
+		Line1:   "4 Sydney Place",
+		Region:  "Somerset",
+	},
+}
+
+func TestAddressService_ListPendingAddresses_Limit(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListPendingAddresses", mock.Anything, repository.ListPendingAddressesOption{Language: "en", Limit: 50}).
+		Return([]models.PendingAddress{{ID: "p1"}}, nil).Once()
+	repo.On("ListPendingAddresses", mock.Anything, repository.ListPendingAddressesOption{Language: "en", Limit: 200}).
+		Return([]models.PendingAddress{}, nil).Once()
+	output, err := service.ListPendingAddresses(context.Background(), ListPendingAddressesInput{Language: "en"})
+	assert.NoError(t, err)
+	assert.Len(t, output.Pending, 1)
+	_, err = service.ListPendingAddresses(context.Background(), ListPendingAddressesInput{Language: "en", Limit: 1000})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ApprovePendingAddress(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
+	repo.On("GetPendingAddress", mock.Anything, repository.GetPendingAddressOption{ID: "p1"}).
+		Return(&models.PendingAddress{ID: "p1", Language: "en", Address: reviewedAddress}, nil).Once()
+	approved := reviewedAddress
+	approved.ID = "a1"
+	repo.On("ApprovePendingAddress", mock.Anything, repository.ApprovePendingAddressOption{
+		ID: "p1", Tags: reviewedAddress.Tags, Actor: "admin-uid",
+	}).
+		Return(&approved, nil).Once()
+	output, err := service.ApprovePendingAddress(context.Background(), ApprovePendingAddressInput{
+		ID:    "p1",
+		Actor: "admin-uid",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a1", output.Address.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_ApprovePendingAddress_Incomplete(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	incomplete := reviewedAddress
+	incomplete.Address.City = ""
+	repo.On("GetPendingAddress", mock.Anything, repository.GetPendingAddressOption{ID: "p1"}).
+		Return(&models.PendingAddress{ID: "p1", Language: "en", Address: incomplete}, nil).Once()
+	output, err := service.ApprovePendingAddress(context.Background(), ApprovePendingAddressInput{ID: "p1"})
+	assert.ErrorIs(t, err, ErrInvalidPendingAddress)
+	assert.Contains(t, err.Error(), "address.city")
+	assert.Nil(t, output)
+	repo.AssertNotCalled(t, "ApprovePendingAddress", mock.Anything, mock.Anything)
+}
+
+func TestAddressService_ApprovePendingAddress_Duplicate(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("ListTaxonomyTags", mock.Anything).Return([]models.Tag{}, nil)
+	repo.On("GetPendingAddress", mock.Anything, mock.Anything).
+		Return(&models.PendingAddress{ID: "p1", Language: "en", Address: reviewedAddress}, nil).Once()
+	repo.On("ApprovePendingAddress", mock.Anything, mock.Anything).
+		Return(nil, repository.ErrDuplicate).Once()
+	_, err := service.ApprovePendingAddress(context.Background(), ApprovePendingAddressInput{ID: "p1"})
+	assert.ErrorIs(t, err, repository.ErrDuplicate)
+	repo.AssertExpectations(t)
+}
+
+func TestAddressService_RejectPendingAddress(t *testing.T) {
+	t.Parallel()
+	service, repo, _ := setupAddressService()
+	repo.On("RejectPendingAddress", mock.Anything, repository.RejectPendingAddressOption{ID: "p1"}).
+		Return(nil).Once()
+	repo.On("RejectPendingAddress", mock.Anything, repository.RejectPendingAddressOption{ID: "missing"}).
+		Return(repository.ErrNotFound).Once()
+	output, err := service.RejectPendingAddress(context.Background(), RejectPendingAddressInput{ID: "p1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", output.ID)
+	_, err = service.RejectPendingAddress(context.Background(), RejectPendingAddressInput{ID: "missing"})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	repo.AssertExpectations(t)
+}
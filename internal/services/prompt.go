@@ -8,8 +8,8 @@ import (
 )
 
 type promptRepository interface {
-	GetSystemPrompt(ctx context.Context, opts repository.GetSystemPromptOptions) (string, error)
-	GetSystemAddressGenerationPrompt(ctx context.Context, opts repository.GetSystemAddressGenerationPromptOptions) (string, error)
+	GetSystemPrompt(ctx context.Context, opts repository.GetSystemPromptOptions) (*repository.SystemPrompt, error)
+	GetSystemAddressGenerationPrompt(ctx context.Context, opts repository.GetSystemAddressGenerationPromptOptions) (*repository.SystemPrompt, error)
 }
 
 type PromptService struct {
@@ -25,7 +25,8 @@ type GetSystemAddressGenerationPromptInput struct {
 }
 
 type GetSystemAddressGenerationPromptOutput struct {
-	Prompt string
+	Language models.Language // language of the prompt, a fallback when the requested language has none
+	Prompt   string
 }
 
 func (p *PromptService) GetSystemAddressGenerationPrompt(
@@ -38,5 +39,5 @@ func (p *PromptService) GetSystemAddressGenerationPrompt(
 	if err != nil {
 		return nil, err
 	}
-	return &GetSystemAddressGenerationPromptOutput{Prompt: prompt}, nil
+	return &GetSystemAddressGenerationPromptOutput{Language: prompt.Language, Prompt: prompt.Prompt}, nil
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/repository"
 	"testing"
 
@@ -16,23 +17,23 @@ type mockPromptRepository struct {
 
 func (m *mockPromptRepository) GetSystemPrompt(
 	ctx context.Context,
-	opts repository.GetSystemPromptOptions) (string, error) {
+	opts repository.GetSystemPromptOptions) (*repository.SystemPrompt, error) {
 	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
-		return "", args.Error(1)
+		return nil, args.Error(1)
 	}
-	return args.String(0), args.Error(1)
+	return args.Get(0).(*repository.SystemPrompt), args.Error(1)
 }
 
 func (m *mockPromptRepository) GetSystemAddressGenerationPrompt(
 	ctx context.Context,
 	opts repository.GetSystemAddressGenerationPromptOptions,
-) (string, error) {
+) (*repository.SystemPrompt, error) {
 	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
-		return "", args.Error(1)
+		return nil, args.Error(1)
 	}
-	return args.String(0), args.Error(1)
+	return args.Get(0).(*repository.SystemPrompt), args.Error(1)
 }
 
 func setupPromptService() (*PromptService, *mockPromptRepository) {
@@ -54,11 +55,12 @@ func TestPromptService_GetSystemAddressGenerationPrompt(t *testing.T) {
 		"GetSystemAddressGenerationPrompt",
 		mock.Anything,
 		mock.Anything,
-	).Return(expectedPrompt, nil).Once()
+	).Return(&repository.SystemPrompt{Language: "en", Prompt: expectedPrompt}, nil).Once()
 	output, err := service.GetSystemAddressGenerationPrompt(ctx, input)
 	repo.AssertExpectations(t)
 	assert.Nil(t, err)
 	assert.Equal(t, output.Prompt, expectedPrompt)
+	assert.Equal(t, models.LanguageEN, output.Language)
 }
 
 func TestPromptService_GetSystemAddressGenerationPrompt_Error(t *testing.T) {
@@ -72,7 +74,7 @@ func TestPromptService_GetSystemAddressGenerationPrompt_Error(t *testing.T) {
 		"GetSystemAddressGenerationPrompt",
 		mock.Anything,
 		mock.Anything,
-	).Return("", errors.New("error")).Once()
+	).Return(nil, errors.New("error")).Once()
 	output, err := service.GetSystemAddressGenerationPrompt(ctx, input)
 	repo.AssertExpectations(t)
 	assert.Error(t, err)
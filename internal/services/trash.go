@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+)
+
+const (
+	trashPurgeInterval    = time.Hour
+	DefaultTrashRetention = 30 * 24 * time.Hour
+)
+
+type trashRepository interface {
+	PurgeDeletedAddresses(ctx context.Context, opts repository.PurgeDeletedAddressesOption) (int, error)
+}
+
+// Permanently removes addresses that stayed in the trash longer than the retention window.
+// Purging is idempotent, so running it on several instances at once is harmless.
+type TrashService struct {
+	repo      trashRepository
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+func NewTrashService(repo trashRepository, retention time.Duration, logger *slog.Logger) *TrashService {
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	return &TrashService{
+		repo:      repo,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// Purge the trash periodically until ctx is canceled
+func (s *TrashService) Run(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	for {
+		s.PurgeExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge the expired addresses of every language, returns how many were removed
+func (s *TrashService) PurgeExpired(ctx context.Context) int {
+	deletedBefore := time.Now().Add(-s.retention).UnixMilli()
+	purged := 0
+	for _, language := range models.Languages().Codes() {
+		count, err := s.repo.PurgeDeletedAddresses(ctx, repository.PurgeDeletedAddressesOption{
+			Language:      language,
+			DeletedBefore: deletedBefore,
+		})
+		if err != nil {
+			s.logger.Warn("failed to purge deleted addresses", "language", language, "error", err)
+			continue
+		}
+		if count > 0 {
+			s.logger.Info("purged deleted addresses", "language", language, "count", count)
+		}
+		purged += count
+	}
+	return purged
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *mockTrashRepository) PurgeDeletedAddresses(
+	ctx context.Context, opts repository.PurgeDeletedAddressesOption) (int, error) {
+	args := m.Called(ctx, opts)
+	return args.Int(0), args.Error(1)
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	t.Parallel()
+	repo := new(mockTrashRepository)
+	retention := 7 * 24 * time.Hour
+	service := NewTrashService(repo, retention, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	before := time.Now().Add(-retention).UnixMilli()
+	matchLanguage := func(language models.Language) interface{} {
+		return mock.MatchedBy(func(opts repository.PurgeDeletedAddressesOption) bool {
+			after := time.Now().Add(-retention).UnixMilli()
+			return opts.Language == language && opts.DeletedBefore >= before && opts.DeletedBefore <= after
+		})
+	}
+	repo.On("PurgeDeletedAddresses", mock.Anything, matchLanguage(models.LanguageZH)).Return(2, nil).Once()
+	// a failing language does not stop the others
+	repo.On("PurgeDeletedAddresses", mock.Anything, matchLanguage(models.LanguageEN)).
+		Return(0, errors.New("firestore unavailable")).Once()
+	assert.Equal(t, 2, service.PurgeExpired(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestNewTrashService_DefaultRetention(t *testing.T) {
+	t.Parallel()
+	service := NewTrashService(new(mockTrashRepository), 0, slog.Default())
+	assert.Equal(t, DefaultTrashRetention, service.retention)
+}
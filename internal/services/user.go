@@ -2,16 +2,27 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/repository"
 )
 
+// Owners can't change their own role, this keeps at least one owner around
+var ErrSelfRoleChange = errors.New("admin users cannot change their own role")
+
 type userRepository interface {
 	SignInAdminUserById(ctx context.Context, opts repository.GetUserByIdOptions) (*models.AdminUser, error)
+	ListAdminUsers(ctx context.Context) ([]models.AdminUser, error)
+	GetAdminRole(ctx context.Context, uid string) (models.AdminRole, error)
+	SetAdminUserRole(ctx context.Context, opts repository.SetAdminUserRoleOptions) (*models.AdminUser, error)
+	DeleteAdminUser(ctx context.Context, uid string) error
 }
 
 type UserService struct {
-	repo userRepository
+	repo   userRepository
+	logger *slog.Logger
 }
 
 type SignInAdminUserByIdInput struct {
@@ -22,8 +33,31 @@ type SignInAdminUserByIdOutput struct {
 	UserData models.AdminUser
 }
 
-func NewUserService(repo userRepository) *UserService {
-	return &UserService{repo: repo}
+type ListAdminUsersOutput struct {
+	Users []models.AdminUser
+}
+
+type GrantAdminRoleInput struct {
+	ActorUid string
+	Uid      string
+	Role     models.AdminRole
+}
+
+type GrantAdminRoleOutput struct {
+	UserData models.AdminUser
+}
+
+type RevokeAdminRoleInput struct {
+	ActorUid string
+	Uid      string
+}
+
+type RevokeAdminRoleOutput struct {
+	Uid string
+}
+
+func NewUserService(repo userRepository, logger *slog.Logger) *UserService {
+	return &UserService{repo: repo, logger: logger}
 }
 
 func (s *UserService) SignInAdminUserById(
@@ -36,3 +70,63 @@ func (s *UserService) SignInAdminUserById(
 	}
 	return &SignInAdminUserByIdOutput{UserData: *adminUserData}, nil
 }
+
+func (s *UserService) ListAdminUsers(ctx context.Context) (*ListAdminUsersOutput, error) {
+	users, err := s.repo.ListAdminUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ListAdminUsersOutput{Users: users}, nil
+}
+
+func (s *UserService) GrantAdminRole(
+	ctx context.Context,
+	input GrantAdminRoleInput) (*GrantAdminRoleOutput, error) {
+	if err := input.Role.Validate(); err != nil {
+		return nil, err
+	}
+	if input.ActorUid == input.Uid {
+		return nil, ErrSelfRoleChange
+	}
+	opts := repository.SetAdminUserRoleOptions{Uid: input.Uid, Role: input.Role}
+	adminUser, err := s.repo.SetAdminUserRole(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &GrantAdminRoleOutput{UserData: *adminUser}, nil
+}
+
+// Grant the owner role to the given users, they must have signed in to the app once.
+// It runs at startup, which is how the first owner gets in before anyone can grant roles.
+// Users that already have an admin record keep their role, an owner demoted through the API stays demoted
+func (s *UserService) BootstrapOwners(ctx context.Context, uids []string) error {
+	for _, uid := range uids {
+		role, err := s.repo.GetAdminRole(ctx, uid)
+		if err != nil {
+			return fmt.Errorf("failed to bootstrap owner %s: %w", uid, err)
+		}
+		if role != "" {
+			if role != models.AdminRoleOwner {
+				s.logger.Warn("bootstrap owner already has an admin role, it is left as is", "uid", uid, "role", role)
+			}
+			continue
+		}
+		opts := repository.SetAdminUserRoleOptions{Uid: uid, Role: models.AdminRoleOwner}
+		if _, err := s.repo.SetAdminUserRole(ctx, opts); err != nil {
+			return fmt.Errorf("failed to bootstrap owner %s: %w", uid, err)
+		}
+	}
+	return nil
+}
+
+func (s *UserService) RevokeAdminRole(
+	ctx context.Context,
+	input RevokeAdminRoleInput) (*RevokeAdminRoleOutput, error) {
+	if input.ActorUid == input.Uid {
+		return nil, ErrSelfRoleChange
+	}
+	if err := s.repo.DeleteAdminUser(ctx, input.Uid); err != nil {
+		return nil, err
+	}
+	return &RevokeAdminRoleOutput{Uid: input.Uid}, nil
+}
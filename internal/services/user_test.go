@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"io"
+	"log/slog"
 	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/repository"
 	"testing"
@@ -25,9 +27,37 @@ func (m *mockUserRepository) SignInAdminUserById(
 	return args.Get(0).(*models.AdminUser), args.Error(1)
 }
 
+func (m *mockUserRepository) ListAdminUsers(ctx context.Context) ([]models.AdminUser, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AdminUser), args.Error(1)
+}
+
+func (m *mockUserRepository) GetAdminRole(ctx context.Context, uid string) (models.AdminRole, error) {
+	args := m.Called(ctx, uid)
+	return args.Get(0).(models.AdminRole), args.Error(1)
+}
+
+func (m *mockUserRepository) SetAdminUserRole(
+	ctx context.Context,
+	opts repository.SetAdminUserRoleOptions) (*models.AdminUser, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AdminUser), args.Error(1)
+}
+
+func (m *mockUserRepository) DeleteAdminUser(ctx context.Context, uid string) error {
+	args := m.Called(ctx, uid)
+	return args.Error(0)
+}
+
 func setupUserService() (*UserService, *mockUserRepository) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	return service, repo
 }
 
@@ -76,3 +106,108 @@ func TestUserService_SignInAdminUserById_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, output)
 }
+
+func TestUserService_ListAdminUsers(t *testing.T) {
+	t.Parallel()
+	service, repo := setupUserService()
+	users := []models.AdminUser{{Uid: "owner-1", Role: models.AdminRoleOwner}}
+	repo.On("ListAdminUsers", mock.Anything).Return(users, nil).Once()
+	output, err := service.ListAdminUsers(context.Background())
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, users, output.Users)
+}
+
+func TestUserService_GrantAdminRole(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		input       GrantAdminRoleInput
+		expectCall  bool
+		repoError   error
+		expectedErr error
+	}{
+		{
+			name:       "success",
+			input:      GrantAdminRoleInput{ActorUid: "owner-1", Uid: "user-2", Role: models.AdminRoleEditor},
+			expectCall: true,
+		},
+		{
+			name:        "self role change",
+			input:       GrantAdminRoleInput{ActorUid: "owner-1", Uid: "owner-1", Role: models.AdminRoleViewer},
+			expectCall:  false,
+			expectedErr: ErrSelfRoleChange,
+		},
+		{
+			name:       "invalid role",
+			input:      GrantAdminRoleInput{ActorUid: "owner-1", Uid: "user-2", Role: "root"},
+			expectCall: false,
+		},
+		{
+			name:       "repository error",
+			input:      GrantAdminRoleInput{ActorUid: "owner-1", Uid: "user-2", Role: models.AdminRoleOwner},
+			expectCall: true,
+			repoError:  errors.New("firestore unavailable"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := setupUserService()
+			if tt.expectCall {
+				opts := repository.SetAdminUserRoleOptions{Uid: tt.input.Uid, Role: tt.input.Role}
+				var adminUser *models.AdminUser
+				if tt.repoError == nil {
+					adminUser = &models.AdminUser{Uid: tt.input.Uid, Role: tt.input.Role}
+				}
+				repo.On("SetAdminUserRole", mock.Anything, opts).Return(adminUser, tt.repoError).Once()
+			}
+			output, err := service.GrantAdminRole(context.Background(), tt.input)
+			repo.AssertExpectations(t)
+			if tt.expectCall && tt.repoError == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.input.Role, output.UserData.Role)
+				return
+			}
+			assert.Error(t, err)
+			assert.Nil(t, output)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestUserService_RevokeAdminRole(t *testing.T) {
+	t.Parallel()
+	service, repo := setupUserService()
+	repo.On("DeleteAdminUser", mock.Anything, "user-2").Return(nil).Once()
+	output, err := service.RevokeAdminRole(context.Background(),
+		RevokeAdminRoleInput{ActorUid: "owner-1", Uid: "user-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user-2", output.Uid)
+
+	output, err = service.RevokeAdminRole(context.Background(),
+		RevokeAdminRoleInput{ActorUid: "owner-1", Uid: "owner-1"})
+	assert.ErrorIs(t, err, ErrSelfRoleChange)
+	assert.Nil(t, output)
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_BootstrapOwners(t *testing.T) {
+	t.Parallel()
+	service, repo := setupUserService()
+	repo.On("GetAdminRole", mock.Anything, "owner-1").Return(models.AdminRole(""), nil).Once()
+	repo.On("SetAdminUserRole", mock.Anything, repository.SetAdminUserRoleOptions{Uid: "owner-1", Role: models.AdminRoleOwner}).
+		Return(&models.AdminUser{Uid: "owner-1", Role: models.AdminRoleOwner}, nil).Once()
+	// an owner demoted through the API is not promoted again
+	repo.On("GetAdminRole", mock.Anything, "demoted").Return(models.AdminRoleViewer, nil).Once()
+	repo.On("GetAdminRole", mock.Anything, "missing").Return(models.AdminRole(""), nil).Once()
+	repo.On("SetAdminUserRole", mock.Anything, repository.SetAdminUserRoleOptions{Uid: "missing", Role: models.AdminRoleOwner}).
+		Return(nil, errors.New("user not found")).Once()
+	assert.NoError(t, service.BootstrapOwners(context.Background(), []string{"owner-1", "demoted"}))
+	err := service.BootstrapOwners(context.Background(), []string{"missing"})
+	assert.ErrorContains(t, err, "missing")
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "SetAdminUserRole", mock.Anything, repository.SetAdminUserRoleOptions{
+		Uid: "demoted", Role: models.AdminRoleOwner})
+}
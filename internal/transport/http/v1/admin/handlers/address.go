@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
 	"north-post/service/internal/services"
 	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
 	"north-post/service/internal/transport/http/v1/utils"
 	"strconv"
 	"strings"
@@ -20,26 +25,69 @@ type addressService interface {
 	GetAddresses(ctx context.Context, input services.GetAddressesInput) (*services.GetAddressesOutput, error)
 	UpdateAddress(ctx context.Context, input services.UpdateAddressInput) (*services.UpdateAddressOutput, error)
 	DeleteAddress(ctx context.Context, input services.DeleteAddressInput) (*services.DeleteAddressOutput, error)
-	RefreshTags(ctx context.Context, input services.RefreshTagsInput) (*services.RefreshTagsOutput, error)
+	RestoreAddress(ctx context.Context, input services.RestoreAddressInput) (*services.RestoreAddressOutput, error)
+	ListDeletedAddresses(
+		ctx context.Context, input services.ListDeletedAddressesInput) (*services.ListDeletedAddressesOutput, error)
+	ListAddressRevisions(
+		ctx context.Context, input services.ListAddressRevisionsInput) (*services.ListAddressRevisionsOutput, error)
+	DiffAddressRevisions(
+		ctx context.Context, input services.DiffAddressRevisionsInput) (*services.DiffAddressRevisionsOutput, error)
+	RollbackAddress(ctx context.Context, input services.RollbackAddressInput) (*services.RollbackAddressOutput, error)
+	ImportAddresses(ctx context.Context, input services.ImportAddressesInput) (*services.ImportAddressesOutput, error)
+	ExportAddresses(
+		ctx context.Context, input services.ExportAddressesInput, w io.Writer) (*services.ExportAddressesOutput, error)
 	GetAllTags(ctx context.Context, input services.GetAllTagsInput) (*services.GetAllTagsOutput, error)
-	SyncToTypesense(ctx context.Context, input services.SyncToTypesenseInput) (*services.SyncToTypesenseOutput, error)
+	ListTaxonomyTags(ctx context.Context, input services.ListTaxonomyTagsInput) (*services.ListTaxonomyTagsOutput, error)
+	CreateTaxonomyTag(ctx context.Context, input services.CreateTaxonomyTagInput) (*services.CreateTaxonomyTagOutput, error)
+	UpdateTaxonomyTag(ctx context.Context, input services.UpdateTaxonomyTagInput) (*services.UpdateTaxonomyTagOutput, error)
+	DeleteTaxonomyTag(ctx context.Context, input services.DeleteTaxonomyTagInput) (*services.DeleteTaxonomyTagOutput, error)
+	ListPendingAddresses(
+		ctx context.Context, input services.ListPendingAddressesInput) (*services.ListPendingAddressesOutput, error)
+	GetPendingAddress(
+		ctx context.Context, input services.GetPendingAddressInput) (*services.GetPendingAddressOutput, error)
+	UpdatePendingAddress(
+		ctx context.Context, input services.UpdatePendingAddressInput) (*services.UpdatePendingAddressOutput, error)
+	ApprovePendingAddress(
+		ctx context.Context, input services.ApprovePendingAddressInput) (*services.ApprovePendingAddressOutput, error)
+	RejectPendingAddress(
+		ctx context.Context, input services.RejectPendingAddressInput) (*services.RejectPendingAddressOutput, error)
+	ListDuplicateClusters(
+		ctx context.Context, input services.ListDuplicateClustersInput) (*services.ListDuplicateClustersOutput, error)
+	MergeAddresses(ctx context.Context, input services.MergeAddressesInput) (*services.MergeAddressesOutput, error)
+	LinkAddressTranslations(
+		ctx context.Context, input services.LinkAddressTranslationsInput) (*services.LinkAddressTranslationsOutput, error)
+	UnlinkAddressTranslation(
+		ctx context.Context, input services.UnlinkAddressTranslationInput) (*services.UnlinkAddressTranslationOutput, error)
+	ListMissingTranslations(
+		ctx context.Context, input services.ListMissingTranslationsInput) (*services.ListMissingTranslationsOutput, error)
+	TranslateAddresses(
+		ctx context.Context, input services.TranslateAddressesInput) (*services.TranslateAddressesOutput, error)
+}
+
+// Upper bound of an import file
+const maxImportSize = 10 << 20
+
+type jobSubmitter interface {
+	SubmitJob(ctx context.Context, input services.SubmitJobInput) (*services.SubmitJobOutput, error)
 }
 
 type AddressHandler struct {
 	service addressService
+	jobs    jobSubmitter
 	logger  *slog.Logger
 }
 
-func NewAddressHandler(service addressService, logger *slog.Logger) *AddressHandler {
+func NewAddressHandler(service addressService, jobs jobSubmitter, logger *slog.Logger) *AddressHandler {
 	return &AddressHandler{
 		service: service,
+		jobs:    jobs,
 		logger:  logger,
 	}
 }
 
 // GetAddresses godoc
 // @Summary Get addresses
-// @Description Get addresses by language, keywords and optional tag filters
+// @Description Get addresses by language, keywords and optional tag filters. filters narrows by tag category, any tag within a category and every category has to match. facets count the matching addresses per tag of each category, sort is updated (default), relevance or name. An unknown sort or category is rejected with 400
 // @Tags Admin Address
 // @Accept json
 // @Produce json
@@ -57,13 +105,19 @@ func (h *AddressHandler) GetAddresses(c *gin.Context) {
 		return
 	}
 	input := services.GetAddressesInput{
-		Language: req.Language,
-		Keywords: req.Keywords,
-		Tags:     req.Tags,
-		PageSize: req.PageSize,
-		Page:     req.Page,
+		Language:   req.Language,
+		Keywords:   req.Keywords,
+		Tags:       req.Tags,
+		TagFilters: req.Filters,
+		Sort:       req.Sort,
+		PageSize:   req.PageSize,
+		Page:       req.Page,
 	}
 	output, err := h.service.GetAddresses(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidSearch) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to get addresses", "error", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
@@ -77,13 +131,14 @@ func (h *AddressHandler) GetAddresses(c *gin.Context) {
 
 // CreateNewAddress godoc
 // @Summary Create a new address
-// @Description Create a new address entry with language-specific information
+// @Description Create a new address entry with language-specific information. Tags are names or synonyms of the tag taxonomy and are stored as its display names, unknown tags are rejected with 400. It is rejected with 409 when the catalog already has the same place, by normalized name with similar tags, a close name or the same street address
 // @Tags Admin Address
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateAddressRequest true "Request body"
 // @Success 200 {object} dto.CreateAddressResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /admin/address [put]
 func (h *AddressHandler) CreateNewAddress(c *gin.Context) {
@@ -97,8 +152,19 @@ func (h *AddressHandler) CreateNewAddress(c *gin.Context) {
 	input := services.CreateNewAddressInput{
 		Language: req.Language,
 		Address:  dto.FromCreateAddressDTO(req),
+		Actor:    c.GetString(middleware.UidKey),
 	}
 	output, err := h.service.CreateNewAddress(c.Request.Context(), input)
+	if errors.Is(err, services.ErrUnknownTags) {
+		h.logger.Warn("address with unknown tags rejected", "name", req.Name, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrDuplicate) {
+		h.logger.Warn("duplicate address rejected", "name", req.Name, "error", err)
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to create new address", "address", req, "error", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
@@ -113,13 +179,18 @@ func (h *AddressHandler) CreateNewAddress(c *gin.Context) {
 // UpdateAddress updates an existing address entry with language-specific information.
 //
 // @Summary Update an existing address
-// @Description Update an existing address entry with language-specific information
+// @Description Update an existing address entry with language-specific information. Tags are checked against the tag taxonomy like on creation. The update only succeeds when the address is unchanged since the client read it, identified by the If-Match header or else by address.updatedAt. A stale update returns 409 with the current version
 // @Tags Admin Address
 // @Accept json
 // @Produce json
+// @Param If-Match header string false "ETag of the edited version, takes precedence over address.updatedAt"
 // @Param request body dto.UpdateAddressRequest true "Request body"
 // @Success 200 {object} dto.UpdateAddressResponse
+// @Header 200,409 {string} ETag "Version of the returned address"
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.UpdateAddressConflictResponse
+// @Failure 428 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /admin/address/update [post]
 func (h *AddressHandler) UpdateAddress(c *gin.Context) {
@@ -130,17 +201,46 @@ func (h *AddressHandler) UpdateAddress(c *gin.Context) {
 	if !utils.ValidateLanguage(c, req.Language, h.logger) {
 		return
 	}
+	expectedUpdatedAt, ok := h.getExpectedUpdatedAt(c, req)
+	if !ok {
+		return
+	}
 	input := services.UpdateAddressInput{
-		Language: req.Language,
-		ID:       req.ID,
-		Address:  dto.FromUpdateAddressDTO(req),
+		Language:          req.Language,
+		ID:                req.ID,
+		Address:           dto.FromUpdateAddressDTO(req),
+		Actor:             c.GetString(middleware.UidKey),
+		ExpectedUpdatedAt: expectedUpdatedAt,
 	}
 	output, err := h.service.UpdateAddress(c.Request.Context(), input)
+	if errors.Is(err, services.ErrUnknownTags) {
+		h.logger.Warn("address with unknown tags rejected", "id", req.ID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Address not found"})
+		return
+	}
+	var conflict *repository.AddressConflictError
+	if errors.As(err, &conflict) {
+		h.logger.Warn("stale address update rejected",
+			"id", req.ID,
+			"expectedUpdatedAt", expectedUpdatedAt,
+			"currentUpdatedAt", conflict.Current.UpdatedAt)
+		setAddressETag(c, conflict.Current)
+		c.JSON(http.StatusConflict, dto.UpdateAddressConflictResponse{
+			Error: "Address was changed by someone else, review the current version and retry",
+			Data:  dto.ToAddressDTO(conflict.Current),
+		})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to update address", "address", req, "error", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
+	setAddressETag(c, output.Address)
 	response := dto.UpdateAddressResponse{
 		Data: dto.ToAddressDTO(output.Address),
 	}
@@ -149,7 +249,7 @@ func (h *AddressHandler) UpdateAddress(c *gin.Context) {
 
 // DeleteAddress godoc
 // @Summary Delete an address
-// @Description Delete an existing address entry by ID and language
+// @Description Move an address to the trash by ID and language. It is hidden from search and lookups, and can be restored until it is purged after the retention window
 // @Tags Admin Address
 // @Accept json
 // @Produce json
@@ -157,6 +257,7 @@ func (h *AddressHandler) UpdateAddress(c *gin.Context) {
 // @Param language query string true "Language code (e.g., en, zh)"
 // @Success 200 {object} dto.DeleteAddressResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /admin/address/{id} [delete]
 func (h *AddressHandler) DeleteAddress(c *gin.Context) {
@@ -179,8 +280,13 @@ func (h *AddressHandler) DeleteAddress(c *gin.Context) {
 	input := services.DeleteAddressInput{
 		Language: language,
 		ID:       id,
+		Actor:    c.GetString(middleware.UidKey),
 	}
 	output, err := h.service.DeleteAddress(c.Request.Context(), input)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Address not found"})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to delete address", "id", id, "error", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
@@ -190,9 +296,340 @@ func (h *AddressHandler) DeleteAddress(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// RestoreAddress godoc
+// @Summary Restore a deleted address
+// @Description Take an address out of the trash, it is searchable again once the search index catches up
+// @Tags Admin Address
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Success 200 {object} dto.RestoreAddressResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/{id}/restore [post]
+func (h *AddressHandler) RestoreAddress(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	input := services.RestoreAddressInput{
+		Language: language,
+		ID:       id,
+		Actor:    c.GetString(middleware.UidKey),
+	}
+	output, err := h.service.RestoreAddress(c.Request.Context(), input)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Address not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrNotInTrash) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Address is not in the trash"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to restore address", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	response := dto.RestoreAddressResponse{Data: dto.ToAddressDTO(output.Address)}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListDeletedAddresses godoc
+// @Summary List deleted addresses
+// @Description List the addresses in the trash of the specified language, most recently deleted first
+// @Tags Admin Address
+// @Produce json
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Param limit query int false "Number of addresses, 50 by default and at most 200"
+// @Success 200 {object} dto.ListDeletedAddressesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/trash [get]
+func (h *AddressHandler) ListDeletedAddresses(c *gin.Context) {
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	limit, ok := utils.QueryLimit(c, h.logger)
+	if !ok {
+		return
+	}
+	input := services.ListDeletedAddressesInput{
+		Language: language,
+		Limit:    limit,
+	}
+	output, err := h.service.ListDeletedAddresses(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to list deleted addresses", "language", language, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	response := dto.ListDeletedAddressesResponse{Data: dto.ToAddressDTOs(output.Addresses)}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListAddressRevisions godoc
+// @Summary List address revisions
+// @Description List the recorded changes of an address, newest first. Every revision carries the admin who made it, a field-level diff and a snapshot of the address after the change
+// @Tags Admin Address
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Param limit query int false "Number of revisions, 20 by default and at most 100"
+// @Success 200 {object} dto.ListAddressRevisionsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/{id}/revisions [get]
+func (h *AddressHandler) ListAddressRevisions(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	limit, ok := utils.QueryLimit(c, h.logger)
+	if !ok {
+		return
+	}
+	input := services.ListAddressRevisionsInput{
+		Language: language,
+		ID:       id,
+		Limit:    limit,
+	}
+	output, err := h.service.ListAddressRevisions(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to list address revisions", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	response := dto.ListAddressRevisionsResponse{Data: dto.ToAddressRevisionDTOs(output.Revisions)}
+	c.JSON(http.StatusOK, response)
+}
+
+// DiffAddressRevisions godoc
+// @Summary Compare two address revisions
+// @Description Field-level changes from one revision of an address to another
+// @Tags Admin Address
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Param from query int true "Revision number to compare from"
+// @Param to query int true "Revision number to compare to"
+// @Success 200 {object} dto.DiffAddressRevisionsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/{id}/revisions/diff [get]
+func (h *AddressHandler) DiffAddressRevisions(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	from, fromErr := parseRevision(c.Query("from"))
+	to, toErr := parseRevision(c.Query("to"))
+	if fromErr != nil || toErr != nil {
+		h.logger.Warn("invalid revision parameters", "from", c.Query("from"), "to", c.Query("to"))
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid from or to revision"})
+		return
+	}
+	input := services.DiffAddressRevisionsInput{
+		Language: language,
+		ID:       id,
+		From:     from,
+		To:       to,
+	}
+	output, err := h.service.DiffAddressRevisions(c.Request.Context(), input)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Revision not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to diff address revisions", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	response := dto.DiffAddressRevisionsResponse{Data: dto.ToRevisionDiffDTO(output)}
+	c.JSON(http.StatusOK, response)
+}
+
+// RollbackAddress godoc
+// @Summary Roll back an address
+// @Description Bring an address back to the content of an earlier revision. The rollback is recorded as a new revision and the search index is updated in the background
+// @Tags Admin Address
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param revision path int true "Revision number to roll back to"
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Success 200 {object} dto.RollbackAddressResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/{id}/revisions/{revision}/rollback [post]
+func (h *AddressHandler) RollbackAddress(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	revision, err := parseRevision(c.Param("revision"))
+	if err != nil {
+		h.logger.Warn("invalid revision parameter", "revision", c.Param("revision"))
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid revision"})
+		return
+	}
+	input := services.RollbackAddressInput{
+		Language: language,
+		ID:       id,
+		Revision: revision,
+		Actor:    c.GetString(middleware.UidKey),
+	}
+	output, err := h.service.RollbackAddress(c.Request.Context(), input)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Address or revision not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to roll back address", "id", id, "revision", revision, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("address rolled back", "actor", input.Actor, "id", id, "revision", revision)
+	response := dto.RollbackAddressResponse{Data: dto.ToAddressDTO(output.Address)}
+	c.JSON(http.StatusOK, response)
+}
+
+// ImportAddresses godoc
+// @Summary Import addresses in bulk
+// @Description Create addresses from a CSV or JSONL file sent as the request body. Every record is validated and checked for duplicates like a created address, against the catalog and the earlier rows of the file, the response reports the outcome of each row. CSV files need a header with the JSON field names of an address (name, briefIntro, tags, address.city, ...) and separate tags with "|". With dryRun=true nothing is written
+// @Tags Admin Address
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Param format query string false "csv or jsonl, taken from the Content-Type when omitted"
+// @Param dryRun query bool false "Only validate and report"
+// @Success 200 {object} dto.ImportAddressesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/import [post]
+func (h *AddressHandler) ImportAddresses(c *gin.Context) {
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	format := models.AddressImportFormat(strings.ToLower(strings.TrimSpace(c.Query("format"))))
+	if format == "" {
+		format = getImportFormat(c.ContentType())
+	}
+	if err := format.Validate(); err != nil {
+		h.logger.Warn("invalid import format", "format", format, "contentType", c.ContentType())
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Format must be csv or jsonl"})
+		return
+	}
+	dryRunStr := strings.TrimSpace(c.Query("dryRun"))
+	dryRun, err := strconv.ParseBool(dryRunStr)
+	if err != nil && dryRunStr != "" {
+		h.logger.Warn("failed to parse dryRun query to boolean", "query", dryRunStr)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid dryRun parameter"})
+		return
+	}
+	input := services.ImportAddressesInput{
+		Language: language,
+		Format:   format,
+		Data:     http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize),
+		DryRun:   dryRun,
+		Actor:    c.GetString(middleware.UidKey),
+	}
+	output, err := h.service.ImportAddresses(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidImport) {
+		h.logger.Warn("rejected address import", "error", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to import addresses", "language", language, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("addresses imported",
+		"actor", input.Actor,
+		"language", language,
+		"dryRun", dryRun,
+		"created", output.Summary.Created,
+		"total", output.Summary.Total)
+	c.JSON(http.StatusOK, dto.ImportAddressesResponse{Data: dto.ToImportAddressesResultDTO(output)})
+}
+
+// ExportAddresses godoc
+// @Summary Export addresses in bulk
+// @Description Stream the address catalog of a language as JSONL or CSV, or of every language as a zip of JSONL files. The CSV uses the same columns as the import. Deleted addresses are not exported
+// @Tags Admin Address
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Produce application/zip
+// @Param language query string false "Language code (e.g., en, zh), required unless format is zip"
+// @Param format query string false "jsonl (default), csv or zip"
+// @Param tags query string false "Comma separated tags, an address matching any of them is exported"
+// @Param updatedSince query int false "Only export addresses updated after this Unix time in milliseconds"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/export [get]
+func (h *AddressHandler) ExportAddresses(c *gin.Context) {
+	format := models.AddressExportFormat(strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "jsonl"))))
+	if err := format.Validate(); err != nil {
+		h.logger.Warn("invalid export format", "format", format)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Format must be jsonl, csv or zip"})
+		return
+	}
+	var language models.Language
+	if format != models.AddressExportFormatZip {
+		var ok bool
+		if language, ok = utils.QueryLanguage(c, h.logger); !ok {
+			return
+		}
+	}
+	updatedSinceStr := strings.TrimSpace(c.Query("updatedSince"))
+	updatedSince, err := strconv.ParseInt(updatedSinceStr, 10, 64)
+	if (err != nil && updatedSinceStr != "") || updatedSince < 0 {
+		h.logger.Warn("failed to parse updatedSince query", "query", updatedSinceStr)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid updatedSince parameter"})
+		return
+	}
+	input := services.ExportAddressesInput{
+		Language:     language,
+		Format:       format,
+		Tags:         getExportTags(c.Query("tags")),
+		UpdatedSince: updatedSince,
+	}
+	c.Header("Content-Type", getExportContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", services.ExportFileName(language, format)))
+	c.Status(http.StatusOK)
+	output, err := h.service.ExportAddresses(c.Request.Context(), input, c.Writer)
+	if err != nil {
+		h.logger.Error("failed to export addresses", "language", language, "format", format, "error", err)
+		// once the stream started the status is sent, the truncated body is all the client gets
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+	h.logger.Info("addresses exported",
+		"actor", c.GetString(middleware.UidKey),
+		"language", language,
+		"format", format,
+		"count", output.Count)
+}
+
 // GenerateNewAddress godoc
 // @Summary Generate new address suggestions
-// @Description Uses LLM to generate new address suggestions based on prompts and reasoning effort
+// @Description Uses LLM to generate new address suggestions based on prompts and reasoning effort. The suggestions are stored in the review queue together with the generation settings, approve them to add them to the catalog
 // @Tags Admin Address
 // @Accept json
 // @Produce json
@@ -216,6 +653,7 @@ func (h *AddressHandler) GenerateNewAddress(c *gin.Context) {
 		Model:           req.Model,
 		ReasoningEffort: req.ReasoningEffort,
 		ThinkingLevel:   req.ThinkingLevel,
+		Actor:           c.GetString(middleware.UidKey),
 	}
 	output, err := h.service.GenerateNewAddress(c.Request.Context(), input)
 	if err != nil {
@@ -224,14 +662,14 @@ func (h *AddressHandler) GenerateNewAddress(c *gin.Context) {
 		return
 	}
 	response := dto.GenerateNewAddressResponse{
-		Data: dto.ToAddressDTOs(output.Addresses),
+		Data: dto.ToPendingAddressDTOs(output.Pending),
 	}
 	c.JSON(http.StatusOK, response)
 }
 
 // GetAllTags godoc
-// @Summary Get or refresh address tags
-// @Description Scans all addresses in the specified language and refreshes the tag collection with unique tags from all categories (country, role, figure)
+// @Summary Get address tags
+// @Description Get the tag collection of the specified language, rebuild it with POST /admin/address/tags/refresh
 // @Tags Admin Address
 // @Accept json
 // @Produce json
@@ -242,7 +680,6 @@ func (h *AddressHandler) GenerateNewAddress(c *gin.Context) {
 // @Router /admin/address/tags [get]
 func (h *AddressHandler) GetAllTags(c *gin.Context) {
 	languageStr := strings.TrimSpace(c.Query("language"))
-	refreshStr := strings.TrimSpace(c.Query("refresh"))
 	if languageStr == "" {
 		h.logger.Warn("missing language parameter")
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Language is required"})
@@ -252,43 +689,48 @@ func (h *AddressHandler) GetAllTags(c *gin.Context) {
 	if !utils.ValidateLanguage(c, language, h.logger) {
 		return
 	}
-	shouldRefresh, err := strconv.ParseBool(refreshStr)
-	if err != nil && refreshStr != "" {
-		h.logger.Error("failed to parse refresh query to boolean", "query", refreshStr)
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid refresh parameter"})
+	input := services.GetAllTagsInput{Language: language}
+	output, err := h.service.GetAllTags(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to get all tags", "language", language, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
-	var response dto.GetAllTagsResponse
-	if shouldRefresh {
-		input := services.RefreshTagsInput{Language: language}
-		output, err := h.service.RefreshTags(c.Request.Context(), input)
-		if err != nil {
-			h.logger.Error("failed to refresh tags", "language", language, "error", err)
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
-			return
-		}
-		response = dto.GetAllTagsResponse{Data: dto.ToTagsRecordDTO(output.TagsRecord, language)}
-	} else {
-		input := services.GetAllTagsInput{Language: language}
-		output, err := h.service.GetAllTags(c.Request.Context(), input)
-		if err != nil {
-			h.logger.Error("failed to get all tags", "language", language, "error", err)
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
-			return
-		}
-		response = dto.GetAllTagsResponse{Data: dto.ToTagsRecordDTO(output.TagsRecord, language)}
-	}
+	response := dto.GetAllTagsResponse{Data: dto.ToTagsRecordDTO(output.TagsRecord, language)}
 	c.JSON(http.StatusOK, response)
 }
 
+// RefreshTags godoc
+// @Summary Refresh address tags
+// @Description Starts a background job that rescans all addresses of the language and rebuilds the unique tags of every category (country, role, figure), the category of a tag comes from the tag taxonomy and tags it doesn't know are left out. Poll the returned job for progress
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTagsRequest true "Request body"
+// @Success 202 {object} dto.JobResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/tags/refresh [post]
+func (h *AddressHandler) RefreshTags(c *gin.Context) {
+	var req dto.RefreshTagsRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateLanguage(c, req.Language, h.logger) {
+		return
+	}
+	params := map[string]string{models.JobParamLanguage: req.Language.Get()}
+	h.submitJob(c, models.JobTypeTagsRefresh, params)
+}
+
 // SyncToTypesense godoc
 // @Summary Sync addresses to Typesense
-// @Description Syncs all addresses for the specified language from Firestore to the Typesense search index
+// @Description Starts a background job that syncs the addresses of the specified language from Firestore to the Typesense search index. Only changes since the last sync are applied unless full is set, poll the returned job for progress
 // @Tags Admin Address
 // @Accept json
 // @Produce json
 // @Param request body dto.SyncToTypesenseRequest true "Request body"
-// @Success 200 {object} dto.SyncToTypesenseResponse
+// @Success 202 {object} dto.JobResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /admin/address/sync [post]
@@ -300,13 +742,93 @@ func (h *AddressHandler) SyncToTypesense(c *gin.Context) {
 	if !utils.ValidateLanguage(c, req.Language, h.logger) {
 		return
 	}
-	input := services.SyncToTypesenseInput{Language: req.Language}
-	output, err := h.service.SyncToTypesense(c.Request.Context(), input)
+	params := map[string]string{
+		models.JobParamLanguage: req.Language.Get(),
+		models.JobParamFullSync: strconv.FormatBool(req.Full),
+	}
+	h.submitJob(c, models.JobTypeTypesenseSync, params)
+}
+
+// ---------- Helper methods ----------
+
+// The version an update is based on, from If-Match or else from the updatedAt of the edited address
+func (h *AddressHandler) getExpectedUpdatedAt(c *gin.Context, req dto.UpdateAddressRequest) (int64, bool) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch != "" {
+		updatedAt, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+		if err != nil || updatedAt <= 0 {
+			h.logger.Warn("invalid If-Match header", "ifMatch", ifMatch)
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid If-Match header"})
+			return 0, false
+		}
+		return updatedAt, true
+	}
+	if req.Address.UpdatedAt <= 0 {
+		h.logger.Warn("update without a version", "id", req.ID)
+		c.JSON(http.StatusPreconditionRequired, dto.ErrorResponse{
+			Error: "address.updatedAt or an If-Match header is required",
+		})
+		return 0, false
+	}
+	return req.Address.UpdatedAt, true
+}
+
+func setAddressETag(c *gin.Context, address models.AddressItem) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(address.UpdatedAt, 10)))
+}
+
+func getImportFormat(contentType string) models.AddressImportFormat {
+	switch contentType {
+	case "text/csv":
+		return models.AddressImportFormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return models.AddressImportFormatJSONL
+	}
+	return ""
+}
+
+func getExportContentType(format models.AddressExportFormat) string {
+	switch format {
+	case models.AddressExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case models.AddressExportFormatZip:
+		return "application/zip"
+	}
+	return "application/x-ndjson"
+}
+
+func getExportTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func parseRevision(value string) (int64, error) {
+	revision, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
 	if err != nil {
-		h.logger.Error("failed to sync to Typesense", "language", req.Language, "error", err)
+		return 0, err
+	}
+	if revision <= 0 {
+		return 0, fmt.Errorf("revision must be positive: %d", revision)
+	}
+	return revision, nil
+}
+
+func (h *AddressHandler) submitJob(c *gin.Context, jobType models.JobType, params map[string]string) {
+	input := services.SubmitJobInput{
+		Type:      jobType,
+		Params:    params,
+		CreatedBy: c.GetString(middleware.UidKey),
+	}
+	output, err := h.jobs.SubmitJob(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to submit job", "type", jobType, "params", params, "error", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
-	response := dto.SyncToTypesenseResponse{Data: dto.ToSyncToTypesenseDTO(output)}
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusAccepted, dto.JobResponse{Data: dto.ToJobDTO(output.Job)})
 }
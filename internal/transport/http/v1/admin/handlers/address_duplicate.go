@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDuplicateClusters godoc
+// @Summary List suspected duplicate addresses
+// @Description Scan the catalog of the specified language for addresses that describe the same place, grouped into clusters. Addresses share a cluster when they have the same normalized name and similar tags, or the same normalized street address. Largest clusters first
+// @Tags Admin Address
+// @Produce json
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Success 200 {object} dto.ListDuplicateClustersResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/duplicates [get]
+func (h *AddressHandler) ListDuplicateClusters(c *gin.Context) {
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	input := services.ListDuplicateClustersInput{Language: language}
+	output, err := h.service.ListDuplicateClusters(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to list duplicate addresses", "language", language, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	response := dto.ListDuplicateClustersResponse{Data: dto.ToDuplicateClusterDTOs(output.Clusters)}
+	c.JSON(http.StatusOK, response)
+}
+
+// MergeAddresses godoc
+// @Summary Merge duplicate addresses
+// @Description Keep the survivor and move the duplicates to the trash, each of them records the survivor in mergedInto. The old IDs keep resolving to the survivor, saved addresses of app users are rewritten to it and the duplicates are removed from search. The survivor takes over the translation group of a linked duplicate, the merge is rejected when the survivor is linked to another group. A merge that failed while rewriting saved addresses can be sent again
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.MergeAddressesRequest true "Request body"
+// @Success 200 {object} dto.MergeAddressesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/merge [post]
+func (h *AddressHandler) MergeAddresses(c *gin.Context) {
+	var req dto.MergeAddressesRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateLanguage(c, req.Language, h.logger) {
+		return
+	}
+	input := services.MergeAddressesInput{
+		Language:     req.Language,
+		SurvivorID:   req.SurvivorID,
+		DuplicateIDs: req.DuplicateIDs,
+		Actor:        c.GetString(middleware.UidKey),
+	}
+	output, err := h.service.MergeAddresses(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidMerge) {
+		h.logger.Warn("rejected address merge", "request", req, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrTranslationConflict) {
+		h.logger.Warn("addresses not merged", "request", req, "error", err)
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to merge addresses", "request", req, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("addresses merged",
+		"actor", input.Actor,
+		"language", req.Language,
+		"survivorID", output.Survivor.ID,
+		"mergedIDs", output.MergedIDs,
+		"migratedUsers", output.MigratedUsers)
+	c.JSON(http.StatusOK, dto.MergeAddressesResponse{Data: dto.MergeAddressesResultDTO{
+		Survivor:      dto.ToAddressDTO(output.Survivor),
+		MergedIDs:     output.MergedIDs,
+		MigratedUsers: output.MigratedUsers,
+	}})
+}
+
+// BackfillDuplicateKeys godoc
+// @Summary Backfill the duplicate detector keys
+// @Description Starts a background job that writes the normalized name and street address keys on the addresses of the language written before the duplicate detector existed, so that it finds them by normalized name and street address. The addresses are otherwise unchanged, poll the returned job for progress
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.BackfillDuplicateKeysRequest true "Request body"
+// @Success 202 {object} dto.JobResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/duplicates/backfill [post]
+func (h *AddressHandler) BackfillDuplicateKeys(c *gin.Context) {
+	var req dto.BackfillDuplicateKeysRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateLanguage(c, req.Language, h.logger) {
+		return
+	}
+	params := map[string]string{models.JobParamLanguage: req.Language.Get()}
+	h.submitJob(c, models.JobTypeDuplicateKeysBackfill, params)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListDuplicateClusters(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	mockSrv.On("ListDuplicateClusters", mock.Anything, services.ListDuplicateClustersInput{Language: "en"}).
+		Return(&services.ListDuplicateClustersOutput{Clusters: []models.DuplicateCluster{{
+			ID:      "a1",
+			Reasons: []models.DuplicateReason{models.DuplicateReasonName},
+			Addresses: []models.AddressItem{
+				{ID: "a1", Name: "Sherlock Holmes Museum"},
+				{ID: "a2", Name: "The Sherlock Holmes Museum"},
+			},
+		}}}, nil).Once()
+	req, _ := http.NewRequest("GET", "/admin/address/duplicates?language=en", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListDuplicateClustersResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Len(t, response.Data[0].Addresses, 2)
+	assert.Equal(t, []models.DuplicateReason{models.DuplicateReasonName}, response.Data[0].Reasons)
+	mockSrv.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/admin/address/duplicates?language=abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMergeAddresses(t *testing.T) {
+	t.Parallel()
+	validBody := dto.MergeAddressesRequest{Language: "en", SurvivorID: "a1", DuplicateIDs: []string{"a2"}}
+	tests := []struct {
+		name           string
+		body           dto.MergeAddressesRequest
+		mockOutput     *services.MergeAddressesOutput
+		mockError      error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: validBody,
+			mockOutput: &services.MergeAddressesOutput{
+				Survivor:      models.AddressItem{ID: "a1"},
+				MergedIDs:     []string{"a2"},
+				MigratedUsers: 3,
+			},
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid merge",
+			body:           validBody,
+			mockError:      fmt.Errorf("%w: the survivor can't be merged into itself", services.ErrInvalidMerge),
+			expectCall:     true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown address",
+			body:           validBody,
+			mockError:      fmt.Errorf("failed to merge addresses into a1: %w", repository.ErrNotFound),
+			expectCall:     true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "survivor linked to another translation group",
+			body:           validBody,
+			mockError:      fmt.Errorf("failed to merge addresses into a1: %w", repository.ErrTranslationConflict),
+			expectCall:     true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failed request",
+			body:           validBody,
+			mockError:      errors.New("firestore unavailable"),
+			expectCall:     true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "missing survivor",
+			body:           dto.MergeAddressesRequest{Language: "en", DuplicateIDs: []string{"a2"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid language",
+			body:           dto.MergeAddressesRequest{Language: "abc", SurvivorID: "a1", DuplicateIDs: []string{"a2"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectCall {
+				mockSrv.On("MergeAddresses", mock.Anything, services.MergeAddressesInput{
+					Language:     tt.body.Language,
+					SurvivorID:   tt.body.SurvivorID,
+					DuplicateIDs: tt.body.DuplicateIDs,
+				}).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/admin/address/merge", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.MergeAddressesResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "a1", response.Data.Survivor.ID)
+				assert.Equal(t, []string{"a2"}, response.Data.MergedIDs)
+				assert.Equal(t, 3, response.Data.MigratedUsers)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackfillDuplicateKeys(t *testing.T) {
+	t.Parallel()
+	mockJobs := new(MockJobSubmitter)
+	router := setupRouter(NewAddressHandler(new(MockAddressService), mockJobs, slog.Default()))
+	mockJobs.On("SubmitJob", mock.Anything, services.SubmitJobInput{
+		Type:   models.JobTypeDuplicateKeysBackfill,
+		Params: map[string]string{models.JobParamLanguage: "en"},
+	}).Return(&services.SubmitJobOutput{Job: models.Job{ID: "job-1", Type: models.JobTypeDuplicateKeysBackfill}}, nil).Once()
+
+	req, _ := http.NewRequest("POST", "/admin/address/duplicates/backfill", bytes.NewBufferString(`{"language":"en"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var response dto.JobResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "job-1", response.Data.ID)
+
+	req, _ = http.NewRequest("POST", "/admin/address/duplicates/backfill", bytes.NewBufferString(`{"language":"fr"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockJobs.AssertExpectations(t)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTaxonomyTags godoc
+// @Summary List the tag taxonomy
+// @Description List the tags addresses can carry with their category, display name and synonyms per language, ordered by category and slug
+// @Tags Admin Address
+// @Produce json
+// @Param category query string false "Only tags of this category (country, role, figure)"
+// @Success 200 {object} dto.ListTaxonomyTagsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/taxonomy [get]
+func (h *AddressHandler) ListTaxonomyTags(c *gin.Context) {
+	input := services.ListTaxonomyTagsInput{
+		Category: models.TagCategory(strings.TrimSpace(c.Query("category"))),
+	}
+	output, err := h.service.ListTaxonomyTags(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidTag) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to list taxonomy tags", "category", input.Category, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListTaxonomyTagsResponse{Data: dto.ToTaxonomyTagDTOs(output.Tags)})
+}
+
+// CreateTaxonomyTag godoc
+// @Summary Create a taxonomy tag
+// @Description Add a tag to the taxonomy. Names and synonyms are keyed by language code and resolve to the tag when an address is saved. It is rejected with 409 when the slug is taken or a name or synonym already belongs to another tag
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTaxonomyTagRequest true "Request body"
+// @Success 200 {object} dto.TaxonomyTagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/taxonomy [post]
+func (h *AddressHandler) CreateTaxonomyTag(c *gin.Context) {
+	var req dto.CreateTaxonomyTagRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	input := services.CreateTaxonomyTagInput{Tag: dto.FromCreateTaxonomyTagDTO(req)}
+	output, err := h.service.CreateTaxonomyTag(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidTag) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) || errors.Is(err, repository.ErrDuplicate) {
+		h.logger.Warn("taxonomy tag not created", "request", req, "error", err)
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to create taxonomy tag", "request", req, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("taxonomy tag created", "actor", c.GetString(middleware.UidKey), "slug", output.Tag.Slug)
+	c.JSON(http.StatusOK, dto.TaxonomyTagResponse{Data: dto.ToTaxonomyTagDTO(output.Tag)})
+}
+
+// UpdateTaxonomyTag godoc
+// @Summary Update a taxonomy tag
+// @Description Replace the category, names and synonyms of a tag. A replaced name is kept as a synonym so addresses that carry it still resolve, run the tag migration to rewrite them. It is rejected with 409 when a name or synonym already belongs to another tag
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param slug path string true "Tag slug"
+// @Param request body dto.UpdateTaxonomyTagRequest true "Request body"
+// @Success 200 {object} dto.TaxonomyTagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/taxonomy/{slug} [put]
+func (h *AddressHandler) UpdateTaxonomyTag(c *gin.Context) {
+	var req dto.UpdateTaxonomyTagRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	input := services.UpdateTaxonomyTagInput{Tag: dto.FromUpdateTaxonomyTagDTO(c.Param("slug"), req)}
+	output, err := h.service.UpdateTaxonomyTag(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidTag) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Tag not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrDuplicate) {
+		h.logger.Warn("taxonomy tag not updated", "slug", c.Param("slug"), "error", err)
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to update taxonomy tag", "slug", c.Param("slug"), "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("taxonomy tag updated", "actor", c.GetString(middleware.UidKey), "slug", output.Tag.Slug)
+	c.JSON(http.StatusOK, dto.TaxonomyTagResponse{Data: dto.ToTaxonomyTagDTO(output.Tag)})
+}
+
+// DeleteTaxonomyTag godoc
+// @Summary Delete a taxonomy tag
+// @Description Remove a tag from the taxonomy. Addresses that carry it keep the tag, it is reported as unknown by the tag migration and left out of the tags record
+// @Tags Admin Address
+// @Produce json
+// @Param slug path string true "Tag slug"
+// @Success 200 {object} dto.DeleteTaxonomyTagResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/taxonomy/{slug} [delete]
+func (h *AddressHandler) DeleteTaxonomyTag(c *gin.Context) {
+	input := services.DeleteTaxonomyTagInput{Slug: c.Param("slug")}
+	output, err := h.service.DeleteTaxonomyTag(c.Request.Context(), input)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Tag not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to delete taxonomy tag", "slug", input.Slug, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("taxonomy tag deleted", "actor", c.GetString(middleware.UidKey), "slug", output.Slug)
+	c.JSON(http.StatusOK, dto.DeleteTaxonomyTagResponse{Data: dto.TaxonomyTagSlug{Slug: output.Slug}})
+}
+
+// MigrateAddressTags godoc
+// @Summary Migrate address tags to the taxonomy
+// @Description Starts a background job that rewrites the tags of every address of the language to the display names of their taxonomy tags, then rebuilds the tags record. With seed=true tags the taxonomy doesn't know yet are added to it first, categorized by their position in the address (country, role, figure). Unknown tags are kept on the addresses, poll the returned job for progress
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.MigrateAddressTagsRequest true "Request body"
+// @Success 202 {object} dto.JobResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/taxonomy/migrate [post]
+func (h *AddressHandler) MigrateAddressTags(c *gin.Context) {
+	var req dto.MigrateAddressTagsRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateLanguage(c, req.Language, h.logger) {
+		return
+	}
+	params := map[string]string{
+		models.JobParamLanguage: req.Language.Get(),
+		models.JobParamSeedTags: strconv.FormatBool(req.Seed),
+		models.JobParamActor:    c.GetString(middleware.UidKey),
+	}
+	h.submitJob(c, models.JobTypeTagsMigration, params)
+}
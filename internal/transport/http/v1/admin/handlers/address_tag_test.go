@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var taxonomyTag = models.Tag{
+	Slug:     "writer",
+	Category: models.TagCategoryRole,
+	Names:    map[string]string{"en": "Writer", "zh": "作家"},
+	Synonyms: map[string][]string{"en": {"Author"}},
+}
+
+func TestListTaxonomyTags(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	mockSrv.On("ListTaxonomyTags", mock.Anything, services.ListTaxonomyTagsInput{Category: models.TagCategoryRole}).
+		Return(&services.ListTaxonomyTagsOutput{Tags: []models.Tag{taxonomyTag}}, nil).Once()
+	mockSrv.On("ListTaxonomyTags", mock.Anything, services.ListTaxonomyTagsInput{Category: "city"}).
+		Return(nil, fmt.Errorf("%w: unsupported tag category: city", services.ErrInvalidTag)).Once()
+	req, _ := http.NewRequest("GET", "/admin/address/taxonomy?category=role", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListTaxonomyTagsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "作家", response.Data[0].Names["zh"])
+
+	req, _ = http.NewRequest("GET", "/admin/address/taxonomy?category=city", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSrv.AssertExpectations(t)
+}
+
+func TestCreateTaxonomyTag(t *testing.T) {
+	t.Parallel()
+	validBody := `{"category":"role","names":{"en":"Writer","zh":"作家"},"synonyms":{"en":["Author"]}}`
+	expectedInput := services.CreateTaxonomyTagInput{Tag: models.Tag{
+		Category: models.TagCategoryRole,
+		Names:    taxonomyTag.Names,
+		Synonyms: taxonomyTag.Synonyms,
+	}}
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			body:           validBody,
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing names",
+			body:           `{"category":"role"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid tag",
+			body:           validBody,
+			mockError:      fmt.Errorf("%w: unsupported tag category: city", services.ErrInvalidTag),
+			expectCall:     true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "slug taken",
+			body:           validBody,
+			mockError:      fmt.Errorf("tag writer: %w", repository.ErrConflict),
+			expectCall:     true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "name of another tag",
+			body:           validBody,
+			mockError:      fmt.Errorf(`en "author" is used by tag novelist: %w`, repository.ErrDuplicate),
+			expectCall:     true,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectCall {
+				var output *services.CreateTaxonomyTagOutput
+				if tt.mockError == nil {
+					output = &services.CreateTaxonomyTagOutput{Tag: taxonomyTag}
+				}
+				mockSrv.On("CreateTaxonomyTag", mock.Anything, expectedInput).Return(output, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", "/admin/address/taxonomy", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.TaxonomyTagResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "writer", response.Data.Slug)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateAndDeleteTaxonomyTag(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	mockSrv.On("UpdateTaxonomyTag", mock.Anything, services.UpdateTaxonomyTagInput{Tag: models.Tag{
+		Slug:     "missing",
+		Category: models.TagCategoryRole,
+		Names:    map[string]string{"en": "Missing"},
+	}}).Return(nil, fmt.Errorf("tag missing: %w", repository.ErrNotFound)).Once()
+	mockSrv.On("DeleteTaxonomyTag", mock.Anything, services.DeleteTaxonomyTagInput{Slug: "writer"}).
+		Return(&services.DeleteTaxonomyTagOutput{Slug: "writer"}, nil).Once()
+
+	body := `{"category":"role","names":{"en":"Missing"}}`
+	req, _ := http.NewRequest("PUT", "/admin/address/taxonomy/missing", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/admin/address/taxonomy/writer", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.DeleteTaxonomyTagResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "writer", response.Data.Slug)
+	mockSrv.AssertExpectations(t)
+}
+
+func TestMigrateAddressTags(t *testing.T) {
+	t.Parallel()
+	mockJobs := new(MockJobSubmitter)
+	router := setupRouter(NewAddressHandler(new(MockAddressService), mockJobs, slog.Default()))
+	mockJobs.On("SubmitJob", mock.Anything, services.SubmitJobInput{
+		Type: models.JobTypeTagsMigration,
+		Params: map[string]string{
+			models.JobParamLanguage: "en",
+			models.JobParamSeedTags: "true",
+			models.JobParamActor:    "",
+		},
+	}).Return(&services.SubmitJobOutput{Job: models.Job{ID: "job-1", Type: models.JobTypeTagsMigration}}, nil).Once()
+
+	req, _ := http.NewRequest("POST", "/admin/address/taxonomy/migrate",
+		bytes.NewBufferString(`{"language":"en","seed":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var response dto.JobResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "job-1", response.Data.ID)
+
+	req, _ = http.NewRequest("POST", "/admin/address/taxonomy/migrate", bytes.NewBufferString(`{"language":"fr"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockJobs.AssertExpectations(t)
+}
@@ -5,12 +5,16 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
 	"north-post/service/internal/services"
 	"north-post/service/internal/transport/http/v1/dto"
+	"strconv"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -58,12 +62,81 @@ func (m *MockAddressService) DeleteAddress(
 	args := m.Called(ctx, input)
 	return args.Get(0).(*services.DeleteAddressOutput), args.Error(1)
 }
-func (m *MockAddressService) RefreshTags(
+func (m *MockAddressService) RestoreAddress(
 	ctx context.Context,
-	input services.RefreshTagsInput,
-) (*services.RefreshTagsOutput, error) {
+	input services.RestoreAddressInput,
+) (*services.RestoreAddressOutput, error) {
 	args := m.Called(ctx, input)
-	return args.Get(0).(*services.RefreshTagsOutput), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.RestoreAddressOutput), args.Error(1)
+}
+func (m *MockAddressService) ListDeletedAddresses(
+	ctx context.Context,
+	input services.ListDeletedAddressesInput,
+) (*services.ListDeletedAddressesOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListDeletedAddressesOutput), args.Error(1)
+}
+func (m *MockAddressService) ListAddressRevisions(
+	ctx context.Context,
+	input services.ListAddressRevisionsInput,
+) (*services.ListAddressRevisionsOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListAddressRevisionsOutput), args.Error(1)
+}
+func (m *MockAddressService) DiffAddressRevisions(
+	ctx context.Context,
+	input services.DiffAddressRevisionsInput,
+) (*services.DiffAddressRevisionsOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DiffAddressRevisionsOutput), args.Error(1)
+}
+func (m *MockAddressService) RollbackAddress(
+	ctx context.Context,
+	input services.RollbackAddressInput,
+) (*services.RollbackAddressOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.RollbackAddressOutput), args.Error(1)
+}
+func (m *MockAddressService) ImportAddresses(
+	ctx context.Context,
+	input services.ImportAddressesInput,
+) (*services.ImportAddressesOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ImportAddressesOutput), args.Error(1)
+}
+
+// Writes the body given to Return before returning
+func (m *MockAddressService) ExportAddresses(
+	ctx context.Context,
+	input services.ExportAddressesInput,
+	w io.Writer,
+) (*services.ExportAddressesOutput, error) {
+	args := m.Called(ctx, input, w)
+	if body := args.String(0); body != "" {
+		io.WriteString(w, body)
+	}
+	if args.Get(1) == nil {
+		return nil, args.Error(2)
+	}
+	return args.Get(1).(*services.ExportAddressesOutput), args.Error(2)
 }
 func (m *MockAddressService) GetAllTags(
 	ctx context.Context,
@@ -72,26 +145,215 @@ func (m *MockAddressService) GetAllTags(
 	args := m.Called(ctx, input)
 	return args.Get(0).(*services.GetAllTagsOutput), args.Error(1)
 }
-func (m *MockAddressService) SyncToTypesense(
+
+func (m *MockAddressService) ListTaxonomyTags(
+	ctx context.Context,
+	input services.ListTaxonomyTagsInput,
+) (*services.ListTaxonomyTagsOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListTaxonomyTagsOutput), args.Error(1)
+}
+
+func (m *MockAddressService) CreateTaxonomyTag(
+	ctx context.Context,
+	input services.CreateTaxonomyTagInput,
+) (*services.CreateTaxonomyTagOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.CreateTaxonomyTagOutput), args.Error(1)
+}
+
+func (m *MockAddressService) UpdateTaxonomyTag(
+	ctx context.Context,
+	input services.UpdateTaxonomyTagInput,
+) (*services.UpdateTaxonomyTagOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.UpdateTaxonomyTagOutput), args.Error(1)
+}
+
+func (m *MockAddressService) DeleteTaxonomyTag(
+	ctx context.Context,
+	input services.DeleteTaxonomyTagInput,
+) (*services.DeleteTaxonomyTagOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DeleteTaxonomyTagOutput), args.Error(1)
+}
+
+// MockJobSubmitter records the jobs submitted by AddressHandler.
+type MockJobSubmitter struct {
+	mock.Mock
+}
+
+func (m *MockJobSubmitter) SubmitJob(
+	ctx context.Context,
+	input services.SubmitJobInput,
+) (*services.SubmitJobOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SubmitJobOutput), args.Error(1)
+}
+
+func (m *MockAddressService) ListPendingAddresses(
+	ctx context.Context,
+	input services.ListPendingAddressesInput,
+) (*services.ListPendingAddressesOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListPendingAddressesOutput), args.Error(1)
+}
+func (m *MockAddressService) GetPendingAddress(
+	ctx context.Context,
+	input services.GetPendingAddressInput,
+) (*services.GetPendingAddressOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.GetPendingAddressOutput), args.Error(1)
+}
+func (m *MockAddressService) UpdatePendingAddress(
+	ctx context.Context,
+	input services.UpdatePendingAddressInput,
+) (*services.UpdatePendingAddressOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.UpdatePendingAddressOutput), args.Error(1)
+}
+func (m *MockAddressService) ApprovePendingAddress(
+	ctx context.Context,
+	input services.ApprovePendingAddressInput,
+) (*services.ApprovePendingAddressOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ApprovePendingAddressOutput), args.Error(1)
+}
+func (m *MockAddressService) RejectPendingAddress(
+	ctx context.Context,
+	input services.RejectPendingAddressInput,
+) (*services.RejectPendingAddressOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.RejectPendingAddressOutput), args.Error(1)
+}
+
+func (m *MockAddressService) ListDuplicateClusters(
+	ctx context.Context,
+	input services.ListDuplicateClustersInput,
+) (*services.ListDuplicateClustersOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListDuplicateClustersOutput), args.Error(1)
+}
+func (m *MockAddressService) MergeAddresses(
 	ctx context.Context,
-	input services.SyncToTypesenseInput,
-) (*services.SyncToTypesenseOutput, error) {
+	input services.MergeAddressesInput,
+) (*services.MergeAddressesOutput, error) {
 	args := m.Called(ctx, input)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*services.SyncToTypesenseOutput), args.Error(1)
+	return args.Get(0).(*services.MergeAddressesOutput), args.Error(1)
+}
+
+func (m *MockAddressService) LinkAddressTranslations(
+	ctx context.Context,
+	input services.LinkAddressTranslationsInput,
+) (*services.LinkAddressTranslationsOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.LinkAddressTranslationsOutput), args.Error(1)
+}
+func (m *MockAddressService) UnlinkAddressTranslation(
+	ctx context.Context,
+	input services.UnlinkAddressTranslationInput,
+) (*services.UnlinkAddressTranslationOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.UnlinkAddressTranslationOutput), args.Error(1)
+}
+func (m *MockAddressService) ListMissingTranslations(
+	ctx context.Context,
+	input services.ListMissingTranslationsInput,
+) (*services.ListMissingTranslationsOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListMissingTranslationsOutput), args.Error(1)
+}
+
+func (m *MockAddressService) TranslateAddresses(
+	ctx context.Context,
+	input services.TranslateAddressesInput,
+) (*services.TranslateAddressesOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TranslateAddressesOutput), args.Error(1)
 }
 
 func setupRouter(handler *AddressHandler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	r.GET("/admin/address/tags", handler.GetAllTags)
+	r.POST("/admin/address/tags/refresh", handler.RefreshTags)
+	r.GET("/admin/address/taxonomy", handler.ListTaxonomyTags)
+	r.POST("/admin/address/taxonomy", handler.CreateTaxonomyTag)
+	r.POST("/admin/address/taxonomy/migrate", handler.MigrateAddressTags)
+	r.POST("/admin/address/duplicates/backfill", handler.BackfillDuplicateKeys)
+	r.PUT("/admin/address/taxonomy/:slug", handler.UpdateTaxonomyTag)
+	r.DELETE("/admin/address/taxonomy/:slug", handler.DeleteTaxonomyTag)
+	r.GET("/admin/address/trash", handler.ListDeletedAddresses)
+	r.GET("/admin/address/:id/revisions", handler.ListAddressRevisions)
+	r.GET("/admin/address/:id/revisions/diff", handler.DiffAddressRevisions)
 	r.POST("/admin/address", handler.GetAddresses)
 	r.POST("/admin/address/generate", handler.GenerateNewAddress)
 	r.POST("/admin/address/update", handler.UpdateAddress)
+	r.GET("/admin/address/export", handler.ExportAddresses)
+	r.POST("/admin/address/import", handler.ImportAddresses)
 	r.POST("/admin/address/sync", handler.SyncToTypesense)
+	r.POST("/admin/address/:id/restore", handler.RestoreAddress)
+	r.POST("/admin/address/:id/revisions/:revision/rollback", handler.RollbackAddress)
 	r.PUT("/admin/address", handler.CreateNewAddress)
+	r.GET("/admin/address/duplicates", handler.ListDuplicateClusters)
+	r.POST("/admin/address/merge", handler.MergeAddresses)
+	r.GET("/admin/address/translations/missing", handler.ListMissingTranslations)
+	r.POST("/admin/address/translations/link", handler.LinkAddressTranslations)
+	r.POST("/admin/address/translations/unlink", handler.UnlinkAddressTranslation)
+	r.POST("/admin/address/translate", handler.TranslateAddresses)
+	r.GET("/admin/address/pending", handler.ListPendingAddresses)
+	r.GET("/admin/address/pending/:id", handler.GetPendingAddress)
+	r.PUT("/admin/address/pending/:id", handler.UpdatePendingAddress)
+	r.POST("/admin/address/pending/:id/approve", handler.ApprovePendingAddress)
+	r.DELETE("/admin/address/pending/:id", handler.RejectPendingAddress)
 	r.DELETE("/admin/address/:id", handler.DeleteAddress)
 	return r
 }
@@ -99,7 +361,7 @@ func setupRouter(handler *AddressHandler) *gin.Engine {
 func TestGetAllAddresses(t *testing.T) {
 	t.Parallel()
 	mockSvc := new(MockAddressService)
-	handler := NewAddressHandler(mockSvc, slog.Default())
+	handler := NewAddressHandler(mockSvc, new(MockJobSubmitter), slog.Default())
 	router := setupRouter(handler)
 	tests := []struct {
 		name           string
@@ -175,7 +437,7 @@ func TestGetAllAddresses(t *testing.T) {
 func TestCreateNewAddress(t *testing.T) {
 	t.Parallel()
 	mockSvc := new(MockAddressService)
-	handler := NewAddressHandler(mockSvc, slog.Default())
+	handler := NewAddressHandler(mockSvc, new(MockJobSubmitter), slog.Default())
 	router := setupRouter(handler)
 	tests := []struct {
 		name           string
@@ -198,6 +460,13 @@ func TestCreateNewAddress(t *testing.T) {
 			mockError:      errors.New("error"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:           "duplicate",
+			language:       "en",
+			mockOutput:     &services.CreateNewAddressOutput{},
+			mockError:      fmt.Errorf("%w: address 'Test Address' matches 'The Test Address' (name)", repository.ErrDuplicate),
+			expectedStatus: http.StatusConflict,
+		},
 		{
 			name:           "invalid language",
 			language:       "abc",
@@ -247,7 +516,7 @@ func TestCreateNewAddress(t *testing.T) {
 func TestGenerateNewAddress(t *testing.T) {
 	t.Parallel()
 	mockSrv := new(MockAddressService)
-	handler := NewAddressHandler(mockSrv, slog.Default())
+	handler := NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default())
 	router := setupRouter(handler)
 	tests := []struct {
 		name           string
@@ -264,8 +533,8 @@ func TestGenerateNewAddress(t *testing.T) {
 				Prompt:   "sys",
 			},
 			mockOutput: &services.GenerateAddressOutput{
-				Addresses: []models.AddressItem{
-					{ID: "1", Name: "Test Address"},
+				Pending: []models.PendingAddress{
+					{ID: "p1", Language: "en", Address: models.AddressItem{Name: "Test Address"}},
 				},
 			},
 			mockError:      nil,
@@ -326,13 +595,14 @@ func TestGenerateNewAddress(t *testing.T) {
 func TestUpdateAddress(t *testing.T) {
 	t.Parallel()
 	mockSrv := new(MockAddressService)
-	handler := NewAddressHandler(mockSrv, slog.Default())
+	handler := NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default())
 	router := setupRouter(handler)
 	mockAddressItem := dto.AddressItemDTO{
 		ID:         "1",
 		Name:       "Test Address",
 		BriefIntro: "Test intro",
 		Tags:       []string{"a", "b"},
+		UpdatedAt:  1718000000000,
 		Address: dto.AddressDTO{
 			City:    "test",
 			Country: "test",
@@ -340,26 +610,62 @@ func TestUpdateAddress(t *testing.T) {
 			Region:  "test",
 		},
 	}
+	unversionedItem := mockAddressItem
+	unversionedItem.UpdatedAt = 0
 	tests := []struct {
-		name           string
-		body           dto.UpdateAddressRequest
-		mockOutput     *services.UpdateAddressOutput
-		mockError      error
-		expectedStatus int
+		name              string
+		body              dto.UpdateAddressRequest
+		ifMatch           string
+		expectedUpdatedAt int64
+		mockOutput        *services.UpdateAddressOutput
+		mockError         error
+		expectedStatus    int
 	}{
 		{
-			name:           "success",
-			body:           dto.UpdateAddressRequest{Language: "EN", ID: "1", Address: mockAddressItem},
-			mockOutput:     &services.UpdateAddressOutput{Address: models.AddressItem{ID: "1"}},
-			mockError:      nil,
-			expectedStatus: http.StatusOK,
+			name:              "success",
+			body:              dto.UpdateAddressRequest{Language: "EN", ID: "1", Address: mockAddressItem},
+			expectedUpdatedAt: 1718000000000,
+			mockOutput:        &services.UpdateAddressOutput{Address: models.AddressItem{ID: "1", UpdatedAt: 1718000000500}},
+			mockError:         nil,
+			expectedStatus:    http.StatusOK,
 		},
 		{
-			name:           "service error",
+			name:              "If-Match takes precedence",
+			body:              dto.UpdateAddressRequest{Language: "EN", ID: "1", Address: unversionedItem},
+			ifMatch:           `W/"1717000000000"`,
+			expectedUpdatedAt: 1717000000000,
+			mockOutput:        &services.UpdateAddressOutput{Address: models.AddressItem{ID: "1", UpdatedAt: 1718000000500}},
+			mockError:         nil,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:              "service error",
+			body:              dto.UpdateAddressRequest{Language: "EN", ID: "1", Address: mockAddressItem},
+			expectedUpdatedAt: 1718000000000,
+			mockOutput:        nil,
+			mockError:         errors.New("update failed"),
+			expectedStatus:    http.StatusInternalServerError,
+		},
+		{
+			name:              "stale version",
+			body:              dto.UpdateAddressRequest{Language: "EN", ID: "1", Address: mockAddressItem},
+			expectedUpdatedAt: 1718000000000,
+			mockOutput:        nil,
+			mockError: fmt.Errorf("failed to update address with ID 1: %w", &repository.AddressConflictError{
+				Current: models.AddressItem{ID: "1", Name: "Edited elsewhere", UpdatedAt: 1718000000900},
+			}),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "missing version",
+			body:           dto.UpdateAddressRequest{Language: "EN", ID: "1", Address: unversionedItem},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:           "invalid If-Match",
 			body:           dto.UpdateAddressRequest{Language: "EN", ID: "1", Address: mockAddressItem},
-			mockOutput:     nil,
-			mockError:      errors.New("update failed"),
-			expectedStatus: http.StatusInternalServerError,
+			ifMatch:        `"latest"`,
+			expectedStatus: http.StatusBadRequest,
 		},
 		{
 			name:           "invalid language",
@@ -379,17 +685,30 @@ func TestUpdateAddress(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			input := services.UpdateAddressInput{
-				Language: tt.body.Language,
-				ID:       tt.body.ID,
-				Address:  dto.FromUpdateAddressDTO(tt.body),
+				Language:          tt.body.Language,
+				ID:                tt.body.ID,
+				Address:           dto.FromUpdateAddressDTO(tt.body),
+				ExpectedUpdatedAt: tt.expectedUpdatedAt,
 			}
 			mockSrv.On("UpdateAddress", mock.Anything, input).
 				Return(tt.mockOutput, tt.mockError).Once()
 			body, _ := json.Marshal(tt.body)
 			req, _ := http.NewRequest("POST", "/admin/address/update", bytes.NewBuffer(body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			switch tt.expectedStatus {
+			case http.StatusOK:
+				assert.Equal(t, `"1718000000500"`, w.Header().Get("ETag"))
+			case http.StatusConflict:
+				var response dto.UpdateAddressConflictResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "Edited elsewhere", response.Data.Name)
+				assert.Equal(t, `"1718000000900"`, w.Header().Get("ETag"))
+			}
 			if tt.mockOutput != nil {
 				mockSrv.AssertExpectations(t)
 			}
@@ -400,7 +719,7 @@ func TestUpdateAddress(t *testing.T) {
 func TestDeleteAddress(t *testing.T) {
 	t.Parallel()
 	mockSrv := new(MockAddressService)
-	handler := NewAddressHandler(mockSrv, slog.Default())
+	handler := NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default())
 	router := setupRouter(handler)
 	tests := []struct {
 		name           string
@@ -423,6 +742,13 @@ func TestDeleteAddress(t *testing.T) {
 			mockError:      errors.New("failed"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:           "address not found",
+			url:            "/admin/address/1?language=en",
+			mockOutput:     nil,
+			mockError:      fmt.Errorf("address 1: %w", repository.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name:           "missing id",
 			url:            "/admin/address/ ?language=en",
@@ -461,68 +787,526 @@ func TestDeleteAddress(t *testing.T) {
 	}
 }
 
-func TestGetAllTags_RefreshTags(t *testing.T) {
+func TestRestoreAddress(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		url            string
+		mockOutput     *services.RestoreAddressOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			url:            "/admin/address/1/restore?language=en",
+			mockOutput:     &services.RestoreAddressOutput{Address: models.AddressItem{ID: "1", Name: "restored"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "address not found",
+			url:            "/admin/address/1/restore?language=en",
+			mockError:      fmt.Errorf("address 1: %w", repository.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "address not in the trash",
+			url:            "/admin/address/1/restore?language=en",
+			mockError:      fmt.Errorf("address 1: %w", repository.ErrNotInTrash),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failed request",
+			url:            "/admin/address/1/restore?language=en",
+			mockError:      errors.New("failed"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "missing language",
+			url:            "/admin/address/1/restore",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.mockOutput != nil || tt.mockError != nil {
+				input := services.RestoreAddressInput{Language: "en", ID: "1"}
+				mockSrv.On("RestoreAddress", mock.Anything, input).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.RestoreAddressResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "restored", response.Data.Name)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListDeletedAddresses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		url            string
+		expectedInput  *services.ListDeletedAddressesInput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			url:            "/admin/address/trash?language=en&limit=10",
+			expectedInput:  &services.ListDeletedAddressesInput{Language: "en", Limit: 10},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failed request",
+			url:            "/admin/address/trash?language=en",
+			expectedInput:  &services.ListDeletedAddressesInput{Language: "en"},
+			mockError:      errors.New("failed"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "invalid language",
+			url:            "/admin/address/trash?language=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			url:            "/admin/address/trash?language=en&limit=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectedInput != nil {
+				var output *services.ListDeletedAddressesOutput
+				if tt.mockError == nil {
+					output = &services.ListDeletedAddressesOutput{
+						Addresses: []models.AddressItem{{ID: "1", DeletedAt: 1718000000000}},
+					}
+				}
+				mockSrv.On("ListDeletedAddresses", mock.Anything, *tt.expectedInput).Return(output, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.ListDeletedAddressesResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.Data, 1)
+				assert.Equal(t, int64(1718000000000), response.Data[0].DeletedAt)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListAddressRevisions(t *testing.T) {
 	t.Parallel()
 	mockSrv := new(MockAddressService)
-	handler := NewAddressHandler(mockSrv, slog.Default())
-	router := setupRouter(handler)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	input := services.ListAddressRevisionsInput{Language: "en", ID: "1", Limit: 5}
+	output := &services.ListAddressRevisionsOutput{Revisions: []models.AddressRevision{{
+		Number:  2,
+		Action:  models.RevisionActionUpdate,
+		Actor:   "admin-uid",
+		Changes: []models.FieldChange{{Field: "name", Before: "old", After: "new"}},
+	}}}
+	mockSrv.On("ListAddressRevisions", mock.Anything, input).Return(output, nil).Once()
+	req, _ := http.NewRequest("GET", "/admin/address/1/revisions?language=en&limit=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListAddressRevisionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "admin-uid", response.Data[0].Actor)
+	assert.Equal(t, "name", response.Data[0].Changes[0].Field)
+	mockSrv.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/admin/address/1/revisions", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDiffAddressRevisions(t *testing.T) {
+	t.Parallel()
 	tests := []struct {
 		name           string
 		url            string
-		mockOutput     *services.RefreshTagsOutput
+		mockOutput     *services.DiffAddressRevisionsOutput
 		mockError      error
 		expectedStatus int
 	}{
 		{
 			name: "success",
-			url:  "/admin/address/tags?language=zh&refresh=true",
-			mockOutput: &services.RefreshTagsOutput{TagsRecord: models.TagsRecord{
-				RefreshedAt: 123,
-				Tags:        map[string][]string{"test": {"test", "test2"}}},
+			url:  "/admin/address/1/revisions/diff?language=en&from=1&to=3",
+			mockOutput: &services.DiffAddressRevisionsOutput{
+				From:    1,
+				To:      3,
+				Changes: []models.FieldChange{{Field: "address.city", Before: "Paris", After: "Lyon"}},
 			},
-			mockError:      nil,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "revision not found",
+			url:            "/admin/address/1/revisions/diff?language=en&from=1&to=3",
+			mockError:      fmt.Errorf("revision 3 of address 1: %w", repository.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing revision",
+			url:            "/admin/address/1/revisions/diff?language=en&from=1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid revision",
+			url:            "/admin/address/1/revisions/diff?language=en&from=0&to=3",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.mockOutput != nil || tt.mockError != nil {
+				input := services.DiffAddressRevisionsInput{Language: "en", ID: "1", From: 1, To: 3}
+				mockSrv.On("DiffAddressRevisions", mock.Anything, input).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.DiffAddressRevisionsResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, int64(3), response.Data.To)
+				assert.Equal(t, "Lyon", response.Data.Changes[0].After)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRollbackAddress(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		url            string
+		mockOutput     *services.RollbackAddressOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			url:            "/admin/address/1/revisions/2/rollback?language=en",
+			mockOutput:     &services.RollbackAddressOutput{Address: models.AddressItem{ID: "1", Name: "earlier"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "revision not found",
+			url:            "/admin/address/1/revisions/2/rollback?language=en",
+			mockError:      fmt.Errorf("revision 2 of address 1: %w", repository.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name:           "failed request",
-			url:            "/admin/address/tags?language=zh&refresh=true",
-			mockOutput:     nil,
-			mockError:      errors.New("failed request"),
+			url:            "/admin/address/1/revisions/2/rollback?language=en",
+			mockError:      errors.New("failed"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:           "invalid revision",
+			url:            "/admin/address/1/revisions/latest/rollback?language=en",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.mockOutput != nil || tt.mockError != nil {
+				input := services.RollbackAddressInput{Language: "en", ID: "1", Revision: 2}
+				mockSrv.On("RollbackAddress", mock.Anything, input).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.RollbackAddressResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "earlier", response.Data.Name)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportAddresses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		url            string
+		contentType    string
+		expectedFormat models.AddressImportFormat
+		expectedDryRun bool
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "csv from content type",
+			url:            "/admin/address/import?language=en",
+			contentType:    "text/csv; charset=utf-8",
+			expectedFormat: models.AddressImportFormatCSV,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "jsonl dry run",
+			url:            "/admin/address/import?language=en&format=jsonl&dryRun=true",
+			contentType:    "application/octet-stream",
+			expectedFormat: models.AddressImportFormatJSONL,
+			expectedDryRun: true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unreadable file",
+			url:            "/admin/address/import?language=en&format=csv",
+			expectedFormat: models.AddressImportFormatCSV,
+			mockError:      fmt.Errorf("%w: missing CSV column \"name\"", services.ErrInvalidImport),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "failed request",
+			url:            "/admin/address/import?language=en&format=csv",
+			expectedFormat: models.AddressImportFormatCSV,
+			mockError:      errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "unknown format",
+			url:            "/admin/address/import?language=en",
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid dry run",
+			url:            "/admin/address/import?language=en&format=csv&dryRun=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "missing language",
-			url:            "/admin/address/tags",
-			mockOutput:     nil,
-			mockError:      errors.New("failed request"),
+			url:            "/admin/address/import?format=csv",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectedFormat != "" {
+				var output *services.ImportAddressesOutput
+				if tt.mockError == nil {
+					output = &services.ImportAddressesOutput{
+						DryRun: tt.expectedDryRun,
+						Rows: []models.AddressImportRow{
+							{Row: 1, Status: models.AddressImportStatusCreated, ID: "a1", Name: "Jane Austen"},
+							{Row: 2, Status: models.AddressImportStatusInvalid, Error: "missing required fields: name"},
+						},
+						Summary: services.ImportAddressesSummary{Total: 2, Created: 1, Invalid: 1, Indexed: 1},
+					}
+				}
+				mockSrv.On("ImportAddresses", mock.Anything, mock.MatchedBy(func(input services.ImportAddressesInput) bool {
+					return input.Language == "en" && input.Format == tt.expectedFormat && input.DryRun == tt.expectedDryRun
+				})).Return(output, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", tt.url, bytes.NewBufferString("name,briefIntro\n"))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.ImportAddressesResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedDryRun, response.Data.DryRun)
+				assert.Equal(t, 1, response.Data.Summary.Created)
+				assert.Len(t, response.Data.Rows, 2)
+				assert.Equal(t, models.AddressImportStatusInvalid, response.Data.Rows[1].Status)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportAddresses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                string
+		url                 string
+		expectedInput       *services.ExportAddressesInput
+		mockBody            string
+		mockError           error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name: "jsonl by default",
+			url:  "/admin/address/export?language=en&tags=UK,%20writer,&updatedSince=1700000000000",
+			expectedInput: &services.ExportAddressesInput{
+				Language:     "en",
+				Format:       models.AddressExportFormatJSONL,
+				Tags:         []string{"UK", "writer"},
+				UpdatedSince: 1700000000000,
+			},
+			mockBody:            `{"id":"a1"}` + "\n",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        `{"id":"a1"}` + "\n",
+		},
+		{
+			name:                "zip without language",
+			url:                 "/admin/address/export?format=zip",
+			expectedInput:       &services.ExportAddressesInput{Format: models.AddressExportFormatZip},
+			mockBody:            "PK",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/zip",
+			expectedBody:        "PK",
+		},
+		{
+			name:           "failure before streaming",
+			url:            "/admin/address/export?language=en&format=csv",
+			expectedInput:  &services.ExportAddressesInput{Language: "en", Format: models.AddressExportFormatCSV},
+			mockError:      errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:                "failure while streaming",
+			url:                 "/admin/address/export?language=en&format=csv",
+			expectedInput:       &services.ExportAddressesInput{Language: "en", Format: models.AddressExportFormatCSV},
+			mockBody:            "id,name\n",
+			mockError:           errors.New("firestore unavailable"),
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,name\n",
+		},
+		{
+			name:           "missing language",
+			url:            "/admin/address/export?format=csv",
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "invalid language",
-			url:            "/admin/address/tags?language=abc",
-			mockOutput:     nil,
-			mockError:      errors.New("failed request"),
+			name:           "unknown format",
+			url:            "/admin/address/export?language=en&format=xml",
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "invalid refresh query",
-			url:            "/admin/address/tags?language=en&refresh=abc",
-			mockOutput:     nil,
-			mockError:      errors.New("failed request"),
+			name:           "invalid updatedSince",
+			url:            "/admin/address/export?language=en&updatedSince=yesterday",
 			expectedStatus: http.StatusBadRequest,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockSrv.On("RefreshTags", mock.Anything, mock.Anything).
-				Return(tt.mockOutput, tt.mockError).Once()
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectedInput != nil {
+				var output *services.ExportAddressesOutput
+				if tt.mockError == nil {
+					output = &services.ExportAddressesOutput{Count: 1}
+				}
+				mockSrv.On("ExportAddresses", mock.Anything, *tt.expectedInput, mock.Anything).
+					Return(tt.mockBody, output, tt.mockError).Once()
+			}
 			req, _ := http.NewRequest("GET", tt.url, nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			if tt.mockError == nil {
-				mockSrv.AssertExpectations(t)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			} else {
+				assert.Empty(t, w.Header().Get("Content-Disposition"))
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefreshTags(t *testing.T) {
+	t.Parallel()
+	mockJobs := new(MockJobSubmitter)
+	handler := NewAddressHandler(new(MockAddressService), mockJobs, slog.Default())
+	router := setupRouter(handler)
+	tests := []struct {
+		name           string
+		body           string
+		mockOutput     *services.SubmitJobOutput
+		mockError      error
+		expectSubmit   bool
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"language":"ZH"}`,
+			mockOutput: &services.SubmitJobOutput{Job: models.Job{
+				ID:     "job_1",
+				Type:   models.JobTypeTagsRefresh,
+				Status: models.JobStatusQueued,
+			}},
+			expectSubmit:   true,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "failed request",
+			body:           `{"language":"zh"}`,
+			mockError:      errors.New("failed request"),
+			expectSubmit:   true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "missing language",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid language",
+			body:           `{"language":"abc"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectSubmit {
+				mockJobs.On("SubmitJob", mock.Anything, services.SubmitJobInput{
+					Type:   models.JobTypeTagsRefresh,
+					Params: map[string]string{models.JobParamLanguage: "zh"},
+				}).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", "/admin/address/tags/refresh", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.mockOutput != nil {
+				var response dto.JobResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockOutput.Job.ID, response.Data.ID)
 			}
+			mockJobs.AssertExpectations(t)
 		})
 	}
 }
@@ -530,7 +1314,7 @@ func TestGetAllTags_RefreshTags(t *testing.T) {
 func TestGetAllTags_GetAllTags(t *testing.T) {
 	t.Parallel()
 	mockSrv := new(MockAddressService)
-	handler := NewAddressHandler(mockSrv, slog.Default())
+	handler := NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default())
 	router := setupRouter(handler)
 	tests := []struct {
 		name           string
@@ -541,8 +1325,9 @@ func TestGetAllTags_GetAllTags(t *testing.T) {
 		expectedStatus int
 	}{
 		{
-			name:     "success with refresh parameter",
-			url:      "/admin/address/tags?language=ZH&refresh=false",
+			// refreshing takes the POST route and its permission, the query is ignored
+			name:     "refresh query doesn't start a job",
+			url:      "/admin/address/tags?language=ZH&refresh=true",
 			language: "ZH",
 			mockOutput: &services.GetAllTagsOutput{TagsRecord: models.TagsRecord{
 				RefreshedAt: 123,
@@ -552,7 +1337,7 @@ func TestGetAllTags_GetAllTags(t *testing.T) {
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name:     "success without refresh parameter",
+			name:     "success",
 			url:      "/admin/address/tags?language=ZH",
 			language: "ZH",
 			mockOutput: &services.GetAllTagsOutput{TagsRecord: models.TagsRecord{
@@ -592,26 +1377,45 @@ func TestGetAllTags_GetAllTags(t *testing.T) {
 
 func TestSyncToTypesense(t *testing.T) {
 	t.Parallel()
-	mockSrv := new(MockAddressService)
-	handler := NewAddressHandler(mockSrv, slog.Default())
+	mockJobs := new(MockJobSubmitter)
+	handler := NewAddressHandler(new(MockAddressService), mockJobs, slog.Default())
 	router := setupRouter(handler)
 	tests := []struct {
 		name           string
 		url            string
 		language       models.Language
+		full           bool
 		validLanguage  bool
-		mockOutput     *services.SyncToTypesenseOutput
+		mockOutput     *services.SubmitJobOutput
 		mockError      error
 		expectedStatus int
 	}{
 		{
-			name:           "success",
-			url:            "/admin/address/sync",
-			language:       "en",
-			validLanguage:  true,
-			mockOutput:     &services.SyncToTypesenseOutput{Total: 10, Success: 10, Failed: 0},
+			name:          "success",
+			url:           "/admin/address/sync",
+			language:      "en",
+			validLanguage: true,
+			mockOutput: &services.SubmitJobOutput{Job: models.Job{
+				ID:     "job_1",
+				Type:   models.JobTypeTypesenseSync,
+				Status: models.JobStatusQueued,
+			}},
 			mockError:      nil,
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:          "full sync",
+			url:           "/admin/address/sync",
+			language:      "zh",
+			full:          true,
+			validLanguage: true,
+			mockOutput: &services.SubmitJobOutput{Job: models.Job{
+				ID:     "job_2",
+				Type:   models.JobTypeTypesenseSync,
+				Status: models.JobStatusQueued,
+			}},
+			mockError:      nil,
+			expectedStatus: http.StatusAccepted,
 		},
 		{
 			name:           "invalid language",
@@ -635,23 +1439,28 @@ func TestSyncToTypesense(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.validLanguage {
-				mockSrv.On("SyncToTypesense", mock.Anything, mock.Anything).Return(tt.mockOutput, tt.mockError).Once()
+				mockJobs.On("SubmitJob", mock.Anything, services.SubmitJobInput{
+					Type: models.JobTypeTypesenseSync,
+					Params: map[string]string{
+						models.JobParamLanguage: tt.language.Get(),
+						models.JobParamFullSync: strconv.FormatBool(tt.full),
+					},
+				}).Return(tt.mockOutput, tt.mockError).Once()
 			}
-			body, _ := json.Marshal(dto.SyncToTypesenseRequest{Language: tt.language})
+			body, _ := json.Marshal(dto.SyncToTypesenseRequest{Language: tt.language, Full: tt.full})
 			req, _ := http.NewRequest("POST", tt.url, bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.mockError == nil && tt.validLanguage {
-				var response dto.SyncToTypesenseResponse
+				var response dto.JobResponse
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.mockOutput.Success, response.Data.Success)
-				mockSrv.AssertCalled(t, "SyncToTypesense", mock.Anything, mock.Anything)
-				mockSrv.AssertExpectations(t)
+				assert.Equal(t, models.JobStatusQueued, response.Data.Status)
+				mockJobs.AssertExpectations(t)
 			}
 			if !tt.validLanguage {
-				mockSrv.AssertNotCalled(t, "SyncToTypesense")
+				mockJobs.AssertNotCalled(t, "SubmitJob")
 			}
 		})
 	}
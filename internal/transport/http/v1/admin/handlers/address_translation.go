@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListMissingTranslations godoc
+// @Summary List addresses with missing translations
+// @Description List the addresses of the specified language that have no linked entry in at least one other language, oldest first. A linked entry in the trash counts as missing
+// @Tags Admin Address
+// @Produce json
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Param limit query int false "Number of addresses, 100 by default and at most 500"
+// @Success 200 {object} dto.ListMissingTranslationsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/translations/missing [get]
+func (h *AddressHandler) ListMissingTranslations(c *gin.Context) {
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	limit, ok := utils.QueryLimit(c, h.logger)
+	if !ok {
+		return
+	}
+	input := services.ListMissingTranslationsInput{
+		Language: language,
+		Limit:    limit,
+	}
+	output, err := h.service.ListMissingTranslations(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to list missing translations", "language", language, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListMissingTranslationsResponse{
+		Total: output.Total,
+		Data:  dto.ToMissingTranslationDTOs(output.Missing),
+	})
+}
+
+// LinkAddressTranslations godoc
+// @Summary Link address translations
+// @Description Link entries of different languages that describe the same place. Groups the entries already belong to are joined, it is rejected with 409 when a language would end up with two entries
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.LinkAddressTranslationsRequest true "Request body"
+// @Success 200 {object} dto.LinkAddressTranslationsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/translations/link [post]
+func (h *AddressHandler) LinkAddressTranslations(c *gin.Context) {
+	var req dto.LinkAddressTranslationsRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	input := services.LinkAddressTranslationsInput{Entries: dto.FromAddressRefDTOs(req.Entries)}
+	output, err := h.service.LinkAddressTranslations(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidTranslationLink) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrTranslationConflict) {
+		h.logger.Warn("translations not linked", "entries", req.Entries, "error", err)
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to link address translations", "entries", req.Entries, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("address translations linked",
+		"actor", c.GetString(middleware.UidKey),
+		"groupID", output.Group.ID,
+		"members", output.Group.Members)
+	c.JSON(http.StatusOK, dto.LinkAddressTranslationsResponse{Data: dto.ToTranslationGroupDTO(output.Group)})
+}
+
+// UnlinkAddressTranslation godoc
+// @Summary Unlink an address translation
+// @Description Take an address out of its translation group, a group left with a single address is removed
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.UnlinkAddressTranslationRequest true "Request body"
+// @Success 200 {object} dto.UnlinkAddressTranslationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/translations/unlink [post]
+func (h *AddressHandler) UnlinkAddressTranslation(c *gin.Context) {
+	var req dto.UnlinkAddressTranslationRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateLanguage(c, req.Language, h.logger) {
+		return
+	}
+	input := services.UnlinkAddressTranslationInput{
+		Language: req.Language,
+		ID:       req.ID,
+	}
+	output, err := h.service.UnlinkAddressTranslation(c.Request.Context(), input)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Address not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrNotLinked) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to unlink address translation", "request", req, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("address translation unlinked",
+		"actor", c.GetString(middleware.UidKey),
+		"language", req.Language,
+		"id", output.ID)
+	c.JSON(http.StatusOK, dto.UnlinkAddressTranslationResponse{Data: dto.AddressID{ID: output.ID}})
+}
+
+// TranslateAddresses godoc
+// @Summary Translate addresses with an LLM
+// @Description Translate the name, intro and tags of addresses into another language, tags through the tag vocabulary of that language. The postal fields are kept in their local script. Addresses linked to an entry in the target language already are reported in translated and not translated again. The drafts are stored in the review queue and linked to their source when they are approved
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param request body dto.TranslateAddressesRequest true "Request body"
+// @Success 200 {object} dto.TranslateAddressesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/translate [post]
+func (h *AddressHandler) TranslateAddresses(c *gin.Context) {
+	var req dto.TranslateAddressesRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateLanguage(c, req.From, h.logger) || !utils.ValidateLanguage(c, req.To, h.logger) {
+		return
+	}
+	input := services.TranslateAddressesInput{
+		From:            req.From,
+		To:              req.To,
+		IDs:             req.IDs,
+		Model:           req.Model,
+		ReasoningEffort: req.ReasoningEffort,
+		ThinkingLevel:   req.ThinkingLevel,
+		Actor:           c.GetString(middleware.UidKey),
+	}
+	output, err := h.service.TranslateAddresses(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidTranslation) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to translate addresses", "request", req, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.TranslateAddressesResponse{
+		Data:         dto.ToPendingAddressDTOs(output.Pending),
+		Untranslated: output.Untranslated,
+		Translated:   output.Translated,
+	})
+}
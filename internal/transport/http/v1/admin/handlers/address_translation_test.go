@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListMissingTranslations(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	mockSrv.On("ListMissingTranslations", mock.Anything, services.ListMissingTranslationsInput{Language: "en", Limit: 1}).
+		Return(&services.ListMissingTranslationsOutput{
+			Total: 2,
+			Missing: []models.MissingTranslation{{
+				Address:          models.AddressItem{ID: "a1", Name: "British Museum"},
+				MissingLanguages: []models.Language{models.LanguageZH},
+			}},
+		}, nil).Once()
+	req, _ := http.NewRequest("GET", "/admin/address/translations/missing?language=en&limit=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListMissingTranslationsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Total)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, []models.Language{models.LanguageZH}, response.Data[0].MissingLanguages)
+	mockSrv.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/admin/address/translations/missing", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLinkAddressTranslations(t *testing.T) {
+	t.Parallel()
+	validBody := `{"entries":[{"language":"en","id":"a1"},{"language":"zh","id":"b1"}]}`
+	entries := []models.AddressRef{{Language: "en", ID: "a1"}, {Language: "zh", ID: "b1"}}
+	tests := []struct {
+		name           string
+		body           string
+		mockOutput     *services.LinkAddressTranslationsOutput
+		mockError      error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: validBody,
+			mockOutput: &services.LinkAddressTranslationsOutput{Group: models.TranslationGroup{
+				ID:      "g1",
+				Members: map[string]string{"en": "a1", "zh": "b1"},
+			}},
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid link",
+			body:           validBody,
+			mockError:      fmt.Errorf("%w: more than one en entry", services.ErrInvalidTranslationLink),
+			expectCall:     true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown address",
+			body:           validBody,
+			mockError:      fmt.Errorf("address b1: %w", repository.ErrNotFound),
+			expectCall:     true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "language already linked",
+			body:           validBody,
+			mockError:      fmt.Errorf("%w: zh is linked to b2, not b1", repository.ErrTranslationConflict),
+			expectCall:     true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failed request",
+			body:           validBody,
+			mockError:      errors.New("firestore unavailable"),
+			expectCall:     true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "single entry",
+			body:           `{"entries":[{"language":"en","id":"a1"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectCall {
+				mockSrv.On("LinkAddressTranslations", mock.Anything, services.LinkAddressTranslationsInput{Entries: entries}).
+					Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", "/admin/address/translations/link", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.LinkAddressTranslationsResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "g1", response.Data.ID)
+				assert.Equal(t, map[string]string{"en": "a1", "zh": "b1"}, response.Data.Members)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnlinkAddressTranslation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{name: "success", body: `{"language":"en","id":"a1"}`, expectCall: true, expectedStatus: http.StatusOK},
+		{
+			name:           "not linked",
+			body:           `{"language":"en","id":"a1"}`,
+			mockError:      fmt.Errorf("address a1: %w", repository.ErrNotLinked),
+			expectCall:     true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "unknown address",
+			body:           `{"language":"en","id":"a1"}`,
+			mockError:      fmt.Errorf("address a1: %w", repository.ErrNotFound),
+			expectCall:     true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{name: "invalid language", body: `{"language":"abc","id":"a1"}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing id", body: `{"language":"en"}`, expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectCall {
+				var output *services.UnlinkAddressTranslationOutput
+				if tt.mockError == nil {
+					output = &services.UnlinkAddressTranslationOutput{ID: "a1"}
+				}
+				mockSrv.On("UnlinkAddressTranslation", mock.Anything,
+					services.UnlinkAddressTranslationInput{Language: "en", ID: "a1"}).
+					Return(output, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", "/admin/address/translations/unlink", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTranslateAddresses(t *testing.T) {
+	t.Parallel()
+	validBody := `{"from":"en","to":"zh","ids":["a1"],"model":"gpt-5-mini"}`
+	expectedInput := services.TranslateAddressesInput{From: "en", To: "zh", IDs: []string{"a1"}, Model: "gpt-5-mini"}
+	tests := []struct {
+		name           string
+		body           string
+		mockOutput     *services.TranslateAddressesOutput
+		mockError      error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: validBody,
+			mockOutput: &services.TranslateAddressesOutput{
+				Pending: []models.PendingAddress{{
+					ID:       "p1",
+					Language: "zh",
+					Source:   &models.AddressRef{Language: "en", ID: "a1"},
+				}},
+				Untranslated: []string{},
+				Translated:   []string{"a2"},
+			},
+			expectCall:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "same language",
+			body:           validBody,
+			mockError:      fmt.Errorf("%w: the source and target language are the same", services.ErrInvalidTranslation),
+			expectCall:     true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown addresses",
+			body:           validBody,
+			mockError:      fmt.Errorf("addresses a1: %w", repository.ErrNotFound),
+			expectCall:     true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failed completion",
+			body:           validBody,
+			mockError:      errors.New("failed to translate addresses"),
+			expectCall:     true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "invalid target language",
+			body:           `{"from":"en","to":"fr","ids":["a1"],"model":"gpt-5-mini"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing model",
+			body:           `{"from":"en","to":"zh","ids":["a1"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectCall {
+				mockSrv.On("TranslateAddresses", mock.Anything, expectedInput).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", "/admin/address/translate", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.TranslateAddressesResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.Data, 1)
+				assert.Equal(t, &dto.AddressRefDTO{Language: "en", ID: "a1"}, response.Data[0].Source)
+				assert.Equal(t, []string{"a2"}, response.Translated)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type jobService interface {
+	GetJob(ctx context.Context, input services.GetJobInput) (*services.GetJobOutput, error)
+	ListJobs(ctx context.Context, input services.ListJobsInput) (*services.ListJobsOutput, error)
+	CancelJob(ctx context.Context, input services.CancelJobInput) (*services.CancelJobOutput, error)
+}
+
+type JobHandler struct {
+	service jobService
+	logger  *slog.Logger
+}
+
+func NewJobHandler(service jobService, logger *slog.Logger) *JobHandler {
+	return &JobHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListJobs godoc
+// @Summary List background jobs
+// @Description List the most recent background jobs, newest first
+// @Tags Admin Job
+// @Param Authorization header string true "Bearer idToken"
+// @Param limit query int false "Number of jobs, 20 by default and at most 100"
+// @Produce json
+// @Success 200 {object} dto.ListJobsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	limitStr := strings.TrimSpace(c.Query("limit"))
+	limit := 0
+	if limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			h.logger.Warn("invalid limit parameter", "limit", limitStr)
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid limit parameter"})
+			return
+		}
+	}
+	output, err := h.service.ListJobs(c.Request.Context(), services.ListJobsInput{Limit: limit})
+	if err != nil {
+		h.logger.Error("failed to list jobs", "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to list jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListJobsResponse{Data: dto.ToJobDTOs(output.Jobs)})
+}
+
+// GetJob godoc
+// @Summary Get a background job
+// @Description Get the status and progress of a background job
+// @Tags Admin Job
+// @Param Authorization header string true "Bearer idToken"
+// @Param id path string true "Job ID"
+// @Produce json
+// @Success 200 {object} dto.JobResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	output, err := h.service.GetJob(c.Request.Context(), services.GetJobInput{ID: id})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Job not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get job", "jobID", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to get job"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.JobResponse{Data: dto.ToJobDTO(output.Job)})
+}
+
+// CancelJob godoc
+// @Summary Cancel a background job
+// @Description Cancel a queued or running job, a running job stops after its current step
+// @Tags Admin Job
+// @Param Authorization header string true "Bearer idToken"
+// @Param id path string true "Job ID"
+// @Produce json
+// @Success 200 {object} dto.JobResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/jobs/{id}/cancel [post]
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	output, err := h.service.CancelJob(c.Request.Context(), services.CancelJobInput{ID: id})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Job not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrJobFinished) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to cancel job", "jobID", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to cancel job"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.JobResponse{Data: dto.ToJobDTO(output.Job)})
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockJobService implements the methods used by JobHandler for testing.
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) GetJob(ctx context.Context, input services.GetJobInput) (*services.GetJobOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.GetJobOutput), args.Error(1)
+}
+
+func (m *MockJobService) ListJobs(ctx context.Context, input services.ListJobsInput) (*services.ListJobsOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListJobsOutput), args.Error(1)
+}
+
+func (m *MockJobService) CancelJob(ctx context.Context, input services.CancelJobInput) (*services.CancelJobOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.CancelJobOutput), args.Error(1)
+}
+
+func setupJobRouter(handler *JobHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin/jobs", handler.ListJobs)
+	r.GET("/admin/jobs/:id", handler.GetJob)
+	r.POST("/admin/jobs/:id/cancel", handler.CancelJob)
+	return r
+}
+
+func TestListJobs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		url            string
+		limit          int
+		mockError      error
+		expectList     bool
+		expectedStatus int
+	}{
+		{name: "default limit", url: "/admin/jobs", expectList: true, expectedStatus: http.StatusOK},
+		{name: "custom limit", url: "/admin/jobs?limit=5", limit: 5, expectList: true, expectedStatus: http.StatusOK},
+		{name: "invalid limit", url: "/admin/jobs?limit=abc", expectedStatus: http.StatusBadRequest},
+		{name: "negative limit", url: "/admin/jobs?limit=-1", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "failed request",
+			url:            "/admin/jobs",
+			mockError:      errors.New("firestore unavailable"),
+			expectList:     true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockJobService)
+			router := setupJobRouter(NewJobHandler(mockSrv, slog.Default()))
+			if tt.expectList {
+				var output *services.ListJobsOutput
+				if tt.mockError == nil {
+					output = &services.ListJobsOutput{Jobs: []models.Job{{ID: "job_1"}, {ID: "job_2"}}}
+				}
+				mockSrv.On("ListJobs", mock.Anything, services.ListJobsInput{Limit: tt.limit}).
+					Return(output, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.ListJobsResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.Data, 2)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockOutput     *services.GetJobOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockOutput: &services.GetJobOutput{Job: models.Job{
+				ID:        "job_1",
+				Status:    models.JobStatusRunning,
+				Processed: 120,
+				Failed:    2,
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "not found", mockError: repository.ErrNotFound, expectedStatus: http.StatusNotFound},
+		{name: "failed request", mockError: errors.New("firestore unavailable"), expectedStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockJobService)
+			router := setupJobRouter(NewJobHandler(mockSrv, slog.Default()))
+			mockSrv.On("GetJob", mock.Anything, services.GetJobInput{ID: "job_1"}).
+				Return(tt.mockOutput, tt.mockError).Once()
+			req, _ := http.NewRequest("GET", "/admin/jobs/job_1", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.mockOutput != nil {
+				var response dto.JobResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, int64(120), response.Data.Processed)
+				assert.Equal(t, int64(2), response.Data.Failed)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockOutput     *services.CancelJobOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockOutput: &services.CancelJobOutput{Job: models.Job{
+				ID:              "job_1",
+				Status:          models.JobStatusRunning,
+				CancelRequested: true,
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "not found", mockError: repository.ErrNotFound, expectedStatus: http.StatusNotFound},
+		{name: "already finished", mockError: repository.ErrJobFinished, expectedStatus: http.StatusConflict},
+		{name: "failed request", mockError: errors.New("firestore unavailable"), expectedStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockJobService)
+			router := setupJobRouter(NewJobHandler(mockSrv, slog.Default()))
+			mockSrv.On("CancelJob", mock.Anything, services.CancelJobInput{ID: "job_1"}).
+				Return(tt.mockOutput, tt.mockError).Once()
+			req, _ := http.NewRequest("POST", "/admin/jobs/job_1/cancel", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.mockOutput != nil {
+				var response dto.JobResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.True(t, response.Data.CancelRequested)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
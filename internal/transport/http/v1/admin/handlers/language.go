@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type languageService interface {
+	ListLanguages(ctx context.Context) (*services.ListLanguagesOutput, error)
+	UpsertLanguage(ctx context.Context, input services.UpsertLanguageInput) (*services.UpsertLanguageOutput, error)
+}
+
+type LanguageHandler struct {
+	service languageService
+	logger  *slog.Logger
+}
+
+func NewLanguageHandler(service languageService, logger *slog.Logger) *LanguageHandler {
+	return &LanguageHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListLanguages godoc
+// @Summary List languages
+// @Description List every language of the catalog with its search locale, fallback language and whether app users can pick it
+// @Tags Admin Language
+// @Param Authorization header string true "Bearer idToken"
+// @Produce json
+// @Success 200 {object} dto.ListLanguagesResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/languages [get]
+func (h *LanguageHandler) ListLanguages(c *gin.Context) {
+	output, err := h.service.ListLanguages(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list languages", "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to list languages"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListLanguagesResponse{Data: dto.ToLanguageDTOs(output.Languages)})
+}
+
+// UpsertLanguage godoc
+// @Summary Add or update a language
+// @Description Add a language or change its settings. A disabled language keeps its catalog and stays available to admins, app users can't pick it. Rejected with 400 when the fallback is unknown or the fallbacks form a cycle
+// @Tags Admin Language
+// @Param Authorization header string true "Bearer idToken"
+// @Accept json
+// @Produce json
+// @Param code path string true "Language code (e.g., ja, fr)"
+// @Param request body dto.UpsertLanguageRequest true "Request body"
+// @Success 200 {object} dto.LanguageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/languages/{code} [put]
+func (h *LanguageHandler) UpsertLanguage(c *gin.Context) {
+	code := models.Language(strings.TrimSpace(c.Param("code")))
+	var req dto.UpsertLanguageRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	input := services.UpsertLanguageInput{Config: dto.FromUpsertLanguageDTO(code, req)}
+	output, err := h.service.UpsertLanguage(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidLanguageConfig) {
+		h.logger.Warn("invalid language config", "language", code, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to update language", "language", code, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to update language"})
+		return
+	}
+	h.logger.Info("language updated", "actor", c.GetString(middleware.UidKey), "language", output.Language.Code)
+	c.JSON(http.StatusOK, dto.LanguageResponse{Data: dto.ToLanguageDTO(output.Language)})
+}
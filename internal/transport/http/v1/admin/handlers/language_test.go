@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLanguageService implements the methods used by LanguageHandler for testing.
+type MockLanguageService struct {
+	mock.Mock
+}
+
+func (m *MockLanguageService) ListLanguages(ctx context.Context) (*services.ListLanguagesOutput, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListLanguagesOutput), args.Error(1)
+}
+
+func (m *MockLanguageService) UpsertLanguage(
+	ctx context.Context, input services.UpsertLanguageInput) (*services.UpsertLanguageOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.UpsertLanguageOutput), args.Error(1)
+}
+
+func setupLanguageRouter(mockSrv *MockLanguageService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewLanguageHandler(mockSrv, slog.Default())
+	router.GET("/admin/languages", handler.ListLanguages)
+	router.PUT("/admin/languages/:code", handler.UpsertLanguage)
+	return router
+}
+
+func TestListLanguages(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failed request",
+			mockError:      errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockLanguageService)
+			router := setupLanguageRouter(mockSrv)
+			var output *services.ListLanguagesOutput
+			if tt.mockError == nil {
+				output = &services.ListLanguagesOutput{Languages: models.DefaultLanguageConfigs}
+			}
+			mockSrv.On("ListLanguages", mock.Anything).Return(output, tt.mockError).Once()
+			req, _ := http.NewRequest("GET", "/admin/languages", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.ListLanguagesResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.Data, 2)
+				assert.Equal(t, "zh", response.Data[1].Locale)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpsertLanguage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		code           string
+		body           string
+		expectedInput  *services.UpsertLanguageInput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			code: "ja",
+			body: `{"name":"Japanese","locale":"ja","fallback":"en","enabled":true}`,
+			expectedInput: &services.UpsertLanguageInput{Config: models.LanguageConfig{
+				Code: "ja", Name: "Japanese", Locale: "ja", Fallback: "en", Enabled: true,
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing name",
+			code:           "ja",
+			body:           `{"locale":"ja"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid config",
+			code: "ja",
+			body: `{"name":"Japanese","fallback":"ko"}`,
+			expectedInput: &services.UpsertLanguageInput{Config: models.LanguageConfig{
+				Code: "ja", Name: "Japanese", Fallback: "ko",
+			}},
+			mockError:      fmt.Errorf("%w: fallback ko of language ja is not configured", services.ErrInvalidLanguageConfig),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "failed request",
+			code: "ja",
+			body: `{"name":"Japanese"}`,
+			expectedInput: &services.UpsertLanguageInput{Config: models.LanguageConfig{
+				Code: "ja", Name: "Japanese",
+			}},
+			mockError:      errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockLanguageService)
+			router := setupLanguageRouter(mockSrv)
+			if tt.expectedInput != nil {
+				var output *services.UpsertLanguageOutput
+				if tt.mockError == nil {
+					output = &services.UpsertLanguageOutput{Language: tt.expectedInput.Config}
+				}
+				mockSrv.On("UpsertLanguage", mock.Anything, *tt.expectedInput).Return(output, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("PUT", "/admin/languages/"+tt.code, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.LanguageResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, models.Language("ja"), response.Data.Code)
+				assert.True(t, response.Data.Enabled)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type outboxService interface {
+	ListOutboxEntries(
+		ctx context.Context, input services.ListOutboxEntriesInput) (*services.ListOutboxEntriesOutput, error)
+}
+
+type OutboxHandler struct {
+	service outboxService
+	logger  *slog.Logger
+}
+
+func NewOutboxHandler(service outboxService, logger *slog.Logger) *OutboxHandler {
+	return &OutboxHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListOutboxEntries godoc
+// @Summary List search outbox entries
+// @Description List the address changes that are not applied to the search index yet (pending) or that ran out of retries (failed)
+// @Tags Admin Outbox
+// @Param Authorization header string true "Bearer idToken"
+// @Param status query string false "pending (default) or failed"
+// @Param limit query int false "Number of entries, 50 by default and at most 200"
+// @Produce json
+// @Success 200 {object} dto.ListOutboxEntriesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/outbox [get]
+func (h *OutboxHandler) ListOutboxEntries(c *gin.Context) {
+	status := models.OutboxStatus(strings.TrimSpace(c.DefaultQuery("status", string(models.OutboxStatusPending))))
+	if err := status.Validate(); err != nil {
+		h.logger.Warn("invalid outbox status", "status", status)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	limitStr := strings.TrimSpace(c.Query("limit"))
+	limit := 0
+	if limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			h.logger.Warn("invalid limit parameter", "limit", limitStr)
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid limit parameter"})
+			return
+		}
+	}
+	input := services.ListOutboxEntriesInput{Status: status, Limit: limit}
+	output, err := h.service.ListOutboxEntries(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to list outbox entries", "status", status, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to list outbox entries"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListOutboxEntriesResponse{Data: dto.ToOutboxEntryDTOs(output.Entries)})
+}
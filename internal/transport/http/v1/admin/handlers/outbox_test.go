@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxService implements the methods used by OutboxHandler for testing.
+type MockOutboxService struct {
+	mock.Mock
+}
+
+func (m *MockOutboxService) ListOutboxEntries(
+	ctx context.Context, input services.ListOutboxEntriesInput) (*services.ListOutboxEntriesOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListOutboxEntriesOutput), args.Error(1)
+}
+
+func TestListOutboxEntries(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		url            string
+		expectedInput  *services.ListOutboxEntriesInput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "pending by default",
+			url:            "/admin/outbox",
+			expectedInput:  &services.ListOutboxEntriesInput{Status: models.OutboxStatusPending},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failed entries",
+			url:            "/admin/outbox?status=failed&limit=10",
+			expectedInput:  &services.ListOutboxEntriesInput{Status: models.OutboxStatusFailed, Limit: 10},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid status",
+			url:            "/admin/outbox?status=done",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			url:            "/admin/outbox?limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "failed request",
+			url:            "/admin/outbox",
+			expectedInput:  &services.ListOutboxEntriesInput{Status: models.OutboxStatusPending},
+			mockError:      errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockOutboxService)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/admin/outbox", NewOutboxHandler(mockSrv, slog.Default()).ListOutboxEntries)
+			if tt.expectedInput != nil {
+				var output *services.ListOutboxEntriesOutput
+				if tt.mockError == nil {
+					output = &services.ListOutboxEntriesOutput{Entries: []models.OutboxEntry{
+						{ID: "en_1", Status: tt.expectedInput.Status, Attempts: 3, LastError: "timeout"},
+					}}
+				}
+				mockSrv.On("ListOutboxEntries", mock.Anything, *tt.expectedInput).Return(output, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.ListOutboxEntriesResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.Data, 1)
+				assert.Equal(t, tt.expectedInput.Status, response.Data[0].Status)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPendingAddresses godoc
+// @Summary List pending addresses
+// @Description List the generated addresses of the specified language that wait for a review, newest first
+// @Tags Admin Address
+// @Produce json
+// @Param language query string true "Language code (e.g., en, zh)"
+// @Param limit query int false "Number of addresses, 50 by default and at most 200"
+// @Success 200 {object} dto.ListPendingAddressesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/pending [get]
+func (h *AddressHandler) ListPendingAddresses(c *gin.Context) {
+	language, ok := utils.QueryLanguage(c, h.logger)
+	if !ok {
+		return
+	}
+	limit, ok := utils.QueryLimit(c, h.logger)
+	if !ok {
+		return
+	}
+	input := services.ListPendingAddressesInput{
+		Language: language,
+		Limit:    limit,
+	}
+	output, err := h.service.ListPendingAddresses(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error("failed to list pending addresses", "language", language, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	response := dto.ListPendingAddressesResponse{Data: dto.ToPendingAddressDTOs(output.Pending)}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPendingAddress godoc
+// @Summary Get a pending address
+// @Description Get a generated address in the review queue with the settings of the run that generated it
+// @Tags Admin Address
+// @Produce json
+// @Param id path string true "Pending address ID"
+// @Success 200 {object} dto.PendingAddressResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/pending/{id} [get]
+func (h *AddressHandler) GetPendingAddress(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	output, err := h.service.GetPendingAddress(c.Request.Context(), services.GetPendingAddressInput{ID: id})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Pending address not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get pending address", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.PendingAddressResponse{Data: dto.ToPendingAddressDTO(output.Pending)})
+}
+
+// UpdatePendingAddress godoc
+// @Summary Edit a pending address
+// @Description Replace the content of a generated address before it is approved
+// @Tags Admin Address
+// @Accept json
+// @Produce json
+// @Param id path string true "Pending address ID"
+// @Param request body dto.UpdatePendingAddressRequest true "Request body"
+// @Success 200 {object} dto.PendingAddressResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/pending/{id} [put]
+func (h *AddressHandler) UpdatePendingAddress(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	var req dto.UpdatePendingAddressRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	input := services.UpdatePendingAddressInput{
+		ID:      id,
+		Address: dto.FromUpdatePendingAddressDTO(req),
+	}
+	output, err := h.service.UpdatePendingAddress(c.Request.Context(), input)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Pending address not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to update pending address", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.PendingAddressResponse{Data: dto.ToPendingAddressDTO(output.Pending)})
+}
+
+// ApprovePendingAddress godoc
+// @Summary Approve a pending address
+// @Description Move a generated address into the catalog of its language, a translation is linked to its source. It is rejected with 409 when a similar address already exists, the item was edited meanwhile, or the source of a translation left the catalog or is linked to an entry of the language already, and with 400 when required fields are missing
+// @Tags Admin Address
+// @Produce json
+// @Param id path string true "Pending address ID"
+// @Success 200 {object} dto.ApprovePendingAddressResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/pending/{id}/approve [post]
+func (h *AddressHandler) ApprovePendingAddress(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	input := services.ApprovePendingAddressInput{
+		ID:    id,
+		Actor: c.GetString(middleware.UidKey),
+	}
+	output, err := h.service.ApprovePendingAddress(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidPendingAddress) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Pending address not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrDuplicate) ||
+		errors.Is(err, repository.ErrConflict) ||
+		errors.Is(err, repository.ErrTranslationConflict) {
+		h.logger.Warn("pending address not approved", "id", id, "error", err)
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to approve pending address", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("pending address approved", "actor", input.Actor, "id", id, "addressID", output.Address.ID)
+	c.JSON(http.StatusOK, dto.ApprovePendingAddressResponse{Data: dto.ToAddressDTO(output.Address)})
+}
+
+// RejectPendingAddress godoc
+// @Summary Reject a pending address
+// @Description Remove a generated address from the review queue without adding it to the catalog
+// @Tags Admin Address
+// @Produce json
+// @Param id path string true "Pending address ID"
+// @Success 200 {object} dto.RejectPendingAddressResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/address/pending/{id} [delete]
+func (h *AddressHandler) RejectPendingAddress(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	output, err := h.service.RejectPendingAddress(c.Request.Context(), services.RejectPendingAddressInput{ID: id})
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Pending address not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to reject pending address", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.logger.Info("pending address rejected", "actor", c.GetString(middleware.UidKey), "id", id)
+	c.JSON(http.StatusOK, dto.RejectPendingAddressResponse{Data: dto.AddressID{ID: output.ID}})
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListPendingAddresses(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	mockSrv.On("ListPendingAddresses", mock.Anything, services.ListPendingAddressesInput{Language: "en", Limit: 10}).
+		Return(&services.ListPendingAddressesOutput{Pending: []models.PendingAddress{{
+			ID:         "p1",
+			Language:   "en",
+			Address:    models.AddressItem{Name: "Jane Austen"},
+			Generation: models.GenerationMetadata{RunID: "run-1", Model: "gpt-5-mini", Prompt: "writers"},
+		}}}, nil).Once()
+	req, _ := http.NewRequest("GET", "/admin/address/pending?language=en&limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListPendingAddressesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "Jane Austen", response.Data[0].Address.Name)
+	assert.Equal(t, "gpt-5-mini", response.Data[0].Generation.Model)
+	mockSrv.AssertExpectations(t)
+
+	req, _ = http.NewRequest("GET", "/admin/address/pending", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetPendingAddress(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	mockSrv.On("GetPendingAddress", mock.Anything, services.GetPendingAddressInput{ID: "p1"}).
+		Return(&services.GetPendingAddressOutput{Pending: models.PendingAddress{ID: "p1"}}, nil).Once()
+	mockSrv.On("GetPendingAddress", mock.Anything, services.GetPendingAddressInput{ID: "missing"}).
+		Return(nil, fmt.Errorf("pending address missing: %w", repository.ErrNotFound)).Once()
+	for id, expectedStatus := range map[string]int{"p1": http.StatusOK, "missing": http.StatusNotFound} {
+		req, _ := http.NewRequest("GET", "/admin/address/pending/"+id, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, expectedStatus, w.Code, id)
+	}
+	mockSrv.AssertExpectations(t)
+}
+
+func TestUpdatePendingAddress(t *testing.T) {
+	t.Parallel()
+	validBody := dto.UpdatePendingAddressRequest{
+		Name:       "Jane Austen",
+		BriefIntro: "Novelist",
+		Tags:       []string{"UK"},
+		Address:    dto.AddressDTO{City: "Bath", Country: "UK", Line1: "4 Sydney Place", Region: "Somerset"},
+	}
+	tests := []struct {
+		name           string
+		body           dto.UpdatePendingAddressRequest
+		mockError      error
+		expectCall     bool
+		expectedStatus int
+	}{
+		{name: "success", body: validBody, expectCall: true, expectedStatus: http.StatusOK},
+		{name: "not found", body: validBody, mockError: repository.ErrNotFound, expectCall: true, expectedStatus: http.StatusNotFound},
+		{name: "failed request", body: validBody, mockError: errors.New("firestore unavailable"), expectCall: true, expectedStatus: http.StatusInternalServerError},
+		{name: "missing fields", body: dto.UpdatePendingAddressRequest{Name: "Jane Austen"}, expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			if tt.expectCall {
+				var output *services.UpdatePendingAddressOutput
+				if tt.mockError == nil {
+					output = &services.UpdatePendingAddressOutput{Pending: models.PendingAddress{ID: "p1"}}
+				}
+				mockSrv.On("UpdatePendingAddress", mock.Anything, services.UpdatePendingAddressInput{
+					ID:      "p1",
+					Address: dto.FromUpdatePendingAddressDTO(tt.body),
+				}).Return(output, tt.mockError).Once()
+			}
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("PUT", "/admin/address/pending/p1", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApprovePendingAddress(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockOutput     *services.ApprovePendingAddressOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			mockOutput:     &services.ApprovePendingAddressOutput{Address: models.AddressItem{ID: "a1", Name: "Jane Austen"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "incomplete address",
+			mockError:      fmt.Errorf("%w: missing required fields: address.city", services.ErrInvalidPendingAddress),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not found",
+			mockError:      repository.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "duplicate",
+			mockError:      fmt.Errorf("%w: address with name 'Jane Austen' already exists", repository.ErrDuplicate),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "edited during approval",
+			mockError:      repository.ErrConflict,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "source translated already",
+			mockError:      fmt.Errorf("%w: source address a1 is linked to the zh entry z1", repository.ErrTranslationConflict),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failed request",
+			mockError:      errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv := new(MockAddressService)
+			router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+			mockSrv.On("ApprovePendingAddress", mock.Anything, services.ApprovePendingAddressInput{ID: "p1"}).
+				Return(tt.mockOutput, tt.mockError).Once()
+			req, _ := http.NewRequest("POST", "/admin/address/pending/p1/approve", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.ApprovePendingAddressResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "a1", response.Data.ID)
+			}
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRejectPendingAddress(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	router := setupRouter(NewAddressHandler(mockSrv, new(MockJobSubmitter), slog.Default()))
+	mockSrv.On("RejectPendingAddress", mock.Anything, services.RejectPendingAddressInput{ID: "p1"}).
+		Return(&services.RejectPendingAddressOutput{ID: "p1"}, nil).Once()
+	mockSrv.On("RejectPendingAddress", mock.Anything, services.RejectPendingAddressInput{ID: "missing"}).
+		Return(nil, repository.ErrNotFound).Once()
+	for id, expectedStatus := range map[string]int{"p1": http.StatusOK, "missing": http.StatusNotFound} {
+		req, _ := http.NewRequest("DELETE", "/admin/address/pending/"+id, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, expectedStatus, w.Code, id)
+	}
+	mockSrv.AssertExpectations(t)
+}
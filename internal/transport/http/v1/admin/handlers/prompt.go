@@ -7,6 +7,7 @@ import (
 	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/services"
 	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,18 +30,19 @@ func NewPromptHandler(service promptService, logger *slog.Logger) *PromptHandler
 
 // GetSystemAddressGenerationPrompt godoc
 // @Summary Get system prompt for address generation
-// @Description Retrieves the system prompt used for address generation, optionally based on language.
+// @Description Retrieves the system prompt used for address generation, optionally based on language. A language without its own prompt gets the one of its fallback languages, the Content-Language header tells which one
 // @Tags Admin Prompt
 // @Accept json
 // @Produce json
 // @Param language query string false "Language code"
 // @Success 200 {object} dto.GetSystemAddressGenerationPromptResponse
+// @Header 200 {string} Content-Language "Language of the returned prompt"
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /admin/prompt/system-address-generation [get]
 func (h *PromptHandler) GetSystemAddressGenerationPrompt(c *gin.Context) {
 	languageStr := c.Query("language")
-	// we can skip language validation here because we've set fallback
-	// language as en in the prompt repository
+	// we can skip language validation here because the prompt repository
+	// walks the fallback chain of the language
 	opts := services.GetSystemAddressGenerationPromptInput{
 		Language: models.Language(languageStr),
 	}
@@ -50,6 +52,7 @@ func (h *PromptHandler) GetSystemAddressGenerationPrompt(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
+	c.Header(middleware.ContentLanguageHeader, prompt.Language.Get())
 	response := dto.GetSystemAddressGenerationPromptResponse{
 		Data: prompt.Prompt,
 	}
@@ -38,7 +38,7 @@ func TestPromptHandler_GetSystemAddressGenerationPrompt(t *testing.T) {
 		{
 			name:           "success",
 			language:       "en",
-			mockOutput:     &services.GetSystemAddressGenerationPromptOutput{Prompt: "test prompt"},
+			mockOutput:     &services.GetSystemAddressGenerationPromptOutput{Language: "en", Prompt: "test prompt"},
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 			expectedBody:   `{"data":"test prompt"}`,
@@ -69,6 +69,9 @@ func TestPromptHandler_GetSystemAddressGenerationPrompt(t *testing.T) {
 			r.ServeHTTP(w, req)
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.mockOutput != nil {
+				assert.Equal(t, tt.mockOutput.Language.Get(), w.Header().Get("Content-Language"))
+			}
 			mockService.AssertExpectations(t)
 		})
 	}
@@ -2,17 +2,24 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"north-post/service/internal/repository"
 	"north-post/service/internal/services"
 	"north-post/service/internal/transport/http/v1/dto"
 	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type userService interface {
 	SignInAdminUserById(ctx context.Context, input services.SignInAdminUserByIdInput) (*services.SignInAdminUserByIdOutput, error)
+	ListAdminUsers(ctx context.Context) (*services.ListAdminUsersOutput, error)
+	GrantAdminRole(ctx context.Context, input services.GrantAdminRoleInput) (*services.GrantAdminRoleOutput, error)
+	RevokeAdminRole(ctx context.Context, input services.RevokeAdminRoleInput) (*services.RevokeAdminRoleOutput, error)
 }
 
 type UserHandler struct {
@@ -62,3 +69,112 @@ func (h *UserHandler) SignInAdminUser(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// ListAdminUsers godoc
+// @Summary List admin users
+// @Description List all admin users with their roles, only owners can call this endpoint
+// @Tags Admin User
+// @Param Authorization header string true "Bearer idToken"
+// @Produce json
+// @Success 200 {object} dto.ListAdminUsersResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/users [get]
+func (h *UserHandler) ListAdminUsers(c *gin.Context) {
+	output, err := h.service.ListAdminUsers(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list admin users", "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to list admin users"})
+		return
+	}
+	response := dto.ListAdminUsersResponse{Data: dto.ToAdminUserDTOs(output.Users)}
+	c.JSON(http.StatusOK, response)
+}
+
+// GrantAdminRole godoc
+// @Summary Grant an admin role
+// @Description Grant a role (viewer, editor, owner) to a user, only owners can call this endpoint
+// @Tags Admin User
+// @Param Authorization header string true "Bearer idToken"
+// @Param uid path string true "User ID"
+// @Param request body dto.GrantAdminRoleRequest true "Request body"
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.GrantAdminRoleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/users/{uid}/role [put]
+func (h *UserHandler) GrantAdminRole(c *gin.Context) {
+	targetUid := strings.TrimSpace(c.Param("uid"))
+	var req dto.GrantAdminRoleRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if err := req.Role.Validate(); err != nil {
+		h.logger.Warn("invalid admin role", "role", req.Role)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	input := services.GrantAdminRoleInput{
+		ActorUid: c.GetString(middleware.UidKey),
+		Uid:      targetUid,
+		Role:     req.Role,
+	}
+	output, err := h.service.GrantAdminRole(c.Request.Context(), input)
+	if errors.Is(err, services.ErrSelfRoleChange) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to grant admin role", "uid", targetUid, "role", req.Role, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to grant admin role"})
+		return
+	}
+	h.logger.Info("admin role granted", "actor", input.ActorUid, "uid", targetUid, "role", req.Role)
+	response := dto.GrantAdminRoleResponse{Data: dto.ToAdminUserDTO(output.UserData)}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAdminRole godoc
+// @Summary Revoke admin access
+// @Description Remove the admin role of a user, only owners can call this endpoint
+// @Tags Admin User
+// @Param Authorization header string true "Bearer idToken"
+// @Param uid path string true "User ID"
+// @Produce json
+// @Success 200 {object} dto.RevokeAdminRoleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/users/{uid}/role [delete]
+func (h *UserHandler) RevokeAdminRole(c *gin.Context) {
+	targetUid := strings.TrimSpace(c.Param("uid"))
+	input := services.RevokeAdminRoleInput{
+		ActorUid: c.GetString(middleware.UidKey),
+		Uid:      targetUid,
+	}
+	output, err := h.service.RevokeAdminRole(c.Request.Context(), input)
+	if errors.Is(err, services.ErrSelfRoleChange) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Admin user not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to revoke admin role", "uid", targetUid, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to revoke admin role"})
+		return
+	}
+	h.logger.Info("admin role revoked", "actor", input.ActorUid, "uid", targetUid)
+	response := dto.RevokeAdminRoleResponse{Data: dto.AdminUid{Uid: output.Uid}}
+	c.JSON(http.StatusOK, response)
+}
@@ -1,14 +1,18 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
 	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
 	"north-post/service/internal/transport/http/v1/middleware"
 	"testing"
 
@@ -27,6 +31,30 @@ func (m *MockUserService) SignInAdminUserById(ctx context.Context, input service
 	return args.Get(0).(*services.SignInAdminUserByIdOutput), args.Error(1)
 }
 
+func (m *MockUserService) ListAdminUsers(ctx context.Context) (*services.ListAdminUsersOutput, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListAdminUsersOutput), args.Error(1)
+}
+
+func (m *MockUserService) GrantAdminRole(ctx context.Context, input services.GrantAdminRoleInput) (*services.GrantAdminRoleOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.GrantAdminRoleOutput), args.Error(1)
+}
+
+func (m *MockUserService) RevokeAdminRole(ctx context.Context, input services.RevokeAdminRoleInput) (*services.RevokeAdminRoleOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.RevokeAdminRoleOutput), args.Error(1)
+}
+
 func setupUserRouter(handler *UserHandler, uid string) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
@@ -36,6 +64,15 @@ func setupUserRouter(handler *UserHandler, uid string) *gin.Engine {
 		}
 		c.Next()
 	}, handler.SignInAdminUser)
+	r.GET("/admin/users", handler.ListAdminUsers)
+	r.PUT("/admin/users/:uid/role", func(c *gin.Context) {
+		c.Set(middleware.UidKey, uid)
+		c.Next()
+	}, handler.GrantAdminRole)
+	r.DELETE("/admin/users/:uid/role", func(c *gin.Context) {
+		c.Set(middleware.UidKey, uid)
+		c.Next()
+	}, handler.RevokeAdminRole)
 	return r
 }
 
@@ -106,3 +143,172 @@ func TestSignInAdminUser(t *testing.T) {
 		})
 	}
 }
+
+func TestListAdminUsers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockOutput     *services.ListAdminUsersOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			mockOutput: &services.ListAdminUsersOutput{
+				Users: []models.AdminUser{
+					{Uid: "owner-1", Email: "owner@example.com", Role: models.AdminRoleOwner},
+					{Uid: "editor-1", Email: "editor@example.com", Role: models.AdminRoleEditor},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "service error",
+			mockOutput:     nil,
+			mockError:      errors.New("service failed"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockUserService)
+			handler := NewUserHandler(mockSvc, slog.Default())
+			router := setupUserRouter(handler, "owner-1")
+			mockSvc.On("ListAdminUsers", mock.Anything).Return(tt.mockOutput, tt.mockError).Once()
+			req, _ := http.NewRequest("GET", "/admin/users", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp dto.ListAdminUsersResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Len(t, resp.Data, 2)
+				assert.Equal(t, models.AdminRoleOwner, resp.Data[0].Role)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGrantAdminRole(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		body           string
+		mockOutput     *services.GrantAdminRoleOutput
+		mockError      error
+		expectedStatus int
+		expectCall     bool
+	}{
+		{
+			name: "success",
+			body: `{"role":"editor"}`,
+			mockOutput: &services.GrantAdminRoleOutput{
+				UserData: models.AdminUser{Uid: "user-2", Role: models.AdminRoleEditor},
+			},
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "invalid role",
+			body:           `{"role":"superuser"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     false,
+		},
+		{
+			name:           "missing role",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     false,
+		},
+		{
+			name:           "self role change",
+			body:           `{"role":"viewer"}`,
+			mockError:      services.ErrSelfRoleChange,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     true,
+		},
+		{
+			name:           "unknown user",
+			body:           `{"role":"viewer"}`,
+			mockError:      fmt.Errorf("user user-2: %w", repository.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectCall:     true,
+		},
+		{
+			name:           "service error",
+			body:           `{"role":"owner"}`,
+			mockError:      errors.New("service failed"),
+			expectedStatus: http.StatusInternalServerError,
+			expectCall:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockUserService)
+			handler := NewUserHandler(mockSvc, slog.Default())
+			router := setupUserRouter(handler, "owner-1")
+			if tt.expectCall {
+				mockSvc.On("GrantAdminRole", mock.Anything, mock.MatchedBy(func(input services.GrantAdminRoleInput) bool {
+					return input.ActorUid == "owner-1" && input.Uid == "user-2"
+				})).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("PUT", "/admin/users/user-2/role", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp dto.GrantAdminRoleResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, models.AdminRoleEditor, resp.Data.Role)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeAdminRole(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockOutput     *services.RevokeAdminRoleOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			mockOutput:     &services.RevokeAdminRoleOutput{Uid: "user-2"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not found",
+			mockError:      repository.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "self role change",
+			mockError:      services.ErrSelfRoleChange,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service error",
+			mockError:      errors.New("service failed"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockUserService)
+			handler := NewUserHandler(mockSvc, slog.Default())
+			router := setupUserRouter(handler, "owner-1")
+			input := services.RevokeAdminRoleInput{ActorUid: "owner-1", Uid: "user-2"}
+			mockSvc.On("RevokeAdminRole", mock.Anything, input).Return(tt.mockOutput, tt.mockError).Once()
+			req, _ := http.NewRequest("DELETE", "/admin/users/user-2/role", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
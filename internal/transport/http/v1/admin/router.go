@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/transport/http/v1/admin/handlers"
 	"north-post/service/internal/transport/http/v1/middleware"
 
@@ -13,41 +14,98 @@ type Handlers struct {
 	User      *handlers.UserHandler
 	Music     *handlers.MusicHandler
 	Typesense *handlers.TypesenseHandler
+	Job       *handlers.JobHandler
+	Outbox    *handlers.OutboxHandler
+	Language  *handlers.LanguageHandler
 }
 
 func SetupAdminRouter(router *gin.RouterGroup, h *Handlers, middlewares *middleware.Middlewares) {
-	admin := router.Group("/admin", middlewares.Auth)
+	admin := router.Group("/admin", middlewares.Auth, middlewares.Admin)
+	read := middlewares.Permission(models.PermissionReadContent)
+	write := middlewares.Permission(models.PermissionWriteAddress)
+	generate := middlewares.Permission(models.PermissionGenerateAddress)
+	remove := middlewares.Permission(models.PermissionDeleteAddress)
+	sync := middlewares.Permission(models.PermissionSyncIndex)
+	manageJobs := middlewares.Permission(models.PermissionManageJobs)
+	manageAdmins := middlewares.Permission(models.PermissionManageAdmins)
+	manageLanguages := middlewares.Permission(models.PermissionManageLanguages)
 	{
 		address := admin.Group("/address")
 		{
 			// GET
-			address.GET("/tags", h.Address.GetAllTags)
+			address.GET("/tags", read, h.Address.GetAllTags)
+			address.GET("/taxonomy", read, h.Address.ListTaxonomyTags)
+			address.GET("/trash", read, h.Address.ListDeletedAddresses)
+			address.GET("/export", read, h.Address.ExportAddresses)
+			address.GET("/duplicates", read, h.Address.ListDuplicateClusters)
+			address.GET("/translations/missing", read, h.Address.ListMissingTranslations)
+			address.GET("/pending", read, h.Address.ListPendingAddresses)
+			address.GET("/pending/:id", read, h.Address.GetPendingAddress)
+			address.GET("/:id/revisions", read, h.Address.ListAddressRevisions)
+			address.GET("/:id/revisions/diff", read, h.Address.DiffAddressRevisions)
 			// POST
-			address.POST("", h.Address.GetAddresses)
-			address.POST("/generate", h.Address.GenerateNewAddress)
-			address.POST("/update", h.Address.UpdateAddress)
-			address.POST("/sync", h.Address.SyncToTypesense)
+			address.POST("", read, h.Address.GetAddresses)
+			address.POST("/generate", generate, h.Address.GenerateNewAddress)
+			address.POST("/translate", generate, h.Address.TranslateAddresses)
+			address.POST("/update", write, h.Address.UpdateAddress)
+			address.POST("/import", write, h.Address.ImportAddresses)
+			address.POST("/merge", remove, h.Address.MergeAddresses)
+			address.POST("/duplicates/backfill", write, h.Address.BackfillDuplicateKeys)
+			address.POST("/translations/link", write, h.Address.LinkAddressTranslations)
+			address.POST("/translations/unlink", write, h.Address.UnlinkAddressTranslation)
+			address.POST("/sync", sync, h.Address.SyncToTypesense)
+			address.POST("/tags/refresh", sync, h.Address.RefreshTags)
+			address.POST("/taxonomy", write, h.Address.CreateTaxonomyTag)
+			address.POST("/taxonomy/migrate", write, h.Address.MigrateAddressTags)
+			address.POST("/pending/:id/approve", write, h.Address.ApprovePendingAddress)
+			address.POST("/:id/restore", remove, h.Address.RestoreAddress)
+			address.POST("/:id/revisions/:revision/rollback", write, h.Address.RollbackAddress)
 			// PUT
-			address.PUT("", h.Address.CreateNewAddress)
+			address.PUT("", write, h.Address.CreateNewAddress)
+			address.PUT("/pending/:id", write, h.Address.UpdatePendingAddress)
+			address.PUT("/taxonomy/:slug", write, h.Address.UpdateTaxonomyTag)
 			// DELETE
-			address.DELETE("/:id", h.Address.DeleteAddress)
+			address.DELETE("/pending/:id", write, h.Address.RejectPendingAddress)
+			address.DELETE("/taxonomy/:slug", remove, h.Address.DeleteTaxonomyTag)
+			address.DELETE("/:id", remove, h.Address.DeleteAddress)
 		}
 		prompt := admin.Group("/prompt")
 		{
-			prompt.GET("/system/address", h.Prompt.GetSystemAddressGenerationPrompt)
+			prompt.GET("/system/address", read, h.Prompt.GetSystemAddressGenerationPrompt)
 		}
 		music := admin.Group("/music")
 		{
-			music.GET("", h.Music.GetMusicList)
-			music.GET("/:genre/:track", h.Music.GetPresignedMusicURL)
+			music.GET("", read, h.Music.GetMusicList)
+			music.GET("/:genre/:track", read, h.Music.GetPresignedMusicURL)
 		}
 		signIn := admin.Group("/signin")
 		{
-			signIn.POST("", h.User.SignInAdminUser)
+			signIn.POST("", read, h.User.SignInAdminUser)
+		}
+		users := admin.Group("/users", manageAdmins)
+		{
+			users.GET("", h.User.ListAdminUsers)
+			users.PUT("/:uid/role", h.User.GrantAdminRole)
+			users.DELETE("/:uid/role", h.User.RevokeAdminRole)
 		}
 		typesense := admin.Group("/typesense")
 		{
-			typesense.GET("/info", h.Typesense.GetSystemInfo)
+			typesense.GET("/info", read, h.Typesense.GetSystemInfo)
+		}
+		jobs := admin.Group("/jobs")
+		{
+			jobs.GET("", read, h.Job.ListJobs)
+			jobs.GET("/:id", read, h.Job.GetJob)
+			jobs.POST("/:id/cancel", manageJobs, h.Job.CancelJob)
+		}
+		outbox := admin.Group("/outbox")
+		{
+			outbox.GET("", read, h.Outbox.ListOutboxEntries)
+		}
+		languages := admin.Group("/languages")
+		{
+			languages.GET("", read, h.Language.ListLanguages)
+			languages.PUT("/:code", manageLanguages, h.Language.UpsertLanguage)
 		}
 	}
 }
@@ -15,3 +15,17 @@ type UpdateUserSavedAddressesResponse struct {
 type GetSavedAddressesResponse struct {
 	Data []AddressItemDTO `json:"data"`
 }
+
+type TranslateSavedAddressesRequest struct {
+	Language models.Language `json:"language"`                // language the addresses are saved into
+	From     models.Language `json:"from" binding:"required"` // language the addresses were saved in
+}
+
+type TranslatedSavedAddressesDTO struct {
+	Addresses       []AddressItemDTO `json:"addresses"`
+	UntranslatedIDs []string         `json:"untranslatedIDs"`
+}
+
+type TranslateSavedAddressesResponse struct {
+	Data TranslatedSavedAddressesDTO `json:"data"`
+}
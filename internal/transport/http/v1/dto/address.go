@@ -9,12 +9,16 @@ type AddressID struct {
 	ID string `json:"id"`
 }
 
+// Language is required from admins, app users get the negotiated language when it is left out
 type GetAddressesRequest struct {
-	Language models.Language `json:"language" binding:"required"`
+	Language models.Language `json:"language"`
 	Keywords string          `json:"keywords"`
-	Tags     []string        `json:"tags"`
-	PageSize int             `json:"pageSize"`
-	Page     int             `json:"page"`
+	Tags     []string        `json:"tags"` // any of the tags, whatever their category
+	// tags by category (country, role, figure), any tag within a category and every category has to match
+	Filters  map[models.TagCategory][]string `json:"filters"`
+	Sort     models.AddressSort              `json:"sort"` // updated (default), relevance or name
+	PageSize int                             `json:"pageSize"`
+	Page     int                             `json:"page"`
 }
 
 type GetAddressesResponse struct {
@@ -37,6 +41,7 @@ type CreateAddressResponse struct {
 	ID string `json:"id"`
 }
 
+// Address.UpdatedAt identifies the version the client edited unless an If-Match header is sent
 type UpdateAddressRequest struct {
 	Language models.Language `json:"language" binding:"required"`
 	ID       string          `json:"id" binding:"required"`
@@ -47,6 +52,12 @@ type UpdateAddressResponse struct {
 	Data AddressItemDTO `json:"data"`
 }
 
+// Returned with 409 when the address changed since the client read it
+type UpdateAddressConflictResponse struct {
+	Error string         `json:"error"`
+	Data  AddressItemDTO `json:"data"` // the current version
+}
+
 type GetAllTagsResponse struct {
 	Data TagsRecordDTO `json:"data"`
 }
@@ -55,6 +66,54 @@ type DeleteAddressResponse struct {
 	Data AddressID `json:"data"`
 }
 
+type RestoreAddressResponse struct {
+	Data AddressItemDTO `json:"data"`
+}
+
+type ListDeletedAddressesResponse struct {
+	Data []AddressItemDTO `json:"data"`
+}
+
+type ListAddressRevisionsResponse struct {
+	Data []AddressRevisionDTO `json:"data"`
+}
+
+type DiffAddressRevisionsResponse struct {
+	Data RevisionDiffDTO `json:"data"`
+}
+
+type RollbackAddressResponse struct {
+	Data AddressItemDTO `json:"data"`
+}
+
+type ImportAddressesResponse struct {
+	Data ImportAddressesResultDTO `json:"data"`
+}
+
+type ImportAddressesResultDTO struct {
+	DryRun  bool                      `json:"dryRun"`
+	Summary ImportAddressesSummaryDTO `json:"summary"`
+	Rows    []ImportAddressRowDTO     `json:"rows"`
+}
+
+type ImportAddressesSummaryDTO struct {
+	Total     int `json:"total"`
+	Ready     int `json:"ready"`
+	Created   int `json:"created"`
+	Invalid   int `json:"invalid"`
+	Duplicate int `json:"duplicate"`
+	Failed    int `json:"failed"`
+	Indexed   int `json:"indexed"` // created addresses searchable right away, the rest follow through the outbox
+}
+
+type ImportAddressRowDTO struct {
+	Row    int                        `json:"row"`
+	Status models.AddressImportStatus `json:"status"`
+	ID     string                     `json:"id,omitempty"`
+	Name   string                     `json:"name,omitempty"`
+	Error  string                     `json:"error,omitempty"`
+}
+
 type GenerateNewAddressRequest struct {
 	Language        models.Language `json:"language" binding:"required"`
 	Prompt          string          `json:"prompt" binding:"required"`
@@ -64,8 +123,9 @@ type GenerateNewAddressRequest struct {
 	ThinkingLevel   string          `json:"thinkingLevel,omitempty"`
 }
 
+// The generated addresses are staged for review, see PendingAddressDTO
 type GenerateNewAddressResponse struct {
-	Data []AddressItemDTO `json:"data"`
+	Data []PendingAddressDTO `json:"data"`
 }
 
 type AddressItemDTO struct {
@@ -76,6 +136,10 @@ type AddressItemDTO struct {
 	CreatedAt  int64      `json:"createdAt"`
 	UpdatedAt  int64      `json:"updatedAt"`
 	Address    AddressDTO `json:"address"`
+	DeletedAt  int64      `json:"deletedAt,omitempty"`
+	MergedInto string     `json:"mergedInto,omitempty"`
+	// shared by the entries of the same place in other languages
+	TranslationGroupID string `json:"translationGroupId,omitempty"`
 }
 
 type AddressDTO struct {
@@ -88,12 +152,55 @@ type AddressDTO struct {
 	Region       string `json:"region" binding:"required"`
 }
 
+type FieldChangeDTO struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AddressRevisionDTO struct {
+	Number     int64                 `json:"number"`
+	Action     models.RevisionAction `json:"action"`
+	Actor      string                `json:"actor"`
+	CreatedAt  int64                 `json:"createdAt"`
+	Changes    []FieldChangeDTO      `json:"changes"`
+	Snapshot   AddressItemDTO        `json:"snapshot"`
+	RollbackOf int64                 `json:"rollbackOf,omitempty"`
+}
+
+type RevisionDiffDTO struct {
+	From    int64            `json:"from"`
+	To      int64            `json:"to"`
+	Changes []FieldChangeDTO `json:"changes"`
+}
+
 type GetAddressesResponseDTO struct {
-	Addresses  []AddressItemDTO `json:"addresses"`
-	TotalCount int64            `json:"totalCount"`
-	TotalPages int              `json:"totalPages"`
-	Page       int              `json:"page"`
-	Language   models.Language  `json:"language"`
+	Addresses []AddressItemDTO `json:"addresses"`
+	// by address ID, only addresses whose name or brief intro matches the keywords
+	Highlights map[string]AddressHighlightDTO `json:"highlights"`
+	Facets     []TagFacetDTO                  `json:"facets"`
+	TotalCount int64                          `json:"totalCount"`
+	TotalPages int                            `json:"totalPages"`
+	Page       int                            `json:"page"`
+	Language   models.Language                `json:"language"`
+}
+
+// Matched keywords wrapped in <mark> tags, the whole name and a snippet of the brief intro.
+// The rest of the text is HTML escaped
+type AddressHighlightDTO struct {
+	Name       string `json:"name,omitempty"`
+	BriefIntro string `json:"briefIntro,omitempty"`
+}
+
+// Number of matching addresses per tag of a category
+type TagFacetDTO struct {
+	Category models.TagCategory `json:"category"`
+	Counts   []TagFacetCountDTO `json:"counts"`
+}
+
+type TagFacetCountDTO struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
 }
 
 type TagsRecordDTO struct {
@@ -102,18 +209,14 @@ type TagsRecordDTO struct {
 	Language    models.Language     `json:"language"`
 }
 
-type SyncToTypesenseRequest struct {
+type RefreshTagsRequest struct {
 	Language models.Language `json:"language" binding:"required"`
 }
 
-type SyncToTypesenseDTO struct {
-	Total   int `json:"total"`
-	Success int `json:"success"`
-	Failed  int `json:"failed"`
-}
-
-type SyncToTypesenseResponse struct {
-	Data SyncToTypesenseDTO `json:"data"`
+type SyncToTypesenseRequest struct {
+	Language models.Language `json:"language" binding:"required"`
+	// Rebuild the whole index instead of syncing the changes since the last run
+	Full bool `json:"full,omitempty"`
 }
 
 func ToAddressDTO(addressItem models.AddressItem) AddressItemDTO {
@@ -128,13 +231,16 @@ func ToAddressDTO(addressItem models.AddressItem) AddressItemDTO {
 		Region:       address.Region,
 	}
 	return AddressItemDTO{
-		ID:         addressItem.ID,
-		Name:       addressItem.Name,
-		BriefIntro: addressItem.BriefIntro,
-		Tags:       addressItem.Tags,
-		CreatedAt:  addressItem.CreatedAt,
-		UpdatedAt:  addressItem.UpdatedAt,
-		Address:    addressDto,
+		ID:                 addressItem.ID,
+		Name:               addressItem.Name,
+		BriefIntro:         addressItem.BriefIntro,
+		Tags:               addressItem.Tags,
+		CreatedAt:          addressItem.CreatedAt,
+		UpdatedAt:          addressItem.UpdatedAt,
+		Address:            addressDto,
+		DeletedAt:          addressItem.DeletedAt,
+		MergedInto:         addressItem.MergedInto,
+		TranslationGroupID: addressItem.TranslationGroupID,
 	}
 }
 
@@ -149,6 +255,8 @@ func ToAddressDTOs(addresses []models.AddressItem) []AddressItemDTO {
 func ToGetAddressesResponseDTO(output *services.GetAddressesOutput, language models.Language) GetAddressesResponseDTO {
 	return GetAddressesResponseDTO{
 		Addresses:  ToAddressDTOs(output.Addresses),
+		Highlights: ToAddressHighlightDTOs(output.Highlights),
+		Facets:     ToTagFacetDTOs(output.Facets),
 		TotalCount: output.TotalCount,
 		TotalPages: output.TotalPages,
 		Page:       output.Page,
@@ -156,6 +264,26 @@ func ToGetAddressesResponseDTO(output *services.GetAddressesOutput, language mod
 	}
 }
 
+func ToAddressHighlightDTOs(highlights map[string]models.AddressHighlight) map[string]AddressHighlightDTO {
+	output := make(map[string]AddressHighlightDTO, len(highlights))
+	for id, highlight := range highlights {
+		output[id] = AddressHighlightDTO{Name: highlight.Name, BriefIntro: highlight.BriefIntro}
+	}
+	return output
+}
+
+func ToTagFacetDTOs(facets []models.TagFacet) []TagFacetDTO {
+	output := make([]TagFacetDTO, len(facets))
+	for i, facet := range facets {
+		counts := make([]TagFacetCountDTO, len(facet.Counts))
+		for j, count := range facet.Counts {
+			counts[j] = TagFacetCountDTO{Value: count.Value, Count: count.Count}
+		}
+		output[i] = TagFacetDTO{Category: facet.Category, Counts: counts}
+	}
+	return output
+}
+
 func ToTagsRecordDTO(tagsRecord models.TagsRecord, language models.Language) TagsRecordDTO {
 	return TagsRecordDTO{
 		Tags:        tagsRecord.Tags,
@@ -164,11 +292,66 @@ func ToTagsRecordDTO(tagsRecord models.TagsRecord, language models.Language) Tag
 	}
 }
 
-func ToSyncToTypesenseDTO(output *services.SyncToTypesenseOutput) SyncToTypesenseDTO {
-	return SyncToTypesenseDTO{
-		Total:   output.Total,
-		Success: output.Success,
-		Failed:  output.Failed,
+func ToFieldChangeDTOs(changes []models.FieldChange) []FieldChangeDTO {
+	output := make([]FieldChangeDTO, len(changes))
+	for i, change := range changes {
+		output[i] = FieldChangeDTO{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		}
+	}
+	return output
+}
+
+func ToAddressRevisionDTOs(revisions []models.AddressRevision) []AddressRevisionDTO {
+	output := make([]AddressRevisionDTO, len(revisions))
+	for i, revision := range revisions {
+		output[i] = AddressRevisionDTO{
+			Number:     revision.Number,
+			Action:     revision.Action,
+			Actor:      revision.Actor,
+			CreatedAt:  revision.CreatedAt,
+			Changes:    ToFieldChangeDTOs(revision.Changes),
+			Snapshot:   ToAddressDTO(revision.Snapshot),
+			RollbackOf: revision.RollbackOf,
+		}
+	}
+	return output
+}
+
+func ToRevisionDiffDTO(output *services.DiffAddressRevisionsOutput) RevisionDiffDTO {
+	return RevisionDiffDTO{
+		From:    output.From,
+		To:      output.To,
+		Changes: ToFieldChangeDTOs(output.Changes),
+	}
+}
+
+func ToImportAddressesResultDTO(output *services.ImportAddressesOutput) ImportAddressesResultDTO {
+	rows := make([]ImportAddressRowDTO, len(output.Rows))
+	for i, row := range output.Rows {
+		rows[i] = ImportAddressRowDTO{
+			Row:    row.Row,
+			Status: row.Status,
+			ID:     row.ID,
+			Name:   row.Name,
+			Error:  row.Error,
+		}
+	}
+	summary := output.Summary
+	return ImportAddressesResultDTO{
+		DryRun: output.DryRun,
+		Summary: ImportAddressesSummaryDTO{
+			Total:     summary.Total,
+			Ready:     summary.Ready,
+			Created:   summary.Created,
+			Invalid:   summary.Invalid,
+			Duplicate: summary.Duplicate,
+			Failed:    summary.Failed,
+			Indexed:   summary.Indexed,
+		},
+		Rows: rows,
 	}
 }
 
@@ -0,0 +1,45 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type DuplicateClusterDTO struct {
+	ID        string                   `json:"id"`
+	Reasons   []models.DuplicateReason `json:"reasons"`
+	Addresses []AddressItemDTO         `json:"addresses"`
+}
+
+type ListDuplicateClustersResponse struct {
+	Data []DuplicateClusterDTO `json:"data"`
+}
+
+type MergeAddressesRequest struct {
+	Language     models.Language `json:"language" binding:"required"`
+	SurvivorID   string          `json:"survivorId" binding:"required"`
+	DuplicateIDs []string        `json:"duplicateIds" binding:"required"`
+}
+
+type BackfillDuplicateKeysRequest struct {
+	Language models.Language `json:"language" binding:"required"`
+}
+
+type MergeAddressesResultDTO struct {
+	Survivor      AddressItemDTO `json:"survivor"`
+	MergedIDs     []string       `json:"mergedIds"`
+	MigratedUsers int            `json:"migratedUsers"`
+}
+
+type MergeAddressesResponse struct {
+	Data MergeAddressesResultDTO `json:"data"`
+}
+
+func ToDuplicateClusterDTOs(clusters []models.DuplicateCluster) []DuplicateClusterDTO {
+	output := make([]DuplicateClusterDTO, len(clusters))
+	for i, cluster := range clusters {
+		output[i] = DuplicateClusterDTO{
+			ID:        cluster.ID,
+			Reasons:   cluster.Reasons,
+			Addresses: ToAddressDTOs(cluster.Addresses),
+		}
+	}
+	return output
+}
@@ -0,0 +1,89 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type TaxonomyTagDTO struct {
+	Slug      string              `json:"slug"`
+	Category  models.TagCategory  `json:"category"`
+	Names     map[string]string   `json:"names"`
+	Synonyms  map[string][]string `json:"synonyms"`
+	CreatedAt int64               `json:"createdAt"`
+	UpdatedAt int64               `json:"updatedAt"`
+}
+
+type CreateTaxonomyTagRequest struct {
+	// derived from the English name, or else the first name, when empty
+	Slug     string              `json:"slug"`
+	Category models.TagCategory  `json:"category" binding:"required"`
+	Names    map[string]string   `json:"names" binding:"required"`
+	Synonyms map[string][]string `json:"synonyms"`
+}
+
+type UpdateTaxonomyTagRequest struct {
+	Category models.TagCategory  `json:"category" binding:"required"`
+	Names    map[string]string   `json:"names" binding:"required"`
+	Synonyms map[string][]string `json:"synonyms"`
+}
+
+type MigrateAddressTagsRequest struct {
+	Language models.Language `json:"language" binding:"required"`
+	// create taxonomy tags for unknown tags, categorized by their position like before the taxonomy
+	Seed bool `json:"seed,omitempty"`
+}
+
+type ListTaxonomyTagsResponse struct {
+	Data []TaxonomyTagDTO `json:"data"`
+}
+
+type TaxonomyTagResponse struct {
+	Data TaxonomyTagDTO `json:"data"`
+}
+
+type TaxonomyTagSlug struct {
+	Slug string `json:"slug"`
+}
+
+type DeleteTaxonomyTagResponse struct {
+	Data TaxonomyTagSlug `json:"data"`
+}
+
+func ToTaxonomyTagDTO(tag models.Tag) TaxonomyTagDTO {
+	synonyms := tag.Synonyms
+	if synonyms == nil {
+		synonyms = map[string][]string{}
+	}
+	return TaxonomyTagDTO{
+		Slug:      tag.Slug,
+		Category:  tag.Category,
+		Names:     tag.Names,
+		Synonyms:  synonyms,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+func ToTaxonomyTagDTOs(tags []models.Tag) []TaxonomyTagDTO {
+	output := make([]TaxonomyTagDTO, len(tags))
+	for i, tag := range tags {
+		output[i] = ToTaxonomyTagDTO(tag)
+	}
+	return output
+}
+
+func FromCreateTaxonomyTagDTO(req CreateTaxonomyTagRequest) models.Tag {
+	return models.Tag{
+		Slug:     req.Slug,
+		Category: req.Category,
+		Names:    req.Names,
+		Synonyms: req.Synonyms,
+	}
+}
+
+func FromUpdateTaxonomyTagDTO(slug string, req UpdateTaxonomyTagRequest) models.Tag {
+	return models.Tag{
+		Slug:     slug,
+		Category: req.Category,
+		Names:    req.Names,
+		Synonyms: req.Synonyms,
+	}
+}
@@ -0,0 +1,86 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type AddressRefDTO struct {
+	Language models.Language `json:"language" binding:"required"`
+	ID       string          `json:"id" binding:"required"`
+}
+
+type LinkAddressTranslationsRequest struct {
+	Entries []AddressRefDTO `json:"entries" binding:"required,min=2,dive"`
+}
+
+type UnlinkAddressTranslationRequest struct {
+	Language models.Language `json:"language" binding:"required"`
+	ID       string          `json:"id" binding:"required"`
+}
+
+type TranslateAddressesRequest struct {
+	From            models.Language `json:"from" binding:"required"`
+	To              models.Language `json:"to" binding:"required"`
+	IDs             []string        `json:"ids" binding:"required,min=1"`
+	Model           string          `json:"model" binding:"required"`
+	ReasoningEffort string          `json:"reasoningEffort,omitempty"`
+	ThinkingLevel   string          `json:"thinkingLevel,omitempty"`
+}
+
+// The translations are staged for review like generated addresses, see PendingAddressDTO
+type TranslateAddressesResponse struct {
+	Data         []PendingAddressDTO `json:"data"`
+	Untranslated []string            `json:"untranslated"` // source IDs the model returned no translation for
+	Translated   []string            `json:"translated"`   // source IDs linked to an entry in the target language already
+}
+
+type TranslationGroupDTO struct {
+	ID        string            `json:"id"`
+	Members   map[string]string `json:"members"` // language code to address ID
+	CreatedAt int64             `json:"createdAt"`
+	UpdatedAt int64             `json:"updatedAt"`
+}
+
+type LinkAddressTranslationsResponse struct {
+	Data TranslationGroupDTO `json:"data"`
+}
+
+type UnlinkAddressTranslationResponse struct {
+	Data AddressID `json:"data"`
+}
+
+type MissingTranslationDTO struct {
+	Address          AddressItemDTO    `json:"address"`
+	MissingLanguages []models.Language `json:"missingLanguages"`
+}
+
+type ListMissingTranslationsResponse struct {
+	Total int                     `json:"total"`
+	Data  []MissingTranslationDTO `json:"data"`
+}
+
+func FromAddressRefDTOs(refs []AddressRefDTO) []models.AddressRef {
+	output := make([]models.AddressRef, len(refs))
+	for i, ref := range refs {
+		output[i] = models.AddressRef{Language: ref.Language, ID: ref.ID}
+	}
+	return output
+}
+
+func ToTranslationGroupDTO(group models.TranslationGroup) TranslationGroupDTO {
+	return TranslationGroupDTO{
+		ID:        group.ID,
+		Members:   group.Members,
+		CreatedAt: group.CreatedAt,
+		UpdatedAt: group.UpdatedAt,
+	}
+}
+
+func ToMissingTranslationDTOs(missing []models.MissingTranslation) []MissingTranslationDTO {
+	output := make([]MissingTranslationDTO, len(missing))
+	for i, item := range missing {
+		output[i] = MissingTranslationDTO{
+			Address:          ToAddressDTO(item.Address),
+			MissingLanguages: item.MissingLanguages,
+		}
+	}
+	return output
+}
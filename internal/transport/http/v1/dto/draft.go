@@ -0,0 +1,64 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type DraftDTO struct {
+	ID                 string          `json:"id"`
+	RecipientAddressID string          `json:"recipientAddressId"`
+	Language           models.Language `json:"language"`
+	Body               string          `json:"body"`
+	MusicFilename      string          `json:"musicFilename,omitempty"`
+	CreatedAt          int64           `json:"createdAt"`
+	UpdatedAt          int64           `json:"updatedAt"`
+}
+
+type CreateDraftRequest struct {
+	RecipientAddressID string          `json:"recipientAddressId"`
+	Language           models.Language `json:"language" binding:"required"`
+	Body               string          `json:"body"`
+	MusicFilename      string          `json:"musicFilename,omitempty"`
+}
+
+// Omitted fields are left unchanged
+type UpdateDraftRequest struct {
+	RecipientAddressID *string          `json:"recipientAddressId,omitempty"`
+	Language           *models.Language `json:"language,omitempty"`
+	Body               *string          `json:"body,omitempty"`
+	MusicFilename      *string          `json:"musicFilename,omitempty"`
+}
+
+type DraftResponse struct {
+	Data DraftDTO `json:"data"`
+}
+
+type ListDraftsResponse struct {
+	Data []DraftDTO `json:"data"`
+}
+
+type DraftID struct {
+	ID string `json:"id"`
+}
+
+type DeleteDraftResponse struct {
+	Data DraftID `json:"data"`
+}
+
+func ToDraftDTO(draft models.Draft) DraftDTO {
+	return DraftDTO{
+		ID:                 draft.ID,
+		RecipientAddressID: draft.RecipientAddressID,
+		Language:           draft.Language,
+		Body:               draft.Body,
+		MusicFilename:      draft.MusicFilename,
+		CreatedAt:          draft.CreatedAt,
+		UpdatedAt:          draft.UpdatedAt,
+	}
+}
+
+func ToDraftDTOs(drafts []models.Draft) []DraftDTO {
+	output := make([]DraftDTO, len(drafts))
+	for i, draft := range drafts {
+		output[i] = ToDraftDTO(draft)
+	}
+	return output
+}
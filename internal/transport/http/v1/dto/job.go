@@ -0,0 +1,53 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type JobDTO struct {
+	ID              string            `json:"id"`
+	Type            models.JobType    `json:"type"`
+	Status          models.JobStatus  `json:"status"`
+	Params          map[string]string `json:"params"`
+	Processed       int64             `json:"processed"`
+	Failed          int64             `json:"failed"`
+	Error           string            `json:"error,omitempty"`
+	CancelRequested bool              `json:"cancelRequested"`
+	CreatedBy       string            `json:"createdBy"`
+	CreatedAt       int64             `json:"createdAt"`
+	StartedAt       int64             `json:"startedAt,omitempty"`
+	FinishedAt      int64             `json:"finishedAt,omitempty"`
+	UpdatedAt       int64             `json:"updatedAt"`
+}
+
+type JobResponse struct {
+	Data JobDTO `json:"data"`
+}
+
+type ListJobsResponse struct {
+	Data []JobDTO `json:"data"`
+}
+
+func ToJobDTO(job models.Job) JobDTO {
+	return JobDTO{
+		ID:              job.ID,
+		Type:            job.Type,
+		Status:          job.Status,
+		Params:          job.Params,
+		Processed:       job.Processed,
+		Failed:          job.Failed,
+		Error:           job.Error,
+		CancelRequested: job.CancelRequested,
+		CreatedBy:       job.CreatedBy,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		UpdatedAt:       job.UpdatedAt,
+	}
+}
+
+func ToJobDTOs(jobs []models.Job) []JobDTO {
+	output := make([]JobDTO, len(jobs))
+	for i, job := range jobs {
+		output[i] = ToJobDTO(job)
+	}
+	return output
+}
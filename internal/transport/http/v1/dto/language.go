@@ -0,0 +1,54 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type LanguageDTO struct {
+	Code     models.Language `json:"code"`
+	Name     string          `json:"name"`
+	Locale   string          `json:"locale"`
+	Fallback models.Language `json:"fallback,omitempty"`
+	Enabled  bool            `json:"enabled"`
+}
+
+type UpsertLanguageRequest struct {
+	Name     string          `json:"name" binding:"required"`
+	Locale   string          `json:"locale"` // the language code by default
+	Fallback models.Language `json:"fallback"`
+	Enabled  bool            `json:"enabled"`
+}
+
+type ListLanguagesResponse struct {
+	Data []LanguageDTO `json:"data"`
+}
+
+type LanguageResponse struct {
+	Data LanguageDTO `json:"data"`
+}
+
+func ToLanguageDTO(config models.LanguageConfig) LanguageDTO {
+	return LanguageDTO{
+		Code:     config.Code,
+		Name:     config.Name,
+		Locale:   config.Locale,
+		Fallback: config.Fallback,
+		Enabled:  config.Enabled,
+	}
+}
+
+func ToLanguageDTOs(configs []models.LanguageConfig) []LanguageDTO {
+	output := make([]LanguageDTO, len(configs))
+	for i, config := range configs {
+		output[i] = ToLanguageDTO(config)
+	}
+	return output
+}
+
+func FromUpsertLanguageDTO(code models.Language, req UpsertLanguageRequest) models.LanguageConfig {
+	return models.LanguageConfig{
+		Code:     code,
+		Name:     req.Name,
+		Locale:   req.Locale,
+		Fallback: req.Fallback,
+		Enabled:  req.Enabled,
+	}
+}
@@ -5,6 +5,7 @@ import "north-post/service/internal/domain/v1/models"
 type MusicDTO struct {
 	Filename     string  `json:"filename"`
 	Title        string  `json:"title"`
+	Artist       string  `json:"artist"`
 	Genre        string  `json:"genre"`
 	Size         float64 `json:"size"`
 	LastModified int64   `json:"lastModified"`
@@ -19,10 +20,28 @@ type GetPresignedMusicURLResponse struct {
 	Data string `json:"data"`
 }
 
+type ReportMusicDurationRequest struct {
+	Filename    string `json:"filename" binding:"required"`
+	DurationSec int64  `json:"durationSec" binding:"required"`
+}
+
+type ReportMusicDurationResponse struct {
+	Data MusicDTO `json:"data"`
+}
+
+type UpdateLikedMusicsResponse struct {
+	Data string `json:"data"`
+}
+
+type GetLikedMusicsResponse struct {
+	Data []MusicDTO `json:"data"`
+}
+
 func ToMusicDTO(music models.Music) MusicDTO {
 	return MusicDTO{
 		Filename:     music.Filename,
 		Title:        music.Title,
+		Artist:       music.Artist,
 		Genre:        music.Genre,
 		Size:         music.Size,
 		LastModified: music.LastModified,
@@ -0,0 +1,39 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type OutboxEntryDTO struct {
+	ID            string                 `json:"id"`
+	Operation     models.OutboxOperation `json:"operation"`
+	Language      models.Language        `json:"language"`
+	AddressID     string                 `json:"addressId"`
+	Status        models.OutboxStatus    `json:"status"`
+	Attempts      int                    `json:"attempts"`
+	LastError     string                 `json:"lastError,omitempty"`
+	NextAttemptAt int64                  `json:"nextAttemptAt"`
+	CreatedAt     int64                  `json:"createdAt"`
+	UpdatedAt     int64                  `json:"updatedAt"`
+}
+
+type ListOutboxEntriesResponse struct {
+	Data []OutboxEntryDTO `json:"data"`
+}
+
+func ToOutboxEntryDTOs(entries []models.OutboxEntry) []OutboxEntryDTO {
+	output := make([]OutboxEntryDTO, len(entries))
+	for i, entry := range entries {
+		output[i] = OutboxEntryDTO{
+			ID:            entry.ID,
+			Operation:     entry.Operation,
+			Language:      entry.Language,
+			AddressID:     entry.AddressID,
+			Status:        entry.Status,
+			Attempts:      entry.Attempts,
+			LastError:     entry.LastError,
+			NextAttemptAt: entry.NextAttemptAt,
+			CreatedAt:     entry.CreatedAt,
+			UpdatedAt:     entry.UpdatedAt,
+		}
+	}
+	return output
+}
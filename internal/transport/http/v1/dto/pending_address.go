@@ -0,0 +1,90 @@
+package dto
+
+import "north-post/service/internal/domain/v1/models"
+
+type GenerationMetadataDTO struct {
+	RunID           string `json:"runId"`
+	Model           string `json:"model"`
+	SystemPrompt    string `json:"systemPrompt"`
+	Prompt          string `json:"prompt"`
+	ReasoningEffort string `json:"reasoningEffort,omitempty"`
+	ThinkingLevel   string `json:"thinkingLevel,omitempty"`
+	GeneratedBy     string `json:"generatedBy"`
+	GeneratedAt     int64  `json:"generatedAt"`
+}
+
+type PendingAddressDTO struct {
+	ID         string                `json:"id"`
+	Language   models.Language       `json:"language"`
+	Address    AddressItemDTO        `json:"address"`
+	Generation GenerationMetadataDTO `json:"generation"`
+	Source     *AddressRefDTO        `json:"source,omitempty"` // set for translations
+	CreatedAt  int64                 `json:"createdAt"`
+	UpdatedAt  int64                 `json:"updatedAt"`
+}
+
+type UpdatePendingAddressRequest struct {
+	Name       string     `json:"name" binding:"required"`
+	BriefIntro string     `json:"briefIntro" binding:"required"`
+	Tags       []string   `json:"tags" binding:"required"`
+	Address    AddressDTO `json:"address" binding:"required"`
+}
+
+type PendingAddressResponse struct {
+	Data PendingAddressDTO `json:"data"`
+}
+
+type ListPendingAddressesResponse struct {
+	Data []PendingAddressDTO `json:"data"`
+}
+
+type ApprovePendingAddressResponse struct {
+	Data AddressItemDTO `json:"data"` // the address created in the catalog
+}
+
+type RejectPendingAddressResponse struct {
+	Data AddressID `json:"data"`
+}
+
+func ToPendingAddressDTO(pending models.PendingAddress) PendingAddressDTO {
+	generation := pending.Generation
+	var source *AddressRefDTO
+	if pending.Source != nil {
+		source = &AddressRefDTO{Language: pending.Source.Language, ID: pending.Source.ID}
+	}
+	return PendingAddressDTO{
+		ID:       pending.ID,
+		Language: pending.Language,
+		Address:  ToAddressDTO(pending.Address),
+		Generation: GenerationMetadataDTO{
+			RunID:           generation.RunID,
+			Model:           generation.Model,
+			SystemPrompt:    generation.SystemPrompt,
+			Prompt:          generation.Prompt,
+			ReasoningEffort: generation.ReasoningEffort,
+			ThinkingLevel:   generation.ThinkingLevel,
+			GeneratedBy:     generation.GeneratedBy,
+			GeneratedAt:     generation.GeneratedAt,
+		},
+		Source:    source,
+		CreatedAt: pending.CreatedAt,
+		UpdatedAt: pending.UpdatedAt,
+	}
+}
+
+func ToPendingAddressDTOs(pending []models.PendingAddress) []PendingAddressDTO {
+	output := make([]PendingAddressDTO, len(pending))
+	for i, item := range pending {
+		output[i] = ToPendingAddressDTO(item)
+	}
+	return output
+}
+
+func FromUpdatePendingAddressDTO(req UpdatePendingAddressRequest) models.AddressItem {
+	return models.AddressItem{
+		Name:       req.Name,
+		BriefIntro: req.BriefIntro,
+		Tags:       req.Tags,
+		Address:    FromAddressDTO(req.Address),
+	}
+}
@@ -6,15 +6,37 @@ type SignInAdminUserResponse struct {
 	Data AdminUserDTO `json:"data"`
 }
 
+type ListAdminUsersResponse struct {
+	Data []AdminUserDTO `json:"data"`
+}
+
+type GrantAdminRoleRequest struct {
+	Role models.AdminRole `json:"role" binding:"required"`
+}
+
+type GrantAdminRoleResponse struct {
+	Data AdminUserDTO `json:"data"`
+}
+
+type AdminUid struct {
+	Uid string `json:"uid"`
+}
+
+type RevokeAdminRoleResponse struct {
+	Data AdminUid `json:"data"`
+}
+
 type AuthenticateAppUserResponse struct {
 	Data AppUserDTO `json:"data"`
 }
 
 type AdminUserDTO struct {
-	Email       string `json:"email"`
-	DisplayName string `json:"displayName"`
-	LastLogin   int64  `json:"lastLogin"`
-	ImageUrl    string `json:"imageUrl,omitempty"`
+	Uid         string           `json:"uid"`
+	Email       string           `json:"email"`
+	DisplayName string           `json:"displayName"`
+	LastLogin   int64            `json:"lastLogin"`
+	ImageUrl    string           `json:"imageUrl,omitempty"`
+	Role        models.AdminRole `json:"role"`
 }
 
 type AppUserAddressBookDTO struct {
@@ -30,15 +52,34 @@ type AppUserDTO struct {
 	LikedMusics []string              `json:"likedMusics"`
 	Drafts      []string              `json:"drafts"`
 	AddressBook AppUserAddressBookDTO `json:"addressBook"`
+	Language    models.Language       `json:"language,omitempty"`
+}
+
+type UpdateUserLanguageRequest struct {
+	Language models.Language `json:"language" binding:"required"`
+}
+
+type UpdateUserLanguageResponse struct {
+	Data string `json:"data"`
 }
 
 func ToAdminUserDTO(adminUser models.AdminUser) AdminUserDTO {
 	return AdminUserDTO{
+		Uid:         adminUser.Uid,
 		Email:       adminUser.Email,
 		DisplayName: adminUser.DisplayName,
 		LastLogin:   adminUser.LastLogin,
 		ImageUrl:    adminUser.ImageUrl,
+		Role:        adminUser.Role,
+	}
+}
+
+func ToAdminUserDTOs(adminUsers []models.AdminUser) []AdminUserDTO {
+	output := make([]AdminUserDTO, len(adminUsers))
+	for i, adminUser := range adminUsers {
+		output[i] = ToAdminUserDTO(adminUser)
 	}
+	return output
 }
 
 func ToAppUserDTO(appUser *models.AppUser) AppUserDTO {
@@ -55,5 +96,6 @@ func ToAppUserDTO(appUser *models.AppUser) AppUserDTO {
 		LikedMusics: appUser.LikedMusics,
 		Drafts:      appUser.Drafts,
 		AddressBook: addressBook,
+		Language:    appUser.Language,
 	}
 }
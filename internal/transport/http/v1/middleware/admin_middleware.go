@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/transport/http/v1/dto"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AdminRoleKey = "admin_role"
+
+	adminCacheTTL = time.Minute
+)
+
+type adminUserChecker interface {
+	GetAdminRole(ctx context.Context, uid string) (models.AdminRole, error)
+}
+
+// AdminMiddleware must run after AuthMiddleware. The admin users collection is the only source
+// of the admin role, custom claims of the id token are ignored so revoking a role can't be
+// bypassed by a token that still carries one. Roles are cached per uid for adminCacheTTL,
+// so role changes take effect within that window.
+func AdminMiddleware(checker adminUserChecker, logger *slog.Logger) gin.HandlerFunc {
+	cache := newTTLCache[models.AdminRole](adminCacheTTL)
+	return adminMiddleware(checker, cache, logger)
+}
+
+func adminMiddleware(
+	checker adminUserChecker,
+	cache *ttlCache[models.AdminRole],
+	logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.GetString(UidKey)
+		clientIP := c.ClientIP()
+		if uid == "" {
+			logger.Error("missing user id from the auth middleware", "path", c.Request.URL.Path, "clientIP", clientIP)
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized id token"})
+			c.Abort()
+			return
+		}
+		role, found := cache.get(uid)
+		if !found {
+			var err error
+			role, err = checker.GetAdminRole(c.Request.Context(), uid)
+			if err != nil {
+				logger.Error("failed to check admin user", "uid", uid, "error", err)
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to verify admin user"})
+				c.Abort()
+				return
+			}
+			cache.set(uid, role)
+		}
+		if role == "" {
+			logger.Warn("non-admin user requested admin route", "uid", uid, "path", c.Request.URL.Path, "clientIP", clientIP)
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Admin access required"})
+			c.Abort()
+			return
+		}
+		c.Set(AdminRoleKey, role)
+		c.Next()
+	}
+}
+
+// PermissionMiddleware must run after AdminMiddleware, each admin route declares the permission it needs
+func PermissionMiddleware(permission models.Permission, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(AdminRoleKey)
+		role, _ := value.(models.AdminRole)
+		if !role.HasPermission(permission) {
+			logger.Warn("admin user lacks permission",
+				"uid", c.GetString(UidKey),
+				"role", role,
+				"permission", permission,
+				"path", c.Request.URL.Path,
+			)
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Permission denied"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"north-post/service/internal/domain/v1/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockAdminUserChecker implements adminUserChecker for testing
+type MockAdminUserChecker struct {
+	GetAdminRoleFn func(ctx context.Context, uid string) (models.AdminRole, error)
+	calls          int
+}
+
+func (m *MockAdminUserChecker) GetAdminRole(ctx context.Context, uid string) (models.AdminRole, error) {
+	m.calls++
+	return m.GetAdminRoleFn(ctx, uid)
+}
+
+func setupAdminTestContext(uid string) (
+	*gin.Context,
+	*httptest.ResponseRecorder,
+) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/test", nil)
+	if uid != "" {
+		c.Set(UidKey, uid)
+	}
+	return c, w
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		uid           string
+		role          models.AdminRole
+		checkerError  error
+		status        int
+		abort         bool
+		expectedCalls int
+		errorMessage  string
+	}{
+		{
+			name:          "admin from database",
+			uid:           "admin-uid",
+			role:          models.AdminRoleEditor,
+			status:        http.StatusOK,
+			abort:         false,
+			expectedCalls: 1,
+		},
+		{
+			name:          "non admin user",
+			uid:           "app-user",
+			role:          "",
+			status:        http.StatusForbidden,
+			abort:         true,
+			expectedCalls: 1,
+			errorMessage:  "non-admin user requested admin route",
+		},
+		{
+			name:          "missing uid",
+			uid:           "",
+			status:        http.StatusUnauthorized,
+			abort:         true,
+			expectedCalls: 0,
+			errorMessage:  "missing user id",
+		},
+		{
+			name:          "checker error",
+			uid:           "admin-uid",
+			checkerError:  errors.New("firestore unavailable"),
+			status:        http.StatusInternalServerError,
+			abort:         true,
+			expectedCalls: 1,
+			errorMessage:  "failed to check admin user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &MockAdminUserChecker{
+				GetAdminRoleFn: func(ctx context.Context, uid string) (models.AdminRole, error) {
+					return tt.role, tt.checkerError
+				},
+			}
+			var logBuffer bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
+			c, w := setupAdminTestContext(tt.uid)
+			AdminMiddleware(checker, logger)(c)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.abort, c.IsAborted())
+			assert.Equal(t, tt.expectedCalls, checker.calls)
+			assert.Contains(t, logBuffer.String(), tt.errorMessage)
+			if !tt.abort {
+				role, exists := c.Get(AdminRoleKey)
+				assert.True(t, exists)
+				assert.NotEmpty(t, role)
+			}
+			if tt.abort {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.NotEmpty(t, response["error"])
+			}
+		})
+	}
+}
+
+func TestAdminMiddleware_CachesDecision(t *testing.T) {
+	checker := &MockAdminUserChecker{
+		GetAdminRoleFn: func(ctx context.Context, uid string) (models.AdminRole, error) {
+			if uid == "admin-uid" {
+				return models.AdminRoleViewer, nil
+			}
+			return "", nil
+		},
+	}
+	now := time.Now()
+	cache := newTTLCache[models.AdminRole](time.Minute)
+	cache.now = func() time.Time { return now }
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	middleware := adminMiddleware(checker, cache, logger)
+
+	// first request hits the checker, the second one is served from the cache
+	for range 2 {
+		c, w := setupAdminTestContext("admin-uid")
+		middleware(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, 1, checker.calls)
+
+	// negative decisions are cached as well
+	for range 2 {
+		c, w := setupAdminTestContext("app-user")
+		middleware(c)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+	assert.Equal(t, 2, checker.calls)
+
+	// expired entries are checked again
+	now = now.Add(2 * time.Minute)
+	c, w := setupAdminTestContext("admin-uid")
+	middleware(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, checker.calls)
+}
+
+func TestPermissionMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       models.AdminRole
+		permission models.Permission
+		status     int
+		abort      bool
+	}{
+		{
+			name:       "viewer can read",
+			role:       models.AdminRoleViewer,
+			permission: models.PermissionReadContent,
+			status:     http.StatusOK,
+			abort:      false,
+		},
+		{
+			name:       "viewer can't write",
+			role:       models.AdminRoleViewer,
+			permission: models.PermissionWriteAddress,
+			status:     http.StatusForbidden,
+			abort:      true,
+		},
+		{
+			name:       "editor can generate",
+			role:       models.AdminRoleEditor,
+			permission: models.PermissionGenerateAddress,
+			status:     http.StatusOK,
+			abort:      false,
+		},
+		{
+			name:       "editor can't sync index",
+			role:       models.AdminRoleEditor,
+			permission: models.PermissionSyncIndex,
+			status:     http.StatusForbidden,
+			abort:      true,
+		},
+		{
+			name:       "owner can manage admins",
+			role:       models.AdminRoleOwner,
+			permission: models.PermissionManageAdmins,
+			status:     http.StatusOK,
+			abort:      false,
+		},
+		{
+			name:       "missing role",
+			role:       "",
+			permission: models.PermissionReadContent,
+			status:     http.StatusForbidden,
+			abort:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := setupAdminTestContext("admin-uid")
+			if tt.role != "" {
+				c.Set(AdminRoleKey, tt.role)
+			}
+			logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+			PermissionMiddleware(tt.permission, logger)(c)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.abort, c.IsAborted())
+		})
+	}
+}
@@ -12,9 +12,7 @@ import (
 	"firebase.google.com/go/v4/auth"
 )
 
-const (
-	UidKey = "user_id"
-)
+const UidKey = "user_id"
 
 type authClient interface {
 	VerifyIDToken(c context.Context, idToken string) (*auth.Token, error)
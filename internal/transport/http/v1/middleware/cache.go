@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// A small TTL cache used by middlewares to avoid hitting the database on every request.
+// Expired entries are swept at most once per ttl, so inserts don't scan the whole map
+type ttlCache[V any] struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	entries   map[string]ttlCacheEntry[V]
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+type ttlCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration) *ttlCache[V] {
+	return &ttlCache[V]{
+		ttl:     ttl,
+		entries: make(map[string]ttlCacheEntry[V]),
+		now:     time.Now,
+	}
+}
+
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok || c.now().After(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache[V]) set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	// drop expired entries now and then while we hold the lock so the map can't grow forever
+	if now.After(c.nextSweep) {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	c.entries[key] = ttlCacheEntry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *ttlCache[V]) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLCache_SweepsExpiredEntriesOncePerTTL(t *testing.T) {
+	now := time.Now()
+	cache := newTTLCache[string](time.Minute)
+	cache.now = func() time.Time { return now }
+	cache.set("a", "1")
+
+	// within the ttl nothing is swept
+	now = now.Add(30 * time.Second)
+	cache.set("b", "2")
+	assert.Len(t, cache.entries, 2)
+
+	// the next insert past the sweep time drops what expired
+	now = now.Add(45 * time.Second)
+	cache.set("c", "3")
+	assert.Len(t, cache.entries, 2)
+	_, found := cache.get("a")
+	assert.False(t, found)
+	value, found := cache.get("b")
+	assert.True(t, found)
+	assert.Equal(t, "2", value)
+
+	cache.delete("b")
+	_, found = cache.get("b")
+	assert.False(t, found)
+}
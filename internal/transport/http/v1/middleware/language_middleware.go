@@ -1,54 +1,175 @@
 package middleware
 
 import (
+	"cmp"
+	"context"
 	"log/slog"
 	"net/http"
 	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/transport/http/v1/dto"
-	"north-post/service/internal/transport/http/v1/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 )
 
 const (
-	LanguageKey = "language"
+	LanguageKey           = "language"
+	ContentLanguageHeader = "Content-Language"
+
+	languagePreferenceCacheTTL = time.Minute
 )
 
-func LanguageFromQueryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+type languagePreferenceReader interface {
+	GetUserLanguage(ctx context.Context, uid string) (models.Language, error)
+}
+
+// Stored languages cached per uid for languagePreferenceCacheTTL, shared with the handler that
+// stores a preference so the new one applies to the next request
+type LanguagePreferenceCache struct {
+	entries *ttlCache[models.Language]
+}
+
+func NewLanguagePreferenceCache() *LanguagePreferenceCache {
+	return &LanguagePreferenceCache{entries: newTTLCache[models.Language](languagePreferenceCacheTTL)}
+}
+
+// Drop the cached language of the user, the next request reads the stored one
+func (c *LanguagePreferenceCache) Forget(uid string) {
+	c.entries.delete(uid)
+}
+
+// LanguageMiddleware must run after AuthMiddleware. The language of the request is negotiated from, in order,
+// the `language` query parameter, the `language` field of a JSON body, the Accept-Language header and the
+// language the user stored. A requested language that is disabled gives way to its fallbacks, then to the
+// default chain of the registry. An unknown language given explicitly is rejected, unknown header values are
+// skipped. The resolved language is stored under LanguageKey and sent back in the Content-Language header.
+// Stored preferences are read through cache.
+func LanguageMiddleware(
+	preferences languagePreferenceReader,
+	cache *LanguagePreferenceCache,
+	logger *slog.Logger) gin.HandlerFunc {
+	return languageMiddleware(preferences, cache.entries, logger)
+}
+
+func languageMiddleware(
+	preferences languagePreferenceReader,
+	cache *ttlCache[models.Language],
+	logger *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		language := models.Language(c.Query("language"))
-		if language == "" {
-			logger.Error("language query is required")
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "language is required"})
-			c.Abort()
-			return
-		}
-		if !utils.ValidateLanguage(c, language, logger) {
-			c.Abort()
-			return
+		var candidates []models.Language
+		if requested := requestedLanguage(c); requested != "" {
+			if err := requested.Validate(); err != nil {
+				logger.Warn("invalid language", "language", requested, "error", err)
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+				c.Abort()
+				return
+			}
+			candidates = []models.Language{requested}
+		} else {
+			candidates = acceptedLanguages(c.GetHeader("Accept-Language"))
+			if !slices.ContainsFunc(candidates, models.Language.IsEnabled) {
+				if preferred := storedLanguage(c, preferences, cache, logger); preferred != "" {
+					candidates = append(candidates, preferred)
+				}
+			}
 		}
+		language := models.Languages().Negotiate(candidates)
 		c.Set(LanguageKey, language.Get())
+		c.Header(ContentLanguageHeader, language.Get())
+		c.Writer.Header().Add("Vary", "Accept-Language")
 		c.Next()
 	}
 }
 
-func LanguageFromBodyMiddleware(logger *slog.Logger) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		var req struct {
-			Language models.Language `json:"language"`
+// ---------- Helper functions ----------
+
+// The language asked for explicitly, from the query or else from a JSON body.
+// A body that isn't JSON is left to the handler
+func requestedLanguage(c *gin.Context) models.Language {
+	if language := strings.TrimSpace(c.Query("language")); language != "" {
+		return models.Language(language)
+	}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || c.ContentType() != binding.MIMEJSON {
+		return ""
+	}
+	var req struct {
+		Language models.Language `json:"language"`
+	}
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		return ""
+	}
+	return models.Language(strings.TrimSpace(string(req.Language)))
+}
+
+// Languages of an Accept-Language header ordered by quality, a tag with subtags is followed by its
+// shorter forms so zh-Hans-CN also matches zh. Wildcards and tags with quality 0 are left out
+func acceptedLanguages(header string) []models.Language {
+	type weighted struct {
+		language models.Language
+		quality  float64
+	}
+	accepted := []weighted{}
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
 		}
-		if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
-			logger.Error("failed to bind language body", "error", err)
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid request body"})
-			c.Abort()
-			return
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
 		}
-		if !utils.ValidateLanguage(c, req.Language, logger) {
-			c.Abort()
-			return
+		if quality <= 0 {
+			continue
 		}
-		c.Set(LanguageKey, req.Language.Get())
-		c.Next()
+		for {
+			accepted = append(accepted, weighted{language: models.Language(tag), quality: quality})
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	slices.SortStableFunc(accepted, func(a, b weighted) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+	languages := make([]models.Language, 0, len(accepted))
+	for _, item := range accepted {
+		if !slices.Contains(languages, item.language) {
+			languages = append(languages, item.language)
+		}
+	}
+	return languages
+}
+
+// Language stored by the signed in user, empty when there is none or it can't be read
+func storedLanguage(
+	c *gin.Context,
+	preferences languagePreferenceReader,
+	cache *ttlCache[models.Language],
+	logger *slog.Logger) models.Language {
+	uid := c.GetString(UidKey)
+	if uid == "" {
+		return ""
+	}
+	if language, found := cache.get(uid); found {
+		return language
+	}
+	language, err := preferences.GetUserLanguage(c.Request.Context(), uid)
+	if err != nil {
+		// the request can still be served in a default language
+		logger.Warn("failed to read stored language", "uid", uid, "error", err)
+		return ""
 	}
+	cache.set(uid, language)
+	return language
 }
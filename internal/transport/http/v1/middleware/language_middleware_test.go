@@ -2,11 +2,16 @@ package middleware
 
 import (
 	"bytes"
-	"fmt"
+	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -16,133 +21,238 @@ func init() {
 	gin.SetMode(gin.TestMode)
 }
 
-func setupLanguageFromQueryTestContext(queryString string) (
-	*gin.Context,
-	*httptest.ResponseRecorder,
-	*bytes.Buffer,
-) {
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	req := httptest.NewRequest("GET", fmt.Sprintf("/test?%s", queryString), nil)
-	c.Request = req
-	var logBuffer bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
-	middleware := LanguageFromQueryMiddleware(logger)
-	middleware(c)
-	return c, w, &logBuffer
+// MockLanguagePreferenceReader implements languagePreferenceReader for testing
+type MockLanguagePreferenceReader struct {
+	language models.Language
+	err      error
+	calls    int
+}
+
+func (m *MockLanguagePreferenceReader) GetUserLanguage(ctx context.Context, uid string) (models.Language, error) {
+	m.calls++
+	return m.language, m.err
+}
+
+type languageTestRequest struct {
+	query          string
+	body           string
+	contentType    string
+	acceptLanguage string
+	uid            string
 }
 
-func setupLanguageFromBodyTestContext(body string) (
-	*gin.Context,
-	*httptest.ResponseRecorder,
-	*bytes.Buffer,
-) {
+func setupLanguageTestContext(req languageTestRequest) (*gin.Context, *httptest.ResponseRecorder) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	req := httptest.NewRequest("POST", "/test", bytes.NewBufferString(body))
-	req.Header.Set("Content-Type", "application/json")
-	c.Request = req
-	var logBuffer bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&logBuffer, nil))
-	middleware := LanguageFromBodyMiddleware(logger)
-	middleware(c)
-	return c, w, &logBuffer
+	method := "GET"
+	var body io.Reader
+	if req.body != "" {
+		method = "POST"
+		body = bytes.NewBufferString(req.body)
+	}
+	c.Request = httptest.NewRequest(method, "/test?"+req.query, body)
+	if req.body != "" {
+		c.Request.Header.Set("Content-Type", req.contentType)
+	}
+	if req.acceptLanguage != "" {
+		c.Request.Header.Set("Accept-Language", req.acceptLanguage)
+	}
+	if req.uid != "" {
+		c.Set(UidKey, req.uid)
+	}
+	return c, w
 }
 
-func TestLanguageFromQueryMiddleware(t *testing.T) {
+// Swap the languages of the registry for the test, the built-in ones are restored afterwards
+func setLanguageRegistry(t *testing.T, configs []models.LanguageConfig, defaultChain []models.Language) {
+	assert.NoError(t, models.Languages().Replace(configs))
+	models.Languages().SetDefaultChain(defaultChain)
+	t.Cleanup(func() {
+		assert.NoError(t, models.Languages().Replace(models.DefaultLanguageConfigs))
+		models.Languages().SetDefaultChain([]models.Language{models.LanguageEN})
+	})
+}
+
+func TestLanguageMiddleware(t *testing.T) {
 	tests := []struct {
-		name          string
-		query         string
-		expectedValue string
-		status        int
-		abort         bool
-		errorMessage  string
+		name             string
+		request          languageTestRequest
+		stored           models.Language
+		storedErr        error
+		expectedLanguage string
+		expectedStatus   int
+		expectedCalls    int
 	}{
 		{
-			name:          "success",
-			query:         "language=zh",
-			expectedValue: "zh",
-			status:        http.StatusOK,
-			abort:         false,
-			errorMessage:  "",
+			name:             "query wins over the header",
+			request:          languageTestRequest{query: "language=ZH", acceptLanguage: "en"},
+			expectedLanguage: "zh",
+			expectedStatus:   http.StatusOK,
 		},
 		{
-			name:          "missing language query",
-			query:         "",
-			expectedValue: "",
-			status:        http.StatusBadRequest,
-			abort:         true,
-			errorMessage:  "language query is required",
+			name: "json body wins over the header",
+			request: languageTestRequest{
+				body: `{"language":"zh"}`, contentType: "application/json", acceptLanguage: "en",
+			},
+			expectedLanguage: "zh",
+			expectedStatus:   http.StatusOK,
 		},
 		{
-			name:          "invalid language",
-			query:         "language=abc",
-			expectedValue: "",
-			status:        http.StatusBadRequest,
-			abort:         true,
-			errorMessage:  "invalid language",
+			name: "body that isn't json is left to the handler",
+			request: languageTestRequest{
+				body: `language=zh`, contentType: "application/x-www-form-urlencoded", acceptLanguage: "en",
+			},
+			expectedLanguage: "en",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "header ordered by quality",
+			request:          languageTestRequest{acceptLanguage: "en;q=0.5, zh;q=0.9"},
+			expectedLanguage: "zh",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "header tag with subtags",
+			request:          languageTestRequest{acceptLanguage: "zh-Hans-CN, en;q=0.8"},
+			expectedLanguage: "zh",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "unknown header languages are skipped",
+			request:          languageTestRequest{acceptLanguage: "fr-FR, fr;q=0.9, zh;q=0.3"},
+			expectedLanguage: "zh",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "stored language when the header has no match",
+			request:          languageTestRequest{acceptLanguage: "fr", uid: "user-1"},
+			stored:           models.LanguageZH,
+			expectedLanguage: "zh",
+			expectedStatus:   http.StatusOK,
+			expectedCalls:    1,
+		},
+		{
+			name:             "stored language not read when the header matches",
+			request:          languageTestRequest{acceptLanguage: "en", uid: "user-1"},
+			stored:           models.LanguageZH,
+			expectedLanguage: "en",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "unreadable stored language",
+			request:          languageTestRequest{uid: "user-1"},
+			storedErr:        errors.New("firestore unavailable"),
+			expectedLanguage: "en",
+			expectedStatus:   http.StatusOK,
+			expectedCalls:    1,
+		},
+		{
+			name:             "default chain",
+			request:          languageTestRequest{acceptLanguage: "*"},
+			expectedLanguage: "en",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:           "invalid query language",
+			request:        languageTestRequest{query: "language=abc", acceptLanguage: "en"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid body language",
+			request: languageTestRequest{
+				body: `{"language":"abc"}`, contentType: "application/json",
+			},
+			expectedStatus: http.StatusBadRequest,
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, w, logBuffer := setupLanguageFromQueryTestContext(tt.query)
-			logOutput := logBuffer.String()
-			assert.Equal(t, tt.status, w.Code)
-			assert.Equal(t, tt.abort, c.IsAborted())
-			assert.Contains(t, logOutput, tt.errorMessage)
-			if !tt.abort {
-				assert.Equal(t, c.MustGet(LanguageKey), tt.expectedValue)
+			preferences := &MockLanguagePreferenceReader{language: tt.stored, err: tt.storedErr}
+			middleware := LanguageMiddleware(preferences, NewLanguagePreferenceCache(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			c, w := setupLanguageTestContext(tt.request)
+			middleware(c)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedCalls, preferences.calls)
+			if tt.expectedStatus != http.StatusOK {
+				assert.True(t, c.IsAborted())
+				return
 			}
+			assert.False(t, c.IsAborted())
+			assert.Equal(t, tt.expectedLanguage, c.GetString(LanguageKey))
+			assert.Equal(t, tt.expectedLanguage, w.Header().Get(ContentLanguageHeader))
+			assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
 		})
 	}
 }
 
-func TestLanguageFromBodyMiddleware(t *testing.T) {
+func TestLanguageMiddleware_Fallbacks(t *testing.T) {
+	configs := slices.Clone(models.DefaultLanguageConfigs)
+	configs = append(configs,
+		models.LanguageConfig{Code: "ja", Name: "Japanese", Locale: "ja", Fallback: models.LanguageZH},
+		models.LanguageConfig{Code: "fr", Name: "French", Locale: "fr", Enabled: true},
+	)
+	setLanguageRegistry(t, configs, []models.Language{"fr"})
+	middleware := LanguageMiddleware(&MockLanguagePreferenceReader{}, NewLanguagePreferenceCache(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// a disabled language gives way to its own fallback before the default chain
+	c, w := setupLanguageTestContext(languageTestRequest{query: "language=ja"})
+	middleware(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "zh", c.GetString(LanguageKey))
+
+	// an enabled language further down the header wins over the fallback of a disabled one
+	c, _ = setupLanguageTestContext(languageTestRequest{acceptLanguage: "ja, en;q=0.2"})
+	middleware(c)
+	assert.Equal(t, "en", c.GetString(LanguageKey))
+
+	// the configured default chain applies when nothing matches
+	c, w = setupLanguageTestContext(languageTestRequest{acceptLanguage: "de"})
+	middleware(c)
+	assert.Equal(t, "fr", c.GetString(LanguageKey))
+	assert.Equal(t, "fr", w.Header().Get(ContentLanguageHeader))
+}
+
+func TestLanguageMiddleware_CachesStoredLanguage(t *testing.T) {
+	preferences := &MockLanguagePreferenceReader{language: models.LanguageZH}
+	now := time.Now()
+	cache := newTTLCache[models.Language](time.Minute)
+	cache.now = func() time.Time { return now }
+	middleware := languageMiddleware(preferences, cache, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for range 2 {
+		c, _ := setupLanguageTestContext(languageTestRequest{uid: "user-1"})
+		middleware(c)
+		assert.Equal(t, "zh", c.GetString(LanguageKey))
+	}
+	assert.Equal(t, 1, preferences.calls)
+
+	// expired entries are read again
+	now = now.Add(2 * time.Minute)
+	c, _ := setupLanguageTestContext(languageTestRequest{uid: "user-1"})
+	middleware(c)
+	assert.Equal(t, 2, preferences.calls)
+
+	// a forgotten preference is read again right away
+	preferences.language = models.LanguageEN
+	(&LanguagePreferenceCache{entries: cache}).Forget("user-1")
+	c, _ = setupLanguageTestContext(languageTestRequest{uid: "user-1"})
+	middleware(c)
+	assert.Equal(t, 3, preferences.calls)
+	assert.Equal(t, "en", c.GetString(LanguageKey))
+}
+
+func TestAcceptedLanguages(t *testing.T) {
 	tests := []struct {
-		name          string
-		body          string
-		expectedValue string
-		status        int
-		abort         bool
-		errorMessage  string
+		header   string
+		expected []models.Language
 	}{
-		{
-			name:          "success",
-			body:          `{"language":"en"}`,
-			expectedValue: "en",
-			status:        http.StatusOK,
-			abort:         false,
-			errorMessage:  "",
-		},
-		{
-			name:          "missing language body",
-			body:          "",
-			expectedValue: "",
-			status:        http.StatusBadRequest,
-			abort:         true,
-			errorMessage:  "failed to bind",
-		},
-		{
-			name:          "invalid language",
-			body:          `{"language":"abc"}`,
-			expectedValue: "",
-			status:        http.StatusBadRequest,
-			abort:         true,
-			errorMessage:  "invalid language",
-		},
+		{header: "", expected: []models.Language{}},
+		{header: "en-US,en;q=0.9,zh;q=0.8", expected: []models.Language{"en-us", "en", "zh"}},
+		{header: "zh;q=0.4, fr-CA, *;q=0.1", expected: []models.Language{"fr-ca", "fr", "zh"}},
+		{header: "de;q=0, ja;q=abc, en", expected: []models.Language{"en"}},
 	}
-
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c, w, logBuffer := setupLanguageFromBodyTestContext(tt.body)
-			logOutput := logBuffer.String()
-			assert.Equal(t, tt.status, w.Code)
-			assert.Equal(t, tt.abort, c.IsAborted())
-			assert.Contains(t, logOutput, tt.errorMessage)
-			if !tt.abort {
-				assert.Equal(t, c.MustGet(LanguageKey), tt.expectedValue)
-			}
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, acceptedLanguages(tt.header))
 		})
 	}
 }
@@ -2,20 +2,30 @@ package middleware
 
 import (
 	"log/slog"
+	"north-post/service/internal/domain/v1/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Middlewares struct {
-	LanguageFromQuery gin.HandlerFunc
-	LanguageFromBody  gin.HandlerFunc
-	Auth              gin.HandlerFunc
+	Language   gin.HandlerFunc
+	Auth       gin.HandlerFunc
+	Admin      gin.HandlerFunc
+	Permission func(models.Permission) gin.HandlerFunc
 }
 
-func SetupMiddlewares(auth authClient, logger *slog.Logger) *Middlewares {
+func SetupMiddlewares(
+	auth authClient,
+	adminUsers adminUserChecker,
+	appUsers languagePreferenceReader,
+	languagePreferences *LanguagePreferenceCache,
+	logger *slog.Logger) *Middlewares {
 	return &Middlewares{
-		LanguageFromQuery: LanguageFromQueryMiddleware(logger),
-		LanguageFromBody:  LanguageFromBodyMiddleware(logger),
-		Auth:              AuthMiddleware(auth, logger),
+		Language: LanguageMiddleware(appUsers, languagePreferences, logger),
+		Auth:     AuthMiddleware(auth, logger),
+		Admin:    AdminMiddleware(adminUsers, logger),
+		Permission: func(permission models.Permission) gin.HandlerFunc {
+			return PermissionMiddleware(permission, logger)
+		},
 	}
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"north-post/service/internal/domain/v1/models"
@@ -40,8 +41,10 @@ func NewAddressHandler(service addressService, logger *slog.Logger) *AddressHand
 // @Tags App User
 // @Accept json
 // @Produce json
-// @Param language query string true "Language code (e.g., en, zh)"
+// @Param language query string false "Language code (e.g., en, zh), negotiated when it is left out"
+// @Param Accept-Language header string false "Preferred languages with quality values"
 // @Success 200 {object} dto.GetAllTagsResponse
+// @Header 200 {string} Content-Language "Negotiated language"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /user/address/tags [get]
@@ -60,12 +63,14 @@ func (h *AddressHandler) GetAllTags(c *gin.Context) {
 
 // GetAddresses godoc
 // @Summary Get addresses
-// @Description Search and return addresses by language, keywords, tags, and pagination
+// @Description Search and return addresses by language, keywords, tags, and pagination. filters narrows by tag category, any tag within a category and every category has to match. facets count the matching addresses per tag of each category, sort is updated (default), relevance or name. highlights wrap the matched keywords of the name and a brief intro snippet in <mark> tags, keyed by address ID, the rest of the text is HTML escaped. Addresses are served from the search index when it has every list view field. An unknown sort or category is rejected with 400
 // @Tags App User
 // @Accept json
 // @Produce json
 // @Param request body dto.GetAddressesRequest true "Get addresses request"
+// @Param Accept-Language header string false "Preferred languages, used when the body has no language"
 // @Success 200 {object} dto.GetAddressesResponse
+// @Header 200 {string} Content-Language "Negotiated language"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /user/address [post]
@@ -74,21 +79,29 @@ func (h *AddressHandler) GetAddresses(c *gin.Context) {
 	if !utils.BindJSON(c, &req, h.logger) {
 		return
 	}
+	language := models.Language(c.GetString(middleware.LanguageKey))
 	input := services.GetAddressesInput{
-		Language: req.Language,
-		Keywords: req.Keywords,
-		Tags:     req.Tags,
-		PageSize: req.PageSize,
-		Page:     req.Page,
+		Language:   language,
+		Keywords:   req.Keywords,
+		Tags:       req.Tags,
+		TagFilters: req.Filters,
+		Sort:       req.Sort,
+		FromIndex:  true,
+		PageSize:   req.PageSize,
+		Page:       req.Page,
 	}
 	output, err := h.service.GetAddresses(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidSearch) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to get addresses", "error", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get addresses"})
 		return
 	}
 	response := dto.GetAddressesResponse{
-		Data: dto.ToGetAddressesResponseDTO(output, req.Language),
+		Data: dto.ToGetAddressesResponseDTO(output, language),
 	}
 	c.JSON(http.StatusOK, response)
 }
@@ -38,8 +38,10 @@ func NewAddressBookHandler(
 // @Tags App User
 // @Param Authorization header string true "Bearer idToken"
 // @Param request body dto.UpdateUserSavedAddressesRequest true "Address ID and action (add/remove)"
+// @Param Accept-Language header string false "Preferred languages, used when the body has no language"
 // @Produce json
 // @Success 200 {object} dto.UpdateUserSavedAddressesResponse
+// @Header 200 {string} Content-Language "Negotiated language"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -75,9 +77,11 @@ func (h *AddressBookHandler) UpdateSavedAddresses(c *gin.Context) {
 // @Description Retrieve all saved addresses for the authenticated user
 // @Tags App User
 // @Param Authorization header string true "Bearer idToken"
-// @Param language query string true "Language code (e.g., en, zh)"
+// @Param language query string false "Language code (e.g., en, zh), negotiated when it is left out"
+// @Param Accept-Language header string false "Preferred languages with quality values"
 // @Produce json
 // @Success 200 {object} dto.GetSavedAddressesResponse
+// @Header 200 {string} Content-Language "Negotiated language"
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /user/address-book [get]
@@ -113,6 +117,77 @@ func (h *AddressBookHandler) GetSavedAddresses(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// TranslateSavedAddresses godoc
+// @Summary Translate user saved addresses
+// @Description Save the entries of another language that are linked to the addresses saved in the from language, for users who switch language. Addresses without a linked entry are reported as untranslated
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param request body dto.TranslateSavedAddressesRequest true "Target language and the language to translate from"
+// @Param Accept-Language header string false "Preferred languages, used when the body has no language"
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.TranslateSavedAddressesResponse
+// @Header 200 {string} Content-Language "Negotiated language"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/address-book/translate [post]
+func (h *AddressBookHandler) TranslateSavedAddresses(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	language := models.Language(c.GetString(middleware.LanguageKey))
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	var req dto.TranslateSavedAddressesRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateEnabledLanguage(c, req.From, h.logger) {
+		return
+	}
+	if req.From.Lower() == language.Lower() {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "from has to differ from the target language"})
+		return
+	}
+	getSavedAddressesOpts := &repository.GetUserSavedAddressesOptions{Uid: uid, Language: req.From}
+	addressIDs, err := h.userRepo.GetUserSavedAddresses(c.Request.Context(), getSavedAddressesOpts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	result, err := h.addressRepo.MapAddressTranslations(c.Request.Context(), &repository.MapAddressTranslationsOption{
+		From: req.From,
+		To:   language,
+		IDs:  addressIDs,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(result.Addresses) > 0 {
+		translatedIDs := make([]string, len(result.Addresses))
+		for i, address := range result.Addresses {
+			translatedIDs[i] = address.ID
+		}
+		_, err := h.userRepo.UpdateUserSavedAddresses(c.Request.Context(), &repository.UpdateUserSavedAddressesOptions{
+			UserID:     uid,
+			Language:   language,
+			AddressIDs: translatedIDs,
+			Action:     repository.Add,
+		})
+		if err != nil {
+			h.logger.Error("failed to save translated addresses", "uid", uid, "error", err)
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update user saved addresses"})
+			return
+		}
+	}
+	response := dto.TranslateSavedAddressesResponse{Data: dto.TranslatedSavedAddressesDTO{
+		Addresses:       dto.ToAddressDTOs(result.Addresses),
+		UntranslatedIDs: result.Untranslated,
+	}}
+	c.JSON(http.StatusOK, response)
+}
+
 // ---------- Helper methods ----------
 func (h *AddressBookHandler) convertUpdateMethod(action string) repository.UpdateSavedAddressesAction {
 	action = strings.ToLower(action)
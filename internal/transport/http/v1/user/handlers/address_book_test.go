@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/transport/http/v1/dto"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -106,3 +110,101 @@ func TestUpdateSavedAddresses(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateSavedAddresses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		body           string
+		mapResult      *repository.MapAddressTranslationsResult
+		mapError       error
+		expectMap      bool
+		expectSave     bool
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"language":"zh","from":"en"}`,
+			mapResult: &repository.MapAddressTranslationsResult{
+				Addresses:    []models.AddressItem{{ID: "zh_1"}},
+				Untranslated: []string{"en_2"},
+			},
+			expectMap:      true,
+			expectSave:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "nothing translated",
+			body: `{"language":"zh","from":"en"}`,
+			mapResult: &repository.MapAddressTranslationsResult{
+				Addresses:    []models.AddressItem{},
+				Untranslated: []string{"en_1", "en_2"},
+			},
+			expectMap:      true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failed mapping",
+			body:           `{"language":"zh","from":"en"}`,
+			mapError:       errors.New("firestore unavailable"),
+			expectMap:      true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "same language",
+			body:           `{"language":"zh","from":"ZH"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid from",
+			body:           `{"language":"zh","from":"fr"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mockUserRepo)
+			mockAddressRepo := new(mockAddressRepo)
+			handler := NewAddressBookHandler(mockUserRepo,
+				mockAddressRepo,
+				slog.New(slog.NewTextHandler(io.Discard, nil)))
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/user/address-book/translate",
+				mockAuthMiddleware("mock_user"),
+				mockLanguageMiddleware("zh"),
+				handler.TranslateSavedAddresses)
+			if tt.expectMap {
+				mockUserRepo.On("GetUserSavedAddresses", mock.Anything, &repository.GetUserSavedAddressesOptions{
+					Uid:      "mock_user",
+					Language: "en",
+				}).Return([]string{"en_1", "en_2"}, nil).Once()
+				mockAddressRepo.On("MapAddressTranslations", mock.Anything, &repository.MapAddressTranslationsOption{
+					From: "en",
+					To:   "zh",
+					IDs:  []string{"en_1", "en_2"},
+				}).Return(tt.mapResult, tt.mapError).Once()
+			}
+			if tt.expectSave {
+				mockUserRepo.On("UpdateUserSavedAddresses", mock.Anything, &repository.UpdateUserSavedAddressesOptions{
+					UserID:     "mock_user",
+					Language:   "zh",
+					AddressIDs: []string{"zh_1"},
+					Action:     repository.Add,
+				}).Return("timestamp", nil).Once()
+			}
+			req, _ := http.NewRequest("POST", "/user/address-book/translate", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.TranslateSavedAddressesResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.Data.Addresses, len(tt.mapResult.Addresses))
+				assert.Equal(t, tt.mapResult.Untranslated, response.Data.UntranslatedIDs)
+			}
+			mockUserRepo.AssertExpectations(t)
+			mockAddressRepo.AssertExpectations(t)
+		})
+	}
+}
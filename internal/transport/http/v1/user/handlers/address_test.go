@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -43,8 +44,9 @@ func setupRouter(handler *AddressHandler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
-	r.GET("/user/address/tags", middleware.LanguageFromQueryMiddleware(logger), handler.GetAllTags)
-	r.POST("/user/address", middleware.LanguageFromBodyMiddleware(logger), handler.GetAddresses)
+	language := middleware.LanguageMiddleware(new(mockUserRepo), middleware.NewLanguagePreferenceCache(), logger)
+	r.GET("/user/address/tags", language, handler.GetAllTags)
+	r.POST("/user/address", language, handler.GetAddresses)
 	return r
 }
 
@@ -182,3 +184,47 @@ func TestGetAddresses(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAddresses_FiltersAndFacets(t *testing.T) {
+	t.Parallel()
+	mockSrv := new(MockAddressService)
+	handler := NewAddressHandler(mockSrv, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := setupRouter(handler)
+	mockSrv.On("GetAddresses", mock.Anything, services.GetAddressesInput{
+		Language:   "en",
+		TagFilters: map[models.TagCategory][]string{models.TagCategoryCountry: {"UK", "France"}, models.TagCategoryRole: {"Writer"}},
+		Sort:       models.AddressSortName,
+		FromIndex:  true,
+	}).Return(&services.GetAddressesOutput{
+		Addresses:  []models.AddressItem{{ID: "1"}},
+		TotalCount: 1,
+		Highlights: map[string]models.AddressHighlight{"1": {Name: "Jane <mark>Austen</mark>'s House"}},
+		Facets: []models.TagFacet{
+			{Category: models.TagCategoryCountry, Counts: []models.TagFacetCount{{Value: "UK", Count: 1}}},
+		},
+	}, nil).Once()
+	mockSrv.On("GetAddresses", mock.Anything, mock.MatchedBy(func(input services.GetAddressesInput) bool {
+		return input.Sort == "popular"
+	})).Return((*services.GetAddressesOutput)(nil), fmt.Errorf("%w: unsupported sort: popular", services.ErrInvalidSearch)).Once()
+
+	body := `{"language":"en","filters":{"country":["UK","France"],"role":["Writer"]},"sort":"name"}`
+	req, _ := http.NewRequest("POST", "/user/address", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.GetAddressesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []dto.TagFacetDTO{
+		{Category: models.TagCategoryCountry, Counts: []dto.TagFacetCountDTO{{Value: "UK", Count: 1}}},
+	}, response.Data.Facets)
+	assert.Equal(t, map[string]dto.AddressHighlightDTO{"1": {Name: "Jane <mark>Austen</mark>'s House"}},
+		response.Data.Highlights)
+
+	req, _ = http.NewRequest("POST", "/user/address", bytes.NewBufferString(`{"language":"en","sort":"popular"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSrv.AssertExpectations(t)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type draftService interface {
+	ListDrafts(ctx context.Context, input services.ListDraftsInput) (*services.ListDraftsOutput, error)
+	GetDraft(ctx context.Context, input services.GetDraftInput) (*services.GetDraftOutput, error)
+	CreateDraft(ctx context.Context, input services.CreateDraftInput) (*services.CreateDraftOutput, error)
+	UpdateDraft(ctx context.Context, input services.UpdateDraftInput) (*services.UpdateDraftOutput, error)
+	DeleteDraft(ctx context.Context, input services.DeleteDraftInput) (*services.DeleteDraftOutput, error)
+}
+
+type DraftHandler struct {
+	service draftService
+	logger  *slog.Logger
+}
+
+func NewDraftHandler(service draftService, logger *slog.Logger) *DraftHandler {
+	return &DraftHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListDrafts godoc
+// @Summary List letter drafts
+// @Description List all letter drafts of the authenticated user, newest first
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Produce json
+// @Success 200 {object} dto.ListDraftsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/drafts [get]
+func (h *DraftHandler) ListDrafts(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	output, err := h.service.ListDrafts(c.Request.Context(), services.ListDraftsInput{Uid: uid})
+	if err != nil {
+		h.logger.Error("failed to list drafts", "uid", uid, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list drafts"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListDraftsResponse{Data: dto.ToDraftDTOs(output.Drafts)})
+}
+
+// GetDraft godoc
+// @Summary Get a letter draft
+// @Description Get a letter draft owned by the authenticated user
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param id path string true "Draft ID"
+// @Produce json
+// @Success 200 {object} dto.DraftResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/drafts/{id} [get]
+func (h *DraftHandler) GetDraft(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	input := services.GetDraftInput{Uid: uid, ID: strings.TrimSpace(c.Param("id"))}
+	output, err := h.service.GetDraft(c.Request.Context(), input)
+	if err != nil {
+		h.handleDraftError(c, "failed to get draft", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.DraftResponse{Data: dto.ToDraftDTO(output.Draft)})
+}
+
+// CreateDraft godoc
+// @Summary Create a letter draft
+// @Description Create a letter draft for the authenticated user
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param request body dto.CreateDraftRequest true "Request body"
+// @Accept json
+// @Produce json
+// @Success 201 {object} dto.DraftResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/drafts [post]
+func (h *DraftHandler) CreateDraft(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	var req dto.CreateDraftRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateEnabledLanguage(c, req.Language, h.logger) {
+		return
+	}
+	if req.MusicFilename != "" && !h.validateMusicFilename(c, req.MusicFilename) {
+		return
+	}
+	input := services.CreateDraftInput{
+		Uid:                uid,
+		RecipientAddressID: req.RecipientAddressID,
+		Language:           req.Language,
+		Body:               req.Body,
+		MusicFilename:      req.MusicFilename,
+	}
+	output, err := h.service.CreateDraft(c.Request.Context(), input)
+	if err != nil {
+		h.handleDraftError(c, "failed to create draft", err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.DraftResponse{Data: dto.ToDraftDTO(output.Draft)})
+}
+
+// UpdateDraft godoc
+// @Summary Update a letter draft
+// @Description Update fields of a letter draft owned by the authenticated user, omitted fields are unchanged
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param id path string true "Draft ID"
+// @Param request body dto.UpdateDraftRequest true "Request body"
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.DraftResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/drafts/{id} [patch]
+func (h *DraftHandler) UpdateDraft(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	var req dto.UpdateDraftRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if req.Language != nil && !utils.ValidateEnabledLanguage(c, *req.Language, h.logger) {
+		return
+	}
+	// an empty filename clears the music of the draft
+	if req.MusicFilename != nil && *req.MusicFilename != "" && !h.validateMusicFilename(c, *req.MusicFilename) {
+		return
+	}
+	input := services.UpdateDraftInput{
+		Uid:                uid,
+		ID:                 strings.TrimSpace(c.Param("id")),
+		RecipientAddressID: req.RecipientAddressID,
+		Language:           req.Language,
+		Body:               req.Body,
+		MusicFilename:      req.MusicFilename,
+	}
+	output, err := h.service.UpdateDraft(c.Request.Context(), input)
+	if err != nil {
+		h.handleDraftError(c, "failed to update draft", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.DraftResponse{Data: dto.ToDraftDTO(output.Draft)})
+}
+
+// DeleteDraft godoc
+// @Summary Delete a letter draft
+// @Description Delete a letter draft owned by the authenticated user
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param id path string true "Draft ID"
+// @Produce json
+// @Success 200 {object} dto.DeleteDraftResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/drafts/{id} [delete]
+func (h *DraftHandler) DeleteDraft(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	input := services.DeleteDraftInput{Uid: uid, ID: strings.TrimSpace(c.Param("id"))}
+	output, err := h.service.DeleteDraft(c.Request.Context(), input)
+	if err != nil {
+		h.handleDraftError(c, "failed to delete draft", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.DeleteDraftResponse{Data: dto.DraftID{ID: output.ID}})
+}
+
+// ---------- Helper methods ----------
+// validateMusicFilename checks a "genre/track" filename the way the music endpoints check their path
+func (h *DraftHandler) validateMusicFilename(c *gin.Context, filename string) bool {
+	genre, track, _ := strings.Cut(filename, "/")
+	return utils.ValidateMusicFilename(c, genre, track, h.logger)
+}
+
+func (h *DraftHandler) handleDraftError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "draft not found"})
+	case errors.Is(err, services.ErrInvalidDraft), errors.Is(err, repository.ErrDraftLimitReached):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		h.logger.Error(message, "uid", c.GetString(middleware.UidKey), "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: message})
+	}
+}
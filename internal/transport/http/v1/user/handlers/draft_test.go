@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/services"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockDraftService struct {
+	mock.Mock
+}
+
+func (m *mockDraftService) ListDrafts(ctx context.Context, input services.ListDraftsInput) (*services.ListDraftsOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ListDraftsOutput), args.Error(1)
+}
+
+func (m *mockDraftService) GetDraft(ctx context.Context, input services.GetDraftInput) (*services.GetDraftOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.GetDraftOutput), args.Error(1)
+}
+
+func (m *mockDraftService) CreateDraft(ctx context.Context, input services.CreateDraftInput) (*services.CreateDraftOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.CreateDraftOutput), args.Error(1)
+}
+
+func (m *mockDraftService) UpdateDraft(ctx context.Context, input services.UpdateDraftInput) (*services.UpdateDraftOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.UpdateDraftOutput), args.Error(1)
+}
+
+func (m *mockDraftService) DeleteDraft(ctx context.Context, input services.DeleteDraftInput) (*services.DeleteDraftOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DeleteDraftOutput), args.Error(1)
+}
+
+func setupDraftRouter(handler *DraftHandler, uid string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	drafts := r.Group("/user/drafts", mockAuthMiddleware(uid))
+	drafts.GET("", handler.ListDrafts)
+	drafts.POST("", handler.CreateDraft)
+	drafts.GET("/:id", handler.GetDraft)
+	drafts.PATCH("/:id", handler.UpdateDraft)
+	drafts.DELETE("/:id", handler.DeleteDraft)
+	return r
+}
+
+func newDraftHandler() (*DraftHandler, *mockDraftService) {
+	mockSvc := new(mockDraftService)
+	handler := NewDraftHandler(mockSvc, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return handler, mockSvc
+}
+
+func TestListDrafts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		uid            string
+		mockOutput     *services.ListDraftsOutput
+		mockError      error
+		expectedStatus int
+		expectCall     bool
+	}{
+		{
+			name: "success",
+			uid:  "user-1",
+			mockOutput: &services.ListDraftsOutput{Drafts: []models.Draft{
+				{ID: "draft-1", Body: "hello", Language: models.LanguageEN},
+			}},
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "missing uid",
+			uid:            "",
+			expectedStatus: http.StatusUnauthorized,
+			expectCall:     false,
+		},
+		{
+			name:           "service error",
+			uid:            "user-1",
+			mockError:      errors.New("service failed"),
+			expectedStatus: http.StatusInternalServerError,
+			expectCall:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockSvc := newDraftHandler()
+			router := setupDraftRouter(handler, tt.uid)
+			if tt.expectCall {
+				mockSvc.On("ListDrafts", mock.Anything, services.ListDraftsInput{Uid: tt.uid}).
+					Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("GET", "/user/drafts", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp dto.ListDraftsResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Len(t, resp.Data, 1)
+				assert.Equal(t, "draft-1", resp.Data[0].ID)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetDraft(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockOutput     *services.GetDraftOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			mockOutput:     &services.GetDraftOutput{Draft: models.Draft{ID: "draft-1"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not found or not owned",
+			mockError:      repository.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockSvc := newDraftHandler()
+			router := setupDraftRouter(handler, "user-1")
+			mockSvc.On("GetDraft", mock.Anything, services.GetDraftInput{Uid: "user-1", ID: "draft-1"}).
+				Return(tt.mockOutput, tt.mockError).Once()
+			req, _ := http.NewRequest("GET", "/user/drafts/draft-1", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateDraft(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		body           string
+		mockOutput     *services.CreateDraftOutput
+		mockError      error
+		expectedStatus int
+		expectCall     bool
+	}{
+		{
+			name:           "success",
+			body:           `{"language":"en","recipientAddressId":"address-1","body":"Dear Sherlock"}`,
+			mockOutput:     &services.CreateDraftOutput{Draft: models.Draft{ID: "draft-1", Body: "Dear Sherlock"}},
+			expectedStatus: http.StatusCreated,
+			expectCall:     true,
+		},
+		{
+			name:           "missing language",
+			body:           `{"body":"Dear Sherlock"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     false,
+		},
+		{
+			name:           "invalid language",
+			body:           `{"language":"abc","body":"Dear Sherlock"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     false,
+		},
+		{
+			name:           "success with music",
+			body:           `{"language":"en","musicFilename":"jazz/blue.mp3"}`,
+			mockOutput:     &services.CreateDraftOutput{Draft: models.Draft{ID: "draft-1"}},
+			expectedStatus: http.StatusCreated,
+			expectCall:     true,
+		},
+		{
+			name:           "music filename without genre",
+			body:           `{"language":"en","musicFilename":"blue.mp3"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     false,
+		},
+		{
+			name:           "music filename with path traversal",
+			body:           `{"language":"en","musicFilename":"jazz/../../secret"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     false,
+		},
+		{
+			name:           "invalid draft",
+			body:           `{"language":"en","recipientAddressId":"missing"}`,
+			mockError:      services.ErrInvalidDraft,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     true,
+		},
+		{
+			name:           "draft limit reached",
+			body:           `{"language":"en"}`,
+			mockError:      repository.ErrDraftLimitReached,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     true,
+		},
+		{
+			name:           "service error",
+			body:           `{"language":"en"}`,
+			mockError:      errors.New("service failed"),
+			expectedStatus: http.StatusInternalServerError,
+			expectCall:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockSvc := newDraftHandler()
+			router := setupDraftRouter(handler, "user-1")
+			if tt.expectCall {
+				mockSvc.On("CreateDraft", mock.Anything, mock.MatchedBy(func(input services.CreateDraftInput) bool {
+					return input.Uid == "user-1"
+				})).Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest("POST", "/user/drafts", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateDraft(t *testing.T) {
+	t.Parallel()
+	handler, mockSvc := newDraftHandler()
+	router := setupDraftRouter(handler, "user-1")
+	mockSvc.On("UpdateDraft", mock.Anything, mock.MatchedBy(func(input services.UpdateDraftInput) bool {
+		// omitted fields must stay nil so they are left unchanged
+		return input.ID == "draft-1" &&
+			input.Body != nil && *input.Body == "new body" &&
+			input.RecipientAddressID == nil &&
+			input.Language == nil
+	})).Return(&services.UpdateDraftOutput{Draft: models.Draft{ID: "draft-1", Body: "new body"}}, nil).Once()
+	req, _ := http.NewRequest("PATCH", "/user/drafts/draft-1", bytes.NewBufferString(`{"body":"new body"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.DraftResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "new body", resp.Data.Body)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestUpdateDraft_InvalidMusicFilename(t *testing.T) {
+	t.Parallel()
+	handler, mockSvc := newDraftHandler()
+	router := setupDraftRouter(handler, "user-1")
+	req, _ := http.NewRequest("PATCH", "/user/drafts/draft-1", bytes.NewBufferString(`{"musicFilename":"jazz/"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "UpdateDraft", mock.Anything, mock.Anything)
+}
+
+func TestDeleteDraft(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		mockOutput     *services.DeleteDraftOutput
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			mockOutput:     &services.DeleteDraftOutput{ID: "draft-1"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not found",
+			mockError:      repository.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "service error",
+			mockError:      errors.New("service failed"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockSvc := newDraftHandler()
+			router := setupDraftRouter(handler, "user-1")
+			mockSvc.On("DeleteDraft", mock.Anything, services.DeleteDraftInput{Uid: "user-1", ID: "draft-1"}).
+				Return(tt.mockOutput, tt.mockError).Once()
+			req, _ := http.NewRequest("DELETE", "/user/drafts/draft-1", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
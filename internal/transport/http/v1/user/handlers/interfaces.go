@@ -18,6 +18,22 @@ type userRepository interface {
 		ctx context.Context,
 		opts *repository.GetUserSavedAddressesOptions,
 	) ([]string, error)
+	UpdateUserLikedMusics(
+		ctx context.Context,
+		opts *repository.UpdateUserLikedMusicsOptions,
+	) (string, error)
+	GetUserLikedMusics(
+		ctx context.Context,
+		opts *repository.GetUserLikedMusicsOptions,
+	) ([]string, error)
+	UpdateUserLanguage(
+		ctx context.Context,
+		opts *repository.UpdateUserLanguageOptions,
+	) (string, error)
+}
+
+type languagePreferenceCache interface {
+	Forget(uid string)
 }
 
 type addressRepository interface {
@@ -25,4 +41,15 @@ type addressRepository interface {
 		ctx context.Context,
 		opts *repository.GetAddressesByIDsOptions,
 	) (*repository.GetAddressesByIDsResponse, error)
+	MapAddressTranslations(
+		ctx context.Context,
+		opts *repository.MapAddressTranslationsOption,
+	) (*repository.MapAddressTranslationsResult, error)
+}
+
+type musicRepository interface {
+	GetMusicByFilenames(
+		ctx context.Context,
+		opts *repository.GetMusicByFilenamesOptions,
+	) (*repository.GetMusicByFilenamesResponse, error)
 }
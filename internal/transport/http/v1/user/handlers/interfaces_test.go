@@ -47,6 +47,47 @@ func (m *mockUserRepo) GetUserSavedAddresses(
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *mockUserRepo) UpdateUserLikedMusics(
+	ctx context.Context,
+	opts *repository.UpdateUserLikedMusicsOptions,
+) (string, error) {
+	args := m.Called(ctx, opts)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockUserRepo) GetUserLikedMusics(
+	ctx context.Context,
+	opts *repository.GetUserLikedMusicsOptions,
+) ([]string, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserRepo) UpdateUserLanguage(
+	ctx context.Context,
+	opts *repository.UpdateUserLanguageOptions,
+) (string, error) {
+	args := m.Called(ctx, opts)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockUserRepo) GetUserLanguage(ctx context.Context, uid string) (models.Language, error) {
+	args := m.Called(ctx, uid)
+	return args.Get(0).(models.Language), args.Error(1)
+}
+
+// --------- Mock Language Preference Cache ----------
+type mockLanguagePreferenceCache struct {
+	mock.Mock
+}
+
+func (m *mockLanguagePreferenceCache) Forget(uid string) {
+	m.Called(uid)
+}
+
 // --------- Mock Address Repo ----------
 type mockAddressRepo struct {
 	mock.Mock
@@ -63,6 +104,33 @@ func (m *mockAddressRepo) GetAddressesByIDs(
 	return args.Get(0).(*repository.GetAddressesByIDsResponse), args.Error(1)
 }
 
+func (m *mockAddressRepo) MapAddressTranslations(
+	ctx context.Context,
+	opts *repository.MapAddressTranslationsOption,
+) (*repository.MapAddressTranslationsResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.MapAddressTranslationsResult), args.Error(1)
+}
+
+// --------- Mock Music Repo ----------
+type mockMusicRepo struct {
+	mock.Mock
+}
+
+func (m *mockMusicRepo) GetMusicByFilenames(
+	ctx context.Context,
+	opts *repository.GetMusicByFilenamesOptions,
+) (*repository.GetMusicByFilenamesResponse, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.GetMusicByFilenamesResponse), args.Error(1)
+}
+
 // --------- Mock Utils ----------
 func mockAuthMiddleware(uid string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/transport/http/v1/dto"
+	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LikedMusicHandler struct {
+	userRepo  userRepository
+	musicRepo musicRepository
+	logger    *slog.Logger
+}
+
+func NewLikedMusicHandler(
+	userRepo userRepository,
+	musicRepo musicRepository,
+	logger *slog.Logger) *LikedMusicHandler {
+	return &LikedMusicHandler{
+		userRepo:  userRepo,
+		musicRepo: musicRepo,
+		logger:    logger,
+	}
+}
+
+// LikeMusic godoc
+// @Summary Like a music track
+// @Description Add a music track to the liked music of the authenticated user
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param genre path string true "Music genre"
+// @Param track path string true "Music track filename"
+// @Produce json
+// @Success 200 {object} dto.UpdateLikedMusicsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/music/likes/{genre}/{track} [put]
+func (h *LikedMusicHandler) LikeMusic(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	filename, ok := h.parseMusicFilename(c)
+	if !ok {
+		return
+	}
+	// only tracks in the music list can be liked
+	results, err := h.musicRepo.GetMusicByFilenames(
+		c.Request.Context(),
+		&repository.GetMusicByFilenamesOptions{Filenames: []string{filename}},
+	)
+	if err != nil {
+		h.logger.Error("failed to check music", "filename", filename, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to like music"})
+		return
+	}
+	if len(results.Music) == 0 {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "music not found"})
+		return
+	}
+	opts := &repository.UpdateUserLikedMusicsOptions{
+		UserID:    uid,
+		Filenames: []string{filename},
+		Action:    repository.Add,
+	}
+	output, err := h.userRepo.UpdateUserLikedMusics(c.Request.Context(), opts)
+	if err != nil {
+		h.logger.Error("failed to like music", "filename", filename, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to like music"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.UpdateLikedMusicsResponse{Data: output})
+}
+
+// UnlikeMusic godoc
+// @Summary Unlike a music track
+// @Description Remove a music track from the liked music of the authenticated user
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param genre path string true "Music genre"
+// @Param track path string true "Music track filename"
+// @Produce json
+// @Success 200 {object} dto.UpdateLikedMusicsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/music/likes/{genre}/{track} [delete]
+func (h *LikedMusicHandler) UnlikeMusic(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	filename, ok := h.parseMusicFilename(c)
+	if !ok {
+		return
+	}
+	// no music list check here, so tracks that were already deleted can still be unliked
+	opts := &repository.UpdateUserLikedMusicsOptions{
+		UserID:    uid,
+		Filenames: []string{filename},
+		Action:    repository.Delete,
+	}
+	output, err := h.userRepo.UpdateUserLikedMusics(c.Request.Context(), opts)
+	if err != nil {
+		h.logger.Error("failed to unlike music", "filename", filename, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to unlike music"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.UpdateLikedMusicsResponse{Data: output})
+}
+
+// GetLikedMusics godoc
+// @Summary Get user liked music
+// @Description Retrieve all liked music tracks of the authenticated user
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Produce json
+// @Success 200 {object} dto.GetLikedMusicsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/music/likes [get]
+func (h *LikedMusicHandler) GetLikedMusics(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	filenames, err := h.userRepo.GetUserLikedMusics(
+		c.Request.Context(),
+		&repository.GetUserLikedMusicsOptions{Uid: uid},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	results, err := h.musicRepo.GetMusicByFilenames(
+		c.Request.Context(),
+		&repository.GetMusicByFilenamesOptions{Filenames: filenames},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	// if invalid filenames is not empty, remove the deleted tracks in the background process
+	if len(results.InvalidFilenames) > 0 {
+		h.removeInvalidFilenamesInBackground(uid, results.InvalidFilenames)
+	}
+	response := dto.GetLikedMusicsResponse{Data: dto.ToMusicDTOs(results.Music)}
+	c.JSON(http.StatusOK, response)
+}
+
+// ---------- Helper methods ----------
+func (h *LikedMusicHandler) parseMusicFilename(c *gin.Context) (string, bool) {
+	genre := strings.TrimSpace(c.Param("genre"))
+	track := strings.TrimSpace(c.Param("track"))
+	if !utils.ValidateMusicFilename(c, genre, track, h.logger) {
+		return "", false
+	}
+	return fmt.Sprintf("%s/%s", genre, track), true
+}
+
+func (h *LikedMusicHandler) removeInvalidFilenamesInBackground(uid string, invalidFilenames []string) {
+	go func(invalidFilenames []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := h.userRepo.UpdateUserLikedMusics(
+			ctx,
+			&repository.UpdateUserLikedMusicsOptions{
+				UserID:    uid,
+				Filenames: invalidFilenames,
+				Action:    repository.Delete,
+			},
+		)
+		if err != nil {
+			h.logger.Error("Failed to remove invalid liked musics",
+				"error", err,
+				"invalidFilenames", invalidFilenames,
+			)
+		}
+	}(invalidFilenames)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
+	"north-post/service/internal/transport/http/v1/dto"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupLikedMusicRouter(handler *LikedMusicHandler, uid string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	likes := r.Group("/user/music/likes", mockAuthMiddleware(uid))
+	likes.GET("", handler.GetLikedMusics)
+	likes.PUT("/:genre/:track", handler.LikeMusic)
+	likes.DELETE("/:genre/:track", handler.UnlikeMusic)
+	return r
+}
+
+func newLikedMusicHandler() (*LikedMusicHandler, *mockUserRepo, *mockMusicRepo) {
+	userRepo := new(mockUserRepo)
+	musicRepo := new(mockMusicRepo)
+	handler := NewLikedMusicHandler(userRepo, musicRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return handler, userRepo, musicRepo
+}
+
+func TestLikeMusic(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		uid            string
+		path           string
+		foundMusic     []models.Music
+		lookupError    error
+		updateError    error
+		expectedStatus int
+		expectLookup   bool
+		expectUpdate   bool
+	}{
+		{
+			name:           "success",
+			uid:            "mock_user",
+			path:           "/user/music/likes/jazz/track.mp3",
+			foundMusic:     []models.Music{{Filename: "jazz/track.mp3"}},
+			expectedStatus: http.StatusOK,
+			expectLookup:   true,
+			expectUpdate:   true,
+		},
+		{
+			name:           "missing uid",
+			uid:            "",
+			path:           "/user/music/likes/jazz/track.mp3",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid track",
+			uid:            "mock_user",
+			path:           "/user/music/likes/jazz/..",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "music not in list",
+			uid:            "mock_user",
+			path:           "/user/music/likes/jazz/missing.mp3",
+			foundMusic:     []models.Music{},
+			expectedStatus: http.StatusNotFound,
+			expectLookup:   true,
+		},
+		{
+			name:           "lookup error",
+			uid:            "mock_user",
+			path:           "/user/music/likes/jazz/track.mp3",
+			lookupError:    errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+			expectLookup:   true,
+		},
+		{
+			name:           "update error",
+			uid:            "mock_user",
+			path:           "/user/music/likes/jazz/track.mp3",
+			foundMusic:     []models.Music{{Filename: "jazz/track.mp3"}},
+			updateError:    errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+			expectLookup:   true,
+			expectUpdate:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, userRepo, musicRepo := newLikedMusicHandler()
+			router := setupLikedMusicRouter(handler, tt.uid)
+			if tt.expectLookup {
+				var response *repository.GetMusicByFilenamesResponse
+				if tt.lookupError == nil {
+					response = &repository.GetMusicByFilenamesResponse{Music: tt.foundMusic}
+				}
+				musicRepo.On("GetMusicByFilenames", mock.Anything, mock.Anything).
+					Return(response, tt.lookupError).Once()
+			}
+			if tt.expectUpdate {
+				userRepo.On("UpdateUserLikedMusics", mock.Anything, &repository.UpdateUserLikedMusicsOptions{
+					UserID:    tt.uid,
+					Filenames: []string{"jazz/track.mp3"},
+					Action:    repository.Add,
+				}).Return("timestamp", tt.updateError).Once()
+			}
+			req, _ := http.NewRequest("PUT", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			musicRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnlikeMusic(t *testing.T) {
+	t.Parallel()
+	handler, userRepo, musicRepo := newLikedMusicHandler()
+	router := setupLikedMusicRouter(handler, "mock_user")
+	userRepo.On("UpdateUserLikedMusics", mock.Anything, &repository.UpdateUserLikedMusicsOptions{
+		UserID:    "mock_user",
+		Filenames: []string{"jazz/deleted.mp3"},
+		Action:    repository.Delete,
+	}).Return("timestamp", nil).Once()
+	req, _ := http.NewRequest("DELETE", "/user/music/likes/jazz/deleted.mp3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	// unliking never checks the music list
+	musicRepo.AssertNotCalled(t, "GetMusicByFilenames", mock.Anything, mock.Anything)
+	userRepo.AssertExpectations(t)
+}
+
+func TestGetLikedMusics(t *testing.T) {
+	t.Parallel()
+	handler, userRepo, musicRepo := newLikedMusicHandler()
+	router := setupLikedMusicRouter(handler, "mock_user")
+	removed := make(chan struct{})
+	userRepo.On("GetUserLikedMusics", mock.Anything, &repository.GetUserLikedMusicsOptions{Uid: "mock_user"}).
+		Return([]string{"jazz/track.mp3", "jazz/deleted.mp3"}, nil).Once()
+	musicRepo.On("GetMusicByFilenames", mock.Anything, &repository.GetMusicByFilenamesOptions{
+		Filenames: []string{"jazz/track.mp3", "jazz/deleted.mp3"},
+	}).Return(&repository.GetMusicByFilenamesResponse{
+		Music:            []models.Music{{Filename: "jazz/track.mp3", Genre: "jazz", Title: "track"}},
+		InvalidFilenames: []string{"jazz/deleted.mp3"},
+	}, nil).Once()
+	userRepo.On("UpdateUserLikedMusics", mock.Anything, &repository.UpdateUserLikedMusicsOptions{
+		UserID:    "mock_user",
+		Filenames: []string{"jazz/deleted.mp3"},
+		Action:    repository.Delete,
+	}).Return("timestamp", nil).Run(func(args mock.Arguments) { close(removed) }).Once()
+	req, _ := http.NewRequest("GET", "/user/music/likes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.GetLikedMusicsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, "jazz/track.mp3", resp.Data[0].Filename)
+	select {
+	case <-removed:
+	case <-time.After(time.Second):
+		t.Fatal("stale liked music was not removed")
+	}
+	userRepo.AssertExpectations(t)
+	musicRepo.AssertExpectations(t)
+}
+
+func TestGetLikedMusics_Error(t *testing.T) {
+	t.Parallel()
+	handler, userRepo, _ := newLikedMusicHandler()
+	router := setupLikedMusicRouter(handler, "mock_user")
+	userRepo.On("GetUserLikedMusics", mock.Anything, mock.Anything).
+		Return(nil, errors.New("firestore unavailable")).Once()
+	req, _ := http.NewRequest("GET", "/user/music/likes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	userRepo.AssertExpectations(t)
+}
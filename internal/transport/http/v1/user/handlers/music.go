@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"north-post/service/internal/repository"
 	"north-post/service/internal/services"
 	"north-post/service/internal/transport/http/v1/dto"
 	"north-post/service/internal/transport/http/v1/utils"
@@ -19,6 +21,10 @@ type musicService interface {
 		ctx context.Context,
 		input services.GetPresignedMusicURLInput,
 	) (*services.GetPresignedMusicURLOutput, error)
+	ReportMusicDuration(
+		ctx context.Context,
+		input services.ReportMusicDurationInput,
+	) (*services.ReportMusicDurationOutput, error)
 }
 
 type MusicHandler struct {
@@ -85,3 +91,46 @@ func (h *MusicHandler) GetPresignedMusicURL(c *gin.Context) {
 	response := dto.GetPresignedMusicURLResponse{Data: output.URL}
 	c.JSON(http.StatusOK, response)
 }
+
+// ReportMusicDuration godoc
+// @Summary Report music duration
+// @Description Report the duration of a track measured by the client. Only saved when the server has not computed one.
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param request body dto.ReportMusicDurationRequest true "Music filename and duration in seconds"
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.ReportMusicDurationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/music/duration [post]
+func (h *MusicHandler) ReportMusicDuration(c *gin.Context) {
+	var req dto.ReportMusicDurationRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	genre, track, _ := strings.Cut(strings.TrimSpace(req.Filename), "/")
+	if !utils.ValidateMusicFilename(c, genre, track, h.logger) {
+		return
+	}
+	input := services.ReportMusicDurationInput{
+		Filename:    fmt.Sprintf("%s/%s", genre, track),
+		DurationSec: req.DurationSec,
+	}
+	output, err := h.service.ReportMusicDuration(c.Request.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMusicDuration):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "music not found"})
+		default:
+			h.logger.Error("failed to report music duration", "filename", input.Filename, "error", err)
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to report music duration"})
+		}
+		return
+	}
+	response := dto.ReportMusicDurationResponse{Data: dto.ToMusicDTO(output.Music)}
+	c.JSON(http.StatusOK, response)
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"north-post/service/internal/domain/v1/models"
+	"north-post/service/internal/repository"
 	"north-post/service/internal/services"
 	"testing"
 
@@ -41,12 +43,24 @@ func (m *mockMusicService) GetPresignedMusicURL(
 	return args.Get(0).(*services.GetPresignedMusicURLOutput), args.Error(1)
 }
 
+func (m *mockMusicService) ReportMusicDuration(
+	ctx context.Context,
+	input services.ReportMusicDurationInput,
+) (*services.ReportMusicDurationOutput, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ReportMusicDurationOutput), args.Error(1)
+}
+
 func setupMusicRouter() (*mockMusicService, *gin.Engine) {
 	mockSrv := new(mockMusicService)
 	handler := NewMusicHandler(mockSrv, slog.Default())
 	router := gin.Default()
 	router.GET("/user/music/list", handler.GetMusicList)
 	router.GET("/user/music/:genre/:track", handler.GetPresignedMusicURL)
+	router.POST("/user/music/duration", handler.ReportMusicDuration)
 	return mockSrv, router
 }
 
@@ -157,3 +171,76 @@ func TestMusicHandler_GetPresignedMusicURL(t *testing.T) {
 		})
 	}
 }
+
+func TestMusicHandler_ReportMusicDuration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name           string
+		body           string
+		mockOutput     *services.ReportMusicDurationOutput
+		mockError      error
+		expectedStatus int
+		expectCall     bool
+	}{
+		{
+			name: "success request",
+			body: `{"filename":"jazz/track.mp3","durationSec":215}`,
+			mockOutput: &services.ReportMusicDurationOutput{
+				Music: models.Music{Filename: "jazz/track.mp3", DurationSec: 215},
+			},
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "missing duration",
+			body:           `{"filename":"jazz/track.mp3"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid filename",
+			body:           `{"filename":"../track.mp3","durationSec":215}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "filename without genre",
+			body:           `{"filename":"track.mp3","durationSec":215}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid duration",
+			body:           `{"filename":"jazz/track.mp3","durationSec":-5}`,
+			mockError:      services.ErrInvalidMusicDuration,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     true,
+		},
+		{
+			name:           "music not found",
+			body:           `{"filename":"jazz/missing.mp3","durationSec":215}`,
+			mockError:      repository.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectCall:     true,
+		},
+		{
+			name:           "service error",
+			body:           `{"filename":"jazz/track.mp3","durationSec":215}`,
+			mockError:      errors.New("failed"),
+			expectedStatus: http.StatusInternalServerError,
+			expectCall:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSrv, router := setupMusicRouter()
+			if tt.expectCall {
+				mockSrv.On("ReportMusicDuration", mock.Anything, mock.Anything).
+					Return(tt.mockOutput, tt.mockError).Once()
+			}
+			req, _ := http.NewRequest(http.MethodPost, "/user/music/duration", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSrv.AssertExpectations(t)
+		})
+	}
+}
@@ -6,19 +6,25 @@ import (
 	"north-post/service/internal/repository"
 	"north-post/service/internal/transport/http/v1/dto"
 	"north-post/service/internal/transport/http/v1/middleware"
+	"north-post/service/internal/transport/http/v1/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
-	repo   userRepository
-	logger *slog.Logger
+	repo        userRepository
+	preferences languagePreferenceCache
+	logger      *slog.Logger
 }
 
-func NewUserHandler(repository userRepository, logger *slog.Logger) *UserHandler {
+func NewUserHandler(
+	repository userRepository,
+	preferences languagePreferenceCache,
+	logger *slog.Logger) *UserHandler {
 	return &UserHandler{
-		repo:   repository,
-		logger: logger,
+		repo:        repository,
+		preferences: preferences,
+		logger:      logger,
 	}
 }
 
@@ -49,3 +55,39 @@ func (h *UserHandler) AuthenticateAppUser(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// UpdateLanguage godoc
+// @Summary Update preferred language
+// @Description Store the language used for requests that pick none by query, body or Accept-Language header. It applies from the next request
+// @Tags App User
+// @Param Authorization header string true "Bearer idToken"
+// @Param request body dto.UpdateUserLanguageRequest true "Preferred language"
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.UpdateUserLanguageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /user/language [put]
+func (h *UserHandler) UpdateLanguage(c *gin.Context) {
+	uid := c.GetString(middleware.UidKey)
+	if !validateUser(c, uid, h.logger) {
+		return
+	}
+	var req dto.UpdateUserLanguageRequest
+	if !utils.BindJSON(c, &req, h.logger) {
+		return
+	}
+	if !utils.ValidateEnabledLanguage(c, req.Language, h.logger) {
+		return
+	}
+	opts := &repository.UpdateUserLanguageOptions{UserID: uid, Language: req.Language}
+	output, err := h.repo.UpdateUserLanguage(c.Request.Context(), opts)
+	if err != nil {
+		h.logger.Error("failed to update user language", "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update user language"})
+		return
+	}
+	h.preferences.Forget(uid)
+	c.JSON(http.StatusOK, dto.UpdateUserLanguageResponse{Data: output})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -19,6 +20,7 @@ func setupUserRouter(handler *UserHandler, uid string) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	r.POST("/user/signin", mockAuthMiddleware(uid), handler.AuthenticateAppUser)
+	r.PUT("/user/language", mockAuthMiddleware(uid), handler.UpdateLanguage)
 	return r
 }
 
@@ -65,7 +67,7 @@ func TestAuthenticateAppUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mockUserRepo)
-			handler := NewUserHandler(mockRepo, slog.Default())
+			handler := NewUserHandler(mockRepo, new(mockLanguagePreferenceCache), slog.Default())
 			router := setupUserRouter(handler, tt.uid)
 			if tt.expectCall {
 				opts := repository.GetUserByIdOptions{Uid: tt.uid}
@@ -86,3 +88,71 @@ func TestAuthenticateAppUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateLanguage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		uid            string
+		body           string
+		expectedOpts   *repository.UpdateUserLanguageOptions
+		mockError      error
+		expectedStatus int
+		expectForget   bool
+	}{
+		{
+			name:           "success",
+			uid:            "user-123",
+			body:           `{"language":"ZH"}`,
+			expectedOpts:   &repository.UpdateUserLanguageOptions{UserID: "user-123", Language: "ZH"},
+			expectedStatus: http.StatusOK,
+			expectForget:   true,
+		},
+		{
+			name:           "unsupported language",
+			uid:            "user-123",
+			body:           `{"language":"abc"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing language",
+			uid:            "user-123",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "server error",
+			uid:            "user-123",
+			body:           `{"language":"en"}`,
+			expectedOpts:   &repository.UpdateUserLanguageOptions{UserID: "user-123", Language: "en"},
+			mockError:      errors.New("firestore unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "missing uid",
+			body:           `{"language":"en"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mockUserRepo)
+			mockCache := new(mockLanguagePreferenceCache)
+			router := setupUserRouter(NewUserHandler(mockRepo, mockCache, slog.Default()), tt.uid)
+			if tt.expectedOpts != nil {
+				mockRepo.On("UpdateUserLanguage", mock.Anything, tt.expectedOpts).Return("123", tt.mockError).Once()
+			}
+			// the cached preference is dropped once the new one is stored
+			if tt.expectForget {
+				mockCache.On("Forget", tt.uid).Once()
+			}
+			req, _ := http.NewRequest("PUT", "/user/language", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockRepo.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
@@ -12,6 +12,8 @@ type Handlers struct {
 	User        *handlers.UserHandler
 	Address     *handlers.AddressHandler
 	AddressBook *handlers.AddressBookHandler
+	Draft       *handlers.DraftHandler
+	LikedMusic  *handlers.LikedMusicHandler
 }
 
 func SetupUserRouter(router *gin.RouterGroup, h *Handlers, middlewares *middleware.Middlewares) {
@@ -21,20 +23,37 @@ func SetupUserRouter(router *gin.RouterGroup, h *Handlers, middlewares *middlewa
 		{
 			music.GET("/list", h.Music.GetMusicList)
 			music.GET("/:genre/:track", h.Music.GetPresignedMusicURL)
+			music.POST("/duration", h.Music.ReportMusicDuration)
+			likes := music.Group("/likes")
+			{
+				likes.GET("", h.LikedMusic.GetLikedMusics)
+				likes.PUT("/:genre/:track", h.LikedMusic.LikeMusic)
+				likes.DELETE("/:genre/:track", h.LikedMusic.UnlikeMusic)
+			}
 		}
 		signIn := user.Group("/signin")
 		{
 			signIn.POST("", h.User.AuthenticateAppUser)
 		}
+		user.PUT("/language", h.User.UpdateLanguage)
 		address := user.Group("/address")
 		{
-			address.POST("", middlewares.LanguageFromBody, h.Address.GetAddresses)
-			address.GET("/tags", middlewares.LanguageFromQuery, h.Address.GetAllTags)
+			address.POST("", middlewares.Language, h.Address.GetAddresses)
+			address.GET("/tags", middlewares.Language, h.Address.GetAllTags)
 		}
 		addressBook := user.Group("/address-book")
 		{
-			addressBook.PATCH("", middlewares.LanguageFromBody, h.AddressBook.UpdateSavedAddresses)
-			addressBook.GET("", middlewares.LanguageFromQuery, h.AddressBook.GetSavedAddresses)
+			addressBook.PATCH("", middlewares.Language, h.AddressBook.UpdateSavedAddresses)
+			addressBook.GET("", middlewares.Language, h.AddressBook.GetSavedAddresses)
+			addressBook.POST("/translate", middlewares.Language, h.AddressBook.TranslateSavedAddresses)
+		}
+		drafts := user.Group("/drafts")
+		{
+			drafts.GET("", h.Draft.ListDrafts)
+			drafts.POST("", h.Draft.CreateDraft)
+			drafts.GET("/:id", h.Draft.GetDraft)
+			drafts.PATCH("/:id", h.Draft.UpdateDraft)
+			drafts.DELETE("/:id", h.Draft.DeleteDraft)
 		}
 	}
 }
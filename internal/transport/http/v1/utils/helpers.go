@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"north-post/service/internal/domain/v1/models"
 	"north-post/service/internal/transport/http/v1/dto"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -29,6 +30,48 @@ func ValidateLanguage(c *gin.Context, language models.Language, logger *slog.Log
 	return true
 }
 
+// Validate a language picked by an app user, a disabled language is rejected like an unknown one
+func ValidateEnabledLanguage(c *gin.Context, language models.Language, logger *slog.Logger) bool {
+	if !ValidateLanguage(c, language, logger) {
+		return false
+	}
+	if !language.IsEnabled() {
+		logger.Warn("disabled language", "language", language)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "unsupported language: " + language.Get()})
+		return false
+	}
+	return true
+}
+
+// Read the required language query parameter
+func QueryLanguage(c *gin.Context, logger *slog.Logger) (models.Language, bool) {
+	language := models.Language(strings.TrimSpace(c.Query("language")))
+	if language == "" {
+		logger.Warn("missing language parameter")
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Language is required"})
+		return "", false
+	}
+	if !ValidateLanguage(c, language, logger) {
+		return "", false
+	}
+	return language, true
+}
+
+// Read the optional limit query parameter, 0 when it is not set
+func QueryLimit(c *gin.Context, logger *slog.Logger) (int, bool) {
+	limitStr := strings.TrimSpace(c.Query("limit"))
+	if limitStr == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		logger.Warn("invalid limit parameter", "limit", limitStr)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid limit parameter"})
+		return 0, false
+	}
+	return limit, true
+}
+
 func ValidateMusicFilename(c *gin.Context, genre string, track string, logger *slog.Logger) bool {
 	if len(track) == 0 || len(genre) == 0 {
 		logger.Error("invalid music filename", "track", track, "genre", genre)
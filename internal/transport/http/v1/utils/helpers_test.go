@@ -126,6 +126,61 @@ func TestValidateLanguage(t *testing.T) {
 	}
 }
 
+func TestQueryLanguage(t *testing.T) {
+	tests := []struct {
+		name             string
+		url              string
+		expectedLanguage models.Language
+		expectedResult   bool
+	}{
+		{name: "valid language", url: "/test?language=en", expectedLanguage: models.LanguageEN, expectedResult: true},
+		{name: "missing language", url: "/test", expectedResult: false},
+		{name: "invalid language", url: "/test?language=abc", expectedResult: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
+			logger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			language, result := QueryLanguage(c, logger)
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, tt.expectedLanguage, language)
+			if !tt.expectedResult {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestQueryLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		expectedLimit  int
+		expectedResult bool
+	}{
+		{name: "no limit", url: "/test", expectedLimit: 0, expectedResult: true},
+		{name: "valid limit", url: "/test?limit=25", expectedLimit: 25, expectedResult: true},
+		{name: "zero limit", url: "/test?limit=0", expectedResult: false},
+		{name: "not a number", url: "/test?limit=ten", expectedResult: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
+			logger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			limit, result := QueryLimit(c, logger)
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, tt.expectedLimit, limit)
+			if !tt.expectedResult {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
 func TestValidateMusicFilename(t *testing.T) {
 	tests := []struct {
 		name           string